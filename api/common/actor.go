@@ -0,0 +1,17 @@
+package common
+
+import "github.com/labstack/echo"
+
+//actorKey key of the request actor inside echo context
+const actorKey = "actor"
+
+//SetActor Record who perform the request, it is written into the access log
+func SetActor(c echo.Context, actor string) {
+	c.Set(actorKey, actor)
+}
+
+//GetActor Get the recorded actor of the request, empty when the handler does not know it
+func GetActor(c echo.Context) string {
+	actor, _ := c.Get(actorKey).(string)
+	return actor
+}
@@ -37,3 +37,91 @@ func NewConflictResponse() DefaultResponse {
 		"Data has been modified",
 	}
 }
+
+//NewInsufficientStockResponse stock is not enough error response
+func NewInsufficientStockResponse() DefaultResponse {
+	return DefaultResponse{
+		409,
+		"Insufficient stock",
+	}
+}
+
+//NewBookingConflictResponse not enough free units for the booking period error response
+func NewBookingConflictResponse() DefaultResponse {
+	return DefaultResponse{
+		409,
+		"Booking conflict with existing bookings",
+	}
+}
+
+//NewInvalidTransitionResponse status can not be changed error response
+func NewInvalidTransitionResponse() DefaultResponse {
+	return DefaultResponse{
+		409,
+		"Invalid status transition",
+	}
+}
+
+//NewNotRentableResponse item has no rental pricing error response
+func NewNotRentableResponse() DefaultResponse {
+	return DefaultResponse{
+		422,
+		"Item is not rentable",
+	}
+}
+
+//NewNotForSaleResponse ordered item has no sale price error response
+func NewNotForSaleResponse() DefaultResponse {
+	return DefaultResponse{
+		422,
+		"Item is not for sale",
+	}
+}
+
+//NewTransitionErrorResponse status change is rejected by state machine error response with the detail
+func NewTransitionErrorResponse(message string) DefaultResponse {
+	return DefaultResponse{
+		409,
+		message,
+	}
+}
+
+//NewReservationExpiredResponse held stock can not be used anymore error response
+func NewReservationExpiredResponse() DefaultResponse {
+	return DefaultResponse{
+		410,
+		"Reservation has expired",
+	}
+}
+
+//NewCartOutdatedResponse cart items have been modified or deleted since they were added error response
+func NewCartOutdatedResponse() DefaultResponse {
+	return DefaultResponse{
+		409,
+		"Cart has outdated items",
+	}
+}
+
+//NewDuplicateCodeResponse code is already used by other data error response
+func NewDuplicateCodeResponse() DefaultResponse {
+	return DefaultResponse{
+		409,
+		"Code is already used",
+	}
+}
+
+//NewPromotionErrorResponse promotion can not be applied error response with the reason
+func NewPromotionErrorResponse(message string) DefaultResponse {
+	return DefaultResponse{
+		422,
+		message,
+	}
+}
+
+//NewItemInUseResponse item still has stock, reservation or booking error response
+func NewItemInUseResponse() DefaultResponse {
+	return DefaultResponse{
+		409,
+		"Item is still in use",
+	}
+}
@@ -0,0 +1,23 @@
+package common
+
+import "sample-order/business/money"
+
+//MoneyResponse money payload in minor unit of its currency
+type MoneyResponse struct {
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	Formatted string `json:"formatted"`
+}
+
+//NewMoneyResponse construct MoneyResponse, return nil if price is not set
+func NewMoneyResponse(price *money.Money) *MoneyResponse {
+	if price == nil {
+		return nil
+	}
+
+	return &MoneyResponse{
+		price.Amount,
+		price.Currency,
+		price.String(),
+	}
+}
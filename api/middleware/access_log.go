@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"sample-order/api/common"
+	"sample-order/util"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/rs/zerolog"
+)
+
+//AccessLog Return echo middleware which write one entry per request into the request logger.
+//Server error is logged as error, client error as warning and the others as info.
+//The error is passed up to be written by RequestID, so bytes_out of error response is not known yet
+func AccessLog() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+
+			req := c.Request()
+			res := c.Response()
+			status := util.ResponseStatus(c, err)
+
+			logger := zerolog.Ctx(req.Context())
+
+			var entry *zerolog.Event
+			switch {
+			case status >= http.StatusInternalServerError:
+				entry = logger.Error().Err(err)
+			case status >= http.StatusBadRequest:
+				entry = logger.Warn()
+			default:
+				entry = logger.Info()
+			}
+
+			entry.
+				Str("method", req.Method).
+				Str("uri", req.RequestURI).
+				Int("status", status).
+				Int64("bytes_out", res.Size).
+				Dur("latency_ms", time.Since(start)).
+				Str("remote_ip", c.RealIP()).
+				Str("actor", common.GetActor(c)).
+				Msg("request handled")
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sample-order/api/common"
+	"sample-order/api/middleware"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/rs/zerolog"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Run("Expect request ID of the caller is kept", func(t *testing.T) {
+		e, _ := newServer()
+
+		req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+		req.Header.Set(middleware.RequestIDHeader, "caller-id-1")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Header().Get(middleware.RequestIDHeader) != "caller-id-1" {
+			t.Error("Expect request ID of the caller is sent back")
+		}
+	})
+
+	t.Run("Expect request ID is generated when missing or invalid", func(t *testing.T) {
+		e, _ := newServer()
+
+		for _, requestID := range []string{"", "has space", strings.Repeat("a", 129)} {
+			req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+			req.Header.Set(middleware.RequestIDHeader, requestID)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			generated := rec.Header().Get(middleware.RequestIDHeader)
+			if generated == "" || generated == requestID {
+				t.Errorf("Expect request ID %q is replaced by generated one", requestID)
+			}
+		}
+	})
+
+	t.Run("Expect error response carry the request ID", func(t *testing.T) {
+		e, _ := newServer()
+
+		for _, path := range []string{"/items/missing", "/items/broken", "/unknown"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set(middleware.RequestIDHeader, "caller-id-2")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal("Expect JSON error response of", path)
+			}
+
+			if body["requestId"] != "caller-id-2" || body["message"] == nil {
+				t.Error("Expect error response of", path, "contain message and request ID, got", rec.Body.String())
+			}
+		}
+	})
+
+	t.Run("Expect success response is not changed", func(t *testing.T) {
+		e, _ := newServer()
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+
+		if strings.Contains(rec.Body.String(), "requestId") {
+			t.Error("Expect success response has no request ID, got", rec.Body.String())
+		}
+	})
+}
+
+func TestAccessLog(t *testing.T) {
+	t.Run("Expect each request is logged with its request ID, status and actor", func(t *testing.T) {
+		e, out := newServer()
+
+		req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+		req.Header.Set(middleware.RequestIDHeader, "caller-id-3")
+		e.ServeHTTP(httptest.NewRecorder(), req)
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+			t.Fatal("Expect single JSON log entry, got", out.String())
+		}
+
+		for field, expected := range map[string]interface{}{
+			"level":      "info",
+			"request_id": "caller-id-3",
+			"method":     "GET",
+			"uri":        "/items/1",
+			"status":     float64(200),
+			"actor":      "alice",
+		} {
+			if entry[field] != expected {
+				t.Errorf("Expect %s is %v, got %v", field, expected, entry[field])
+			}
+		}
+
+		if _, ok := entry["latency_ms"]; !ok {
+			t.Error("Expect latency is logged")
+		}
+	})
+
+	t.Run("Expect level follow the response status", func(t *testing.T) {
+		e, out := newServer()
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/missing", nil))
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/broken", nil))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatal("Expect 2 log entries, got", len(lines))
+		}
+
+		for idx, expected := range []string{`"level":"warn"`, `"level":"error"`} {
+			if !strings.Contains(lines[idx], expected) {
+				t.Error("Expect log entry contain", expected, "got", lines[idx])
+			}
+		}
+
+		if !strings.Contains(lines[1], `"error":"code=500, message=broken"`) || !strings.Contains(lines[1], `"status":500`) {
+			t.Error("Expect server error is logged with its status, got", lines[1])
+		}
+	})
+}
+
+func newServer() (*echo.Echo, *bytes.Buffer) {
+	out := new(bytes.Buffer)
+
+	e := echo.New()
+	e.Use(middleware.RequestID(zerolog.New(out)), middleware.AccessLog())
+	e.GET("/items/:id", func(c echo.Context) error {
+		common.SetActor(c, "alice")
+
+		switch c.Param("id") {
+		case "missing":
+			return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+		case "broken":
+			return echo.NewHTTPError(http.StatusInternalServerError, "broken")
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{"id": c.Param("id")})
+	})
+
+	return e, out
+}
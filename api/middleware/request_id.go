@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"sample-order/api/common"
+	"sample-order/util"
+
+	"github.com/labstack/echo"
+	"github.com/rs/zerolog"
+)
+
+//RequestIDHeader header which carry the request ID from the caller and back into the response
+const RequestIDHeader = "X-Request-ID"
+
+//maxRequestIDLength longest request ID accepted from the caller
+const maxRequestIDLength = 128
+
+//RequestID Return echo middleware which take the request ID from the caller or generate new one when missing or invalid.
+//The ID is sent back in the response header, added into every error response and into every entry of the request logger
+func RequestID(logger zerolog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			requestID := req.Header.Get(RequestIDHeader)
+			if !isValidRequestID(requestID) {
+				requestID = util.GenerateID()
+			}
+
+			c.Response().Header().Set(RequestIDHeader, requestID)
+
+			requestLogger := logger.With().Str("request_id", requestID).Logger()
+			c.SetRequest(req.WithContext(requestLogger.WithContext(req.Context())))
+
+			//the error is written by this context, so the response carry the request ID too.
+			//The inner middlewares pass the error up and only this outermost one write it
+			rc := &requestContext{c, requestID}
+			if err := next(rc); err != nil {
+				rc.Error(err)
+			}
+
+			return nil
+		}
+	}
+}
+
+//requestContext echo context which add the request ID into error response
+type requestContext struct {
+	echo.Context
+	requestID string
+}
+
+//errorResponse error payload with the ID of the failed request
+type errorResponse struct {
+	common.DefaultResponse
+	RequestID string `json:"requestId"`
+}
+
+//JSON implement echo.Context
+func (c *requestContext) JSON(code int, i interface{}) error {
+	if code < http.StatusBadRequest {
+		return c.Context.JSON(code, i)
+	}
+
+	switch response := i.(type) {
+	case common.DefaultResponse:
+		i = errorResponse{response, c.requestID}
+	case echo.Map:
+		//echo write its own error, such as unknown route, as map with message
+		response["requestId"] = c.requestID
+	}
+
+	return c.Context.JSON(code, i)
+}
+
+//Error implement echo.Context, the error handler receive this context instead of the wrapped one
+func (c *requestContext) Error(err error) {
+	c.Echo().HTTPErrorHandler(err, c)
+}
+
+//isValidRequestID only short printable ID is accepted, so the caller cannot inject anything into the log
+func isValidRequestID(requestID string) bool {
+	if len(requestID) == 0 || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range requestID {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+
+	return true
+}
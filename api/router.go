@@ -1,26 +1,135 @@
 package http
 
 import (
+	"sample-order/api/v1/booking"
+	"sample-order/api/v1/cart"
 	"sample-order/api/v1/item"
+	"sample-order/api/v1/order"
+	"sample-order/api/v1/pricing"
+	"sample-order/api/v1/promotion"
+	"sample-order/api/v1/stock"
+	"sample-order/api/v1/warehouse"
+	"sample-order/api/v1/webhook"
 
 	"github.com/labstack/echo"
 )
 
 //RegisterPath Registera V1 API path
-func RegisterPath(e *echo.Echo, itemController *item.Controller) {
+func RegisterPath(e *echo.Echo, itemController *item.Controller, stockController *stock.Controller, warehouseController *warehouse.Controller, bookingController *booking.Controller, pricingController *pricing.Controller, orderController *order.Controller, cartController *cart.Controller, promotionController *promotion.Controller, webhookController *webhook.Controller) {
 	if itemController == nil {
 		panic("item controller cannot be nil")
 	}
 
+	if stockController == nil {
+		panic("stock controller cannot be nil")
+	}
+
+	if warehouseController == nil {
+		panic("warehouse controller cannot be nil")
+	}
+
+	if bookingController == nil {
+		panic("booking controller cannot be nil")
+	}
+
+	if pricingController == nil {
+		panic("pricing controller cannot be nil")
+	}
+
+	if orderController == nil {
+		panic("order controller cannot be nil")
+	}
+
+	if cartController == nil {
+		panic("cart controller cannot be nil")
+	}
+
+	if promotionController == nil {
+		panic("promotion controller cannot be nil")
+	}
+
+	if webhookController == nil {
+		panic("webhook controller cannot be nil")
+	}
+
 	//item
 	itemV1 := e.Group("v1/items")
+	itemV1.GET("/export", itemController.ExportItems)
+	itemV1.GET("/changes", itemController.GetChanges)
+	itemV1.GET("/stream", itemController.StreamItems)
 	itemV1.GET("/:id", itemController.GetItemByID)
+	itemV1.GET("/:id/presence", itemController.JoinPresence)
 	itemV1.GET("/tag/:tag", itemController.FindItemByTag)
 	itemV1.POST("", itemController.CreateNewItem)
+	itemV1.POST("/import", itemController.ImportItems)
 	itemV1.PUT("/:id", itemController.UpdateItem)
+	itemV1.DELETE("/:id", itemController.DeleteItem)
+
+	//stock
+	itemV1.GET("/:id/stock", stockController.GetAvailability)
+	itemV1.GET("/:id/stock/movements", stockController.GetMovements)
+	itemV1.POST("/:id/stock/movements", stockController.RecordMovement)
+	itemV1.POST("/:id/stock/transfers", stockController.TransferStock)
+	itemV1.POST("/:id/reservations", stockController.ReserveStock)
+
+	reservationV1 := e.Group("v1/reservations")
+	reservationV1.GET("/:id", stockController.GetReservationByID)
+	reservationV1.POST("/:id/release", stockController.ReleaseReservation)
+	reservationV1.POST("/:id/convert", stockController.ConvertReservation)
+
+	//booking
+	itemV1.GET("/:id/availability", bookingController.GetFreeSlots)
+	itemV1.GET("/:id/bookings", bookingController.GetBookingsByItemID)
+	itemV1.POST("/:id/bookings", bookingController.CreateBooking)
+
+	//pricing
+	itemV1.GET("/:id/pricing", pricingController.GetRule)
+	itemV1.PUT("/:id/pricing", pricingController.UpdateRule)
+	itemV1.POST("/:id/quote", pricingController.GetQuote)
+
+	bookingV1 := e.Group("v1/bookings")
+	bookingV1.GET("/:id", bookingController.GetBookingByID)
+	bookingV1.PUT("/:id/status", bookingController.UpdateBookingStatus)
+
+	//warehouse
+	warehouseV1 := e.Group("v1/warehouses")
+	warehouseV1.GET("", warehouseController.GetWarehouses)
+	warehouseV1.GET("/:id", warehouseController.GetWarehouseByID)
+	warehouseV1.POST("", warehouseController.CreateWarehouse)
+
+	//order
+	orderV1 := e.Group("v1/orders")
+	orderV1.GET("", orderController.GetOrdersByCustomer)
+	orderV1.GET("/:id", orderController.GetOrderByID)
+	orderV1.POST("", orderController.CreateOrder)
+	orderV1.POST("/:id/transitions", orderController.TransitionOrder)
+	orderV1.POST("/:id/cancel", orderController.CancelOrder)
+
+	//cart
+	cartV1 := e.Group("v1/carts")
+	cartV1.GET("/:owner", cartController.GetCart)
+	cartV1.POST("/:owner/items", cartController.AddItem)
+	cartV1.DELETE("/:owner/items/:itemId", cartController.RemoveItem)
+	cartV1.POST("/:owner/refresh", cartController.RefreshCart)
+	cartV1.PUT("/:owner/promotion", cartController.ApplyPromotion)
+	cartV1.DELETE("/:owner/promotion", cartController.RemovePromotion)
+	cartV1.POST("/:owner/checkout", cartController.CheckoutCart)
+
+	//promotion
+	promotionV1 := e.Group("v1/promotions")
+	promotionV1.GET("", promotionController.GetPromotions)
+	promotionV1.GET("/:id", promotionController.GetPromotionByID)
+	promotionV1.POST("", promotionController.CreatePromotion)
+	promotionV1.PUT("/:id", promotionController.UpdatePromotion)
+	promotionV1.DELETE("/:id", promotionController.DeletePromotion)
 
-	//health check
-	e.GET("/health", func(c echo.Context) error {
-		return c.NoContent(200)
-	})
+	//webhook
+	webhookV1 := e.Group("v1/webhooks")
+	webhookV1.GET("", webhookController.GetSubscriptions)
+	webhookV1.GET("/dead-letters", webhookController.GetDeadLetters)
+	webhookV1.GET("/:id", webhookController.GetSubscriptionByID)
+	webhookV1.POST("", webhookController.CreateSubscription)
+	webhookV1.PUT("/:id", webhookController.UpdateSubscription)
+	webhookV1.DELETE("/:id", webhookController.DeleteSubscription)
+	webhookV1.POST("/deliveries/:id/redeliver", webhookController.Redeliver)
 }
@@ -0,0 +1,158 @@
+package booking
+
+import (
+	"net/http"
+	"sample-order/api/common"
+	"sample-order/api/v1/booking/request"
+	"sample-order/api/v1/booking/response"
+	"sample-order/business"
+	bookingBusiness "sample-order/business/booking"
+	"time"
+
+	v10 "github.com/go-playground/validator/v10"
+	"github.com/labstack/echo"
+)
+
+//dateLayout format of from and to query param
+const dateLayout = "2006-01-02"
+
+//Controller Get booking API controller
+type Controller struct {
+	service   bookingBusiness.Service
+	validator *v10.Validate
+}
+
+//NewController Construct booking API controller
+func NewController(service bookingBusiness.Service) *Controller {
+	return &Controller{
+		service,
+		v10.New(),
+	}
+}
+
+//GetFreeSlots Get periods where the item still has free units echo handler,
+//from and to query param are required in YYYY-MM-DD format and to is exclusive
+func (controller *Controller) GetFreeSlots(c echo.Context) error {
+	from, err := time.Parse(dateLayout, c.QueryParam("from"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	to, err := time.Parse(dateLayout, c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	slots, err := controller.service.GetFreeSlots(c.Param("id"), from, to)
+
+	if err != nil {
+		switch err {
+		case business.ErrInvalidSpec:
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		case business.ErrNotFound:
+			return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+		}
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewGetFreeSlotsResponse(c.Param("id"), slots)
+	return c.JSON(http.StatusOK, response)
+}
+
+//GetBookingsByItemID Get all bookings of item echo handler
+func (controller *Controller) GetBookingsByItemID(c echo.Context) error {
+	bookings, err := controller.service.GetBookingsByItemID(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewGetBookingsResponse(bookings)
+	return c.JSON(http.StatusOK, response)
+}
+
+//GetBookingByID Get booking by ID echo handler
+func (controller *Controller) GetBookingByID(c echo.Context) error {
+	booking, err := controller.service.GetBookingByID(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	} else if booking == nil {
+		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+	}
+
+	response := response.NewGetBookingResponse(*booking)
+	return c.JSON(http.StatusOK, response)
+}
+
+//CreateBooking Reserve units of item for a period echo handler
+func (controller *Controller) CreateBooking(c echo.Context) error {
+	createBookingRequest := new(request.CreateBookingRequest)
+
+	if err := c.Bind(createBookingRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.validator.Struct(createBookingRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	common.SetActor(c, createBookingRequest.Actor)
+
+	ID, err := controller.service.CreateBooking(
+		c.Param("id"),
+		*createBookingRequest.ToCreateBookingSpec(),
+		createBookingRequest.Actor)
+
+	if err != nil {
+		switch err {
+		case business.ErrInvalidSpec:
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		case business.ErrNotFound:
+			return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+		case business.ErrBookingConflict:
+			return c.JSON(http.StatusConflict, common.NewBookingConflictResponse())
+		}
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewCreateBookingResponse(ID)
+	return c.JSON(http.StatusCreated, response)
+}
+
+//UpdateBookingStatus Move booking into next status echo handler
+func (controller *Controller) UpdateBookingStatus(c echo.Context) error {
+	updateStatusRequest := new(request.UpdateBookingStatusRequest)
+
+	if err := c.Bind(updateStatusRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.validator.Struct(updateStatusRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	common.SetActor(c, updateStatusRequest.Actor)
+
+	err := controller.service.UpdateBookingStatus(
+		c.Param("id"),
+		bookingBusiness.Status(updateStatusRequest.Status),
+		updateStatusRequest.Version,
+		updateStatusRequest.Actor)
+
+	if err != nil {
+		switch err {
+		case business.ErrInvalidSpec:
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		case business.ErrNotFound:
+			return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+		case business.ErrHasBeenModified:
+			return c.JSON(http.StatusConflict, common.NewConflictResponse())
+		case business.ErrInvalidTransition:
+			return c.JSON(http.StatusConflict, common.NewInvalidTransitionResponse())
+		}
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,29 @@
+package request
+
+import (
+	"sample-order/business/booking/spec"
+	"time"
+)
+
+//dateLayout format of booking date in request
+const dateLayout = "2006-01-02"
+
+//CreateBookingRequest create booking request payload, the date format is YYYY-MM-DD and to is exclusive
+type CreateBookingRequest struct {
+	Quantity int    `json:"quantity"`
+	From     string `json:"from" validate:"required,datetime=2006-01-02"`
+	To       string `json:"to" validate:"required,datetime=2006-01-02"`
+	Customer string `json:"customer"`
+	Actor    string `json:"actor" validate:"required"`
+}
+
+//ToCreateBookingSpec convert into booking.CreateBookingSpec object, must be called after the request is validated
+func (req *CreateBookingRequest) ToCreateBookingSpec() *spec.CreateBookingSpec {
+	var createBookingSpec spec.CreateBookingSpec
+	createBookingSpec.Quantity = req.Quantity
+	createBookingSpec.From, _ = time.Parse(dateLayout, req.From)
+	createBookingSpec.To, _ = time.Parse(dateLayout, req.To)
+	createBookingSpec.Customer = req.Customer
+
+	return &createBookingSpec
+}
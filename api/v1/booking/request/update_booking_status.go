@@ -0,0 +1,8 @@
+package request
+
+//UpdateBookingStatusRequest update booking status request payload
+type UpdateBookingStatusRequest struct {
+	Status  string `json:"status" validate:"required,oneof=picked_up returned cancelled"`
+	Version int    `json:"version" validate:"required"`
+	Actor   string `json:"actor" validate:"required"`
+}
@@ -0,0 +1,13 @@
+package response
+
+//CreateBookingResponse Create booking response payload
+type CreateBookingResponse struct {
+	ID string `json:"id"`
+}
+
+//NewCreateBookingResponse construct CreateBookingResponse
+func NewCreateBookingResponse(id string) *CreateBookingResponse {
+	return &CreateBookingResponse{
+		id,
+	}
+}
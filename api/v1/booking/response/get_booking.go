@@ -0,0 +1,63 @@
+package response
+
+import (
+	"sample-order/api/common"
+	"sample-order/business/booking"
+	"time"
+)
+
+//dateLayout format of booking date in response
+const dateLayout = "2006-01-02"
+
+//GetBookingResponse Get booking response payload
+type GetBookingResponse struct {
+	ID            string                `json:"id"`
+	ItemID        string                `json:"itemId"`
+	Quantity      int                   `json:"quantity"`
+	From          string                `json:"from"`
+	To            string                `json:"to"`
+	Customer      string                `json:"customer"`
+	Status        string                `json:"status"`
+	FinalCharge   *common.MoneyResponse `json:"finalCharge,omitempty"`
+	LateFee       *common.MoneyResponse `json:"lateFee,omitempty"`
+	DepositRefund *common.MoneyResponse `json:"depositRefund,omitempty"`
+	ModifiedAt    time.Time             `json:"modifiedAt"`
+	Version       int                   `json:"version"`
+}
+
+//NewGetBookingResponse construct GetBookingResponse
+func NewGetBookingResponse(booking booking.Booking) *GetBookingResponse {
+	var bookingResponse GetBookingResponse
+	bookingResponse.ID = booking.ID
+	bookingResponse.ItemID = booking.ItemID
+	bookingResponse.Quantity = booking.Quantity
+	bookingResponse.From = booking.From.Format(dateLayout)
+	bookingResponse.To = booking.To.Format(dateLayout)
+	bookingResponse.Customer = booking.Customer
+	bookingResponse.Status = string(booking.Status)
+	bookingResponse.FinalCharge = common.NewMoneyResponse(booking.FinalCharge)
+	bookingResponse.LateFee = common.NewMoneyResponse(booking.LateFee)
+	bookingResponse.DepositRefund = common.NewMoneyResponse(booking.DepositRefund)
+	bookingResponse.ModifiedAt = booking.ModifiedAt
+	bookingResponse.Version = booking.Version
+
+	return &bookingResponse
+}
+
+//GetBookingsResponse Get bookings of item response payload
+type GetBookingsResponse struct {
+	Bookings []*GetBookingResponse `json:"bookings"`
+}
+
+//NewGetBookingsResponse construct GetBookingsResponse
+func NewGetBookingsResponse(bookings []booking.Booking) *GetBookingsResponse {
+	bookingResponses := make([]*GetBookingResponse, 0)
+
+	for _, booking := range bookings {
+		bookingResponses = append(bookingResponses, NewGetBookingResponse(booking))
+	}
+
+	return &GetBookingsResponse{
+		bookingResponses,
+	}
+}
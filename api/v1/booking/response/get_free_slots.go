@@ -0,0 +1,34 @@
+package response
+
+import "sample-order/business/booking"
+
+//SlotResponse period with free units, to is exclusive
+type SlotResponse struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Available int    `json:"available"`
+}
+
+//GetFreeSlotsResponse Item availability calendar response payload
+type GetFreeSlotsResponse struct {
+	ItemID string          `json:"itemId"`
+	Slots  []*SlotResponse `json:"slots"`
+}
+
+//NewGetFreeSlotsResponse construct GetFreeSlotsResponse
+func NewGetFreeSlotsResponse(itemID string, slots []booking.Slot) *GetFreeSlotsResponse {
+	slotResponses := make([]*SlotResponse, 0)
+
+	for _, slot := range slots {
+		slotResponses = append(slotResponses, &SlotResponse{
+			slot.From.Format(dateLayout),
+			slot.To.Format(dateLayout),
+			slot.Available,
+		})
+	}
+
+	return &GetFreeSlotsResponse{
+		itemID,
+		slotResponses,
+	}
+}
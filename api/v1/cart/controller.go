@@ -0,0 +1,166 @@
+package cart
+
+import (
+	"errors"
+	"net/http"
+	"sample-order/api/common"
+	"sample-order/api/v1/cart/request"
+	"sample-order/api/v1/cart/response"
+	"sample-order/business"
+	cartBusiness "sample-order/business/cart"
+	"strconv"
+
+	"github.com/labstack/echo"
+)
+
+//Controller Get cart API controller
+type Controller struct {
+	service cartBusiness.Service
+}
+
+//NewController Construct cart API controller
+func NewController(service cartBusiness.Service) *Controller {
+	return &Controller{
+		service,
+	}
+}
+
+//GetCart Get cart of user or session echo handler
+func (controller *Controller) GetCart(c echo.Context) error {
+	common.SetActor(c, c.Param("owner"))
+
+	view, err := controller.service.GetCart(c.Param("owner"))
+	if err != nil {
+		return cartErrorResponse(c, err)
+	}
+
+	response := response.NewGetCartResponse(*view)
+	return c.JSON(http.StatusOK, response)
+}
+
+//AddItem Add item into cart echo handler
+func (controller *Controller) AddItem(c echo.Context) error {
+	common.SetActor(c, c.Param("owner"))
+
+	addItemRequest := new(request.AddItemRequest)
+
+	if err := c.Bind(addItemRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	view, err := controller.service.AddItem(c.Param("owner"), *addItemRequest.ToAddItemSpec())
+	if err != nil {
+		return cartErrorResponse(c, err)
+	}
+
+	response := response.NewGetCartResponse(*view)
+	return c.JSON(http.StatusOK, response)
+}
+
+//RemoveItem Remove item from cart echo handler, optional quantity query param decrease the quantity only
+func (controller *Controller) RemoveItem(c echo.Context) error {
+	common.SetActor(c, c.Param("owner"))
+
+	quantity := 0
+
+	if param := c.QueryParam("quantity"); param != "" {
+		var err error
+		if quantity, err = strconv.Atoi(param); err != nil {
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		}
+	}
+
+	view, err := controller.service.RemoveItem(c.Param("owner"), c.Param("itemId"), quantity)
+	if err != nil {
+		return cartErrorResponse(c, err)
+	}
+
+	response := response.NewGetCartResponse(*view)
+	return c.JSON(http.StatusOK, response)
+}
+
+//RefreshCart Accept the current state of the cart items echo handler
+func (controller *Controller) RefreshCart(c echo.Context) error {
+	common.SetActor(c, c.Param("owner"))
+
+	view, err := controller.service.RefreshCart(c.Param("owner"))
+	if err != nil {
+		return cartErrorResponse(c, err)
+	}
+
+	response := response.NewGetCartResponse(*view)
+	return c.JSON(http.StatusOK, response)
+}
+
+//ApplyPromotion Set promotion code of the cart echo handler
+func (controller *Controller) ApplyPromotion(c echo.Context) error {
+	common.SetActor(c, c.Param("owner"))
+
+	applyPromotionRequest := new(request.ApplyPromotionRequest)
+
+	if err := c.Bind(applyPromotionRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	view, err := controller.service.ApplyPromotion(c.Param("owner"), *applyPromotionRequest.ToApplyPromotionSpec())
+	if err != nil {
+		return cartErrorResponse(c, err)
+	}
+
+	response := response.NewGetCartResponse(*view)
+	return c.JSON(http.StatusOK, response)
+}
+
+//RemovePromotion Clear promotion code of the cart echo handler
+func (controller *Controller) RemovePromotion(c echo.Context) error {
+	common.SetActor(c, c.Param("owner"))
+
+	view, err := controller.service.RemovePromotion(c.Param("owner"))
+	if err != nil {
+		return cartErrorResponse(c, err)
+	}
+
+	response := response.NewGetCartResponse(*view)
+	return c.JSON(http.StatusOK, response)
+}
+
+//CheckoutCart Convert cart into order echo handler
+func (controller *Controller) CheckoutCart(c echo.Context) error {
+	common.SetActor(c, c.Param("owner"))
+
+	checkoutCartRequest := new(request.CheckoutCartRequest)
+
+	if err := c.Bind(checkoutCartRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	orderID, err := controller.service.CheckoutCart(c.Param("owner"), *checkoutCartRequest.ToCheckoutCartSpec())
+	if err != nil {
+		return cartErrorResponse(c, err)
+	}
+
+	response := response.NewCheckoutCartResponse(orderID)
+	return c.JSON(http.StatusCreated, response)
+}
+
+func cartErrorResponse(c echo.Context, err error) error {
+	var promotionErr *business.PromotionError
+	if errors.As(err, &promotionErr) {
+		return c.JSON(http.StatusUnprocessableEntity, common.NewPromotionErrorResponse(promotionErr.Error()))
+	}
+
+	switch err {
+	case business.ErrInvalidSpec:
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	case business.ErrNotFound:
+		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+	case business.ErrHasBeenModified:
+		return c.JSON(http.StatusConflict, common.NewConflictResponse())
+	case business.ErrNotForSale:
+		return c.JSON(http.StatusUnprocessableEntity, common.NewNotForSaleResponse())
+	case business.ErrCartOutdated:
+		return c.JSON(http.StatusConflict, common.NewCartOutdatedResponse())
+	}
+
+	return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+}
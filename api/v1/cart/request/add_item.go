@@ -0,0 +1,18 @@
+package request
+
+import "sample-order/business/cart/spec"
+
+//AddItemRequest add item into cart request payload
+type AddItemRequest struct {
+	ItemID   string `json:"itemId"`
+	Quantity int    `json:"quantity"`
+}
+
+//ToAddItemSpec convert into cart.AddItemSpec object
+func (req *AddItemRequest) ToAddItemSpec() *spec.AddItemSpec {
+	var addItemSpec spec.AddItemSpec
+	addItemSpec.ItemID = req.ItemID
+	addItemSpec.Quantity = req.Quantity
+
+	return &addItemSpec
+}
@@ -0,0 +1,16 @@
+package request
+
+import "sample-order/business/cart/spec"
+
+//ApplyPromotionRequest set promotion code of the cart request payload
+type ApplyPromotionRequest struct {
+	Code string `json:"code"`
+}
+
+//ToApplyPromotionSpec convert into cart.ApplyPromotionSpec object
+func (req *ApplyPromotionRequest) ToApplyPromotionSpec() *spec.ApplyPromotionSpec {
+	var applyPromotionSpec spec.ApplyPromotionSpec
+	applyPromotionSpec.Code = req.Code
+
+	return &applyPromotionSpec
+}
@@ -0,0 +1,16 @@
+package request
+
+import "sample-order/business/cart/spec"
+
+//CheckoutCartRequest convert cart into order request payload, customer is optional
+type CheckoutCartRequest struct {
+	Customer string `json:"customer"`
+}
+
+//ToCheckoutCartSpec convert into cart.CheckoutCartSpec object
+func (req *CheckoutCartRequest) ToCheckoutCartSpec() *spec.CheckoutCartSpec {
+	var checkoutCartSpec spec.CheckoutCartSpec
+	checkoutCartSpec.Customer = req.Customer
+
+	return &checkoutCartSpec
+}
@@ -0,0 +1,13 @@
+package response
+
+//CheckoutCartResponse Convert cart into order response payload
+type CheckoutCartResponse struct {
+	OrderID string `json:"orderId"`
+}
+
+//NewCheckoutCartResponse construct CheckoutCartResponse
+func NewCheckoutCartResponse(orderID string) *CheckoutCartResponse {
+	return &CheckoutCartResponse{
+		orderID,
+	}
+}
@@ -0,0 +1,68 @@
+package response
+
+import (
+	"sample-order/api/common"
+	"sample-order/business/cart"
+	"time"
+)
+
+//LineResponse cart line with the current item name and price
+type LineResponse struct {
+	ItemID    string                `json:"itemId"`
+	ItemName  string                `json:"itemName"`
+	Quantity  int                   `json:"quantity"`
+	UnitPrice *common.MoneyResponse `json:"unitPrice"`
+	Subtotal  *common.MoneyResponse `json:"subtotal"`
+	Status    string                `json:"status"`
+	AddedAt   time.Time             `json:"addedAt"`
+}
+
+//GetCartResponse Get cart response payload. Discount and grand total are only set when the promotion code can be applied,
+//otherwise the promotion error tell the reason
+type GetCartResponse struct {
+	Owner          string                `json:"owner"`
+	Lines          []*LineResponse       `json:"lines"`
+	Total          *common.MoneyResponse `json:"total"`
+	PromotionCode  string                `json:"promotionCode"`
+	Discount       *common.MoneyResponse `json:"discount"`
+	GrandTotal     *common.MoneyResponse `json:"grandTotal"`
+	PromotionError string                `json:"promotionError,omitempty"`
+	Outdated       bool                  `json:"outdated"`
+	ModifiedAt     time.Time             `json:"modifiedAt"`
+	Version        int                   `json:"version"`
+}
+
+//NewGetCartResponse construct GetCartResponse
+func NewGetCartResponse(view cart.View) *GetCartResponse {
+	lineResponses := make([]*LineResponse, 0)
+
+	for _, line := range view.Lines {
+		lineResponses = append(lineResponses, &LineResponse{
+			line.ItemID,
+			line.ItemName,
+			line.Quantity,
+			common.NewMoneyResponse(line.UnitPrice),
+			common.NewMoneyResponse(line.Subtotal),
+			string(line.Status),
+			line.AddedAt,
+		})
+	}
+
+	var cartResponse GetCartResponse
+	cartResponse.Owner = view.Cart.Owner
+	cartResponse.Lines = lineResponses
+	cartResponse.Total = common.NewMoneyResponse(view.Total)
+	cartResponse.PromotionCode = view.Cart.PromotionCode
+	cartResponse.Discount = common.NewMoneyResponse(view.Discount)
+	cartResponse.GrandTotal = common.NewMoneyResponse(view.GrandTotal)
+
+	if view.PromotionError != nil {
+		cartResponse.PromotionError = view.PromotionError.Error()
+	}
+
+	cartResponse.Outdated = view.Outdated
+	cartResponse.ModifiedAt = view.Cart.ModifiedAt
+	cartResponse.Version = view.Cart.Version
+
+	return &cartResponse
+}
@@ -1,27 +1,54 @@
 package item
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"sample-order/api/common"
 	"sample-order/api/v1/item/request"
 	"sample-order/api/v1/item/response"
 	"sample-order/business"
 	itemBusiness "sample-order/business/item"
+	"sample-order/business/money"
+	stockBusiness "sample-order/business/stock"
+	"sample-order/modules/itemfile"
+	"strconv"
+	"time"
 
 	v10 "github.com/go-playground/validator/v10"
 	"github.com/labstack/echo"
+	"golang.org/x/net/websocket"
 )
 
+//exportFlushSize number of exported item written before flushing the response
+const exportFlushSize = 100
+
+//defaultChangeLimit number of changes returned when the limit query param is not given
+const defaultChangeLimit = 100
+
+//maxChangeLimit maximum number of changes returned at once
+const maxChangeLimit = 1000
+
+//streamHeartbeat interval of comment line sent into idle event stream, so proxy does not close the connection
+const streamHeartbeat = 15 * time.Second
+
 //Controller Get item API controller
 type Controller struct {
-	service   itemBusiness.Service
-	validator *v10.Validate
+	service      itemBusiness.Service
+	stockService stockBusiness.Service
+	broadcaster  *itemBusiness.Broadcaster
+	presence     *itemBusiness.Presence
+	validator    *v10.Validate
 }
 
-//NewController Construct item API controller
-func NewController(service itemBusiness.Service) *Controller {
+//NewController Construct item API controller, the broadcaster feed the item event stream
+//and the presence track who has each item opened
+func NewController(service itemBusiness.Service, stockService stockBusiness.Service, broadcaster *itemBusiness.Broadcaster, presence *itemBusiness.Presence) *Controller {
 	return &Controller{
 		service,
+		stockService,
+		broadcaster,
+		presence,
 		v10.New(),
 	}
 }
@@ -29,7 +56,7 @@ func NewController(service itemBusiness.Service) *Controller {
 //GetItemByID Get item by ID echo handler
 func (controller *Controller) GetItemByID(c echo.Context) error {
 	ID := c.Param("id")
-	item, err := controller.service.GetItemByID(ID)
+	item, err := controller.service.GetItemByID(c.Request().Context(), ID)
 
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
@@ -37,16 +64,33 @@ func (controller *Controller) GetItemByID(c echo.Context) error {
 		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
 	}
 
-	response := response.NewGetItemByIDResponse(*item)
-	return c.JSON(http.StatusOK, response)
+	availability, err := controller.stockService.GetAvailability(ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	itemResponse := response.NewGetItemByIDResponse(*item)
+	itemResponse.Availability = response.NewAvailabilityResponse(*availability)
+
+	return c.JSON(http.StatusOK, itemResponse)
 }
 
-//FindItemByTag Find item by tag echo handler
+//FindItemByTag Find item by tag echo handler, optionally filtered by sale price using
+//minPrice and maxPrice query param in minor unit of the given currency
 func (controller *Controller) FindItemByTag(c echo.Context) error {
 	tag := c.Param("tag")
-	items, err := controller.service.GetItemsByTag(tag)
 
+	priceRange, err := parsePriceRange(c)
 	if err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	items, err := controller.service.GetItemsByTagAndPrice(c.Request().Context(), tag, priceRange)
+
+	if err != nil {
+		if err == business.ErrInvalidSpec {
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		}
 		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
 	}
 
@@ -62,7 +106,7 @@ func (controller *Controller) CreateNewItem(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
 	}
 
-	ID, err := controller.service.CreateItem(*createItemRequest.ToUpsertItemSpec(), "creator")
+	ID, err := controller.service.CreateItem(c.Request().Context(), *createItemRequest.ToUpsertItemSpec(), "creator")
 
 	if err != nil {
 		if err == business.ErrInvalidSpec {
@@ -89,19 +133,316 @@ func (controller *Controller) UpdateItem(c echo.Context) error {
 	}
 
 	err = controller.service.UpdateItem(
+		c.Request().Context(),
 		c.Param("id"),
 		*updateItemRequest.ToUpsertItemSpec(),
 		updateItemRequest.Version,
 		"updater")
 
 	if err != nil {
-		if err == business.ErrNotFound {
+		switch err {
+		case business.ErrInvalidSpec:
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		case business.ErrNotFound:
 			return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+		case business.ErrHasBeenModified:
+			return c.JSON(http.StatusConflict, common.NewConflictResponse())
 		}
-		if err == business.ErrHasBeenModified {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+//DeleteItem delete item echo handler, the version query param is required
+func (controller *Controller) DeleteItem(c echo.Context) error {
+	version, err := strconv.Atoi(c.QueryParam("version"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	err = controller.service.DeleteItem(c.Request().Context(), c.Param("id"), version, "deleter")
+
+	if err != nil {
+		switch err {
+		case business.ErrInvalidSpec:
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		case business.ErrNotFound:
+			return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+		case business.ErrHasBeenModified:
 			return c.JSON(http.StatusConflict, common.NewConflictResponse())
+		case business.ErrItemInUse:
+			return c.JSON(http.StatusConflict, common.NewItemInUseResponse())
 		}
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
 	}
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+//ImportItems Bulk import items from uploaded CSV or NDJSON file echo handler
+func (controller *Controller) ImportItems(c echo.Context) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	var format itemfile.Format
+	if formatName := c.FormValue("format"); formatName != "" {
+		format, err = itemfile.ParseFormat(formatName)
+	} else {
+		format, err = itemfile.FormatFromFilename(fileHeader.Filename)
+	}
+
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	dryRun := false
+	if dryRunValue := c.FormValue("dryRun"); dryRunValue != "" {
+		if dryRun, err = strconv.ParseBool(dryRunValue); err != nil {
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	defer file.Close()
+
+	rows, err := itemfile.ReadRows(file, format)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	report, err := controller.service.ImportItems(c.Request().Context(), rows, "importer", dryRun)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewImportItemsResponse(*report)
+	return c.JSON(http.StatusOK, response)
+}
+
+//ExportItems Stream all items or items with given tag in NDJSON, CSV or JSON format echo handler
+func (controller *Controller) ExportItems(c echo.Context) error {
+	formatName := c.QueryParam("format")
+	if formatName == "" {
+		formatName = string(itemfile.NDJSON)
+	}
+
+	format, err := itemfile.ParseFormat(formatName)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	res := c.Response()
+	writer, err := itemfile.NewWriter(res, format)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	res.Header().Set(echo.HeaderContentType, format.ContentType())
+	res.Header().Set(echo.HeaderContentDisposition, "attachment; filename=items."+string(format))
+	res.WriteHeader(http.StatusOK)
+
+	count := 0
+	err = controller.service.ExportItems(c.Request().Context(), c.QueryParam("tag"), func(item itemBusiness.Item) error {
+		if err := writer.Write(item); err != nil {
+			return err
+		}
+
+		//flush periodically so the client receive the data while we still read from database
+		count++
+		if count%exportFlushSize == 0 {
+			res.Flush()
+		}
+
+		return nil
+	})
+
+	//status code already sent, so error only can stop the stream
+	if err != nil {
+		return err
+	}
+
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	res.Flush()
+	return nil
+}
+
+//GetChanges Get item changes since the continuation token echo handler. Empty since query param start from the beginning,
+//the next token of the response is given back to get the following changes
+func (controller *Controller) GetChanges(c echo.Context) error {
+	limit := defaultChangeLimit
+
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitParam); err != nil || limit <= 0 || limit > maxChangeLimit {
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		}
+	}
+
+	feed, err := controller.service.GetChanges(c.Request().Context(), c.QueryParam("since"), limit)
+
+	if err != nil {
+		if err == business.ErrInvalidSpec {
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		}
+
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	return c.JSON(http.StatusOK, response.NewChangeFeedResponse(*feed))
+}
+
+//StreamItems Push item changes as server-sent events echo handler, only items with the tag query param when given.
+//Client which reconnect with Last-Event-ID header receive the events it missed, or reset event when they are no longer kept
+func (controller *Controller) StreamItems(c echo.Context) error {
+	subscription, missed, resumed := controller.broadcaster.Subscribe(c.Request().Header.Get("Last-Event-ID"), c.QueryParam("tag"))
+	defer subscription.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	if !resumed {
+		if _, err := fmt.Fprint(res, "event: reset\ndata: {}\n\n"); err != nil {
+			return nil
+		}
+	}
+
+	for _, event := range missed {
+		if err := writeItemEvent(res, event); err != nil {
+			return nil
+		}
+	}
+
+	res.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case event, ok := <-subscription.Events():
+			if !ok {
+				//too slow or server is shutting down, the client reconnect with the last event ID
+				return nil
+			}
+
+			if err := writeItemEvent(res, event); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+		}
+
+		res.Flush()
+	}
+}
+
+//JoinPresence Open WebSocket which push who is viewing or editing the item and its new version as soon as it is saved.
+//The user query param is required, mode is viewing by default. Client send {"mode": "editing"} when the user start editing
+func (controller *Controller) JoinPresence(c echo.Context) error {
+	user := c.QueryParam("user")
+
+	mode := itemBusiness.Mode(c.QueryParam("mode"))
+	if mode == "" {
+		mode = itemBusiness.Viewing
+	}
+
+	if len(user) == 0 || !mode.IsValid() {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	item, err := controller.service.GetItemByID(c.Request().Context(), c.Param("id"))
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	} else if item == nil {
+		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+	}
+
+	//the API has no cookie based session, so request from any origin is accepted
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		session := controller.presence.Join(item.ID, user, mode)
+		defer session.Leave()
+
+		//read mode changes until the client disconnect, leaving the room close the messages and end the writer below
+		go func() {
+			defer session.Leave()
+
+			for {
+				changeModeRequest := new(request.ChangeModeRequest)
+				if err := websocket.JSON.Receive(ws, changeModeRequest); err != nil {
+					return
+				}
+
+				if mode := itemBusiness.Mode(changeModeRequest.Mode); mode.IsValid() {
+					session.ChangeMode(mode)
+				}
+			}
+		}()
+
+		for message := range session.Messages() {
+			if err := websocket.JSON.Send(ws, response.NewPresenceMessageResponse(message)); err != nil {
+				return
+			}
+		}
+	}}
+
+	server.ServeHTTP(c.Response(), c.Request())
+
+	return nil
+}
+
+func writeItemEvent(res *echo.Response, event itemBusiness.Event) error {
+	data, err := json.Marshal(response.NewItemEventResponse(event))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(res, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+func parsePriceRange(c echo.Context) (itemBusiness.PriceRange, error) {
+	var priceRange itemBusiness.PriceRange
+	currency := c.QueryParam("currency")
+
+	for param, boundary := range map[string]**money.Money{"minPrice": &priceRange.Min, "maxPrice": &priceRange.Max} {
+		value := c.QueryParam(param)
+		if value == "" {
+			continue
+		}
+
+		amount, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return priceRange, err
+		}
+
+		price, err := money.New(amount, currency)
+		if err != nil {
+			return priceRange, err
+		}
+
+		*boundary = &price
+	}
+
+	return priceRange, nil
+}
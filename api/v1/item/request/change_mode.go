@@ -0,0 +1,6 @@
+package request
+
+//ChangeModeRequest message sent by the client over the presence WebSocket when the user start or stop editing
+type ChangeModeRequest struct {
+	Mode string `json:"mode"`
+}
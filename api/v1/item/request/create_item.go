@@ -4,9 +4,11 @@ import "sample-order/business/item/spec"
 
 //CreateItemRequest create item request payload
 type CreateItemRequest struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Tags        []string `json:"tags"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Tags        []string      `json:"tags"`
+	SalePrice   *MoneyRequest `json:"salePrice"`
+	RentalRate  *MoneyRequest `json:"rentalRate"`
 }
 
 //ToUpsertItemSpec convert into item.UpsertItemSpec object
@@ -15,6 +17,8 @@ func (req *CreateItemRequest) ToUpsertItemSpec() *spec.UpsertItemSpec {
 	upsertItemSpec.Name = req.Name
 	upsertItemSpec.Description = req.Description
 	upsertItemSpec.Tags = req.Tags
+	upsertItemSpec.SalePrice = req.SalePrice.ToMoneySpec()
+	upsertItemSpec.RentalRate = req.RentalRate.ToMoneySpec()
 
 	return &upsertItemSpec
 }
@@ -0,0 +1,21 @@
+package request
+
+import "sample-order/business/item/spec"
+
+//MoneyRequest money payload in minor unit (e.g. cent) of ISO 4217 currency
+type MoneyRequest struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+//ToMoneySpec convert into spec.MoneySpec object, nil request mean no price
+func (req *MoneyRequest) ToMoneySpec() *spec.MoneySpec {
+	if req == nil {
+		return nil
+	}
+
+	return &spec.MoneySpec{
+		Amount:   req.Amount,
+		Currency: req.Currency,
+	}
+}
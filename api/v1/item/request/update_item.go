@@ -4,10 +4,12 @@ import "sample-order/business/item/spec"
 
 //UpdateItemRequest update item request payload
 type UpdateItemRequest struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Tags        []string `json:"tags"`
-	Version     int      `json:"version" validate:"required"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Tags        []string      `json:"tags"`
+	SalePrice   *MoneyRequest `json:"salePrice"`
+	RentalRate  *MoneyRequest `json:"rentalRate"`
+	Version     int           `json:"version" validate:"required"`
 }
 
 //ToUpsertItemSpec convert into item.UpsertItemSpec object
@@ -16,6 +18,8 @@ func (req *UpdateItemRequest) ToUpsertItemSpec() *spec.UpsertItemSpec {
 	upsertItemSpec.Name = req.Name
 	upsertItemSpec.Description = req.Description
 	upsertItemSpec.Tags = req.Tags
+	upsertItemSpec.SalePrice = req.SalePrice.ToMoneySpec()
+	upsertItemSpec.RentalRate = req.RentalRate.ToMoneySpec()
 
 	return &upsertItemSpec
 }
@@ -0,0 +1,40 @@
+package response
+
+import "sample-order/business/stock"
+
+//WarehouseQuantityResponse Quantity of item in single warehouse
+type WarehouseQuantityResponse struct {
+	WarehouseID string `json:"warehouseId"`
+	Quantity    int    `json:"quantity"`
+	Reserved    int    `json:"reserved"`
+	Available   int    `json:"available"`
+}
+
+//AvailabilityResponse Aggregated stock of item across warehouses
+type AvailabilityResponse struct {
+	Total      int                          `json:"total"`
+	Reserved   int                          `json:"reserved"`
+	Available  int                          `json:"available"`
+	Warehouses []*WarehouseQuantityResponse `json:"warehouses"`
+}
+
+//NewAvailabilityResponse construct AvailabilityResponse
+func NewAvailabilityResponse(availability stock.Availability) *AvailabilityResponse {
+	warehouseResponses := make([]*WarehouseQuantityResponse, 0)
+
+	for _, stock := range availability.Warehouses {
+		warehouseResponses = append(warehouseResponses, &WarehouseQuantityResponse{
+			stock.WarehouseID,
+			stock.Quantity,
+			stock.Reserved,
+			stock.Available(),
+		})
+	}
+
+	return &AvailabilityResponse{
+		availability.Total,
+		availability.Reserved,
+		availability.Available,
+		warehouseResponses,
+	}
+}
@@ -0,0 +1,49 @@
+package response
+
+import (
+	"sample-order/business/item"
+	"time"
+)
+
+//ChangeResponse Latest change of single item, the item is nil when it has been deleted
+type ChangeResponse struct {
+	ItemID     string               `json:"itemId"`
+	Deleted    bool                 `json:"deleted"`
+	Version    int                  `json:"version"`
+	Item       *GetItemByIDResponse `json:"item"`
+	ModifiedAt time.Time            `json:"modifiedAt"`
+	ModifiedBy string               `json:"modifiedBy"`
+}
+
+//ChangeFeedResponse Page of item changes with the continuation token
+type ChangeFeedResponse struct {
+	Changes []ChangeResponse `json:"changes"`
+	Next    string           `json:"next"`
+	HasMore bool             `json:"hasMore"`
+}
+
+//NewChangeFeedResponse construct ChangeFeedResponse
+func NewChangeFeedResponse(feed item.ChangeFeed) *ChangeFeedResponse {
+	changeResponses := make([]ChangeResponse, 0, len(feed.Changes))
+
+	for _, change := range feed.Changes {
+		var changeResponse ChangeResponse
+		changeResponse.ItemID = change.ItemID
+		changeResponse.Deleted = change.Deleted
+		changeResponse.Version = change.Version
+		changeResponse.ModifiedAt = change.ModifiedAt
+		changeResponse.ModifiedBy = change.ModifiedBy
+
+		if change.Item != nil {
+			changeResponse.Item = NewGetItemByIDResponse(*change.Item)
+		}
+
+		changeResponses = append(changeResponses, changeResponse)
+	}
+
+	return &ChangeFeedResponse{
+		changeResponses,
+		feed.Next.String(),
+		feed.HasMore,
+	}
+}
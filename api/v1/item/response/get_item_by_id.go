@@ -1,18 +1,22 @@
 package response
 
 import (
+	"sample-order/api/common"
 	"sample-order/business/item"
 	"time"
 )
 
 //GetItemByIDResponse Get item by ID response payload
 type GetItemByIDResponse struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Tags        []string  `json:"tags"`
-	ModifiedAt  time.Time `json:"modifiedAt"`
-	Version     int       `json:"version"`
+	ID           string                `json:"id"`
+	Name         string                `json:"name"`
+	Description  string                `json:"description"`
+	Tags         []string              `json:"tags"`
+	SalePrice    *common.MoneyResponse `json:"salePrice,omitempty"`
+	RentalRate   *common.MoneyResponse `json:"rentalRate,omitempty"`
+	Availability *AvailabilityResponse `json:"availability,omitempty"`
+	ModifiedAt   time.Time             `json:"modifiedAt"`
+	Version      int                   `json:"version"`
 }
 
 //NewGetItemByIDResponse construct GetItemByIDResponse
@@ -22,6 +26,8 @@ func NewGetItemByIDResponse(item item.Item) *GetItemByIDResponse {
 	itemResponse.Name = item.Name
 	itemResponse.Description = item.Description
 	itemResponse.Tags = item.Tags
+	itemResponse.SalePrice = common.NewMoneyResponse(item.SalePrice)
+	itemResponse.RentalRate = common.NewMoneyResponse(item.RentalRate)
 	itemResponse.ModifiedAt = item.ModifiedAt
 	itemResponse.Version = item.Version
 
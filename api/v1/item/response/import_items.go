@@ -0,0 +1,45 @@
+package response
+
+import "sample-order/business/item"
+
+//ImportItemResultResponse Import result of single row
+type ImportItemResultResponse struct {
+	Line  int    `json:"line"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+//ImportItemsResponse Import items response payload
+type ImportItemsResponse struct {
+	DryRun    bool                        `json:"dryRun"`
+	Total     int                         `json:"total"`
+	Succeeded int                         `json:"succeeded"`
+	Failed    int                         `json:"failed"`
+	Rows      []*ImportItemResultResponse `json:"rows"`
+}
+
+//NewImportItemsResponse construct ImportItemsResponse
+func NewImportItemsResponse(report item.ImportReport) *ImportItemsResponse {
+	rows := make([]*ImportItemResultResponse, 0, len(report.Results))
+
+	for _, result := range report.Results {
+		row := &ImportItemResultResponse{
+			Line: result.Line,
+			ID:   result.ID,
+		}
+
+		if result.Err != nil {
+			row.Error = result.Err.Error()
+		}
+
+		rows = append(rows, row)
+	}
+
+	return &ImportItemsResponse{
+		report.DryRun,
+		report.Total,
+		report.Succeeded,
+		report.Failed,
+		rows,
+	}
+}
@@ -0,0 +1,34 @@
+package response
+
+import (
+	"sample-order/business/item"
+	"time"
+)
+
+//ItemEventResponse Item change notification payload, the item is the state after the change and nil when deleted
+type ItemEventResponse struct {
+	ID         string               `json:"id"`
+	Type       string               `json:"type"`
+	ItemID     string               `json:"itemId"`
+	Version    int                  `json:"version"`
+	Item       *GetItemByIDResponse `json:"item"`
+	Actor      string               `json:"actor"`
+	OccurredAt time.Time            `json:"occurredAt"`
+}
+
+//NewItemEventResponse construct ItemEventResponse
+func NewItemEventResponse(event item.Event) *ItemEventResponse {
+	var eventResponse ItemEventResponse
+	eventResponse.ID = event.ID
+	eventResponse.Type = string(event.Type)
+	eventResponse.ItemID = event.ItemID
+	eventResponse.Version = event.Version
+	eventResponse.Actor = event.Actor
+	eventResponse.OccurredAt = event.OccurredAt
+
+	if event.After != nil {
+		eventResponse.Item = NewGetItemByIDResponse(*event.After)
+	}
+
+	return &eventResponse
+}
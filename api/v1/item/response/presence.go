@@ -0,0 +1,46 @@
+package response
+
+import (
+	"sample-order/business/item"
+	"time"
+)
+
+//PresenceMessageResponse Message pushed to everyone who has the item opened. Presence message has the participants,
+//saved message has the new version and who save it
+type PresenceMessageResponse struct {
+	Type         string                 `json:"type"`
+	ItemID       string                 `json:"itemId"`
+	Participants []*ParticipantResponse `json:"participants,omitempty"`
+	Version      int                    `json:"version,omitempty"`
+	Actor        string                 `json:"actor,omitempty"`
+	At           time.Time              `json:"at"`
+}
+
+//ParticipantResponse user who has the item opened
+type ParticipantResponse struct {
+	SessionID string    `json:"sessionId"`
+	User      string    `json:"user"`
+	Mode      string    `json:"mode"`
+	JoinedAt  time.Time `json:"joinedAt"`
+}
+
+//NewPresenceMessageResponse construct PresenceMessageResponse
+func NewPresenceMessageResponse(message item.PresenceMessage) *PresenceMessageResponse {
+	var messageResponse PresenceMessageResponse
+	messageResponse.Type = string(message.Type)
+	messageResponse.ItemID = message.ItemID
+	messageResponse.Version = message.Version
+	messageResponse.Actor = message.Actor
+	messageResponse.At = message.At
+
+	for _, participant := range message.Participants {
+		messageResponse.Participants = append(messageResponse.Participants, &ParticipantResponse{
+			participant.SessionID,
+			participant.User,
+			string(participant.Mode),
+			participant.JoinedAt,
+		})
+	}
+
+	return &messageResponse
+}
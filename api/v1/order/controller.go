@@ -0,0 +1,160 @@
+package order
+
+import (
+	"errors"
+	"net/http"
+	"sample-order/api/common"
+	"sample-order/api/v1/order/request"
+	"sample-order/api/v1/order/response"
+	"sample-order/business"
+	orderBusiness "sample-order/business/order"
+
+	v10 "github.com/go-playground/validator/v10"
+	"github.com/labstack/echo"
+)
+
+//Controller Get order API controller
+type Controller struct {
+	service   orderBusiness.Service
+	validator *v10.Validate
+}
+
+//NewController Construct order API controller
+func NewController(service orderBusiness.Service) *Controller {
+	return &Controller{
+		service,
+		v10.New(),
+	}
+}
+
+//GetOrderByID Get order by ID echo handler
+func (controller *Controller) GetOrderByID(c echo.Context) error {
+	order, err := controller.service.GetOrderByID(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	} else if order == nil {
+		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+	}
+
+	response := response.NewGetOrderResponse(*order)
+	return c.JSON(http.StatusOK, response)
+}
+
+//GetOrdersByCustomer Get orders of customer given in customer query param echo handler
+func (controller *Controller) GetOrdersByCustomer(c echo.Context) error {
+	customer := c.QueryParam("customer")
+	if customer == "" {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	orders, err := controller.service.GetOrdersByCustomer(customer)
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewGetOrdersResponse(orders)
+	return c.JSON(http.StatusOK, response)
+}
+
+//CreateOrder Place new order echo handler
+func (controller *Controller) CreateOrder(c echo.Context) error {
+	createOrderRequest := new(request.CreateOrderRequest)
+
+	if err := c.Bind(createOrderRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	ID, err := controller.service.CreateOrder(*createOrderRequest.ToCreateOrderSpec(), "creator")
+
+	if err != nil {
+		var promotionErr *business.PromotionError
+		if errors.As(err, &promotionErr) {
+			return c.JSON(http.StatusUnprocessableEntity, common.NewPromotionErrorResponse(promotionErr.Error()))
+		}
+
+		switch err {
+		case business.ErrInvalidSpec:
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		case business.ErrNotForSale:
+			return c.JSON(http.StatusUnprocessableEntity, common.NewNotForSaleResponse())
+		}
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewCreateOrderResponse(ID)
+	return c.JSON(http.StatusCreated, response)
+}
+
+//TransitionOrder Move order into next lifecycle status echo handler
+func (controller *Controller) TransitionOrder(c echo.Context) error {
+	transitionOrderRequest := new(request.TransitionOrderRequest)
+
+	if err := c.Bind(transitionOrderRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.validator.Struct(transitionOrderRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	common.SetActor(c, transitionOrderRequest.Actor)
+
+	err := controller.service.TransitionOrder(
+		c.Param("id"),
+		orderBusiness.Status(transitionOrderRequest.Status),
+		transitionOrderRequest.Version,
+		transitionOrderRequest.Actor)
+
+	if err != nil {
+		return transitionErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+//CancelOrder Cancel draft or placed order echo handler
+func (controller *Controller) CancelOrder(c echo.Context) error {
+	cancelOrderRequest := new(request.CancelOrderRequest)
+
+	if err := c.Bind(cancelOrderRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.validator.Struct(cancelOrderRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	common.SetActor(c, cancelOrderRequest.Actor)
+
+	err := controller.service.CancelOrder(c.Param("id"), cancelOrderRequest.Version, cancelOrderRequest.Actor)
+
+	if err != nil {
+		return transitionErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func transitionErrorResponse(c echo.Context, err error) error {
+	var transitionErr *business.TransitionError
+	if errors.As(err, &transitionErr) {
+		return c.JSON(http.StatusConflict, common.NewTransitionErrorResponse(transitionErr.Error()))
+	}
+
+	switch err {
+	case business.ErrInvalidSpec:
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	case business.ErrNotFound:
+		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+	case business.ErrHasBeenModified:
+		return c.JSON(http.StatusConflict, common.NewConflictResponse())
+	case business.ErrInsufficientStock:
+		return c.JSON(http.StatusConflict, common.NewInsufficientStockResponse())
+	case business.ErrReservationExpired:
+		return c.JSON(http.StatusGone, common.NewReservationExpiredResponse())
+	}
+
+	return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+}
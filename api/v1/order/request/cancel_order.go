@@ -0,0 +1,7 @@
+package request
+
+//CancelOrderRequest cancel order request payload
+type CancelOrderRequest struct {
+	Version int    `json:"version" validate:"required"`
+	Actor   string `json:"actor" validate:"required"`
+}
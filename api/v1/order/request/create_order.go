@@ -0,0 +1,32 @@
+package request
+
+import "sample-order/business/order/spec"
+
+//CreateOrderRequest create order request payload, promotion code is optional
+type CreateOrderRequest struct {
+	Customer      string        `json:"customer"`
+	Lines         []LineRequest `json:"lines"`
+	PromotionCode string        `json:"promotionCode"`
+}
+
+//LineRequest ordered item and its quantity
+type LineRequest struct {
+	ItemID   string `json:"itemId"`
+	Quantity int    `json:"quantity"`
+}
+
+//ToCreateOrderSpec convert into order.CreateOrderSpec object
+func (req *CreateOrderRequest) ToCreateOrderSpec() *spec.CreateOrderSpec {
+	var createOrderSpec spec.CreateOrderSpec
+	createOrderSpec.Customer = req.Customer
+	createOrderSpec.PromotionCode = req.PromotionCode
+
+	for _, line := range req.Lines {
+		createOrderSpec.Lines = append(createOrderSpec.Lines, spec.LineSpec{
+			ItemID:   line.ItemID,
+			Quantity: line.Quantity,
+		})
+	}
+
+	return &createOrderSpec
+}
@@ -0,0 +1,8 @@
+package request
+
+//TransitionOrderRequest move order into next status request payload
+type TransitionOrderRequest struct {
+	Status  string `json:"status" validate:"required"`
+	Version int    `json:"version" validate:"required"`
+	Actor   string `json:"actor" validate:"required"`
+}
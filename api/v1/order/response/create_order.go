@@ -0,0 +1,13 @@
+package response
+
+//CreateOrderResponse Create order response payload
+type CreateOrderResponse struct {
+	ID string `json:"id"`
+}
+
+//NewCreateOrderResponse construct CreateOrderResponse
+func NewCreateOrderResponse(id string) *CreateOrderResponse {
+	return &CreateOrderResponse{
+		id,
+	}
+}
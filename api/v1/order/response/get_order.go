@@ -0,0 +1,100 @@
+package response
+
+import (
+	"sample-order/api/common"
+	"sample-order/business/order"
+	"time"
+)
+
+//LineResponse ordered item with captured name and price
+type LineResponse struct {
+	ItemID    string                `json:"itemId"`
+	ItemName  string                `json:"itemName"`
+	Quantity  int                   `json:"quantity"`
+	UnitPrice *common.MoneyResponse `json:"unitPrice"`
+	Subtotal  *common.MoneyResponse `json:"subtotal"`
+}
+
+//TransitionResponse recorded status change
+type TransitionResponse struct {
+	From  string    `json:"from"`
+	To    string    `json:"to"`
+	Actor string    `json:"actor"`
+	At    time.Time `json:"at"`
+}
+
+//GetOrderResponse Get order response payload
+type GetOrderResponse struct {
+	ID            string                `json:"id"`
+	Customer      string                `json:"customer"`
+	Lines         []*LineResponse       `json:"lines"`
+	Subtotal      *common.MoneyResponse `json:"subtotal"`
+	Discount      *common.MoneyResponse `json:"discount"`
+	PromotionCode string                `json:"promotionCode"`
+	Total         *common.MoneyResponse `json:"total"`
+	Status        string                `json:"status"`
+	Transitions   []*TransitionResponse `json:"transitions"`
+	CreatedAt     time.Time             `json:"createdAt"`
+	ModifiedAt    time.Time             `json:"modifiedAt"`
+	Version       int                   `json:"version"`
+}
+
+//NewGetOrderResponse construct GetOrderResponse
+func NewGetOrderResponse(order order.Order) *GetOrderResponse {
+	lineResponses := make([]*LineResponse, 0)
+
+	for _, line := range order.Lines {
+		lineResponses = append(lineResponses, &LineResponse{
+			line.ItemID,
+			line.ItemName,
+			line.Quantity,
+			common.NewMoneyResponse(&line.UnitPrice),
+			common.NewMoneyResponse(&line.Subtotal),
+		})
+	}
+
+	transitionResponses := make([]*TransitionResponse, 0)
+
+	for _, transition := range order.Transitions {
+		transitionResponses = append(transitionResponses, &TransitionResponse{
+			string(transition.From),
+			string(transition.To),
+			transition.Actor,
+			transition.At,
+		})
+	}
+
+	var orderResponse GetOrderResponse
+	orderResponse.ID = order.ID
+	orderResponse.Customer = order.Customer
+	orderResponse.Lines = lineResponses
+	orderResponse.Subtotal = common.NewMoneyResponse(&order.Subtotal)
+	orderResponse.Discount = common.NewMoneyResponse(&order.Discount)
+	orderResponse.PromotionCode = order.PromotionCode
+	orderResponse.Total = common.NewMoneyResponse(&order.Total)
+	orderResponse.Status = string(order.Status)
+	orderResponse.Transitions = transitionResponses
+	orderResponse.CreatedAt = order.CreatedAt
+	orderResponse.ModifiedAt = order.ModifiedAt
+	orderResponse.Version = order.Version
+
+	return &orderResponse
+}
+
+//GetOrdersResponse Get orders of customer response payload
+type GetOrdersResponse struct {
+	Orders []*GetOrderResponse `json:"orders"`
+}
+
+//NewGetOrdersResponse construct GetOrdersResponse
+func NewGetOrdersResponse(orders []order.Order) *GetOrdersResponse {
+	orderResponses := make([]*GetOrderResponse, 0)
+
+	for _, order := range orders {
+		orderResponses = append(orderResponses, NewGetOrderResponse(order))
+	}
+
+	return &GetOrdersResponse{
+		orderResponses,
+	}
+}
@@ -0,0 +1,91 @@
+package pricing
+
+import (
+	"net/http"
+	"sample-order/api/common"
+	"sample-order/api/v1/pricing/request"
+	"sample-order/api/v1/pricing/response"
+	"sample-order/business"
+	pricingBusiness "sample-order/business/pricing"
+
+	v10 "github.com/go-playground/validator/v10"
+	"github.com/labstack/echo"
+)
+
+//Controller Get pricing API controller
+type Controller struct {
+	service   pricingBusiness.Service
+	validator *v10.Validate
+}
+
+//NewController Construct pricing API controller
+func NewController(service pricingBusiness.Service) *Controller {
+	return &Controller{
+		service,
+		v10.New(),
+	}
+}
+
+//GetRule Get rental pricing rule of item echo handler
+func (controller *Controller) GetRule(c echo.Context) error {
+	rule, err := controller.service.GetRule(c.Param("id"))
+
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	response := response.NewGetRuleResponse(*rule)
+	return c.JSON(http.StatusOK, response)
+}
+
+//UpdateRule Replace rental pricing rule of item echo handler
+func (controller *Controller) UpdateRule(c echo.Context) error {
+	updateRuleRequest := new(request.UpdateRuleRequest)
+
+	if err := c.Bind(updateRuleRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	err := controller.service.UpdateRule(c.Param("id"), *updateRuleRequest.ToUpsertRuleSpec(), "updater")
+
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+//GetQuote Compute rental price of item for a period echo handler
+func (controller *Controller) GetQuote(c echo.Context) error {
+	getQuoteRequest := new(request.GetQuoteRequest)
+
+	if err := c.Bind(getQuoteRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.validator.Struct(getQuoteRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	quote, err := controller.service.GetQuote(c.Param("id"), *getQuoteRequest.ToQuoteSpec())
+
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	response := response.NewGetQuoteResponse(*quote)
+	return c.JSON(http.StatusOK, response)
+}
+
+func errorResponse(c echo.Context, err error) error {
+	switch err {
+	case business.ErrInvalidSpec:
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	case business.ErrNotFound:
+		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+	case business.ErrNotRentable:
+		return c.JSON(http.StatusUnprocessableEntity, common.NewNotRentableResponse())
+	}
+
+	return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+}
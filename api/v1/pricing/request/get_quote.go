@@ -0,0 +1,31 @@
+package request
+
+import (
+	"sample-order/business/pricing/spec"
+	"time"
+)
+
+//dateLayout format of rental date in request
+const dateLayout = "2006-01-02"
+
+//GetQuoteRequest rental quote request payload, the date format is YYYY-MM-DD and to is exclusive
+type GetQuoteRequest struct {
+	Quantity int    `json:"quantity"`
+	From     string `json:"from" validate:"required,datetime=2006-01-02"`
+	To       string `json:"to" validate:"required,datetime=2006-01-02"`
+}
+
+//ToQuoteSpec convert into pricing.QuoteSpec object, must be called after the request is validated.
+//Quantity default to one unit
+func (req *GetQuoteRequest) ToQuoteSpec() *spec.QuoteSpec {
+	var quoteSpec spec.QuoteSpec
+	quoteSpec.Quantity = req.Quantity
+	quoteSpec.From, _ = time.Parse(dateLayout, req.From)
+	quoteSpec.To, _ = time.Parse(dateLayout, req.To)
+
+	if quoteSpec.Quantity == 0 {
+		quoteSpec.Quantity = 1
+	}
+
+	return &quoteSpec
+}
@@ -0,0 +1,44 @@
+package request
+
+import "sample-order/business/pricing/spec"
+
+//UpdateRuleRequest update rental pricing rule request payload, every money must have the same currency
+type UpdateRuleRequest struct {
+	DailyRate               MoneyRequest  `json:"dailyRate"`
+	WeeklyRate              *MoneyRequest `json:"weeklyRate"`
+	MinDays                 int           `json:"minDays"`
+	WeekendSurchargePercent int           `json:"weekendSurchargePercent"`
+	Deposit                 *MoneyRequest `json:"deposit"`
+	LateFeePerDay           *MoneyRequest `json:"lateFeePerDay"`
+}
+
+//MoneyRequest money payload in minor unit (e.g. cent) of ISO 4217 currency
+type MoneyRequest struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+//ToUpsertRuleSpec convert into pricing.UpsertRuleSpec object
+func (req *UpdateRuleRequest) ToUpsertRuleSpec() *spec.UpsertRuleSpec {
+	var upsertRuleSpec spec.UpsertRuleSpec
+	upsertRuleSpec.DailyRate = *req.DailyRate.ToMoneySpec()
+	upsertRuleSpec.WeeklyRate = req.WeeklyRate.ToMoneySpec()
+	upsertRuleSpec.MinDays = req.MinDays
+	upsertRuleSpec.WeekendSurchargePercent = req.WeekendSurchargePercent
+	upsertRuleSpec.Deposit = req.Deposit.ToMoneySpec()
+	upsertRuleSpec.LateFeePerDay = req.LateFeePerDay.ToMoneySpec()
+
+	return &upsertRuleSpec
+}
+
+//ToMoneySpec convert into spec.MoneySpec object, nil request mean not set
+func (req *MoneyRequest) ToMoneySpec() *spec.MoneySpec {
+	if req == nil {
+		return nil
+	}
+
+	return &spec.MoneySpec{
+		Amount:   req.Amount,
+		Currency: req.Currency,
+	}
+}
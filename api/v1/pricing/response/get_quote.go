@@ -0,0 +1,40 @@
+package response
+
+import (
+	"sample-order/api/common"
+	"sample-order/business/pricing"
+)
+
+//dateLayout format of rental date in response
+const dateLayout = "2006-01-02"
+
+//GetQuoteResponse Rental quote response payload, total include the refundable deposit
+type GetQuoteResponse struct {
+	ItemID           string                `json:"itemId"`
+	Quantity         int                   `json:"quantity"`
+	From             string                `json:"from"`
+	To               string                `json:"to"`
+	Days             int                   `json:"days"`
+	BillableDays     int                   `json:"billableDays"`
+	RentalCharge     *common.MoneyResponse `json:"rentalCharge"`
+	WeekendSurcharge *common.MoneyResponse `json:"weekendSurcharge"`
+	Deposit          *common.MoneyResponse `json:"deposit"`
+	Total            *common.MoneyResponse `json:"total"`
+}
+
+//NewGetQuoteResponse construct GetQuoteResponse
+func NewGetQuoteResponse(quote pricing.Quote) *GetQuoteResponse {
+	var quoteResponse GetQuoteResponse
+	quoteResponse.ItemID = quote.ItemID
+	quoteResponse.Quantity = quote.Quantity
+	quoteResponse.From = quote.From.Format(dateLayout)
+	quoteResponse.To = quote.To.Format(dateLayout)
+	quoteResponse.Days = quote.Days
+	quoteResponse.BillableDays = quote.BillableDays
+	quoteResponse.RentalCharge = common.NewMoneyResponse(&quote.RentalCharge)
+	quoteResponse.WeekendSurcharge = common.NewMoneyResponse(&quote.WeekendSurcharge)
+	quoteResponse.Deposit = common.NewMoneyResponse(&quote.Deposit)
+	quoteResponse.Total = common.NewMoneyResponse(&quote.Total)
+
+	return &quoteResponse
+}
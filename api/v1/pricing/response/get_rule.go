@@ -0,0 +1,34 @@
+package response
+
+import (
+	"sample-order/api/common"
+	"sample-order/business/pricing"
+	"time"
+)
+
+//GetRuleResponse Rental pricing rule response payload
+type GetRuleResponse struct {
+	ItemID                  string                `json:"itemId"`
+	DailyRate               *common.MoneyResponse `json:"dailyRate"`
+	WeeklyRate              *common.MoneyResponse `json:"weeklyRate,omitempty"`
+	MinDays                 int                   `json:"minDays"`
+	WeekendSurchargePercent int                   `json:"weekendSurchargePercent"`
+	Deposit                 *common.MoneyResponse `json:"deposit,omitempty"`
+	LateFeePerDay           *common.MoneyResponse `json:"lateFeePerDay,omitempty"`
+	ModifiedAt              time.Time             `json:"modifiedAt"`
+}
+
+//NewGetRuleResponse construct GetRuleResponse
+func NewGetRuleResponse(rule pricing.Rule) *GetRuleResponse {
+	var ruleResponse GetRuleResponse
+	ruleResponse.ItemID = rule.ItemID
+	ruleResponse.DailyRate = common.NewMoneyResponse(&rule.DailyRate)
+	ruleResponse.WeeklyRate = common.NewMoneyResponse(rule.WeeklyRate)
+	ruleResponse.MinDays = rule.MinDays
+	ruleResponse.WeekendSurchargePercent = rule.WeekendSurchargePercent
+	ruleResponse.Deposit = common.NewMoneyResponse(rule.Deposit)
+	ruleResponse.LateFeePerDay = common.NewMoneyResponse(rule.LateFeePerDay)
+	ruleResponse.ModifiedAt = rule.ModifiedAt
+
+	return &ruleResponse
+}
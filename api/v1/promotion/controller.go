@@ -0,0 +1,126 @@
+package promotion
+
+import (
+	"net/http"
+	"sample-order/api/common"
+	"sample-order/api/v1/promotion/request"
+	"sample-order/api/v1/promotion/response"
+	"sample-order/business"
+	promotionBusiness "sample-order/business/promotion"
+	"strconv"
+
+	v10 "github.com/go-playground/validator/v10"
+	"github.com/labstack/echo"
+)
+
+//Controller Get promotion API controller
+type Controller struct {
+	service   promotionBusiness.Service
+	validator *v10.Validate
+}
+
+//NewController Construct promotion API controller
+func NewController(service promotionBusiness.Service) *Controller {
+	return &Controller{
+		service,
+		v10.New(),
+	}
+}
+
+//GetPromotionByID Get promotion by ID echo handler
+func (controller *Controller) GetPromotionByID(c echo.Context) error {
+	promotion, err := controller.service.GetPromotionByID(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	} else if promotion == nil {
+		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+	}
+
+	response := response.NewGetPromotionResponse(*promotion)
+	return c.JSON(http.StatusOK, response)
+}
+
+//GetPromotions Get all promotions echo handler
+func (controller *Controller) GetPromotions(c echo.Context) error {
+	promotions, err := controller.service.GetPromotions()
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewGetPromotionsResponse(promotions)
+	return c.JSON(http.StatusOK, response)
+}
+
+//CreatePromotion Create new promotion echo handler
+func (controller *Controller) CreatePromotion(c echo.Context) error {
+	createPromotionRequest := new(request.CreatePromotionRequest)
+
+	if err := c.Bind(createPromotionRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	ID, err := controller.service.CreatePromotion(*createPromotionRequest.ToUpsertPromotionSpec(), "creator")
+
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	response := response.NewCreatePromotionResponse(ID)
+	return c.JSON(http.StatusCreated, response)
+}
+
+//UpdatePromotion Replace the rule of promotion echo handler
+func (controller *Controller) UpdatePromotion(c echo.Context) error {
+	updatePromotionRequest := new(request.UpdatePromotionRequest)
+
+	if err := c.Bind(updatePromotionRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.validator.Struct(updatePromotionRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	err := controller.service.UpdatePromotion(
+		c.Param("id"),
+		*updatePromotionRequest.ToUpsertPromotionSpec(),
+		updatePromotionRequest.Version,
+		"updater")
+
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+//DeletePromotion Delete promotion echo handler, the version query param is required
+func (controller *Controller) DeletePromotion(c echo.Context) error {
+	version, err := strconv.Atoi(c.QueryParam("version"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.service.DeletePromotion(c.Param("id"), version); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func errorResponse(c echo.Context, err error) error {
+	switch err {
+	case business.ErrInvalidSpec:
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	case business.ErrNotFound:
+		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+	case business.ErrHasBeenModified:
+		return c.JSON(http.StatusConflict, common.NewConflictResponse())
+	case business.ErrDuplicateCode:
+		return c.JSON(http.StatusConflict, common.NewDuplicateCodeResponse())
+	}
+
+	return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+}
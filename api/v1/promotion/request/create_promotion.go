@@ -0,0 +1,58 @@
+package request
+
+import (
+	"sample-order/business/promotion/spec"
+	"time"
+)
+
+//CreatePromotionRequest create promotion request payload. Percentage promotion use percent, fixed promotion use amount.
+//Zero usage limit means unlimited and empty item IDs and tags apply the promotion to every item
+type CreatePromotionRequest struct {
+	Code         string        `json:"code"`
+	Description  string        `json:"description"`
+	Type         string        `json:"type"`
+	Percent      int64         `json:"percent"`
+	Amount       *MoneyRequest `json:"amount"`
+	ItemIDs      []string      `json:"itemIds"`
+	Tags         []string      `json:"tags"`
+	ValidFrom    time.Time     `json:"validFrom"`
+	ValidUntil   *time.Time    `json:"validUntil"`
+	UsageLimit   int           `json:"usageLimit"`
+	PerUserLimit int           `json:"perUserLimit"`
+}
+
+//MoneyRequest money payload in minor unit (e.g. cent) of ISO 4217 currency
+type MoneyRequest struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+//ToUpsertPromotionSpec convert into promotion.UpsertPromotionSpec object
+func (req *CreatePromotionRequest) ToUpsertPromotionSpec() *spec.UpsertPromotionSpec {
+	var upsertPromotionSpec spec.UpsertPromotionSpec
+	upsertPromotionSpec.Code = req.Code
+	upsertPromotionSpec.Description = req.Description
+	upsertPromotionSpec.Type = req.Type
+	upsertPromotionSpec.Percent = req.Percent
+	upsertPromotionSpec.Amount = req.Amount.ToMoneySpec()
+	upsertPromotionSpec.ItemIDs = req.ItemIDs
+	upsertPromotionSpec.Tags = req.Tags
+	upsertPromotionSpec.ValidFrom = req.ValidFrom
+	upsertPromotionSpec.ValidUntil = req.ValidUntil
+	upsertPromotionSpec.UsageLimit = req.UsageLimit
+	upsertPromotionSpec.PerUserLimit = req.PerUserLimit
+
+	return &upsertPromotionSpec
+}
+
+//ToMoneySpec convert into spec.MoneySpec object, nil request mean not set
+func (req *MoneyRequest) ToMoneySpec() *spec.MoneySpec {
+	if req == nil {
+		return nil
+	}
+
+	return &spec.MoneySpec{
+		Amount:   req.Amount,
+		Currency: req.Currency,
+	}
+}
@@ -0,0 +1,7 @@
+package request
+
+//UpdatePromotionRequest update promotion request payload, the whole rule is replaced
+type UpdatePromotionRequest struct {
+	CreatePromotionRequest
+	Version int `json:"version" validate:"required"`
+}
@@ -0,0 +1,13 @@
+package response
+
+//CreatePromotionResponse Create promotion response payload
+type CreatePromotionResponse struct {
+	ID string `json:"id"`
+}
+
+//NewCreatePromotionResponse construct CreatePromotionResponse
+func NewCreatePromotionResponse(id string) *CreatePromotionResponse {
+	return &CreatePromotionResponse{
+		id,
+	}
+}
@@ -0,0 +1,66 @@
+package response
+
+import (
+	"sample-order/api/common"
+	"sample-order/business/promotion"
+	"time"
+)
+
+//GetPromotionResponse Get promotion response payload
+type GetPromotionResponse struct {
+	ID           string                `json:"id"`
+	Code         string                `json:"code"`
+	Description  string                `json:"description"`
+	Type         string                `json:"type"`
+	Percent      int64                 `json:"percent"`
+	Amount       *common.MoneyResponse `json:"amount"`
+	ItemIDs      []string              `json:"itemIds"`
+	Tags         []string              `json:"tags"`
+	ValidFrom    time.Time             `json:"validFrom"`
+	ValidUntil   *time.Time            `json:"validUntil"`
+	UsageLimit   int                   `json:"usageLimit"`
+	PerUserLimit int                   `json:"perUserLimit"`
+	UsedCount    int                   `json:"usedCount"`
+	ModifiedAt   time.Time             `json:"modifiedAt"`
+	Version      int                   `json:"version"`
+}
+
+//NewGetPromotionResponse construct GetPromotionResponse
+func NewGetPromotionResponse(promotion promotion.Promotion) *GetPromotionResponse {
+	var promotionResponse GetPromotionResponse
+	promotionResponse.ID = promotion.ID
+	promotionResponse.Code = promotion.Code
+	promotionResponse.Description = promotion.Description
+	promotionResponse.Type = string(promotion.Type)
+	promotionResponse.Percent = promotion.Percent
+	promotionResponse.Amount = common.NewMoneyResponse(promotion.Amount)
+	promotionResponse.ItemIDs = promotion.ItemIDs
+	promotionResponse.Tags = promotion.Tags
+	promotionResponse.ValidFrom = promotion.ValidFrom
+	promotionResponse.ValidUntil = promotion.ValidUntil
+	promotionResponse.UsageLimit = promotion.UsageLimit
+	promotionResponse.PerUserLimit = promotion.PerUserLimit
+	promotionResponse.UsedCount = promotion.UsedCount
+	promotionResponse.ModifiedAt = promotion.ModifiedAt
+	promotionResponse.Version = promotion.Version
+
+	return &promotionResponse
+}
+
+//GetPromotionsResponse Get all promotions response payload
+type GetPromotionsResponse struct {
+	Promotions []*GetPromotionResponse `json:"promotions"`
+}
+
+//NewGetPromotionsResponse construct GetPromotionsResponse
+func NewGetPromotionsResponse(promotions []promotion.Promotion) *GetPromotionsResponse {
+	promotionResponses := make([]*GetPromotionResponse, 0)
+
+	for _, promotion := range promotions {
+		promotionResponses = append(promotionResponses, NewGetPromotionResponse(promotion))
+	}
+
+	return &GetPromotionsResponse{
+		promotionResponses,
+	}
+}
@@ -0,0 +1,243 @@
+package stock
+
+import (
+	"errors"
+	"net/http"
+	"sample-order/api/common"
+	"sample-order/api/v1/stock/request"
+	"sample-order/api/v1/stock/response"
+	"sample-order/business"
+	stockBusiness "sample-order/business/stock"
+
+	v10 "github.com/go-playground/validator/v10"
+	"github.com/labstack/echo"
+)
+
+//Controller Get stock API controller
+type Controller struct {
+	service   stockBusiness.Service
+	validator *v10.Validate
+}
+
+//NewController Construct stock API controller
+func NewController(service stockBusiness.Service) *Controller {
+	return &Controller{
+		service,
+		v10.New(),
+	}
+}
+
+//GetAvailability Get item stock in every warehouse echo handler
+func (controller *Controller) GetAvailability(c echo.Context) error {
+	availability, err := controller.service.GetAvailability(c.Param("id"))
+
+	if err != nil {
+		if err == business.ErrNotFound {
+			return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+		}
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewGetAvailabilityResponse(*availability)
+	return c.JSON(http.StatusOK, response)
+}
+
+//GetMovements Get item stock movement history echo handler
+func (controller *Controller) GetMovements(c echo.Context) error {
+	movements, err := controller.service.GetMovements(c.Param("id"))
+
+	if err != nil {
+		if err == business.ErrNotFound {
+			return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+		}
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewGetMovementsResponse(movements)
+	return c.JSON(http.StatusOK, response)
+}
+
+//RecordMovement Record stock movement echo handler
+func (controller *Controller) RecordMovement(c echo.Context) error {
+	recordMovementRequest := new(request.RecordMovementRequest)
+
+	if err := c.Bind(recordMovementRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.validator.Struct(recordMovementRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	common.SetActor(c, recordMovementRequest.Actor)
+
+	stock, err := controller.service.RecordMovement(
+		c.Param("id"),
+		*recordMovementRequest.ToRecordMovementSpec(),
+		recordMovementRequest.Actor)
+
+	if err != nil {
+		switch err {
+		case business.ErrInvalidSpec:
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		case business.ErrNotFound:
+			return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+		case business.ErrInsufficientStock:
+			return c.JSON(http.StatusConflict, common.NewInsufficientStockResponse())
+		}
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewGetStockResponse(*stock)
+	return c.JSON(http.StatusCreated, response)
+}
+
+//TransferStock Transfer item stock between warehouses echo handler
+func (controller *Controller) TransferStock(c echo.Context) error {
+	transferStockRequest := new(request.TransferStockRequest)
+
+	if err := c.Bind(transferStockRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.validator.Struct(transferStockRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	common.SetActor(c, transferStockRequest.Actor)
+
+	stocks, err := controller.service.TransferStock(
+		c.Param("id"),
+		*transferStockRequest.ToTransferStockSpec(),
+		transferStockRequest.Actor)
+
+	if err != nil {
+		switch err {
+		case business.ErrInvalidSpec:
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		case business.ErrNotFound:
+			return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+		case business.ErrInsufficientStock:
+			return c.JSON(http.StatusConflict, common.NewInsufficientStockResponse())
+		}
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewTransferStockResponse(stocks)
+	return c.JSON(http.StatusCreated, response)
+}
+
+//GetReservationByID Get stock reservation by ID echo handler
+func (controller *Controller) GetReservationByID(c echo.Context) error {
+	reservation, err := controller.service.GetReservationByID(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	} else if reservation == nil {
+		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+	}
+
+	response := response.NewGetReservationResponse(*reservation)
+	return c.JSON(http.StatusOK, response)
+}
+
+//ReserveStock Hold item stock during checkout echo handler
+func (controller *Controller) ReserveStock(c echo.Context) error {
+	reserveStockRequest := new(request.ReserveStockRequest)
+
+	if err := c.Bind(reserveStockRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.validator.Struct(reserveStockRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	common.SetActor(c, reserveStockRequest.Actor)
+
+	reservation, err := controller.service.ReserveStock(
+		c.Param("id"),
+		*reserveStockRequest.ToReserveStockSpec(),
+		reserveStockRequest.Actor)
+
+	if err != nil {
+		switch err {
+		case business.ErrInvalidSpec:
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		case business.ErrNotFound:
+			return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+		case business.ErrInsufficientStock:
+			return c.JSON(http.StatusConflict, common.NewInsufficientStockResponse())
+		}
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewGetReservationResponse(*reservation)
+	return c.JSON(http.StatusCreated, response)
+}
+
+//ReleaseReservation Give reserved stock back echo handler
+func (controller *Controller) ReleaseReservation(c echo.Context) error {
+	finishReservationRequest := new(request.FinishReservationRequest)
+
+	if err := c.Bind(finishReservationRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.validator.Struct(finishReservationRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	common.SetActor(c, finishReservationRequest.Actor)
+
+	if err := controller.service.ReleaseReservation(c.Param("id"), finishReservationRequest.Actor); err != nil {
+		return reservationErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+//ConvertReservation Sell reserved stock echo handler
+func (controller *Controller) ConvertReservation(c echo.Context) error {
+	finishReservationRequest := new(request.FinishReservationRequest)
+
+	if err := c.Bind(finishReservationRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.validator.Struct(finishReservationRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	common.SetActor(c, finishReservationRequest.Actor)
+
+	stock, err := controller.service.ConvertReservation(c.Param("id"), finishReservationRequest.Actor)
+	if err != nil {
+		return reservationErrorResponse(c, err)
+	}
+
+	response := response.NewGetStockResponse(*stock)
+	return c.JSON(http.StatusOK, response)
+}
+
+func reservationErrorResponse(c echo.Context, err error) error {
+	var transitionErr *business.TransitionError
+	if errors.As(err, &transitionErr) {
+		return c.JSON(http.StatusConflict, common.NewTransitionErrorResponse(transitionErr.Error()))
+	}
+
+	switch err {
+	case business.ErrInvalidSpec:
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	case business.ErrNotFound:
+		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+	case business.ErrHasBeenModified:
+		return c.JSON(http.StatusConflict, common.NewConflictResponse())
+	case business.ErrReservationExpired:
+		return c.JSON(http.StatusGone, common.NewReservationExpiredResponse())
+	case business.ErrInsufficientStock:
+		return c.JSON(http.StatusConflict, common.NewInsufficientStockResponse())
+	}
+
+	return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+}
@@ -0,0 +1,6 @@
+package request
+
+//FinishReservationRequest release or convert reservation request payload
+type FinishReservationRequest struct {
+	Actor string `json:"actor" validate:"required"`
+}
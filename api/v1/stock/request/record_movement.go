@@ -0,0 +1,23 @@
+package request
+
+import "sample-order/business/stock/spec"
+
+//RecordMovementRequest record stock movement request payload
+type RecordMovementRequest struct {
+	WarehouseID string `json:"warehouseId"`
+	Type        string `json:"type"`
+	Quantity    int    `json:"quantity"`
+	Reason      string `json:"reason"`
+	Actor       string `json:"actor" validate:"required"`
+}
+
+//ToRecordMovementSpec convert into stock.RecordMovementSpec object
+func (req *RecordMovementRequest) ToRecordMovementSpec() *spec.RecordMovementSpec {
+	var recordMovementSpec spec.RecordMovementSpec
+	recordMovementSpec.WarehouseID = req.WarehouseID
+	recordMovementSpec.Type = req.Type
+	recordMovementSpec.Quantity = req.Quantity
+	recordMovementSpec.Reason = req.Reason
+
+	return &recordMovementSpec
+}
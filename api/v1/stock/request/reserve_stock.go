@@ -0,0 +1,21 @@
+package request
+
+import "sample-order/business/stock/spec"
+
+//ReserveStockRequest hold stock request payload, warehouse is optional
+type ReserveStockRequest struct {
+	WarehouseID string `json:"warehouseId"`
+	Quantity    int    `json:"quantity"`
+	Reference   string `json:"reference"`
+	Actor       string `json:"actor" validate:"required"`
+}
+
+//ToReserveStockSpec convert into stock.ReserveStockSpec object
+func (req *ReserveStockRequest) ToReserveStockSpec() *spec.ReserveStockSpec {
+	var reserveStockSpec spec.ReserveStockSpec
+	reserveStockSpec.WarehouseID = req.WarehouseID
+	reserveStockSpec.Quantity = req.Quantity
+	reserveStockSpec.Reference = req.Reference
+
+	return &reserveStockSpec
+}
@@ -0,0 +1,23 @@
+package request
+
+import "sample-order/business/stock/spec"
+
+//TransferStockRequest transfer stock between warehouses request payload
+type TransferStockRequest struct {
+	FromWarehouseID string `json:"fromWarehouseId"`
+	ToWarehouseID   string `json:"toWarehouseId"`
+	Quantity        int    `json:"quantity"`
+	Reason          string `json:"reason"`
+	Actor           string `json:"actor" validate:"required"`
+}
+
+//ToTransferStockSpec convert into stock.TransferStockSpec object
+func (req *TransferStockRequest) ToTransferStockSpec() *spec.TransferStockSpec {
+	var transferStockSpec spec.TransferStockSpec
+	transferStockSpec.FromWarehouseID = req.FromWarehouseID
+	transferStockSpec.ToWarehouseID = req.ToWarehouseID
+	transferStockSpec.Quantity = req.Quantity
+	transferStockSpec.Reason = req.Reason
+
+	return &transferStockSpec
+}
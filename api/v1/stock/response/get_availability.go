@@ -0,0 +1,40 @@
+package response
+
+import "sample-order/business/stock"
+
+//WarehouseQuantityResponse Quantity of item in single warehouse
+type WarehouseQuantityResponse struct {
+	WarehouseID string `json:"warehouseId"`
+	Quantity    int    `json:"quantity"`
+	Reserved    int    `json:"reserved"`
+	Available   int    `json:"available"`
+}
+
+//GetAvailabilityResponse Aggregated stock of item across warehouses response payload
+type GetAvailabilityResponse struct {
+	Total      int                          `json:"total"`
+	Reserved   int                          `json:"reserved"`
+	Available  int                          `json:"available"`
+	Warehouses []*WarehouseQuantityResponse `json:"warehouses"`
+}
+
+//NewGetAvailabilityResponse construct GetAvailabilityResponse
+func NewGetAvailabilityResponse(availability stock.Availability) *GetAvailabilityResponse {
+	warehouseResponses := make([]*WarehouseQuantityResponse, 0)
+
+	for _, stock := range availability.Warehouses {
+		warehouseResponses = append(warehouseResponses, &WarehouseQuantityResponse{
+			stock.WarehouseID,
+			stock.Quantity,
+			stock.Reserved,
+			stock.Available(),
+		})
+	}
+
+	return &GetAvailabilityResponse{
+		availability.Total,
+		availability.Reserved,
+		availability.Available,
+		warehouseResponses,
+	}
+}
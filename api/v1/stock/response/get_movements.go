@@ -0,0 +1,43 @@
+package response
+
+import (
+	"sample-order/business/stock"
+	"time"
+)
+
+//MovementResponse Stock movement response payload
+type MovementResponse struct {
+	ID          string    `json:"id"`
+	WarehouseID string    `json:"warehouseId"`
+	Type        string    `json:"type"`
+	Quantity    int       `json:"quantity"`
+	Reason      string    `json:"reason"`
+	Actor       string    `json:"actor"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+//GetMovementsResponse Get stock movement history response payload
+type GetMovementsResponse struct {
+	Movements []*MovementResponse `json:"movements"`
+}
+
+//NewGetMovementsResponse construct GetMovementsResponse
+func NewGetMovementsResponse(movements []stock.Movement) *GetMovementsResponse {
+	movementResponses := make([]*MovementResponse, 0)
+
+	for _, movement := range movements {
+		movementResponses = append(movementResponses, &MovementResponse{
+			movement.ID,
+			movement.WarehouseID,
+			string(movement.Type),
+			movement.Quantity,
+			movement.Reason,
+			movement.Actor,
+			movement.CreatedAt,
+		})
+	}
+
+	return &GetMovementsResponse{
+		movementResponses,
+	}
+}
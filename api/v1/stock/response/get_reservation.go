@@ -0,0 +1,39 @@
+package response
+
+import (
+	"sample-order/business/stock"
+	"time"
+)
+
+//GetReservationResponse Get stock reservation response payload
+type GetReservationResponse struct {
+	ID          string    `json:"id"`
+	ItemID      string    `json:"itemId"`
+	WarehouseID string    `json:"warehouseId"`
+	Quantity    int       `json:"quantity"`
+	Reference   string    `json:"reference"`
+	Status      string    `json:"status"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CreatedBy   string    `json:"createdBy"`
+	ModifiedAt  time.Time `json:"modifiedAt"`
+	ModifiedBy  string    `json:"modifiedBy"`
+}
+
+//NewGetReservationResponse construct GetReservationResponse
+func NewGetReservationResponse(reservation stock.Reservation) *GetReservationResponse {
+	var reservationResponse GetReservationResponse
+	reservationResponse.ID = reservation.ID
+	reservationResponse.ItemID = reservation.ItemID
+	reservationResponse.WarehouseID = reservation.WarehouseID
+	reservationResponse.Quantity = reservation.Quantity
+	reservationResponse.Reference = reservation.Reference
+	reservationResponse.Status = string(reservation.Status)
+	reservationResponse.ExpiresAt = reservation.ExpiresAt
+	reservationResponse.CreatedAt = reservation.CreatedAt
+	reservationResponse.CreatedBy = reservation.CreatedBy
+	reservationResponse.ModifiedAt = reservation.ModifiedAt
+	reservationResponse.ModifiedBy = reservation.ModifiedBy
+
+	return &reservationResponse
+}
@@ -0,0 +1,45 @@
+package response
+
+import (
+	"sample-order/business/stock"
+	"time"
+)
+
+//GetStockResponse Stock of item in single warehouse response payload
+type GetStockResponse struct {
+	ItemID      string    `json:"itemId"`
+	WarehouseID string    `json:"warehouseId"`
+	Quantity    int       `json:"quantity"`
+	Reserved    int       `json:"reserved"`
+	ModifiedAt  time.Time `json:"modifiedAt"`
+}
+
+//NewGetStockResponse construct GetStockResponse
+func NewGetStockResponse(stock stock.Stock) *GetStockResponse {
+	var stockResponse GetStockResponse
+	stockResponse.ItemID = stock.ItemID
+	stockResponse.WarehouseID = stock.WarehouseID
+	stockResponse.Quantity = stock.Quantity
+	stockResponse.Reserved = stock.Reserved
+	stockResponse.ModifiedAt = stock.ModifiedAt
+
+	return &stockResponse
+}
+
+//TransferStockResponse Transfer stock response payload, stock of source and destination warehouse
+type TransferStockResponse struct {
+	Stocks []*GetStockResponse `json:"stocks"`
+}
+
+//NewTransferStockResponse construct TransferStockResponse
+func NewTransferStockResponse(stocks []stock.Stock) *TransferStockResponse {
+	stockResponses := make([]*GetStockResponse, 0)
+
+	for _, stock := range stocks {
+		stockResponses = append(stockResponses, NewGetStockResponse(stock))
+	}
+
+	return &TransferStockResponse{
+		stockResponses,
+	}
+}
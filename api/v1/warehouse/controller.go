@@ -0,0 +1,71 @@
+package warehouse
+
+import (
+	"net/http"
+	"sample-order/api/common"
+	"sample-order/api/v1/warehouse/request"
+	"sample-order/api/v1/warehouse/response"
+	"sample-order/business"
+	warehouseBusiness "sample-order/business/warehouse"
+
+	"github.com/labstack/echo"
+)
+
+//Controller Get warehouse API controller
+type Controller struct {
+	service warehouseBusiness.Service
+}
+
+//NewController Construct warehouse API controller
+func NewController(service warehouseBusiness.Service) *Controller {
+	return &Controller{
+		service,
+	}
+}
+
+//GetWarehouseByID Get warehouse by ID echo handler
+func (controller *Controller) GetWarehouseByID(c echo.Context) error {
+	warehouse, err := controller.service.GetWarehouseByID(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	} else if warehouse == nil {
+		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+	}
+
+	response := response.NewGetWarehouseResponse(*warehouse)
+	return c.JSON(http.StatusOK, response)
+}
+
+//GetWarehouses Get all warehouses echo handler
+func (controller *Controller) GetWarehouses(c echo.Context) error {
+	warehouses, err := controller.service.GetWarehouses()
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewGetWarehousesResponse(warehouses)
+	return c.JSON(http.StatusOK, response)
+}
+
+//CreateWarehouse Create new warehouse echo handler
+func (controller *Controller) CreateWarehouse(c echo.Context) error {
+	createWarehouseRequest := new(request.CreateWarehouseRequest)
+
+	if err := c.Bind(createWarehouseRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	ID, err := controller.service.CreateWarehouse(*createWarehouseRequest.ToCreateWarehouseSpec(), "creator")
+
+	if err != nil {
+		if err == business.ErrInvalidSpec {
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		}
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewCreateWarehouseResponse(ID)
+	return c.JSON(http.StatusCreated, response)
+}
@@ -0,0 +1,18 @@
+package request
+
+import "sample-order/business/warehouse/spec"
+
+//CreateWarehouseRequest create warehouse request payload
+type CreateWarehouseRequest struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+//ToCreateWarehouseSpec convert into warehouse.CreateWarehouseSpec object
+func (req *CreateWarehouseRequest) ToCreateWarehouseSpec() *spec.CreateWarehouseSpec {
+	var createWarehouseSpec spec.CreateWarehouseSpec
+	createWarehouseSpec.Name = req.Name
+	createWarehouseSpec.Address = req.Address
+
+	return &createWarehouseSpec
+}
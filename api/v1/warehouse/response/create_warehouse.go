@@ -0,0 +1,13 @@
+package response
+
+//CreateWarehouseResponse Create warehouse response payload
+type CreateWarehouseResponse struct {
+	ID string `json:"id"`
+}
+
+//NewCreateWarehouseResponse construct CreateWarehouseResponse
+func NewCreateWarehouseResponse(id string) *CreateWarehouseResponse {
+	return &CreateWarehouseResponse{
+		id,
+	}
+}
@@ -0,0 +1,43 @@
+package response
+
+import (
+	"sample-order/business/warehouse"
+	"time"
+)
+
+//GetWarehouseResponse Get warehouse response payload
+type GetWarehouseResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+//NewGetWarehouseResponse construct GetWarehouseResponse
+func NewGetWarehouseResponse(warehouse warehouse.Warehouse) *GetWarehouseResponse {
+	var warehouseResponse GetWarehouseResponse
+	warehouseResponse.ID = warehouse.ID
+	warehouseResponse.Name = warehouse.Name
+	warehouseResponse.Address = warehouse.Address
+	warehouseResponse.CreatedAt = warehouse.CreatedAt
+
+	return &warehouseResponse
+}
+
+//GetWarehousesResponse Get all warehouses response payload
+type GetWarehousesResponse struct {
+	Warehouses []*GetWarehouseResponse `json:"warehouses"`
+}
+
+//NewGetWarehousesResponse construct GetWarehousesResponse
+func NewGetWarehousesResponse(warehouses []warehouse.Warehouse) *GetWarehousesResponse {
+	warehouseResponses := make([]*GetWarehouseResponse, 0)
+
+	for _, warehouse := range warehouses {
+		warehouseResponses = append(warehouseResponses, NewGetWarehouseResponse(warehouse))
+	}
+
+	return &GetWarehousesResponse{
+		warehouseResponses,
+	}
+}
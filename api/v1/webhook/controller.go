@@ -0,0 +1,167 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"sample-order/api/common"
+	"sample-order/api/v1/webhook/request"
+	"sample-order/api/v1/webhook/response"
+	"sample-order/business"
+	webhookBusiness "sample-order/business/webhook"
+	"strconv"
+
+	v10 "github.com/go-playground/validator/v10"
+	"github.com/labstack/echo"
+)
+
+const (
+	//defaultDeadLetterLimit number of dead letters returned when the limit query param is not given
+	defaultDeadLetterLimit = 100
+	//maxDeadLetterLimit maximum number of dead letters returned at once
+	maxDeadLetterLimit = 500
+)
+
+//Controller Get webhook API controller
+type Controller struct {
+	service   webhookBusiness.Service
+	validator *v10.Validate
+}
+
+//NewController Construct webhook API controller
+func NewController(service webhookBusiness.Service) *Controller {
+	return &Controller{
+		service,
+		v10.New(),
+	}
+}
+
+//GetSubscriptionByID Get webhook subscription by ID echo handler
+func (controller *Controller) GetSubscriptionByID(c echo.Context) error {
+	subscription, err := controller.service.GetSubscriptionByID(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	} else if subscription == nil {
+		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+	}
+
+	response := response.NewGetSubscriptionResponse(*subscription)
+	return c.JSON(http.StatusOK, response)
+}
+
+//GetSubscriptions Get all webhook subscriptions echo handler
+func (controller *Controller) GetSubscriptions(c echo.Context) error {
+	subscriptions, err := controller.service.GetSubscriptions()
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewGetSubscriptionsResponse(subscriptions)
+	return c.JSON(http.StatusOK, response)
+}
+
+//CreateSubscription Create new webhook subscription echo handler
+func (controller *Controller) CreateSubscription(c echo.Context) error {
+	createSubscriptionRequest := new(request.CreateSubscriptionRequest)
+
+	if err := c.Bind(createSubscriptionRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	ID, err := controller.service.CreateSubscription(*createSubscriptionRequest.ToUpsertSubscriptionSpec(), "creator")
+
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	response := response.NewCreateSubscriptionResponse(ID)
+	return c.JSON(http.StatusCreated, response)
+}
+
+//UpdateSubscription Update webhook subscription echo handler
+func (controller *Controller) UpdateSubscription(c echo.Context) error {
+	updateSubscriptionRequest := new(request.UpdateSubscriptionRequest)
+
+	if err := c.Bind(updateSubscriptionRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.validator.Struct(updateSubscriptionRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	err := controller.service.UpdateSubscription(
+		c.Param("id"),
+		*updateSubscriptionRequest.ToUpsertSubscriptionSpec(),
+		updateSubscriptionRequest.Version,
+		"updater")
+
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+//DeleteSubscription Delete webhook subscription echo handler, the version query param is required
+func (controller *Controller) DeleteSubscription(c echo.Context) error {
+	version, err := strconv.Atoi(c.QueryParam("version"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	}
+
+	if err := controller.service.DeleteSubscription(c.Param("id"), version); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+//GetDeadLetters Get deliveries which run out of attempts echo handler, with optional limit query param
+func (controller *Controller) GetDeadLetters(c echo.Context) error {
+	limit := defaultDeadLetterLimit
+
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitParam); err != nil || limit <= 0 || limit > maxDeadLetterLimit {
+			return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+		}
+	}
+
+	deliveries, err := controller.service.GetDeadLetters(limit)
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+	}
+
+	response := response.NewGetDeliveriesResponse(deliveries)
+	return c.JSON(http.StatusOK, response)
+}
+
+//Redeliver Send the delivery again echo handler
+func (controller *Controller) Redeliver(c echo.Context) error {
+	if err := controller.service.Redeliver(c.Param("id")); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+func errorResponse(c echo.Context, err error) error {
+	var transitionErr *business.TransitionError
+	if errors.As(err, &transitionErr) {
+		return c.JSON(http.StatusConflict, common.NewTransitionErrorResponse(transitionErr.Error()))
+	}
+
+	switch err {
+	case business.ErrInvalidSpec:
+		return c.JSON(http.StatusBadRequest, common.NewBadRequestResponse())
+	case business.ErrNotFound:
+		return c.JSON(http.StatusNotFound, common.NewNotFoundResponse())
+	case business.ErrHasBeenModified:
+		return c.JSON(http.StatusConflict, common.NewConflictResponse())
+	}
+
+	return c.JSON(http.StatusInternalServerError, common.NewInternalServerErrorResponse())
+}
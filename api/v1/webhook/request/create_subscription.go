@@ -0,0 +1,22 @@
+package request
+
+import "sample-order/business/webhook/spec"
+
+//CreateSubscriptionRequest create webhook subscription request payload. Empty tag subscribe events of every item
+type CreateSubscriptionRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	Tag        string   `json:"tag"`
+	Secret     string   `json:"secret"`
+}
+
+//ToUpsertSubscriptionSpec convert into webhook.UpsertSubscriptionSpec object
+func (req *CreateSubscriptionRequest) ToUpsertSubscriptionSpec() *spec.UpsertSubscriptionSpec {
+	var upsertSubscriptionSpec spec.UpsertSubscriptionSpec
+	upsertSubscriptionSpec.URL = req.URL
+	upsertSubscriptionSpec.EventTypes = req.EventTypes
+	upsertSubscriptionSpec.Tag = req.Tag
+	upsertSubscriptionSpec.Secret = req.Secret
+
+	return &upsertSubscriptionSpec
+}
@@ -0,0 +1,7 @@
+package request
+
+//UpdateSubscriptionRequest update webhook subscription request payload, empty secret keep the old one
+type UpdateSubscriptionRequest struct {
+	CreateSubscriptionRequest
+	Version int `json:"version" validate:"required"`
+}
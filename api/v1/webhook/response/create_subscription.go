@@ -0,0 +1,13 @@
+package response
+
+//CreateSubscriptionResponse Create webhook subscription response payload
+type CreateSubscriptionResponse struct {
+	ID string `json:"id"`
+}
+
+//NewCreateSubscriptionResponse construct CreateSubscriptionResponse
+func NewCreateSubscriptionResponse(id string) *CreateSubscriptionResponse {
+	return &CreateSubscriptionResponse{
+		id,
+	}
+}
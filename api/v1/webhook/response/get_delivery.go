@@ -0,0 +1,60 @@
+package response
+
+import (
+	"encoding/json"
+	"sample-order/business/webhook"
+	"time"
+)
+
+//GetDeliveryResponse Get webhook delivery response payload, the payload is the event sent to the partner
+type GetDeliveryResponse struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscriptionId"`
+	MessageID      string          `json:"messageId"`
+	EventType      string          `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	LastStatusCode int             `json:"lastStatusCode"`
+	LastError      string          `json:"lastError"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt"`
+}
+
+//NewGetDeliveryResponse construct GetDeliveryResponse
+func NewGetDeliveryResponse(delivery webhook.Delivery) *GetDeliveryResponse {
+	var deliveryResponse GetDeliveryResponse
+	deliveryResponse.ID = delivery.ID
+	deliveryResponse.SubscriptionID = delivery.SubscriptionID
+	deliveryResponse.MessageID = delivery.MessageID
+	deliveryResponse.EventType = delivery.EventType
+	deliveryResponse.Payload = json.RawMessage(delivery.Payload)
+	deliveryResponse.Status = string(delivery.Status)
+	deliveryResponse.Attempts = delivery.Attempts
+	deliveryResponse.NextAttemptAt = delivery.NextAttemptAt
+	deliveryResponse.LastStatusCode = delivery.LastStatusCode
+	deliveryResponse.LastError = delivery.LastError
+	deliveryResponse.CreatedAt = delivery.CreatedAt
+	deliveryResponse.DeliveredAt = delivery.DeliveredAt
+
+	return &deliveryResponse
+}
+
+//GetDeliveriesResponse Get webhook deliveries response payload
+type GetDeliveriesResponse struct {
+	Deliveries []*GetDeliveryResponse `json:"deliveries"`
+}
+
+//NewGetDeliveriesResponse construct GetDeliveriesResponse
+func NewGetDeliveriesResponse(deliveries []webhook.Delivery) *GetDeliveriesResponse {
+	deliveryResponses := make([]*GetDeliveryResponse, 0)
+
+	for _, delivery := range deliveries {
+		deliveryResponses = append(deliveryResponses, NewGetDeliveryResponse(delivery))
+	}
+
+	return &GetDeliveriesResponse{
+		deliveryResponses,
+	}
+}
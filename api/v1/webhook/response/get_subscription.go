@@ -0,0 +1,47 @@
+package response
+
+import (
+	"sample-order/business/webhook"
+	"time"
+)
+
+//GetSubscriptionResponse Get webhook subscription response payload, the secret is never returned
+type GetSubscriptionResponse struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	Tag        string    `json:"tag"`
+	ModifiedAt time.Time `json:"modifiedAt"`
+	Version    int       `json:"version"`
+}
+
+//NewGetSubscriptionResponse construct GetSubscriptionResponse
+func NewGetSubscriptionResponse(subscription webhook.Subscription) *GetSubscriptionResponse {
+	var subscriptionResponse GetSubscriptionResponse
+	subscriptionResponse.ID = subscription.ID
+	subscriptionResponse.URL = subscription.URL
+	subscriptionResponse.EventTypes = subscription.EventTypes
+	subscriptionResponse.Tag = subscription.Tag
+	subscriptionResponse.ModifiedAt = subscription.ModifiedAt
+	subscriptionResponse.Version = subscription.Version
+
+	return &subscriptionResponse
+}
+
+//GetSubscriptionsResponse Get all webhook subscriptions response payload
+type GetSubscriptionsResponse struct {
+	Subscriptions []*GetSubscriptionResponse `json:"subscriptions"`
+}
+
+//NewGetSubscriptionsResponse construct GetSubscriptionsResponse
+func NewGetSubscriptionsResponse(subscriptions []webhook.Subscription) *GetSubscriptionsResponse {
+	subscriptionResponses := make([]*GetSubscriptionResponse, 0)
+
+	for _, subscription := range subscriptions {
+		subscriptionResponses = append(subscriptionResponses, NewGetSubscriptionResponse(subscription))
+	}
+
+	return &GetSubscriptionsResponse{
+		subscriptionResponses,
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sample-order/config"
+	"sample-order/util"
+)
+
+//runCheckConfig print the effective config and optionally try to connect into database
+func runCheckConfig(args []string) (code int) {
+	flags := flag.NewFlagSet("check-config", flag.ExitOnError)
+	connect := flags.Bool("connect", false, "try to connect into configured database")
+	flags.Parse(args)
+
+	config := config.GetConfig()
+
+	password := ""
+	if config.Database.Password != "" {
+		password = "******"
+	}
+
+	fmt.Println("port:", config.Port)
+	fmt.Println("database.driver:", config.Database.Driver)
+	fmt.Println("database.name:", config.Database.Name)
+	fmt.Println("database.address:", config.Database.Address)
+	fmt.Println("database.port:", config.Database.Port)
+	fmt.Println("database.username:", config.Database.Username)
+	fmt.Println("database.password:", password)
+	fmt.Println("database.automigrate:", config.Database.AutoMigrate)
+	fmt.Println("reservation.ttl:", config.Reservation.TTL)
+	fmt.Println("reservation.sweepinterval:", config.Reservation.SweepInterval)
+	fmt.Println("outbox.relayinterval:", config.Outbox.RelayInterval)
+	fmt.Println("outbox.batchsize:", config.Outbox.BatchSize)
+	fmt.Println("stream.replaysize:", config.Stream.ReplaySize)
+	fmt.Println("webhook.deliveryinterval:", config.Webhook.DeliveryInterval)
+	fmt.Println("webhook.batchsize:", config.Webhook.BatchSize)
+	fmt.Println("webhook.timeout:", config.Webhook.Timeout)
+	fmt.Println("webhook.maxattempts:", config.Webhook.MaxAttempts)
+	fmt.Println("webhook.backoffbase:", config.Webhook.BackoffBase)
+	fmt.Println("webhook.backoffmax:", config.Webhook.BackoffMax)
+	fmt.Println("tracing.exporter:", config.Tracing.Exporter)
+	fmt.Println("tracing.endpoint:", config.Tracing.Endpoint)
+	fmt.Println("tracing.insecure:", config.Tracing.Insecure)
+	fmt.Println("tracing.servicename:", config.Tracing.ServiceName)
+	fmt.Println("tracing.sampleratio:", config.Tracing.SampleRatio)
+	fmt.Println("log.level:", config.Log.Level)
+	fmt.Println("log.format:", config.Log.Format)
+	fmt.Println("health.timeout:", config.Health.Timeout)
+	fmt.Println("shutdown.drainperiod:", config.Shutdown.DrainPeriod)
+	fmt.Println("shutdown.timeout:", config.Shutdown.Timeout)
+	fmt.Println("shutdown.totaltimeout:", config.Shutdown.TotalTimeout)
+
+	if config.Database.Driver != string(util.MongoDB) && config.Database.Driver != string(util.MySQL) {
+		fmt.Fprintln(os.Stderr, "invalid config: unsupported database driver", config.Database.Driver)
+		return exitFailure
+	}
+
+	if *connect {
+		//database connection will panic when failed, convert it into readable message
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Fprintln(os.Stderr, "failed to connect database:", r)
+				code = exitFailure
+			}
+		}()
+
+		dbCon := util.NewDatabaseConnection(config)
+		defer dbCon.CloseConnection()
+		fmt.Println("database connection: ok")
+
+		if err := dbCon.CheckTransaction(context.Background()); err != nil {
+			fmt.Fprintln(os.Stderr, "invalid database deployment:", err)
+			return exitFailure
+		}
+		fmt.Println("database transaction: ok")
+	}
+
+	return exitOK
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sample-order/business"
+	businessItem "sample-order/business/item"
+	"sample-order/config"
+	"sample-order/modules/checkpoint"
+	itemRepo "sample-order/modules/repository/item"
+	"sample-order/util"
+)
+
+//runCopy copy all items from configured database into another database
+func runCopy(args []string) int {
+	sourceConfig := config.GetConfig()
+
+	//target config start from the source config so only the difference need to be given
+	targetConfig := *sourceConfig
+
+	flags := flag.NewFlagSet("copy", flag.ExitOnError)
+	flags.StringVar(&targetConfig.Database.Driver, "to-driver", targetConfig.Database.Driver, "target database driver (mongodb or mysql)")
+	flags.StringVar(&targetConfig.Database.Name, "to-name", targetConfig.Database.Name, "target database name")
+	flags.StringVar(&targetConfig.Database.Address, "to-address", targetConfig.Database.Address, "target database address")
+	flags.IntVar(&targetConfig.Database.Port, "to-port", targetConfig.Database.Port, "target database port")
+	flags.StringVar(&targetConfig.Database.Username, "to-username", targetConfig.Database.Username, "target database username")
+	flags.StringVar(&targetConfig.Database.Password, "to-password", targetConfig.Database.Password, "target database password")
+	checkpointPath := flags.String("checkpoint", "copy.checkpoint.json", "checkpoint file used to resume interrupted copy")
+	batchSize := flags.Int("batch", 500, "number of items copied per batch")
+	verifyOnly := flags.Bool("verify-only", false, "skip copy and only compare source and target")
+	flags.Parse(args)
+
+	if targetConfig.Database == sourceConfig.Database {
+		fmt.Fprintln(os.Stderr, "target database must be different from the source database")
+		return exitUsage
+	}
+
+	if *batchSize < 1 {
+		fmt.Fprintln(os.Stderr, "batch must be a positive number")
+		return exitUsage
+	}
+
+	sourceCon := util.NewDatabaseConnection(sourceConfig)
+	defer sourceCon.CloseConnection()
+
+	targetCon := util.NewDatabaseConnection(&targetConfig)
+	defer targetCon.CloseConnection()
+
+	//only the target is written, the source can be standalone MongoDB
+	if !*verifyOnly {
+		if err := targetCon.CheckTransaction(context.Background()); err != nil {
+			fmt.Fprintln(os.Stderr, "target database:", err)
+			return exitFailure
+		}
+	}
+
+	copier := businessItem.NewCopier(
+		itemRepo.RepositoryFactory(sourceCon),
+		databaseName(sourceConfig),
+		itemRepo.RepositoryFactory(targetCon),
+		databaseName(&targetConfig),
+		checkpoint.NewFileStore(*checkpointPath),
+		*batchSize)
+
+	if !*verifyOnly {
+		result, err := copier.Copy(context.Background(), func(checkpoint businessItem.Checkpoint) {
+			fmt.Printf("copied %d item(s), last id %s\n", checkpoint.Copied, checkpoint.LastID)
+		})
+
+		if err == business.ErrCheckpointMismatch {
+			fmt.Fprintln(os.Stderr, *checkpointPath, "was saved while copying between other databases, remove it or give other -checkpoint file")
+			return exitFailure
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, "copy interrupted, run the same command again to resume:", err)
+			return exitFailure
+		}
+
+		fmt.Printf("copy finished, %d item(s) copied\n", result.Copied)
+	}
+
+	report, err := copier.Verify(context.Background())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to verify:", err)
+		return exitFailure
+	}
+
+	fmt.Printf("source: %d item(s), checksum %s\n", report.SourceCount, report.SourceChecksum)
+	fmt.Printf("target: %d item(s), checksum %s\n", report.TargetCount, report.TargetChecksum)
+
+	if !report.Match() {
+		fmt.Fprintln(os.Stderr, "verification failed, source and target are different")
+		return exitFailure
+	}
+
+	fmt.Println("verification passed")
+	return exitOK
+}
+
+//databaseName identify the database in the checkpoint, without the credentials
+func databaseName(appConfig *config.AppConfig) string {
+	return fmt.Sprintf("%s://%s:%d/%s",
+		appConfig.Database.Driver,
+		appConfig.Database.Address,
+		appConfig.Database.Port,
+		appConfig.Database.Name)
+}
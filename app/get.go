@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+//runGet print single item by given ID
+func runGet(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: get <id>")
+		return exitUsage
+	}
+
+	itemService, dbCon := newItemService()
+	defer dbCon.CloseConnection()
+
+	item, err := itemService.GetItemByID(context.Background(), args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to get item:", err)
+		return exitFailure
+	} else if item == nil {
+		fmt.Fprintln(os.Stderr, "item not found")
+		return exitFailure
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(item)
+	return exitOK
+}
@@ -1,66 +1,78 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"os"
-	"os/signal"
-	api "sample-order/api"
-	itemControllerV1 "sample-order/api/v1/item"
 	businessItem "sample-order/business/item"
 	"sample-order/config"
 	itemRepo "sample-order/modules/repository/item"
 	"sample-order/util"
-	"time"
-
-	"github.com/labstack/echo"
-	"github.com/labstack/gommon/log"
 )
 
-func main() {
-	//load config if available or set to default
-	config := config.GetConfig()
+//exit code of the commands, usage error is the same code flag package use
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
 
-	//initialize database connection based on given config
-	dbCon := util.NewDatabaseConnection(config)
+//command CLI sub command, it return the exit code so the deferred cleanup run before the process exit
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) int
+}
 
-	//initiate item repository
-	itemRepo := itemRepo.RepositoryFactory(dbCon)
+var commands = []command{
+	{"serve", "serve [-auto-migrate]                 start the API server (default command)", runServe},
+	{"migrate", "migrate up|down [steps]|status        manage database schema migration", runMigrate},
+	{"seed", "seed [-count n]                       insert fake items for testing", runSeed},
+	{"import", "import -file path [-dry-run]          create items from CSV or NDJSON file", runImport},
+	{"export", "export [-tag tag] [-format f] [-out path] stream items as NDJSON, CSV or JSON", runExport},
+	{"copy", "copy -to-driver d [-to-...] [-verify-only] copy all items into another database", runCopy},
+	{"get", "get <id>                              print single item as JSON", runGet},
+	{"check-config", "check-config [-connect]               print loaded config and verify it", runCheckConfig},
+}
 
-	//initiate item service
-	itemService := businessItem.NewService(itemRepo)
+func main() {
+	args := os.Args[1:]
 
-	//initiate item controller
-	itemControllerV1 := itemControllerV1.NewController(itemService)
+	//serve is the default command to keep `go run ./app` starting the server
+	if len(args) == 0 || (len(args[0]) > 0 && args[0][0] == '-') {
+		os.Exit(runServe(args))
+	}
 
-	//create echo http
-	e := echo.New()
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			os.Exit(cmd.run(args[1:]))
+		}
+	}
 
-	//register API path and handler
-	api.RegisterPath(e, itemControllerV1)
+	usage()
+	os.Exit(exitUsage)
+}
 
-	// run server
-	go func() {
-		address := fmt.Sprintf("localhost:%d", config.Port)
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: app <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "commands:")
 
-		if err := e.Start(address); err != nil {
-			log.Info("shutting down the server")
-		}
-	}()
+	for _, cmd := range commands {
+		fmt.Fprintln(os.Stderr, "  "+cmd.usage)
+	}
+}
 
-	// Wait for interrupt signal to gracefully shutdown the server with
-	quit := make(chan os.Signal)
-	signal.Notify(quit, os.Interrupt)
-	<-quit
+//newItemService initialize database connection and item service from loaded config
+func newItemService() (businessItem.Service, *util.DatabaseConnection) {
+	//load config if available or set to default
+	config := config.GetConfig()
 
-	//close db
-	defer dbCon.CloseConnection()
+	//initialize database connection based on given config
+	dbCon := util.NewDatabaseConnection(config)
 
-	// a timeout of 10 seconds to shutdown the server
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	//initiate item repository
+	itemRepo := itemRepo.RepositoryFactory(dbCon)
 
-	if err := e.Shutdown(ctx); err != nil {
-		log.Fatal(err)
-	}
+	//initiate item service
+	return businessItem.NewService(itemRepo), dbCon
 }
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sample-order/config"
+	"sample-order/modules/migration"
+	"sample-order/util"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+//runMigrate handle `migrate up|down [steps]|status` command
+func runMigrate(args []string) int {
+	dbCon := util.NewDatabaseConnection(config.GetConfig())
+	defer dbCon.CloseConnection()
+
+	migrator := migration.MigratorFactory(dbCon)
+
+	action := "status"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up()
+		printMigrations("applied", applied)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "failed to apply migration:", err)
+			return exitFailure
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				fmt.Fprintln(os.Stderr, "steps must be a positive number")
+				return exitUsage
+			}
+			steps = n
+		}
+
+		reverted, err := migrator.Down(steps)
+		printMigrations("reverted", reverted)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "failed to revert migration:", err)
+			return exitFailure
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied at " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%4d %-40s %s\n", status.Version, status.Name, state)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "failed to read migration status:", err)
+			return exitFailure
+		}
+	default:
+		fmt.Fprintln(os.Stderr, "usage: migrate up|down [steps]|status")
+		return exitUsage
+	}
+
+	return exitOK
+}
+
+//autoMigrate apply pending migration before server start
+func autoMigrate(dbCon *util.DatabaseConnection) {
+	applied, err := migration.MigratorFactory(dbCon).Up()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, status := range applied {
+		log.Info().Int("version", status.Version).Str("name", status.Name).Msg("migration applied")
+	}
+}
+
+func printMigrations(action string, statuses []migration.Status) {
+	if len(statuses) == 0 {
+		fmt.Println("nothing", action)
+		return
+	}
+
+	for _, status := range statuses {
+		fmt.Printf("%s %d %s\n", action, status.Version, status.Name)
+	}
+}
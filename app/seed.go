@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"sample-order/business/item/spec"
+	"time"
+)
+
+var seedAdjectives = []string{"Compact", "Vintage", "Portable", "Deluxe", "Rugged", "Classic", "Smart", "Wireless"}
+var seedNouns = []string{"Camera", "Tent", "Drill", "Projector", "Bicycle", "Speaker", "Kayak", "Ladder"}
+var seedTags = []string{"outdoor", "electronic", "tools", "sport", "event", "camping", "home", "music"}
+
+//runSeed insert fake items through item service
+func runSeed(args []string) int {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	count := flags.Int("count", 10, "number of item to create")
+	creator := flags.String("creator", "seeder", "value of created by")
+	flags.Parse(args)
+
+	itemService, dbCon := newItemService()
+	defer dbCon.CloseConnection()
+
+	if err := dbCon.CheckTransaction(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for i := 0; i < *count; i++ {
+		var upsertItemSpec spec.UpsertItemSpec
+		upsertItemSpec.Name = fmt.Sprintf("%s %s",
+			seedAdjectives[random.Intn(len(seedAdjectives))],
+			seedNouns[random.Intn(len(seedNouns))])
+		upsertItemSpec.Description = fmt.Sprintf("Fake %s generated by seeder", upsertItemSpec.Name)
+		upsertItemSpec.Tags = randomTags(random)
+
+		ID, err := itemService.CreateItem(context.Background(), upsertItemSpec, *creator)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "failed to seed item:", err)
+			return exitFailure
+		}
+
+		fmt.Println(ID, upsertItemSpec.Name, upsertItemSpec.Tags)
+	}
+
+	return exitOK
+}
+
+func randomTags(random *rand.Rand) []string {
+	var tags []string
+
+	for _, index := range random.Perm(len(seedTags))[:1+random.Intn(3)] {
+		tags = append(tags, seedTags[index])
+	}
+
+	return tags
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	api "sample-order/api"
+	"sample-order/api/middleware"
+	bookingControllerV1 "sample-order/api/v1/booking"
+	cartControllerV1 "sample-order/api/v1/cart"
+	itemControllerV1 "sample-order/api/v1/item"
+	orderControllerV1 "sample-order/api/v1/order"
+	pricingControllerV1 "sample-order/api/v1/pricing"
+	promotionControllerV1 "sample-order/api/v1/promotion"
+	stockControllerV1 "sample-order/api/v1/stock"
+	warehouseControllerV1 "sample-order/api/v1/warehouse"
+	webhookControllerV1 "sample-order/api/v1/webhook"
+	businessBooking "sample-order/business/booking"
+	businessCart "sample-order/business/cart"
+	businessItem "sample-order/business/item"
+	businessOrder "sample-order/business/order"
+	businessOutbox "sample-order/business/outbox"
+	businessPricing "sample-order/business/pricing"
+	businessPromotion "sample-order/business/promotion"
+	businessStock "sample-order/business/stock"
+	businessWarehouse "sample-order/business/warehouse"
+	businessWebhook "sample-order/business/webhook"
+	"sample-order/config"
+	"sample-order/modules/health"
+	"sample-order/modules/lifecycle"
+	"sample-order/modules/logger"
+	"sample-order/modules/metrics"
+	"sample-order/modules/relay"
+	bookingRepo "sample-order/modules/repository/booking"
+	cartRepo "sample-order/modules/repository/cart"
+	itemRepo "sample-order/modules/repository/item"
+	orderRepo "sample-order/modules/repository/order"
+	outboxRepo "sample-order/modules/repository/outbox"
+	pricingRepo "sample-order/modules/repository/pricing"
+	promotionRepo "sample-order/modules/repository/promotion"
+	stockRepo "sample-order/modules/repository/stock"
+	warehouseRepo "sample-order/modules/repository/warehouse"
+	webhookRepo "sample-order/modules/repository/webhook"
+	"sample-order/modules/sender"
+	"sample-order/modules/sweeper"
+	"sample-order/modules/tracing"
+	"sample-order/util"
+	"syscall"
+
+	"github.com/labstack/echo"
+	"github.com/rs/zerolog/log"
+)
+
+//runServe start the API server
+func runServe(args []string) int {
+	//load config if available or set to default
+	config := config.GetConfig()
+
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags.BoolVar(&config.Database.AutoMigrate, "auto-migrate", config.Database.AutoMigrate, "apply pending schema migration before server start")
+	flags.Parse(args)
+
+	//write structured log, the background workers use the global logger
+	appLogger, err := logger.New(config.Log.Level, config.Log.Format, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid log config:", err)
+		return exitFailure
+	}
+	log.Logger = appLogger
+
+	//initialize database connection based on given config
+	dbCon := util.NewDatabaseConnection(config)
+
+	//fail at startup instead of on the first write
+	if err := dbCon.CheckTransaction(context.Background()); err != nil {
+		log.Error().Err(err).Msg("unsupported database deployment")
+		dbCon.CloseConnection()
+		return exitFailure
+	}
+
+	if config.Database.AutoMigrate {
+		autoMigrate(dbCon)
+	}
+
+	//collect metrics of HTTP requests, item repository and database pool
+	appMetrics := metrics.New()
+	if dbCon.MySQLDB != nil {
+		appMetrics.RegisterSQLDB(dbCon.MySQLDB)
+	}
+
+	//the service is ready only when the database answer its ping
+	appHealth := health.New(config.Health.Timeout)
+	appHealth.AddCheck(string(dbCon.Driver), dbCon.Ping)
+
+	//trace requests through the item service down to the database
+	appTracing, err := tracing.New(tracing.Options{
+		Exporter:    config.Tracing.Exporter,
+		Endpoint:    config.Tracing.Endpoint,
+		Insecure:    config.Tracing.Insecure,
+		ServiceName: config.Tracing.ServiceName,
+		SampleRatio: config.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to initialize tracing")
+		dbCon.CloseConnection()
+		return exitFailure
+	}
+
+	//initiate item repository, its operations are timed and traced
+	driver := string(dbCon.Driver)
+	itemRepo := appTracing.TraceItemRepository(appMetrics.InstrumentItemRepository(itemRepo.RepositoryFactory(dbCon), driver), driver)
+
+	//initiate item service, every stored change is pushed into the item event stream and to the item editors
+	itemBroadcaster := businessItem.NewBroadcaster(config.Stream.ReplaySize)
+	itemPresence := businessItem.NewPresence()
+	itemService := appTracing.TraceItemService(businessItem.NewService(itemRepo, itemBroadcaster, itemPresence))
+
+	//initiate webhook repository and service, failed delivery is retried with exponential backoff
+	retryPolicy := businessWebhook.RetryPolicy{
+		MaxAttempts: config.Webhook.MaxAttempts,
+		BaseDelay:   config.Webhook.BackoffBase,
+		MaxDelay:    config.Webhook.BackoffMax,
+	}
+	webhookService := businessWebhook.NewService(webhookRepo.RepositoryFactory(dbCon), sender.NewHTTPSender(config.Webhook.Timeout), retryPolicy)
+
+	//publish item events written into the outbox as webhook deliveries in background
+	outboxService := businessOutbox.NewService(outboxRepo.RepositoryFactory(dbCon), webhookService)
+	outboxRelay := relay.NewOutboxRelay(outboxService, config.Outbox.RelayInterval, config.Outbox.BatchSize)
+	outboxRelay.Start()
+
+	//send due webhook deliveries in background
+	webhookRelay := relay.NewWebhookRelay(webhookService, config.Webhook.DeliveryInterval, config.Webhook.BatchSize)
+	webhookRelay.Start()
+
+	//initiate warehouse repository and service
+	warehouseService := businessWarehouse.NewService(warehouseRepo.RepositoryFactory(dbCon))
+
+	//initiate stock repository and service
+	stockService := businessStock.NewService(stockRepo.RepositoryFactory(dbCon), itemService, warehouseService, config.Reservation.TTL)
+
+	//release the stock of expired reservations in background
+	reservationSweeper := sweeper.NewReservationSweeper(stockService, config.Reservation.SweepInterval)
+	reservationSweeper.Start()
+
+	//initiate pricing repository and service
+	pricingService := businessPricing.NewService(pricingRepo.RepositoryFactory(dbCon), itemService)
+
+	//initiate booking repository and service
+	bookingService := businessBooking.NewService(bookingRepo.RepositoryFactory(dbCon), stockService, pricingService)
+
+	//initiate promotion repository and service
+	promotionService := businessPromotion.NewService(promotionRepo.RepositoryFactory(dbCon))
+
+	//initiate order repository and service
+	orderService := businessOrder.NewService(orderRepo.RepositoryFactory(dbCon), itemService, stockService, promotionService)
+
+	//initiate cart repository and service
+	cartService := businessCart.NewService(cartRepo.RepositoryFactory(dbCon), itemService, orderService, promotionService)
+
+	//initiate API controllers
+	itemControllerV1 := itemControllerV1.NewController(itemService, stockService, itemBroadcaster, itemPresence)
+	stockControllerV1 := stockControllerV1.NewController(stockService)
+	warehouseControllerV1 := warehouseControllerV1.NewController(warehouseService)
+	bookingControllerV1 := bookingControllerV1.NewController(bookingService)
+	pricingControllerV1 := pricingControllerV1.NewController(pricingService)
+	orderControllerV1 := orderControllerV1.NewController(orderService)
+	cartControllerV1 := cartControllerV1.NewController(cartService)
+	promotionControllerV1 := promotionControllerV1.NewController(promotionService)
+	webhookControllerV1 := webhookControllerV1.NewController(webhookService)
+
+	//create echo http, every request get request ID and access log entry, then it is traced, counted and timed by its route
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.Use(middleware.RequestID(appLogger), middleware.AccessLog(), appTracing.Middleware(), appMetrics.Middleware())
+	e.GET("/metrics", appMetrics.Handler())
+	e.GET("/health/live", appHealth.LiveHandler())
+	e.GET("/health/ready", appHealth.ReadyHandler())
+
+	//register API path and handler
+	api.RegisterPath(e, itemControllerV1, stockControllerV1, warehouseControllerV1, bookingControllerV1, pricingControllerV1, orderControllerV1, cartControllerV1, promotionControllerV1, webhookControllerV1)
+
+	//stop the components in order when the server receive SIGINT or SIGTERM
+	//the whole shutdown is cut at the total timeout, so the server finish before it is killed
+	appLifecycle := lifecycle.New(config.Shutdown.TotalTimeout)
+
+	//stop receiving new traffic and keep serving until the load balancer notice it
+	appLifecycle.OnShutdown("readiness", 0, func(ctx context.Context) error {
+		appHealth.MarkShuttingDown()
+		return nil
+	})
+	appLifecycle.OnShutdown("drain", 0, lifecycle.Delay(config.Shutdown.DrainPeriod))
+
+	//end the open event streams and presence sockets, otherwise server shutdown wait for them until the timeout
+	appLifecycle.OnShutdown("item stream", config.Shutdown.Timeout, lifecycle.Func(itemBroadcaster.Close))
+	appLifecycle.OnShutdown("item presence", config.Shutdown.Timeout, lifecycle.Func(itemPresence.Close))
+
+	//wait for the running requests
+	appLifecycle.OnShutdown("server", config.Shutdown.Timeout, e.Shutdown)
+
+	//stop the background workers before the db is closed
+	appLifecycle.OnShutdown("reservation sweeper", config.Shutdown.Timeout, lifecycle.Func(reservationSweeper.Stop))
+	appLifecycle.OnShutdown("outbox relay", config.Shutdown.Timeout, lifecycle.Func(outboxRelay.Stop))
+	appLifecycle.OnShutdown("webhook relay", config.Shutdown.Timeout, lifecycle.Func(webhookRelay.Stop))
+
+	//export the spans of the last requests
+	appLifecycle.OnShutdown("tracing", config.Shutdown.Timeout, appTracing.Shutdown)
+
+	appLifecycle.OnShutdown("database", config.Shutdown.Timeout, lifecycle.Func(dbCon.CloseConnection))
+
+	// run server
+	go func() {
+		address := fmt.Sprintf("localhost:%d", config.Port)
+		log.Info().Str("address", address).Msg("starting the server")
+
+		if err := e.Start(address); err != nil && err != http.ErrServerClosed {
+			log.Fatal().Err(err).Msg("failed to start the server")
+		}
+	}()
+
+	received := appLifecycle.Wait(syscall.SIGINT, syscall.SIGTERM)
+	log.Info().Str("signal", received.String()).Msg("shutting down the server")
+
+	//every stage after the drain period is waited for its own timeout, within the total timeout
+	if err := appLifecycle.Shutdown(context.Background()); err != nil {
+		log.Error().Err(err).Msg("server is not shut down gracefully")
+		return exitFailure
+	}
+
+	log.Info().Msg("server is shut down")
+	return exitOK
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sample-order/modules/itemfile"
+)
+
+//runImport create items from CSV or NDJSON file through bulk import
+func runImport(args []string) int {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	path := flags.String("file", "", "CSV or NDJSON file path, use - for stdin")
+	formatName := flags.String("format", "", "file format (csv or ndjson), detected from file extension when empty")
+	creator := flags.String("creator", "importer", "value of created by")
+	dryRun := flags.Bool("dry-run", false, "only validate the rows without storing it")
+	flags.Parse(args)
+
+	if *path == "" {
+		flags.Usage()
+		return exitUsage
+	}
+
+	var format itemfile.Format
+	var err error
+
+	if *formatName != "" {
+		format, err = itemfile.ParseFormat(*formatName)
+	} else {
+		format, err = itemfile.FormatFromFilename(*path)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	var reader io.Reader = os.Stdin
+	if *path != "-" {
+		file, err := os.Open(*path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "failed to open file:", err)
+			return exitFailure
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	rows, err := itemfile.ReadRows(reader, format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to read file:", err)
+		return exitFailure
+	}
+
+	itemService, dbCon := newItemService()
+	defer dbCon.CloseConnection()
+
+	if !*dryRun {
+		if err = dbCon.CheckTransaction(context.Background()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailure
+		}
+	}
+
+	report, err := itemService.ImportItems(context.Background(), rows, *creator, *dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to import items:", err)
+		return exitFailure
+	}
+
+	for _, result := range report.Results {
+		if result.Err != nil {
+			fmt.Printf("line %d: failed: %v\n", result.Line, result.Err)
+		} else {
+			fmt.Printf("line %d: ok %s\n", result.Line, result.ID)
+		}
+	}
+
+	if report.DryRun {
+		fmt.Printf("dry run: %d valid row(s), %d invalid\n", report.Succeeded, report.Failed)
+	} else {
+		fmt.Printf("imported %d item(s), %d failed\n", report.Succeeded, report.Failed)
+	}
+
+	if report.Failed > 0 {
+		return exitFailure
+	}
+
+	return exitOK
+}
+
+//runExport stream all items or items with given tag into file
+func runExport(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	tag := flags.String("tag", "", "only export items with this tag")
+	formatName := flags.String("format", "ndjson", "output format (ndjson, csv or json)")
+	path := flags.String("out", "-", "output file path, use - for stdout")
+	flags.Parse(args)
+
+	format, err := itemfile.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	var output io.Writer = os.Stdout
+	if *path != "-" {
+		file, err := os.Create(*path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "failed to create file:", err)
+			return exitFailure
+		}
+		defer file.Close()
+		output = file
+	}
+
+	bufferedOutput := bufio.NewWriter(output)
+
+	writer, _ := itemfile.NewWriter(bufferedOutput, format)
+
+	itemService, dbCon := newItemService()
+	defer dbCon.CloseConnection()
+
+	if err = itemService.ExportItems(context.Background(), *tag, writer.Write); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to export items:", err)
+		return exitFailure
+	}
+
+	if err = writer.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to export items:", err)
+		return exitFailure
+	}
+
+	if err = bufferedOutput.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to export items:", err)
+		return exitFailure
+	}
+
+	return exitOK
+}
@@ -0,0 +1,115 @@
+package booking
+
+import (
+	"sample-order/business/money"
+	"time"
+)
+
+//Status lifecycle state of a booking
+type Status string
+
+const (
+	//Reserved units are held for the booking period
+	Reserved Status = "reserved"
+	//PickedUp units are handed over to the customer
+	PickedUp Status = "picked_up"
+	//Returned units are back, the booking is finished
+	Returned Status = "returned"
+	//Cancelled booking released before pick up
+	Cancelled Status = "cancelled"
+)
+
+//transitions next status allowed from each status
+var transitions = map[Status][]Status{
+	Reserved: {PickedUp, Cancelled},
+	PickedUp: {Returned},
+}
+
+//CanChangeTo Return true if booking with this status may move into next status
+func (status Status) CanChangeTo(next Status) bool {
+	for _, allowed := range transitions[status] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+//IsActive Return true if booking with this status still hold its units
+func (status Status) IsActive() bool {
+	return status == Reserved || status == PickedUp
+}
+
+//Booking units of an item reserved for a customer in a date range. From is inclusive and To is exclusive,
+//both are midnight UTC
+type Booking struct {
+	ID       string
+	ItemID   string
+	Quantity int
+	From     time.Time
+	To       time.Time
+	Customer string
+	Status   Status
+
+	//FinalCharge rental charge including late fee, only set when the booking is returned
+	FinalCharge   *money.Money
+	LateFee       *money.Money
+	DepositRefund *money.Money
+
+	CreatedAt  time.Time
+	CreatedBy  string
+	ModifiedAt time.Time
+	ModifiedBy string
+	Version    int
+}
+
+//NewBooking create new reserved booking
+func NewBooking(
+	id string,
+	itemID string,
+	quantity int,
+	from time.Time,
+	to time.Time,
+	customer string,
+	creator string,
+	createdAt time.Time) Booking {
+
+	return Booking{
+		ID:         id,
+		ItemID:     itemID,
+		Quantity:   quantity,
+		From:       Day(from),
+		To:         Day(to),
+		Customer:   customer,
+		Status:     Reserved,
+		CreatedAt:  createdAt,
+		CreatedBy:  creator,
+		ModifiedAt: createdAt,
+		ModifiedBy: creator,
+		Version:    1,
+	}
+}
+
+//ChangeStatus update status of existing booking
+func (oldBooking *Booking) ChangeStatus(newStatus Status, updater string, modifiedAt time.Time) Booking {
+	booking := *oldBooking
+	booking.Status = newStatus
+	booking.ModifiedAt = modifiedAt
+	booking.ModifiedBy = updater
+	booking.Version = oldBooking.Version + 1
+
+	return booking
+}
+
+//SetCharge record the final charge of returned booking
+func (oldBooking *Booking) SetCharge(finalCharge money.Money, lateFee money.Money, depositRefund money.Money) {
+	oldBooking.FinalCharge = &finalCharge
+	oldBooking.LateFee = &lateFee
+	oldBooking.DepositRefund = &depositRefund
+}
+
+//Overlaps Return true if booking period intersect with range [from, to)
+func (oldBooking *Booking) Overlaps(from time.Time, to time.Time) bool {
+	return oldBooking.From.Before(to) && from.Before(oldBooking.To)
+}
@@ -0,0 +1,65 @@
+package booking
+
+import "time"
+
+//MaxRangeDays longest period accepted for booking and availability query
+const MaxRangeDays = 366
+
+//Slot consecutive days with the same number of free units, From is inclusive and To is exclusive
+type Slot struct {
+	From      time.Time
+	To        time.Time
+	Available int
+}
+
+//Day Return midnight UTC of given time
+func Day(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+//NewCalendar count free units of each day in range [from, to) and merge consecutive days with the same count.
+//Only active bookings reduce the capacity
+func NewCalendar(capacity int, bookings []Booking, from time.Time, to time.Time) []Slot {
+	var slots []Slot
+
+	for day := Day(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		available := capacity - bookedOn(bookings, day)
+		if available < 0 {
+			available = 0
+		}
+
+		last := len(slots) - 1
+		if last >= 0 && slots[last].Available == available {
+			slots[last].To = day.AddDate(0, 0, 1)
+			continue
+		}
+
+		slots = append(slots, Slot{day, day.AddDate(0, 0, 1), available})
+	}
+
+	return slots
+}
+
+//HasCapacity Return true if booking quantity fit into the free units on every day of its period
+func HasCapacity(capacity int, bookings []Booking, booking Booking) bool {
+	for _, slot := range NewCalendar(capacity, bookings, booking.From, booking.To) {
+		if slot.Available < booking.Quantity {
+			return false
+		}
+	}
+
+	return true
+}
+
+func bookedOn(bookings []Booking, day time.Time) int {
+	booked := 0
+
+	for _, booking := range bookings {
+		if booking.Status.IsActive() && booking.Overlaps(day, day.AddDate(0, 0, 1)) {
+			booked += booking.Quantity
+		}
+	}
+
+	return booked
+}
@@ -0,0 +1,196 @@
+package booking
+
+import (
+	"sample-order/business"
+	"sample-order/business/booking/spec"
+	"sample-order/business/pricing"
+	"sample-order/business/stock"
+	"sample-order/util"
+	"time"
+
+	validator "github.com/go-playground/validator/v10"
+)
+
+//Repository ingoing port for booking
+type Repository interface {
+	//FindBookingByID If data not found will return nil without error
+	FindBookingByID(ID string) (*Booking, error)
+
+	//FindBookingsByItemID Return all bookings of the item ordered by period, empty slice if there is none
+	FindBookingsByItemID(itemID string) ([]Booking, error)
+
+	//FindActiveBookings Return reserved and picked up bookings of the item which overlap range [from, to)
+	FindActiveBookings(itemID string, from time.Time, to time.Time) ([]Booking, error)
+
+	//InsertBooking Insert booking only if it fit into capacity together with the active bookings.
+	//The check and insert must be atomic against concurrent insert of the same item, return business.ErrBookingConflict if not fit
+	InsertBooking(booking Booking, capacity int) error
+
+	//UpdateBooking if data not found or version is not match will return business.ErrZeroAffected
+	UpdateBooking(booking Booking, currentVersion int) error
+}
+
+//StockService outgoing port to get the number of units owned
+type StockService interface {
+	GetAvailability(itemID string) (*stock.Availability, error)
+}
+
+//PricingService outgoing port to compute the final charge of returned booking
+type PricingService interface {
+	GetFinalCharge(itemID string, quantity int, from time.Time, to time.Time, returnedAt time.Time) (*pricing.Charge, error)
+}
+
+//Service outgoing port for booking
+type Service interface {
+	GetFreeSlots(itemID string, from time.Time, to time.Time) ([]Slot, error)
+
+	GetBookingByID(ID string) (*Booking, error)
+
+	GetBookingsByItemID(itemID string) ([]Booking, error)
+
+	CreateBooking(itemID string, createBookingSpec spec.CreateBookingSpec, createdBy string) (string, error)
+
+	UpdateBookingStatus(ID string, status Status, currentVersion int, modifiedBy string) error
+}
+
+//=============== The implementation of those interface put below =======================
+
+type service struct {
+	repository     Repository
+	stockService   StockService
+	pricingService PricingService
+	validate       *validator.Validate
+}
+
+//NewService Construct booking service object
+func NewService(repository Repository, stockService StockService, pricingService PricingService) Service {
+	return &service{
+		repository,
+		stockService,
+		pricingService,
+		validator.New(),
+	}
+}
+
+//GetFreeSlots Get periods in range [from, to) where the item still has free units, fully booked days are left out.
+//Will return ErrNotFound when item is not exists
+func (s *service) GetFreeSlots(itemID string, from time.Time, to time.Time) ([]Slot, error) {
+	from, to = Day(from), Day(to)
+	if !isValidRange(from, to) {
+		return nil, business.ErrInvalidSpec
+	}
+
+	availability, err := s.stockService.GetAvailability(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookings, err := s.repository.FindActiveBookings(itemID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := []Slot{}
+	for _, slot := range NewCalendar(availability.Total, bookings, from, to) {
+		if slot.Available > 0 {
+			slots = append(slots, slot)
+		}
+	}
+
+	return slots, nil
+}
+
+//GetBookingByID Get booking by given ID, return nil if not exist
+func (s *service) GetBookingByID(ID string) (*Booking, error) {
+	return s.repository.FindBookingByID(ID)
+}
+
+//GetBookingsByItemID Get all bookings of the item, return zero array if there is no booking
+func (s *service) GetBookingsByItemID(itemID string) ([]Booking, error) {
+	bookings, err := s.repository.FindBookingsByItemID(itemID)
+	if err != nil || bookings == nil {
+		return []Booking{}, err
+	}
+
+	return bookings, nil
+}
+
+//CreateBooking Reserve units of the item for the period.
+//Will return ErrBookingConflict when there are not enough free units on any day of the period
+func (s *service) CreateBooking(itemID string, createBookingSpec spec.CreateBookingSpec, createdBy string) (string, error) {
+	if err := s.validate.Struct(createBookingSpec); err != nil {
+		return "", business.ErrInvalidSpec
+	}
+
+	booking := NewBooking(
+		util.GenerateID(),
+		itemID,
+		createBookingSpec.Quantity,
+		createBookingSpec.From,
+		createBookingSpec.To,
+		createBookingSpec.Customer,
+		createdBy,
+		time.Now(),
+	)
+
+	if !isValidRange(booking.From, booking.To) {
+		return "", business.ErrInvalidSpec
+	}
+
+	availability, err := s.stockService.GetAvailability(itemID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.repository.InsertBooking(booking, availability.Total); err != nil {
+		return "", err
+	}
+
+	return booking.ID, nil
+}
+
+//UpdateBookingStatus Move booking into next lifecycle status, returned booking get its final charge
+//unless the item is not rentable anymore. Will return ErrInvalidTransition when the status can not be reached or ErrHasBeenModified if data version is not match
+func (s *service) UpdateBookingStatus(ID string, status Status, currentVersion int, modifiedBy string) error {
+	if len(ID) == 0 || len(modifiedBy) == 0 {
+		return business.ErrInvalidSpec
+	}
+
+	booking, err := s.repository.FindBookingByID(ID)
+
+	if err != nil {
+		return err
+	} else if booking == nil {
+		return business.ErrNotFound
+	} else if booking.Version != currentVersion {
+		return business.ErrHasBeenModified
+	} else if !booking.Status.CanChangeTo(status) {
+		return business.ErrInvalidTransition
+	}
+
+	newBooking := booking.ChangeStatus(status, modifiedBy, time.Now())
+
+	if status == Returned {
+		charge, err := s.pricingService.GetFinalCharge(booking.ItemID, booking.Quantity, booking.From, booking.To, newBooking.ModifiedAt)
+
+		if err == nil {
+			newBooking.SetCharge(charge.Total, charge.LateFee, charge.DepositRefund)
+		} else if err != business.ErrNotRentable {
+			return err
+		}
+	}
+
+	if err := s.repository.UpdateBooking(newBooking, currentVersion); err != nil {
+		if err == business.ErrZeroAffected {
+			return business.ErrHasBeenModified
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func isValidRange(from time.Time, to time.Time) bool {
+	return from.Before(to) && !to.After(from.AddDate(0, 0, MaxRangeDays))
+}
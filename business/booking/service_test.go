@@ -0,0 +1,300 @@
+package booking_test
+
+import (
+	"errors"
+	"sample-order/business"
+	"sample-order/business/booking"
+	"sample-order/business/booking/spec"
+	"sample-order/business/money"
+	"sample-order/business/pricing"
+	"sample-order/business/stock"
+	"sort"
+	"testing"
+	"time"
+)
+
+var existingItemID = "5f350b7d21148431abc65290"
+var notFoundItemID = "5f350b7d21148431abc65299"
+var errorItemID = "error-item-id"
+var errorFind = errors.New("error on find")
+
+var firstDay = time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+
+func day(offset int) time.Time {
+	return firstDay.AddDate(0, 0, offset)
+}
+
+func TestCreateBooking(t *testing.T) {
+	t.Run("Expect booking reserved", func(t *testing.T) {
+		service := newService()
+		ID, err := service.CreateBooking(existingItemID, spec.CreateBookingSpec{Quantity: 2, From: day(0).Add(10 * time.Hour), To: day(3), Customer: "john"}, "cashier")
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		current, _ := service.GetBookingByID(ID)
+		if current.Status != booking.Reserved || current.Version != 1 || !current.From.Equal(day(0)) || !current.To.Equal(day(3)) {
+			t.Error("Expect reserved booking with date only period", current)
+		}
+	})
+
+	t.Run("Expect overlap allowed while stock is enough", func(t *testing.T) {
+		service := newService()
+		service.CreateBooking(existingItemID, spec.CreateBookingSpec{Quantity: 2, From: day(0), To: day(3), Customer: "john"}, "cashier")
+
+		if _, err := service.CreateBooking(existingItemID, spec.CreateBookingSpec{Quantity: 1, From: day(2), To: day(5), Customer: "jane"}, "cashier"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		if _, err := service.CreateBooking(existingItemID, spec.CreateBookingSpec{Quantity: 1, From: day(2), To: day(3), Customer: "joe"}, "cashier"); err != business.ErrBookingConflict {
+			t.Error("Expect error booking conflict. Error is: ", err)
+		}
+
+		//the booking end is exclusive, so next booking can start on that day
+		if _, err := service.CreateBooking(existingItemID, spec.CreateBookingSpec{Quantity: 2, From: day(5), To: day(6), Customer: "joe"}, "cashier"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+	})
+
+	t.Run("Expect cancelled booking release the units", func(t *testing.T) {
+		service := newService()
+		ID, _ := service.CreateBooking(existingItemID, spec.CreateBookingSpec{Quantity: 3, From: day(0), To: day(3), Customer: "john"}, "cashier")
+		service.UpdateBookingStatus(ID, booking.Cancelled, 1, "cashier")
+
+		if _, err := service.CreateBooking(existingItemID, spec.CreateBookingSpec{Quantity: 3, From: day(1), To: day(2), Customer: "jane"}, "cashier"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+	})
+
+	t.Run("Expect failed on invalid spec", func(t *testing.T) {
+		service := newService()
+		invalidSpecs := []spec.CreateBookingSpec{
+			{Quantity: 0, From: day(0), To: day(1), Customer: "john"},
+			{Quantity: 1, From: day(1), To: day(0), Customer: "john"},
+			{Quantity: 1, From: day(0), To: day(0).Add(time.Hour), Customer: "john"},
+			{Quantity: 1, From: day(0), To: day(booking.MaxRangeDays + 1), Customer: "john"},
+			{Quantity: 1, From: day(0), To: day(1), Customer: ""},
+		}
+
+		for _, invalidSpec := range invalidSpecs {
+			if _, err := service.CreateBooking(existingItemID, invalidSpec, "cashier"); err != business.ErrInvalidSpec {
+				t.Error("Expect error invalid spec. Error is: ", err, invalidSpec)
+			}
+		}
+	})
+
+	t.Run("Expect failed on item not found", func(t *testing.T) {
+		service := newService()
+		_, err := service.CreateBooking(notFoundItemID, spec.CreateBookingSpec{Quantity: 1, From: day(0), To: day(1), Customer: "john"}, "cashier")
+
+		if err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+	})
+}
+
+func TestGetFreeSlots(t *testing.T) {
+	t.Run("Expect whole range free when there is no booking", func(t *testing.T) {
+		service := newService()
+		slots, err := service.GetFreeSlots(existingItemID, day(0), day(10))
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if len(slots) != 1 || slots[0].Available != 3 || !slots[0].From.Equal(day(0)) || !slots[0].To.Equal(day(10)) {
+			t.Error("Expect single slot with all units", slots)
+		}
+	})
+
+	t.Run("Expect slots split by bookings and fully booked days left out", func(t *testing.T) {
+		service := newService()
+		service.CreateBooking(existingItemID, spec.CreateBookingSpec{Quantity: 1, From: day(2), To: day(6), Customer: "john"}, "cashier")
+		service.CreateBooking(existingItemID, spec.CreateBookingSpec{Quantity: 2, From: day(4), To: day(5), Customer: "jane"}, "cashier")
+
+		slots, _ := service.GetFreeSlots(existingItemID, day(0), day(8))
+		expected := []booking.Slot{
+			{From: day(0), To: day(2), Available: 3},
+			{From: day(2), To: day(4), Available: 2},
+			{From: day(5), To: day(6), Available: 2},
+			{From: day(6), To: day(8), Available: 3},
+		}
+
+		if len(slots) != len(expected) {
+			t.Error("Expect four slots", slots)
+			t.FailNow()
+		}
+
+		for i, slot := range slots {
+			if !slot.From.Equal(expected[i].From) || !slot.To.Equal(expected[i].To) || slot.Available != expected[i].Available {
+				t.Error("Expect slot ", expected[i], " but got ", slot)
+			}
+		}
+	})
+
+	t.Run("Expect failed on invalid range", func(t *testing.T) {
+		service := newService()
+
+		if _, err := service.GetFreeSlots(existingItemID, day(3), day(1)); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed on stock service error", func(t *testing.T) {
+		service := newService()
+
+		if _, err := service.GetFreeSlots(errorItemID, day(0), day(1)); err != errorFind {
+			t.Error("Expect error on find. Error is: ", err)
+		}
+	})
+}
+
+func TestUpdateBookingStatus(t *testing.T) {
+	t.Run("Expect booking follow the lifecycle", func(t *testing.T) {
+		service := newService()
+		ID, _ := service.CreateBooking(existingItemID, spec.CreateBookingSpec{Quantity: 1, From: day(0), To: day(1), Customer: "john"}, "cashier")
+
+		if err := service.UpdateBookingStatus(ID, booking.PickedUp, 1, "cashier"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		if err := service.UpdateBookingStatus(ID, booking.Returned, 2, "cashier"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		current, _ := service.GetBookingByID(ID)
+		if current.Status != booking.Returned || current.Version != 3 {
+			t.Error("Expect returned booking with version 3", current)
+		}
+
+		if current.FinalCharge == nil || current.FinalCharge.Amount != 10000 {
+			t.Error("Expect final charge of one day", current.FinalCharge)
+		}
+	})
+
+	t.Run("Expect failed on invalid transition", func(t *testing.T) {
+		service := newService()
+		ID, _ := service.CreateBooking(existingItemID, spec.CreateBookingSpec{Quantity: 1, From: day(0), To: day(1), Customer: "john"}, "cashier")
+
+		if err := service.UpdateBookingStatus(ID, booking.Returned, 1, "cashier"); err != business.ErrInvalidTransition {
+			t.Error("Expect error invalid transition. Error is: ", err)
+		}
+
+		service.UpdateBookingStatus(ID, booking.Cancelled, 1, "cashier")
+
+		if err := service.UpdateBookingStatus(ID, booking.PickedUp, 2, "cashier"); err != business.ErrInvalidTransition {
+			t.Error("Expect error invalid transition on cancelled booking. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed on version mismatch", func(t *testing.T) {
+		service := newService()
+		ID, _ := service.CreateBooking(existingItemID, spec.CreateBookingSpec{Quantity: 1, From: day(0), To: day(1), Customer: "john"}, "cashier")
+
+		if err := service.UpdateBookingStatus(ID, booking.PickedUp, 2, "cashier"); err != business.ErrHasBeenModified {
+			t.Error("Expect error has been modified. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed on booking not found", func(t *testing.T) {
+		service := newService()
+
+		if err := service.UpdateBookingStatus("5f350b7d21148431abc65000", booking.PickedUp, 1, "cashier"); err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+	})
+}
+
+func newService() booking.Service {
+	repo := newInMemoryRepository()
+	return booking.NewService(repo, &inMemoryStockService{}, &inMemoryPricingService{})
+}
+
+type inMemoryStockService struct{}
+
+func (s *inMemoryStockService) GetAvailability(itemID string) (*stock.Availability, error) {
+	if itemID == errorItemID {
+		return nil, errorFind
+	} else if itemID != existingItemID {
+		return nil, business.ErrNotFound
+	}
+
+	availability := stock.NewAvailability(itemID, []stock.Stock{{ItemID: itemID, Quantity: 3}})
+	return &availability, nil
+}
+
+//inMemoryPricingService charge 100 USD per unit per day without late fee
+type inMemoryPricingService struct{}
+
+func (s *inMemoryPricingService) GetFinalCharge(itemID string, quantity int, from time.Time, to time.Time, returnedAt time.Time) (*pricing.Charge, error) {
+	days := int64(to.Sub(from).Hours() / 24)
+	total := money.Money{Amount: 10000 * days * int64(quantity), Currency: "USD"}
+
+	return &pricing.Charge{
+		Total:         total,
+		LateFee:       money.Money{Currency: "USD"},
+		DepositRefund: money.Money{Currency: "USD"},
+	}, nil
+}
+
+type inMemoryRepository struct {
+	bookingByID map[string]booking.Booking
+}
+
+func newInMemoryRepository() *inMemoryRepository {
+	return &inMemoryRepository{make(map[string]booking.Booking)}
+}
+
+func (repo *inMemoryRepository) FindBookingByID(ID string) (*booking.Booking, error) {
+	current, ok := repo.bookingByID[ID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &current, nil
+}
+
+func (repo *inMemoryRepository) FindBookingsByItemID(itemID string) ([]booking.Booking, error) {
+	var bookings []booking.Booking
+
+	for _, current := range repo.bookingByID {
+		if current.ItemID == itemID {
+			bookings = append(bookings, current)
+		}
+	}
+
+	sort.Slice(bookings, func(i, j int) bool { return bookings[i].From.Before(bookings[j].From) })
+	return bookings, nil
+}
+
+func (repo *inMemoryRepository) FindActiveBookings(itemID string, from time.Time, to time.Time) ([]booking.Booking, error) {
+	var bookings []booking.Booking
+
+	for _, current := range repo.bookingByID {
+		if current.ItemID == itemID && current.Status.IsActive() && current.Overlaps(from, to) {
+			bookings = append(bookings, current)
+		}
+	}
+
+	return bookings, nil
+}
+
+func (repo *inMemoryRepository) InsertBooking(newBooking booking.Booking, capacity int) error {
+	bookings, _ := repo.FindActiveBookings(newBooking.ItemID, newBooking.From, newBooking.To)
+	if !booking.HasCapacity(capacity, bookings, newBooking) {
+		return business.ErrBookingConflict
+	}
+
+	repo.bookingByID[newBooking.ID] = newBooking
+	return nil
+}
+
+func (repo *inMemoryRepository) UpdateBooking(newBooking booking.Booking, currentVersion int) error {
+	current, ok := repo.bookingByID[newBooking.ID]
+	if !ok || current.Version != currentVersion {
+		return business.ErrZeroAffected
+	}
+
+	repo.bookingByID[newBooking.ID] = newBooking
+	return nil
+}
@@ -0,0 +1,11 @@
+package spec
+
+import "time"
+
+//CreateBookingSpec create booking spec. Only the date part of From and To is used, To is exclusive
+type CreateBookingSpec struct {
+	Quantity int       `validate:"required,gt=0"`
+	From     time.Time `validate:"required"`
+	To       time.Time `validate:"required,gtfield=From"`
+	Customer string    `validate:"required"`
+}
@@ -0,0 +1,252 @@
+package cart
+
+import (
+	"sample-order/business/item"
+	"sample-order/business/money"
+	"sample-order/business/promotion"
+	"time"
+)
+
+//LineStatus state of the cart line item compared with the time it was added
+type LineStatus string
+
+const (
+	//Available item is unchanged and can be ordered
+	Available LineStatus = "available"
+	//Modified item has been modified since it was added, the price may have changed
+	Modified LineStatus = "modified"
+	//Deleted item does not exist anymore
+	Deleted LineStatus = "deleted"
+	//NotForSale item does not have sale price anymore
+	NotForSale LineStatus = "not_for_sale"
+	//CurrencyMismatch item price currency is different with the other lines
+	CurrencyMismatch LineStatus = "currency_mismatch"
+)
+
+//Line item added into cart, the item version is captured to detect later modification
+type Line struct {
+	ItemID      string
+	Quantity    int
+	ItemVersion int
+	AddedAt     time.Time
+}
+
+//Cart items collected by a user or anonymous session before checkout. Owner is the user ID or session ID
+type Cart struct {
+	Owner         string
+	PromotionCode string
+	Lines         []Line
+	CreatedAt     time.Time
+	ModifiedAt    time.Time
+	Version       int
+}
+
+//NewCart create new empty cart, version is zero until it is stored
+func NewCart(owner string, createdAt time.Time) Cart {
+	return Cart{
+		Owner:      owner,
+		Lines:      []Line{},
+		CreatedAt:  createdAt,
+		ModifiedAt: createdAt,
+	}
+}
+
+//AddItem add quantity of the item into the cart. Item which already in the cart get its quantity increased
+//and its version replaced with the given one
+func (oldCart *Cart) AddItem(itemID string, quantity int, itemVersion int, addedAt time.Time) Cart {
+	cart := oldCart.next(addedAt)
+
+	for i, line := range cart.Lines {
+		if line.ItemID == itemID {
+			cart.Lines[i].Quantity += quantity
+			cart.Lines[i].ItemVersion = itemVersion
+			return cart
+		}
+	}
+
+	cart.Lines = append(cart.Lines, Line{itemID, quantity, itemVersion, addedAt})
+	return cart
+}
+
+//RemoveItem decrease quantity of the item, the line is removed when quantity is zero or not less than the line quantity.
+//Return false when the item is not in the cart
+func (oldCart *Cart) RemoveItem(itemID string, quantity int, removedAt time.Time) (Cart, bool) {
+	cart := oldCart.next(removedAt)
+
+	for i, line := range cart.Lines {
+		if line.ItemID != itemID {
+			continue
+		}
+
+		if quantity > 0 && quantity < line.Quantity {
+			cart.Lines[i].Quantity -= quantity
+		} else {
+			cart.Lines = append(cart.Lines[:i], cart.Lines[i+1:]...)
+		}
+
+		return cart, true
+	}
+
+	return Cart{}, false
+}
+
+//Refresh drop the lines which can not be ordered anymore and capture the current version of modified items
+func (oldCart *Cart) Refresh(view View, refreshedAt time.Time) Cart {
+	cart := oldCart.next(refreshedAt)
+	cart.Lines = []Line{}
+
+	for _, lineView := range view.Lines {
+		switch lineView.Status {
+		case Available:
+			cart.Lines = append(cart.Lines, lineView.Line)
+		case Modified:
+			line := lineView.Line
+			line.ItemVersion = lineView.CurrentVersion
+			cart.Lines = append(cart.Lines, line)
+		}
+	}
+
+	return cart
+}
+
+//ApplyPromotion set the promotion code used on checkout, it replace the previous code
+func (oldCart *Cart) ApplyPromotion(code string, appliedAt time.Time) Cart {
+	cart := oldCart.next(appliedAt)
+	cart.PromotionCode = promotion.NormalizeCode(code)
+
+	return cart
+}
+
+//RemovePromotion clear the promotion code of the cart
+func (oldCart *Cart) RemovePromotion(removedAt time.Time) Cart {
+	cart := oldCart.next(removedAt)
+	cart.PromotionCode = ""
+
+	return cart
+}
+
+//next copy of the cart with the lines cloned and increased version
+func (oldCart *Cart) next(modifiedAt time.Time) Cart {
+	cart := *oldCart
+	cart.Lines = append([]Line{}, oldCart.Lines...)
+	cart.ModifiedAt = modifiedAt
+	cart.Version = oldCart.Version + 1
+
+	return cart
+}
+
+//LineView cart line with the current name and price of the item
+type LineView struct {
+	Line
+	ItemName       string
+	CurrentVersion int
+	UnitPrice      *money.Money
+	Subtotal       *money.Money
+	Status         LineStatus
+}
+
+//View cart with the total computed from the current item prices. Total is nil when no line has price.
+//Discount and GrandTotal are nil when the cart has no promotion code or the promotion can not be applied
+type View struct {
+	Cart           Cart
+	Lines          []LineView
+	Total          *money.Money
+	Discount       *money.Money
+	GrandTotal     *money.Money
+	PromotionError error
+	Outdated       bool
+}
+
+//NewView compare every line with the current item, nil item means it has been deleted.
+//Total currency follow the first line with price
+func NewView(cart Cart, itemByID map[string]*item.Item) View {
+	view := View{
+		Cart:  cart,
+		Lines: make([]LineView, 0, len(cart.Lines)),
+	}
+
+	for _, line := range cart.Lines {
+		lineView := LineView{Line: line, Status: Available}
+		current := itemByID[line.ItemID]
+
+		if current == nil {
+			lineView.Status = Deleted
+			view.Lines = append(view.Lines, lineView)
+			view.Outdated = true
+			continue
+		}
+
+		lineView.ItemName = current.Name
+		lineView.CurrentVersion = current.Version
+
+		if current.SalePrice == nil {
+			lineView.Status = NotForSale
+			view.Lines = append(view.Lines, lineView)
+			view.Outdated = true
+			continue
+		}
+
+		unitPrice := *current.SalePrice
+		subtotal := unitPrice.Multiply(int64(line.Quantity))
+		lineView.UnitPrice = &unitPrice
+		lineView.Subtotal = &subtotal
+
+		if view.Total == nil {
+			total := money.Money{Currency: unitPrice.Currency}
+			view.Total = &total
+		}
+
+		if total, err := view.Total.Add(subtotal); err != nil {
+			lineView.Status = CurrencyMismatch
+			lineView.Subtotal = nil
+		} else {
+			view.Total = &total
+
+			if current.Version != line.ItemVersion {
+				lineView.Status = Modified
+			}
+		}
+
+		if lineView.Status != Available {
+			view.Outdated = true
+		}
+
+		view.Lines = append(view.Lines, lineView)
+	}
+
+	return view
+}
+
+//PromotionLines return the priced lines evaluated by the promotion
+func (view *View) PromotionLines(itemByID map[string]*item.Item) []promotion.Line {
+	var lines []promotion.Line
+
+	for _, lineView := range view.Lines {
+		if lineView.Subtotal == nil {
+			continue
+		}
+
+		lines = append(lines, promotion.Line{
+			ItemID:   lineView.ItemID,
+			Tags:     itemByID[lineView.ItemID].Tags,
+			Subtotal: *lineView.Subtotal,
+		})
+	}
+
+	return lines
+}
+
+//ApplyDiscount reduce the total by the promotion discount
+func (view *View) ApplyDiscount(discount money.Money) {
+	if view.Total == nil {
+		return
+	}
+
+	grandTotal, err := view.Total.Subtract(discount)
+	if err != nil {
+		return
+	}
+
+	view.Discount = &discount
+	view.GrandTotal = &grandTotal
+}
@@ -0,0 +1,329 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sample-order/business"
+	"sample-order/business/cart/spec"
+	"sample-order/business/item"
+	orderSpec "sample-order/business/order/spec"
+	"sample-order/business/promotion"
+	"time"
+
+	validator "github.com/go-playground/validator/v10"
+)
+
+//Repository ingoing port for cart
+type Repository interface {
+	//FindCartByOwner If data not found will return nil without error
+	FindCartByOwner(owner string) (*Cart, error)
+
+	//InsertCart Insert new cart. If the owner already has a cart will return business.ErrZeroAffected
+	InsertCart(cart Cart) error
+
+	//UpdateCart Replace the lines of the cart. If data not found or version is not match will return business.ErrZeroAffected
+	UpdateCart(cart Cart, currentVersion int) error
+
+	//DeleteCart Delete the cart and its lines. If data not found or version is not match will return business.ErrZeroAffected
+	DeleteCart(owner string, currentVersion int) error
+}
+
+//ItemService outgoing port to get the current version and price of the item
+type ItemService interface {
+	GetItemByID(ctx context.Context, ID string) (*item.Item, error)
+}
+
+//OrderService outgoing port to convert the cart into order
+type OrderService interface {
+	CreateOrder(createOrderSpec orderSpec.CreateOrderSpec, createdBy string) (string, error)
+}
+
+//PromotionService outgoing port to compute the discount of the promotion code before checkout
+type PromotionService interface {
+	EvaluatePromotion(code string, user string, lines []promotion.Line) (*promotion.Discount, error)
+}
+
+//Service outgoing port for cart
+type Service interface {
+	GetCart(owner string) (*View, error)
+
+	AddItem(owner string, addItemSpec spec.AddItemSpec) (*View, error)
+
+	RemoveItem(owner string, itemID string, quantity int) (*View, error)
+
+	RefreshCart(owner string) (*View, error)
+
+	ApplyPromotion(owner string, applyPromotionSpec spec.ApplyPromotionSpec) (*View, error)
+
+	RemovePromotion(owner string) (*View, error)
+
+	CheckoutCart(owner string, checkoutCartSpec spec.CheckoutCartSpec) (string, error)
+}
+
+//=============== The implementation of those interface put below =======================
+
+type service struct {
+	repository       Repository
+	itemService      ItemService
+	orderService     OrderService
+	promotionService PromotionService
+	validate         *validator.Validate
+}
+
+//NewService Construct cart service object
+func NewService(repository Repository, itemService ItemService, orderService OrderService, promotionService PromotionService) Service {
+	return &service{
+		repository,
+		itemService,
+		orderService,
+		promotionService,
+		validator.New(),
+	}
+}
+
+//GetCart Get cart of the owner with the total of current item prices, return empty cart if the owner has none
+func (s *service) GetCart(owner string) (*View, error) {
+	cart, err := s.findCart(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.view(*cart)
+}
+
+//AddItem Add quantity of the item into the cart of the owner, the cart is created on the first item.
+//Will return ErrInvalidSpec when item is not exists or ErrNotForSale when item has no sale price
+func (s *service) AddItem(owner string, addItemSpec spec.AddItemSpec) (*View, error) {
+	if err := s.validate.Struct(addItemSpec); err != nil || len(owner) == 0 {
+		return nil, business.ErrInvalidSpec
+	}
+
+	item, err := s.itemService.GetItemByID(context.TODO(), addItemSpec.ItemID)
+	if err != nil {
+		return nil, err
+	} else if item == nil {
+		return nil, business.ErrInvalidSpec
+	} else if item.SalePrice == nil {
+		return nil, business.ErrNotForSale
+	}
+
+	cart, err := s.findCart(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	newCart := cart.AddItem(item.ID, addItemSpec.Quantity, item.Version, time.Now())
+
+	if err := s.save(newCart, cart.Version); err != nil {
+		return nil, err
+	}
+
+	return s.view(newCart)
+}
+
+//RemoveItem Decrease quantity of the item in the cart, zero quantity remove the whole line.
+//Will return ErrNotFound when the item is not in the cart
+func (s *service) RemoveItem(owner string, itemID string, quantity int) (*View, error) {
+	if quantity < 0 {
+		return nil, business.ErrInvalidSpec
+	}
+
+	cart, err := s.findCart(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	newCart, ok := cart.RemoveItem(itemID, quantity, time.Now())
+	if !ok {
+		return nil, business.ErrNotFound
+	}
+
+	if err := s.save(newCart, cart.Version); err != nil {
+		return nil, err
+	}
+
+	return s.view(newCart)
+}
+
+//RefreshCart Accept the current state of the items, deleted and not for sale items are removed from the cart
+func (s *service) RefreshCart(owner string) (*View, error) {
+	cart, err := s.findCart(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := s.view(*cart)
+	if err != nil || !view.Outdated {
+		return view, err
+	}
+
+	newCart := cart.Refresh(*view, time.Now())
+
+	if err := s.save(newCart, cart.Version); err != nil {
+		return nil, err
+	}
+
+	return s.view(newCart)
+}
+
+//ApplyPromotion Set the promotion code of the cart, the code is redeemed on checkout.
+//Will return business.PromotionError when the promotion can not be applied into the current items
+func (s *service) ApplyPromotion(owner string, applyPromotionSpec spec.ApplyPromotionSpec) (*View, error) {
+	if err := s.validate.Struct(applyPromotionSpec); err != nil {
+		return nil, business.ErrInvalidSpec
+	}
+
+	cart, err := s.findCart(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	newCart := cart.ApplyPromotion(applyPromotionSpec.Code, time.Now())
+
+	view, err := s.view(newCart)
+	if err != nil {
+		return nil, err
+	} else if view.PromotionError != nil {
+		return nil, view.PromotionError
+	}
+
+	if err := s.save(newCart, cart.Version); err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+//RemovePromotion Clear the promotion code of the cart
+func (s *service) RemovePromotion(owner string) (*View, error) {
+	cart, err := s.findCart(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	newCart := cart.RemovePromotion(time.Now())
+
+	if err := s.save(newCart, cart.Version); err != nil {
+		return nil, err
+	}
+
+	return s.view(newCart)
+}
+
+//CheckoutCart Empty the cart, then create draft order from it with the current item prices and the cart promotion code.
+//The cart is claimed first so double submitted checkout create single order, it is restored when the order is not created.
+//Will return ErrInvalidSpec when the cart is empty, ErrCartOutdated when any item has changed since it was added
+//or business.PromotionError when the promotion is not applicable anymore
+func (s *service) CheckoutCart(owner string, checkoutCartSpec spec.CheckoutCartSpec) (string, error) {
+	cart, err := s.findCart(owner)
+	if err != nil {
+		return "", err
+	}
+
+	view, err := s.view(*cart)
+	if err != nil {
+		return "", err
+	}
+
+	if len(cart.Lines) == 0 {
+		return "", business.ErrInvalidSpec
+	} else if view.Outdated {
+		return "", business.ErrCartOutdated
+	}
+
+	customer := checkoutCartSpec.Customer
+	if len(customer) == 0 {
+		customer = owner
+	}
+
+	createOrderSpec := orderSpec.CreateOrderSpec{Customer: customer, PromotionCode: cart.PromotionCode}
+
+	for _, line := range cart.Lines {
+		createOrderSpec.Lines = append(createOrderSpec.Lines, orderSpec.LineSpec{ItemID: line.ItemID, Quantity: line.Quantity})
+	}
+
+	if err := s.repository.DeleteCart(owner, cart.Version); err != nil {
+		if err == business.ErrZeroAffected {
+			return "", business.ErrHasBeenModified
+		}
+
+		return "", err
+	}
+
+	orderID, err := s.orderService.CreateOrder(createOrderSpec, owner)
+	if err != nil {
+		//the owner may have started new cart in the meantime, which is kept and the failure is reported with the order error
+		if restoreErr := s.repository.InsertCart(*cart); restoreErr != nil {
+			return "", fmt.Errorf("%w, and the cart of %s can not be restored: %v", err, owner, restoreErr)
+		}
+
+		return "", err
+	}
+
+	return orderID, nil
+}
+
+//findCart return stored cart of the owner or new empty cart
+func (s *service) findCart(owner string) (*Cart, error) {
+	if len(owner) == 0 {
+		return nil, business.ErrInvalidSpec
+	}
+
+	cart, err := s.repository.FindCartByOwner(owner)
+	if err != nil {
+		return nil, err
+	} else if cart == nil {
+		newCart := NewCart(owner, time.Now())
+		return &newCart, nil
+	}
+
+	return cart, nil
+}
+
+//save insert the cart on its first version, otherwise update it
+func (s *service) save(cart Cart, currentVersion int) error {
+	var err error
+	if currentVersion == 0 {
+		err = s.repository.InsertCart(cart)
+	} else {
+		err = s.repository.UpdateCart(cart, currentVersion)
+	}
+
+	if err == business.ErrZeroAffected {
+		return business.ErrHasBeenModified
+	}
+
+	return err
+}
+
+//view get the current item of every line and compute the totals, promotion which can not be applied is reported in the view
+func (s *service) view(cart Cart) (*View, error) {
+	itemByID := make(map[string]*item.Item)
+
+	for _, line := range cart.Lines {
+		item, err := s.itemService.GetItemByID(context.TODO(), line.ItemID)
+		if err != nil {
+			return nil, err
+		}
+
+		itemByID[line.ItemID] = item
+	}
+
+	view := NewView(cart, itemByID)
+
+	if len(cart.PromotionCode) == 0 || view.Total == nil {
+		return &view, nil
+	}
+
+	discount, err := s.promotionService.EvaluatePromotion(cart.PromotionCode, cart.Owner, view.PromotionLines(itemByID))
+	if errors.Is(err, business.ErrPromotionNotApplicable) {
+		view.PromotionError = err
+	} else if err != nil {
+		return nil, err
+	} else {
+		view.ApplyDiscount(discount.Amount)
+	}
+
+	return &view, nil
+}
@@ -0,0 +1,491 @@
+package cart_test
+
+import (
+	"context"
+	"errors"
+	"sample-order/business"
+	"sample-order/business/cart"
+	"sample-order/business/cart/spec"
+	"sample-order/business/item"
+	"sample-order/business/money"
+	orderSpec "sample-order/business/order/spec"
+	"sample-order/business/promotion"
+	"testing"
+	"time"
+)
+
+var cameraItemID = "5f350b7d21148431abc65290"
+var tripodItemID = "5f350b7d21148431abc65291"
+var rupiahItemID = "5f350b7d21148431abc65292"
+var notForSaleItemID = "5f350b7d21148431abc65293"
+var strapItemID = "5f350b7d21148431abc65294"
+var notFoundItemID = "5f350b7d21148431abc65299"
+var errorItemID = "error-item-id"
+var errorFind = errors.New("error on find")
+
+func TestAddItem(t *testing.T) {
+	t.Run("Expect cart created on the first item with the total", func(t *testing.T) {
+		service, _, _ := newService()
+
+		view, err := service.AddItem("session-1", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		view, _ = service.AddItem("session-1", spec.AddItemSpec{ItemID: tripodItemID, Quantity: 2})
+		view, _ = service.AddItem("session-1", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+
+		if len(view.Lines) != 2 || view.Lines[0].Quantity != 2 || view.Lines[0].ItemName != "Camera" {
+			t.Error("Expect same item merged into one line", view.Lines)
+		}
+
+		if view.Total == nil || *view.Total != (money.Money{Amount: 105000, Currency: "USD"}) || view.Outdated {
+			t.Error("Expect total is USD 1050.00", view.Total)
+		}
+
+		if view.Cart.Version != 3 {
+			t.Error("Expect version is 3 but got ", view.Cart.Version)
+		}
+	})
+
+	t.Run("Expect empty cart when the owner has none", func(t *testing.T) {
+		service, _, _ := newService()
+		view, err := service.GetCart("session-1")
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if len(view.Lines) != 0 || view.Total != nil || view.Cart.Owner != "session-1" || view.Cart.Version != 0 {
+			t.Error("Expect empty cart", view)
+		}
+	})
+
+	t.Run("Expect failed on invalid item", func(t *testing.T) {
+		service, _, _ := newService()
+
+		if _, err := service.AddItem("session-1", spec.AddItemSpec{ItemID: notFoundItemID, Quantity: 1}); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec on item not found. Error is: ", err)
+		}
+
+		if _, err := service.AddItem("session-1", spec.AddItemSpec{ItemID: notForSaleItemID, Quantity: 1}); err != business.ErrNotForSale {
+			t.Error("Expect error not for sale. Error is: ", err)
+		}
+
+		if _, err := service.AddItem("session-1", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 0}); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec on zero quantity. Error is: ", err)
+		}
+
+		if _, err := service.AddItem("", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1}); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec on empty owner. Error is: ", err)
+		}
+
+		if _, err := service.AddItem("session-1", spec.AddItemSpec{ItemID: errorItemID, Quantity: 1}); err != errorFind {
+			t.Error("Expect error on find. Error is: ", err)
+		}
+	})
+}
+
+func TestRemoveItem(t *testing.T) {
+	t.Run("Expect quantity decreased then line removed", func(t *testing.T) {
+		service, _, _ := newService()
+		service.AddItem("john", spec.AddItemSpec{ItemID: tripodItemID, Quantity: 3})
+
+		view, err := service.RemoveItem("john", tripodItemID, 1)
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if view.Lines[0].Quantity != 2 {
+			t.Error("Expect quantity is 2 but got ", view.Lines[0].Quantity)
+		}
+
+		view, _ = service.RemoveItem("john", tripodItemID, 0)
+		if len(view.Lines) != 0 || view.Total != nil {
+			t.Error("Expect empty cart", view)
+		}
+	})
+
+	t.Run("Expect failed on item not in the cart", func(t *testing.T) {
+		service, _, _ := newService()
+		service.AddItem("john", spec.AddItemSpec{ItemID: tripodItemID, Quantity: 3})
+
+		if _, err := service.RemoveItem("john", cameraItemID, 1); err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+
+		if _, err := service.RemoveItem("john", tripodItemID, -1); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec on negative quantity. Error is: ", err)
+		}
+	})
+}
+
+func TestOutdatedCart(t *testing.T) {
+	t.Run("Expect modified and deleted items detected with the current price", func(t *testing.T) {
+		service, itemService, _ := newService()
+		service.AddItem("john", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+		service.AddItem("john", spec.AddItemSpec{ItemID: tripodItemID, Quantity: 1})
+
+		itemService.modify(cameraItemID, &money.Money{Amount: 45000, Currency: "USD"})
+		delete(itemService.itemByID, tripodItemID)
+
+		view, _ := service.GetCart("john")
+
+		if !view.Outdated || view.Lines[0].Status != cart.Modified || view.Lines[1].Status != cart.Deleted {
+			t.Error("Expect camera modified and tripod deleted", view.Lines)
+		}
+
+		if *view.Total != (money.Money{Amount: 45000, Currency: "USD"}) {
+			t.Error("Expect total use the current price", view.Total)
+		}
+	})
+
+	t.Run("Expect refresh accept the current items", func(t *testing.T) {
+		service, itemService, _ := newService()
+		service.AddItem("john", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+		service.AddItem("john", spec.AddItemSpec{ItemID: tripodItemID, Quantity: 1})
+		service.AddItem("john", spec.AddItemSpec{ItemID: strapItemID, Quantity: 1})
+
+		itemService.modify(cameraItemID, &money.Money{Amount: 45000, Currency: "USD"})
+		itemService.modify(strapItemID, nil)
+
+		view, err := service.RefreshCart("john")
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if view.Outdated || len(view.Lines) != 2 || view.Lines[0].Status != cart.Available {
+			t.Error("Expect camera and tripod available", view.Lines)
+		}
+	})
+
+	t.Run("Expect line with other currency excluded from the total", func(t *testing.T) {
+		service, _, _ := newService()
+		service.AddItem("john", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+		view, _ := service.AddItem("john", spec.AddItemSpec{ItemID: rupiahItemID, Quantity: 1})
+
+		if !view.Outdated || view.Lines[1].Status != cart.CurrencyMismatch || view.Total.Amount != 50000 {
+			t.Error("Expect rupiah line flagged", view.Lines, view.Total)
+		}
+	})
+}
+
+func TestCheckoutCart(t *testing.T) {
+	t.Run("Expect cart converted into order and emptied", func(t *testing.T) {
+		service, _, orderService := newService()
+		service.AddItem("session-1", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+		service.AddItem("session-1", spec.AddItemSpec{ItemID: tripodItemID, Quantity: 2})
+
+		orderID, err := service.CheckoutCart("session-1", spec.CheckoutCartSpec{Customer: "john"})
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		created := orderService.created
+		if orderID != "order-1" || created.Customer != "john" || len(created.Lines) != 2 || created.Lines[1].Quantity != 2 {
+			t.Error("Expect order created from the cart lines", created)
+		}
+
+		view, _ := service.GetCart("session-1")
+		if len(view.Lines) != 0 {
+			t.Error("Expect cart is empty after checkout", view.Lines)
+		}
+	})
+
+	t.Run("Expect owner become the customer when not given", func(t *testing.T) {
+		service, _, orderService := newService()
+		service.AddItem("john", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+		service.CheckoutCart("john", spec.CheckoutCartSpec{})
+
+		if orderService.created.Customer != "john" {
+			t.Error("Expect customer is john but got ", orderService.created.Customer)
+		}
+	})
+
+	t.Run("Expect failed on outdated or empty cart", func(t *testing.T) {
+		service, itemService, _ := newService()
+
+		if _, err := service.CheckoutCart("john", spec.CheckoutCartSpec{}); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec on empty cart. Error is: ", err)
+		}
+
+		service.AddItem("john", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+		itemService.modify(cameraItemID, &money.Money{Amount: 45000, Currency: "USD"})
+
+		if _, err := service.CheckoutCart("john", spec.CheckoutCartSpec{}); err != business.ErrCartOutdated {
+			t.Error("Expect error cart outdated. Error is: ", err)
+		}
+
+		view, _ := service.GetCart("john")
+		if len(view.Lines) != 1 {
+			t.Error("Expect cart is unchanged", view.Lines)
+		}
+	})
+
+	t.Run("Expect checkout submitted twice create single order", func(t *testing.T) {
+		service, _, orderService := newService()
+		service.AddItem("john", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+
+		var secondErr error
+		orderService.onCreate = func() {
+			orderService.onCreate = nil
+			_, secondErr = service.CheckoutCart("john", spec.CheckoutCartSpec{})
+		}
+
+		if _, err := service.CheckoutCart("john", spec.CheckoutCartSpec{}); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		if secondErr != business.ErrInvalidSpec || orderService.count != 1 {
+			t.Error("Expect the second checkout find the cart claimed", secondErr, orderService.count)
+		}
+	})
+
+	t.Run("Expect cart restored when order is not created", func(t *testing.T) {
+		service, _, orderService := newService()
+		service.AddItem("john", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+		service.ApplyPromotion("john", spec.ApplyPromotionSpec{Code: "SAVE10"})
+		orderService.err = business.ErrInsufficientStock
+
+		if _, err := service.CheckoutCart("john", spec.CheckoutCartSpec{}); err != business.ErrInsufficientStock {
+			t.Error("Expect error insufficient stock. Error is: ", err)
+		}
+
+		view, _ := service.GetCart("john")
+		if len(view.Lines) != 1 || view.Cart.PromotionCode != "SAVE10" {
+			t.Error("Expect cart is restored", view.Lines, view.Cart.PromotionCode)
+		}
+	})
+
+	t.Run("Expect failed restore reported with the order error", func(t *testing.T) {
+		service, _, orderService := newService()
+		service.AddItem("john", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+		orderService.err = business.ErrInsufficientStock
+
+		//the owner start new cart while the order is being created
+		orderService.onCreate = func() {
+			service.AddItem("john", spec.AddItemSpec{ItemID: tripodItemID, Quantity: 1})
+		}
+
+		_, err := service.CheckoutCart("john", spec.CheckoutCartSpec{})
+		if !errors.Is(err, business.ErrInsufficientStock) || err == business.ErrInsufficientStock {
+			t.Error("Expect order error wrapped with the restore failure. Error is: ", err)
+		}
+
+		view, _ := service.GetCart("john")
+		if len(view.Lines) != 1 || view.Lines[0].ItemID != tripodItemID {
+			t.Error("Expect the new cart is kept", view.Lines)
+		}
+	})
+}
+
+func TestCartPromotion(t *testing.T) {
+	t.Run("Expect cart total discounted by applied promotion", func(t *testing.T) {
+		service, _, _ := newService()
+		service.AddItem("john", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+
+		view, err := service.ApplyPromotion("john", spec.ApplyPromotionSpec{Code: " save10 "})
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if view.Cart.PromotionCode != "SAVE10" || view.Discount.Amount != 5000 || view.GrandTotal.Amount != 45000 {
+			t.Error("Expect 10 percent discount of the cart total", view.Cart.PromotionCode, view.Discount, view.GrandTotal)
+		}
+
+		view, _ = service.GetCart("john")
+		if view.Discount == nil || view.Total.Amount != 50000 {
+			t.Error("Expect stored promotion code is evaluated on every read", view.Discount, view.Total)
+		}
+	})
+
+	t.Run("Expect failed apply promotion which is not applicable", func(t *testing.T) {
+		service, _, _ := newService()
+		service.AddItem("john", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+
+		var promotionError *business.PromotionError
+
+		_, err := service.ApplyPromotion("john", spec.ApplyPromotionSpec{Code: "EXPIRED"})
+		if !errors.As(err, &promotionError) || promotionError.Reason != promotion.ReasonExpired {
+			t.Error("Expect error promotion expired. Error is: ", err)
+		}
+
+		_, err = service.ApplyPromotion("john", spec.ApplyPromotionSpec{Code: "UNKNOWN"})
+		if !errors.Is(err, business.ErrPromotionNotApplicable) {
+			t.Error("Expect error promotion not applicable. Error is: ", err)
+		}
+
+		view, _ := service.GetCart("john")
+		if view.Cart.PromotionCode != "" {
+			t.Error("Expect promotion code is not stored", view.Cart.PromotionCode)
+		}
+	})
+
+	t.Run("Expect promotion error reported when the cart no longer match", func(t *testing.T) {
+		service, _, _ := newService()
+		service.AddItem("john", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+		service.AddItem("john", spec.AddItemSpec{ItemID: tripodItemID, Quantity: 1})
+		service.ApplyPromotion("john", spec.ApplyPromotionSpec{Code: "TRIPOD"})
+
+		view, err := service.RemoveItem("john", tripodItemID, 0)
+
+		if err != nil || !errors.Is(view.PromotionError, business.ErrPromotionNotApplicable) || view.Discount != nil {
+			t.Error("Expect promotion error in the view", err, view.PromotionError, view.Discount)
+		}
+	})
+
+	t.Run("Expect promotion code removed and passed into order on checkout", func(t *testing.T) {
+		service, _, orderService := newService()
+		service.AddItem("john", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+		service.ApplyPromotion("john", spec.ApplyPromotionSpec{Code: "SAVE10"})
+		service.CheckoutCart("john", spec.CheckoutCartSpec{})
+
+		if orderService.created.PromotionCode != "SAVE10" {
+			t.Error("Expect order created with the promotion code but got ", orderService.created.PromotionCode)
+		}
+
+		service.AddItem("jane", spec.AddItemSpec{ItemID: cameraItemID, Quantity: 1})
+		service.ApplyPromotion("jane", spec.ApplyPromotionSpec{Code: "SAVE10"})
+
+		view, err := service.RemovePromotion("jane")
+		if err != nil || view.Cart.PromotionCode != "" || view.Discount != nil {
+			t.Error("Expect promotion removed from the cart", err, view)
+		}
+	})
+}
+
+func newService() (cart.Service, *inMemoryItemService, *inMemoryOrderService) {
+	itemService := newInMemoryItemService()
+	orderService := &inMemoryOrderService{}
+	return cart.NewService(newInMemoryRepository(), itemService, orderService, newInMemoryPromotionService()), itemService, orderService
+}
+
+type inMemoryItemService struct {
+	itemByID map[string]item.Item
+}
+
+func newInMemoryItemService() *inMemoryItemService {
+	return &inMemoryItemService{map[string]item.Item{
+		cameraItemID:     {ID: cameraItemID, Name: "Camera", SalePrice: &money.Money{Amount: 50000, Currency: "USD"}, Version: 1},
+		tripodItemID:     {ID: tripodItemID, Name: "Tripod", SalePrice: &money.Money{Amount: 2500, Currency: "USD"}, Version: 1},
+		rupiahItemID:     {ID: rupiahItemID, Name: "Bag", SalePrice: &money.Money{Amount: 150000, Currency: "IDR"}, Version: 1},
+		notForSaleItemID: {ID: notForSaleItemID, Name: "Lens", Version: 1},
+		strapItemID:      {ID: strapItemID, Name: "Strap", SalePrice: &money.Money{Amount: 500, Currency: "USD"}, Version: 1},
+	}}
+}
+
+func (s *inMemoryItemService) GetItemByID(ctx context.Context, ID string) (*item.Item, error) {
+	if ID == errorItemID {
+		return nil, errorFind
+	}
+
+	current, ok := s.itemByID[ID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &current, nil
+}
+
+func (s *inMemoryItemService) modify(ID string, salePrice *money.Money) {
+	current := s.itemByID[ID]
+	current.SalePrice = salePrice
+	current.Version++
+	s.itemByID[ID] = current
+}
+
+type inMemoryOrderService struct {
+	created  orderSpec.CreateOrderSpec
+	count    int
+	err      error
+	onCreate func()
+}
+
+func (s *inMemoryOrderService) CreateOrder(createOrderSpec orderSpec.CreateOrderSpec, createdBy string) (string, error) {
+	if s.onCreate != nil {
+		s.onCreate()
+	}
+
+	if s.err != nil {
+		return "", s.err
+	}
+
+	s.created = createOrderSpec
+	s.count++
+	return "order-1", nil
+}
+
+type inMemoryPromotionService struct {
+	promotionByCode map[string]promotion.Promotion
+}
+
+func newInMemoryPromotionService() *inMemoryPromotionService {
+	lastWeek := time.Now().AddDate(0, 0, -7)
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	return &inMemoryPromotionService{map[string]promotion.Promotion{
+		"SAVE10":  {ID: "promotion-1", Code: "SAVE10", Type: promotion.Percentage, Percent: 10, ValidFrom: lastWeek},
+		"EXPIRED": {ID: "promotion-2", Code: "EXPIRED", Type: promotion.Percentage, Percent: 10, ValidFrom: lastWeek, ValidUntil: &yesterday},
+		"TRIPOD":  {ID: "promotion-3", Code: "TRIPOD", Type: promotion.Percentage, Percent: 50, ItemIDs: []string{tripodItemID}, ValidFrom: lastWeek},
+	}}
+}
+
+func (s *inMemoryPromotionService) EvaluatePromotion(code string, user string, lines []promotion.Line) (*promotion.Discount, error) {
+	current, ok := s.promotionByCode[code]
+	if !ok {
+		return nil, &business.PromotionError{Code: code, Reason: promotion.ReasonUnknownCode}
+	}
+
+	discount, err := current.Evaluate(lines, 0, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &discount, nil
+}
+
+type inMemoryRepository struct {
+	cartByOwner map[string]cart.Cart
+}
+
+func newInMemoryRepository() *inMemoryRepository {
+	return &inMemoryRepository{make(map[string]cart.Cart)}
+}
+
+func (repo *inMemoryRepository) FindCartByOwner(owner string) (*cart.Cart, error) {
+	current, ok := repo.cartByOwner[owner]
+	if !ok {
+		return nil, nil
+	}
+
+	return &current, nil
+}
+
+func (repo *inMemoryRepository) InsertCart(cart cart.Cart) error {
+	if _, ok := repo.cartByOwner[cart.Owner]; ok {
+		return business.ErrZeroAffected
+	}
+
+	repo.cartByOwner[cart.Owner] = cart
+	return nil
+}
+
+func (repo *inMemoryRepository) UpdateCart(cart cart.Cart, currentVersion int) error {
+	current, ok := repo.cartByOwner[cart.Owner]
+	if !ok || current.Version != currentVersion {
+		return business.ErrZeroAffected
+	}
+
+	repo.cartByOwner[cart.Owner] = cart
+	return nil
+}
+
+func (repo *inMemoryRepository) DeleteCart(owner string, currentVersion int) error {
+	current, ok := repo.cartByOwner[owner]
+	if !ok || current.Version != currentVersion {
+		return business.ErrZeroAffected
+	}
+
+	delete(repo.cartByOwner, owner)
+	return nil
+}
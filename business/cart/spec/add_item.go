@@ -0,0 +1,7 @@
+package spec
+
+//AddItemSpec add item into cart spec
+type AddItemSpec struct {
+	ItemID   string `validate:"required"`
+	Quantity int    `validate:"required,gt=0"`
+}
@@ -0,0 +1,6 @@
+package spec
+
+//ApplyPromotionSpec set promotion code of the cart spec
+type ApplyPromotionSpec struct {
+	Code string `validate:"required,max=50"`
+}
@@ -0,0 +1,6 @@
+package spec
+
+//CheckoutCartSpec convert cart into order spec. The cart owner become the customer when it is empty
+type CheckoutCartSpec struct {
+	Customer string
+}
@@ -1,6 +1,9 @@
 package business
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	//ErrHasBeenModified Error when update item that has been modified
@@ -14,4 +17,67 @@ var (
 
 	//ErrZeroAffected Data not found
 	ErrZeroAffected = errors.New("No record affected")
+
+	//ErrInsufficientStock Error when stock quantity is not enough for the requested decrement
+	ErrInsufficientStock = errors.New("Insufficient stock")
+
+	//ErrBookingConflict Error when there are not enough free units for the whole booking period
+	ErrBookingConflict = errors.New("Booking conflict with existing bookings")
+
+	//ErrInvalidTransition Error when the requested status can not be reached from the current status
+	ErrInvalidTransition = errors.New("Invalid status transition")
+
+	//ErrNotRentable Error when item has no rental rate or pricing rule
+	ErrNotRentable = errors.New("Item is not rentable")
+
+	//ErrNotForSale Error when ordered item has no sale price
+	ErrNotForSale = errors.New("Item is not for sale")
+
+	//ErrReservationExpired Error when held stock is used after the reservation expired
+	ErrReservationExpired = errors.New("Reservation has expired")
+
+	//ErrCartOutdated Error when checkout cart which items have been modified or deleted since they were added
+	ErrCartOutdated = errors.New("Cart has outdated items")
+
+	//ErrDuplicateCode Error when the code is already used by other data
+	ErrDuplicateCode = errors.New("Code is already used")
+
+	//ErrPromotionNotApplicable Error when the promotion can not be applied into the cart or order
+	ErrPromotionNotApplicable = errors.New("Promotion is not applicable")
+
+	//ErrItemInUse Error when delete item which still has stock on hand, active reservation or active booking
+	ErrItemInUse = errors.New("Item is still in use")
+
+	//ErrCheckpointMismatch Error when resume copy from checkpoint saved for other source or target database
+	ErrCheckpointMismatch = errors.New("Checkpoint belongs to other source or target")
 )
+
+//TransitionError Error when state machine reject a status change, it match ErrInvalidTransition using errors.Is
+type TransitionError struct {
+	From string
+	To   string
+}
+
+func (err *TransitionError) Error() string {
+	return fmt.Sprintf("Invalid status transition from %s to %s", err.From, err.To)
+}
+
+//Is Return true if target is ErrInvalidTransition
+func (err *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+//PromotionError Error when promotion can not be applied with the reason, it match ErrPromotionNotApplicable using errors.Is
+type PromotionError struct {
+	Code   string
+	Reason string
+}
+
+func (err *PromotionError) Error() string {
+	return fmt.Sprintf("Promotion %s is not applicable: %s", err.Code, err.Reason)
+}
+
+//Is Return true if target is ErrPromotionNotApplicable
+func (err *PromotionError) Is(target error) bool {
+	return target == ErrPromotionNotApplicable
+}
@@ -0,0 +1,142 @@
+package item
+
+import "sync"
+
+//subscriberBufferSize number of events waiting for slow subscriber before it is disconnected
+const subscriberBufferSize = 64
+
+//Broadcaster Listener which fan out item events to subscribers and keep the latest events in bounded replay buffer,
+//so subscriber which reconnect can resume from the last event it received
+type Broadcaster struct {
+	lock        sync.Mutex
+	replaySize  int
+	replay      []Event
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+//Subscription stream of item events of single subscriber. The channel is closed when the subscriber can not keep up
+//or the broadcaster is closed, subscriber should reconnect with the last received event ID
+type Subscription struct {
+	tag         string
+	events      chan Event
+	broadcaster *Broadcaster
+}
+
+//NewBroadcaster Generate broadcaster which keep at most replaySize latest events
+func NewBroadcaster(replaySize int) *Broadcaster {
+	return &Broadcaster{
+		replaySize:  replaySize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+//ItemChanged Keep the event for replay and send it to every subscriber interested in the item.
+//Slow subscriber is disconnected instead of blocking the item service
+func (broadcaster *Broadcaster) ItemChanged(event Event) {
+	broadcaster.lock.Lock()
+	defer broadcaster.lock.Unlock()
+
+	if broadcaster.closed {
+		return
+	}
+
+	broadcaster.replay = append(broadcaster.replay, event)
+	if len(broadcaster.replay) > broadcaster.replaySize {
+		broadcaster.replay = broadcaster.replay[len(broadcaster.replay)-broadcaster.replaySize:]
+	}
+
+	for subscription := range broadcaster.subscribers {
+		if !subscription.matches(event) {
+			continue
+		}
+
+		select {
+		case subscription.events <- event:
+		default:
+			broadcaster.remove(subscription)
+		}
+	}
+}
+
+//Subscribe Start receiving events of items with given tag, empty tag means every item. Events after lastEventID which
+//are still in the replay buffer are returned first. Resumed is false when lastEventID is given but no longer in the buffer,
+//so events may have been missed and subscriber should reload the items
+func (broadcaster *Broadcaster) Subscribe(lastEventID string, tag string) (subscription *Subscription, missed []Event, resumed bool) {
+	broadcaster.lock.Lock()
+	defer broadcaster.lock.Unlock()
+
+	subscription = &Subscription{
+		tag,
+		make(chan Event, subscriberBufferSize),
+		broadcaster,
+	}
+
+	if broadcaster.closed {
+		close(subscription.events)
+		return subscription, nil, true
+	}
+
+	broadcaster.subscribers[subscription] = struct{}{}
+
+	if len(lastEventID) == 0 {
+		return subscription, nil, true
+	}
+
+	position := -1
+	for idx, event := range broadcaster.replay {
+		if event.ID == lastEventID {
+			position = idx
+			break
+		}
+	}
+
+	if position == -1 {
+		return subscription, nil, false
+	}
+
+	for _, event := range broadcaster.replay[position+1:] {
+		if subscription.matches(event) {
+			missed = append(missed, event)
+		}
+	}
+
+	return subscription, missed, true
+}
+
+//Close Disconnect every subscriber and stop accepting new one, used when the server shutting down
+func (broadcaster *Broadcaster) Close() {
+	broadcaster.lock.Lock()
+	defer broadcaster.lock.Unlock()
+
+	broadcaster.closed = true
+
+	for subscription := range broadcaster.subscribers {
+		broadcaster.remove(subscription)
+	}
+}
+
+//remove must be called while holding the lock
+func (broadcaster *Broadcaster) remove(subscription *Subscription) {
+	if _, ok := broadcaster.subscribers[subscription]; ok {
+		delete(broadcaster.subscribers, subscription)
+		close(subscription.events)
+	}
+}
+
+//Events Return channel of new events, it is closed when the subscription end
+func (subscription *Subscription) Events() <-chan Event {
+	return subscription.events
+}
+
+//Close Stop receiving events
+func (subscription *Subscription) Close() {
+	subscription.broadcaster.lock.Lock()
+	defer subscription.broadcaster.lock.Unlock()
+
+	subscription.broadcaster.remove(subscription)
+}
+
+func (subscription *Subscription) matches(event Event) bool {
+	return len(subscription.tag) == 0 || event.HasTag(subscription.tag)
+}
@@ -0,0 +1,107 @@
+package item_test
+
+import (
+	"sample-order/business/item"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestBroadcaster(t *testing.T) {
+	t.Run("Expect subscriber receive events of its tag", func(t *testing.T) {
+		broadcaster := item.NewBroadcaster(10)
+
+		all, _, _ := broadcaster.Subscribe("", "")
+		tagged, _, _ := broadcaster.Subscribe("", "tag3")
+
+		broadcaster.ItemChanged(item.NewCreatedEvent("event-1", item1))
+		broadcaster.ItemChanged(item.NewCreatedEvent("event-2", item2))
+
+		if received := drain(all); len(received) != 2 {
+			t.Error("Expect subscriber without tag receive every event", received)
+		}
+
+		if received := drain(tagged); len(received) != 1 || received[0].ID != "event-2" {
+			t.Error("Expect subscriber with tag receive only event of item with the tag", received)
+		}
+	})
+
+	t.Run("Expect deleted item match its tag before deleted", func(t *testing.T) {
+		broadcaster := item.NewBroadcaster(10)
+		tagged, _, _ := broadcaster.Subscribe("", "tag1")
+
+		broadcaster.ItemChanged(item.NewDeletedEvent("event-1", item1, "deleter", time.Now()))
+
+		if received := drain(tagged); len(received) != 1 || received[0].Type != item.ItemDeleted {
+			t.Error("Expect deleted event is received", received)
+		}
+	})
+
+	t.Run("Expect resume from last event ID inside replay buffer", func(t *testing.T) {
+		broadcaster := item.NewBroadcaster(3)
+		for idx := 1; idx <= 5; idx++ {
+			broadcaster.ItemChanged(item.NewCreatedEvent("event-"+strconv.Itoa(idx), item1))
+		}
+
+		_, missed, resumed := broadcaster.Subscribe("event-3", "")
+		if !resumed || len(missed) != 2 || missed[0].ID != "event-4" || missed[1].ID != "event-5" {
+			t.Error("Expect events after the last event ID are replayed", missed)
+		}
+
+		_, missed, resumed = broadcaster.Subscribe("event-1", "")
+		if resumed || len(missed) != 0 {
+			t.Error("Expect not resumed when last event ID is no longer kept")
+		}
+	})
+
+	t.Run("Expect slow subscriber is disconnected", func(t *testing.T) {
+		broadcaster := item.NewBroadcaster(10)
+		slow, _, _ := broadcaster.Subscribe("", "")
+
+		for idx := 0; idx < 100; idx++ {
+			broadcaster.ItemChanged(item.NewCreatedEvent(strconv.Itoa(idx), item1))
+		}
+
+		received := drain(slow)
+		if len(received) == 0 || len(received) == 100 {
+			t.Error("Expect subscriber receive buffered events only", len(received))
+		}
+
+		if _, ok := <-slow.Events(); ok {
+			t.Error("Expect events channel is closed")
+		}
+	})
+
+	t.Run("Expect close end every subscription", func(t *testing.T) {
+		broadcaster := item.NewBroadcaster(10)
+		subscription, _, _ := broadcaster.Subscribe("", "")
+
+		broadcaster.Close()
+		subscription.Close()
+
+		if _, ok := <-subscription.Events(); ok {
+			t.Error("Expect events channel is closed")
+		}
+
+		late, _, _ := broadcaster.Subscribe("", "")
+		if _, ok := <-late.Events(); ok {
+			t.Error("Expect subscription after close is ended immediately")
+		}
+	})
+}
+
+//drain Return the events waiting in the subscription without blocking
+func drain(subscription *item.Subscription) []item.Event {
+	var events []item.Event
+	for {
+		select {
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package item
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sample-order/business"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ChangeToken position in the change feed, the changes after it are returned next. Zero token means from the beginning.
+//MySQL order the changes by Seq while MongoDB order them by ModifiedAt then ID
+type ChangeToken struct {
+	Seq        int64
+	ModifiedAt time.Time
+	ID         string
+}
+
+//IsZero Return true if the token point into the beginning of the feed
+func (token ChangeToken) IsZero() bool {
+	return token.Seq == 0 && token.ModifiedAt.IsZero() && token.ID == ""
+}
+
+//String Encode the token into opaque string given to the client, zero token is empty string
+func (token ChangeToken) String() string {
+	if token.IsZero() {
+		return ""
+	}
+
+	var modifiedAt int64
+	if !token.ModifiedAt.IsZero() {
+		modifiedAt = token.ModifiedAt.UnixNano()
+	}
+
+	raw := fmt.Sprintf("%d.%d.%s", token.Seq, modifiedAt, token.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+//ParseChangeToken Decode token produced by ChangeToken.String, empty string is zero token.
+//Will return business.ErrInvalidSpec when the token is malformed
+func ParseChangeToken(value string) (ChangeToken, error) {
+	if value == "" {
+		return ChangeToken{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return ChangeToken{}, business.ErrInvalidSpec
+	}
+
+	parts := strings.SplitN(string(raw), ".", 3)
+	if len(parts) != 3 {
+		return ChangeToken{}, business.ErrInvalidSpec
+	}
+
+	seq, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || seq < 0 {
+		return ChangeToken{}, business.ErrInvalidSpec
+	}
+
+	modifiedAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return ChangeToken{}, business.ErrInvalidSpec
+	}
+
+	token := ChangeToken{Seq: seq, ID: parts[2]}
+	if modifiedAt != 0 {
+		token.ModifiedAt = time.Unix(0, modifiedAt).UTC()
+	}
+
+	return token, nil
+}
+
+//Change latest state of single item in the change feed. Deleted change is a tombstone without item
+type Change struct {
+	ItemID     string
+	Deleted    bool
+	Item       *Item
+	Version    int
+	ModifiedAt time.Time
+	ModifiedBy string
+	Token      ChangeToken
+}
+
+//ChangeFeed page of changes in modification order. Next is given back to get the following changes,
+//it is the requested token when there is no new change
+type ChangeFeed struct {
+	Changes []Change
+	Next    ChangeToken
+	HasMore bool
+}
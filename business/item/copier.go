@@ -0,0 +1,183 @@
+package item
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sample-order/business"
+	"sort"
+	"strings"
+	"time"
+)
+
+//Checkpoint progress of copying items, used to resume interrupted copy.
+//Source and Target identify the databases the progress belongs to
+type Checkpoint struct {
+	Source    string
+	Target    string
+	LastID    string
+	Copied    int
+	UpdatedAt time.Time
+}
+
+//CheckpointStore outgoing port to persist copy progress
+type CheckpointStore interface {
+	//Load If there is no checkpoint will return nil without error
+	Load() (*Checkpoint, error)
+
+	Save(checkpoint Checkpoint) error
+}
+
+//VerifyReport comparison result between source and target repository
+type VerifyReport struct {
+	SourceCount    int
+	TargetCount    int
+	SourceChecksum string
+	TargetChecksum string
+}
+
+//Match Return true when both repository have identical items
+func (report *VerifyReport) Match() bool {
+	return report.SourceCount == report.TargetCount && report.SourceChecksum == report.TargetChecksum
+}
+
+//Copier copy all items from one repository into another repository while preserving the item ID
+type Copier struct {
+	source      Repository
+	sourceName  string
+	target      Repository
+	targetName  string
+	checkpoints CheckpointStore
+	batchSize   int
+}
+
+//NewCopier Construct copier object. The names identify the source and target database in the checkpoint
+func NewCopier(source Repository, sourceName string, target Repository, targetName string, checkpoints CheckpointStore, batchSize int) *Copier {
+	return &Copier{
+		source,
+		sourceName,
+		target,
+		targetName,
+		checkpoints,
+		batchSize,
+	}
+}
+
+//Copy Copy items in ID order, resuming from the last saved checkpoint.
+//progress is called after every copied batch. Will return business.ErrCheckpointMismatch
+//when the checkpoint was saved while copying between other databases
+func (c *Copier) Copy(ctx context.Context, progress func(checkpoint Checkpoint)) (*Checkpoint, error) {
+	checkpoint, err := c.checkpoints.Load()
+	if err != nil {
+		return nil, err
+	} else if checkpoint == nil {
+		checkpoint = &Checkpoint{Source: c.sourceName, Target: c.targetName}
+	} else if checkpoint.Source != c.sourceName || checkpoint.Target != c.targetName {
+		return nil, business.ErrCheckpointMismatch
+	}
+
+	//the batch after checkpoint may have been stored partially before interrupted
+	isFirstBatch := true
+
+	for {
+		items, err := c.source.FindItemsAfterID(ctx, checkpoint.LastID, c.batchSize)
+		if err != nil {
+			return checkpoint, err
+		} else if len(items) == 0 {
+			return checkpoint, nil
+		}
+
+		pending := items
+		if isFirstBatch {
+			if pending, err = c.excludeExisting(ctx, items); err != nil {
+				return checkpoint, err
+			}
+			isFirstBatch = false
+		}
+
+		if len(pending) > 0 {
+			//copied items are not new to other systems, so no event is written
+			if err = c.target.InsertItems(ctx, pending, nil); err != nil {
+				return checkpoint, err
+			}
+		}
+
+		checkpoint.LastID = items[len(items)-1].ID
+		checkpoint.Copied += len(items)
+		checkpoint.UpdatedAt = time.Now()
+
+		if err = c.checkpoints.Save(*checkpoint); err != nil {
+			return checkpoint, err
+		}
+
+		if progress != nil {
+			progress(*checkpoint)
+		}
+	}
+}
+
+//Verify Compare number of items and checksum of all items between source and target
+func (c *Copier) Verify(ctx context.Context) (*VerifyReport, error) {
+	var report VerifyReport
+	var err error
+
+	if report.SourceCount, report.SourceChecksum, err = c.checksum(ctx, c.source); err != nil {
+		return nil, err
+	}
+
+	if report.TargetCount, report.TargetChecksum, err = c.checksum(ctx, c.target); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func (c *Copier) excludeExisting(ctx context.Context, items []Item) ([]Item, error) {
+	var pending []Item
+
+	for _, item := range items {
+		existing, err := c.target.FindItemByID(ctx, item.ID)
+		if err != nil {
+			return nil, err
+		} else if existing == nil {
+			pending = append(pending, item)
+		}
+	}
+
+	return pending, nil
+}
+
+//checksum Calculate hash of all items in ID order. The value normalized into what
+//both database able to keep, time in second precision and tags in sorted order
+func (c *Copier) checksum(ctx context.Context, repository Repository) (int, string, error) {
+	hash := sha256.New()
+	count := 0
+	lastID := ""
+
+	for {
+		items, err := repository.FindItemsAfterID(ctx, lastID, c.batchSize)
+		if err != nil {
+			return 0, "", err
+		} else if len(items) == 0 {
+			break
+		}
+
+		for _, item := range items {
+			tags := append([]string{}, item.Tags...)
+			sort.Strings(tags)
+
+			fmt.Fprintf(hash, "%s\x00%s\x00%s\x00%s\x00%v\x00%v\x00%d\x00%s\x00%d\x00%s\x00%d\n",
+				item.ID, item.Name, item.Description, strings.Join(tags, ","),
+				item.SalePrice, item.RentalRate,
+				item.CreatedAt.Unix(), item.CreatedBy,
+				item.ModifiedAt.Unix(), item.ModifiedBy,
+				item.Version)
+		}
+
+		count += len(items)
+		lastID = items[len(items)-1].ID
+	}
+
+	return count, hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,136 @@
+package item_test
+
+import (
+	"sample-order/business"
+	"sample-order/business/item"
+	"testing"
+	"time"
+)
+
+func TestCopyItems(t *testing.T) {
+	t.Run("Expect copy all items and verification match", func(t *testing.T) {
+		source := newInMemoryRepository()
+		target := newEmptyInMemoryRepository()
+		checkpoints := &inMemoryCheckpointStore{}
+
+		copier := item.NewCopier(&source, "source", &target, "target", checkpoints, 1)
+
+		progressCount := 0
+		result, err := copier.Copy(ctx, func(checkpoint item.Checkpoint) {
+			progressCount++
+		})
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if result.Copied != 2 || progressCount != 2 {
+			t.Error("Expect two items copied in two batches", result, progressCount)
+		}
+
+		if result.LastID != item2.ID || checkpoints.saved == nil || checkpoints.saved.LastID != item2.ID {
+			t.Error("Expect checkpoint saved at the last item")
+		}
+
+		if checkpoints.saved.Source != "source" || checkpoints.saved.Target != "target" {
+			t.Error("Expect checkpoint saved with the databases", checkpoints.saved)
+		}
+
+		if len(target.events) != 0 {
+			t.Error("Expect no event written for copied items")
+		}
+
+		report, err := copier.Verify(ctx)
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if !report.Match() || report.TargetCount != 2 {
+			t.Error("Expect source and target match", report)
+		}
+	})
+
+	t.Run("Expect resume from checkpoint and skip already copied items", func(t *testing.T) {
+		source := newInMemoryRepository()
+		target := newEmptyInMemoryRepository()
+
+		//item1 copied but the checkpoint was not saved before interrupted
+		target.InsertItems(ctx, []item.Item{item1}, nil)
+		checkpoints := &inMemoryCheckpointStore{}
+
+		result, err := item.NewCopier(&source, "source", &target, "target", checkpoints, 10).Copy(ctx, nil)
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if result.Copied != 2 {
+			t.Error("Expect two items copied", result)
+		}
+
+		if len(target.itemByID) != 2 {
+			t.Error("Expect target has two items")
+		}
+	})
+
+	t.Run("Expect refuse to resume checkpoint of other databases", func(t *testing.T) {
+		source := newInMemoryRepository()
+		target := newEmptyInMemoryRepository()
+		checkpoints := &inMemoryCheckpointStore{&item.Checkpoint{Source: "source", Target: "other", LastID: item1.ID, Copied: 1}}
+
+		_, err := item.NewCopier(&source, "source", &target, "target", checkpoints, 10).Copy(ctx, nil)
+
+		if err != business.ErrCheckpointMismatch {
+			t.Error("Expect error checkpoint mismatch. Error is: ", err)
+		}
+
+		if len(target.itemByID) != 0 || checkpoints.saved.Target != "other" {
+			t.Error("Expect nothing copied and the checkpoint kept")
+		}
+	})
+
+	t.Run("Expect verification detect different item", func(t *testing.T) {
+		source := newInMemoryRepository()
+		target := newEmptyInMemoryRepository()
+		target.InsertItems(ctx, []item.Item{item1}, nil)
+
+		changedItem := item2
+		changedItem.Name = "changed"
+		target.InsertItems(ctx, []item.Item{changedItem}, nil)
+
+		report, _ := item.NewCopier(&source, "source", &target, "target", &inMemoryCheckpointStore{}, 10).Verify(ctx)
+
+		if report.Match() {
+			t.Error("Expect verification not match")
+		}
+
+		if report.SourceCount != report.TargetCount {
+			t.Error("Expect count is equal")
+		}
+	})
+}
+
+func newEmptyInMemoryRepository() inMemoryRepository {
+	var repo inMemoryRepository
+	repo.itemByID = make(map[string]item.Item)
+	repo.itemByTag = make(map[string][]item.Item)
+
+	return repo
+}
+
+type inMemoryCheckpointStore struct {
+	saved *item.Checkpoint
+}
+
+func (store *inMemoryCheckpointStore) Load() (*item.Checkpoint, error) {
+	return store.saved, nil
+}
+
+func (store *inMemoryCheckpointStore) Save(checkpoint item.Checkpoint) error {
+	checkpoint.UpdatedAt = time.Now()
+	store.saved = &checkpoint
+	return nil
+}
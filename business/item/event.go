@@ -0,0 +1,85 @@
+package item
+
+import "time"
+
+//EventType kind of item lifecycle event
+type EventType string
+
+const (
+	//ItemCreated item has been created, the event has no before state
+	ItemCreated EventType = "ItemCreated"
+	//ItemUpdated item has been modified
+	ItemUpdated EventType = "ItemUpdated"
+	//ItemDeleted item has been deleted, the event has no after state
+	ItemDeleted EventType = "ItemDeleted"
+)
+
+//Event item lifecycle change, stored into the outbox together with the change itself.
+//Version is the item version after the change, or the deleted version on ItemDeleted
+type Event struct {
+	ID         string
+	Type       EventType
+	ItemID     string
+	Version    int
+	Before     *Item
+	After      *Item
+	Actor      string
+	OccurredAt time.Time
+}
+
+//NewCreatedEvent create event of new item
+func NewCreatedEvent(id string, created Item) Event {
+	return Event{
+		ID:         id,
+		Type:       ItemCreated,
+		ItemID:     created.ID,
+		Version:    created.Version,
+		After:      &created,
+		Actor:      created.CreatedBy,
+		OccurredAt: created.CreatedAt,
+	}
+}
+
+//NewUpdatedEvent create event of modified item
+func NewUpdatedEvent(id string, before Item, after Item) Event {
+	return Event{
+		ID:         id,
+		Type:       ItemUpdated,
+		ItemID:     after.ID,
+		Version:    after.Version,
+		Before:     &before,
+		After:      &after,
+		Actor:      after.ModifiedBy,
+		OccurredAt: after.ModifiedAt,
+	}
+}
+
+//NewDeletedEvent create event of deleted item
+func NewDeletedEvent(id string, deleted Item, deleter string, deletedAt time.Time) Event {
+	return Event{
+		ID:         id,
+		Type:       ItemDeleted,
+		ItemID:     deleted.ID,
+		Version:    deleted.Version,
+		Before:     &deleted,
+		Actor:      deleter,
+		OccurredAt: deletedAt,
+	}
+}
+
+//HasTag Return true if the item has the tag before or after the change
+func (event *Event) HasTag(tag string) bool {
+	for _, state := range []*Item{event.Before, event.After} {
+		if state == nil {
+			continue
+		}
+
+		for _, itemTag := range state.Tags {
+			if itemTag == tag {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,127 @@
+package item
+
+import (
+	"context"
+	"sample-order/business"
+	"sample-order/business/item/spec"
+	"sample-order/util"
+	"time"
+)
+
+//importBatchSize number of item inserted into repository at once
+const importBatchSize = 100
+
+//ImportRow single row parsed from import source. Err is filled when the row cannot be parsed
+type ImportRow struct {
+	Line int
+	Spec spec.UpsertItemSpec
+	Err  error
+}
+
+//ImportResult outcome of single imported row. ID is empty on dry run or when failed
+type ImportResult struct {
+	Line int
+	ID   string
+	Err  error
+}
+
+//ImportReport summary of bulk import
+type ImportReport struct {
+	DryRun    bool
+	Total     int
+	Succeeded int
+	Failed    int
+	Results   []ImportResult
+}
+
+//ImportItems Validate each row and insert the valid one in batches, rows of failed batch are retried one by one.
+//On dry run the rows are only validated and nothing is stored
+func (s *service) ImportItems(ctx context.Context, rows []ImportRow, createdBy string, dryRun bool) (*ImportReport, error) {
+	results := make([]ImportResult, len(rows))
+
+	//batch hold the index of rows waiting to be inserted
+	var batch []int
+	var batchItems []Item
+	var batchEvents []Event
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := s.repository.InsertItems(ctx, batchItems, batchEvents); err == nil {
+			s.notify(batchEvents...)
+
+			for idx, rowIdx := range batch {
+				results[rowIdx].ID = batchItems[idx].ID
+			}
+		} else {
+			//retry the rows one by one, so only the row which cannot be stored is reported as failed
+			for idx, rowIdx := range batch {
+				if err := s.repository.InsertItems(ctx, batchItems[idx:idx+1], batchEvents[idx:idx+1]); err != nil {
+					results[rowIdx].Err = err
+					continue
+				}
+
+				s.notify(batchEvents[idx])
+				results[rowIdx].ID = batchItems[idx].ID
+			}
+		}
+
+		batch = nil
+		batchItems = nil
+		batchEvents = nil
+	}
+
+	now := time.Now()
+
+	for idx, row := range rows {
+		results[idx].Line = row.Line
+
+		if row.Err != nil {
+			results[idx].Err = row.Err
+			continue
+		}
+
+		if err := s.validate.Struct(row.Spec); err != nil {
+			results[idx].Err = business.ErrInvalidSpec
+			continue
+		}
+
+		if dryRun {
+			continue
+		}
+
+		item := NewItem(
+			util.GenerateID(),
+			row.Spec.Name,
+			row.Spec.Description,
+			row.Spec.Tags,
+			toMoney(row.Spec.SalePrice),
+			toMoney(row.Spec.RentalRate),
+			createdBy,
+			now,
+		)
+
+		batch = append(batch, idx)
+		batchItems = append(batchItems, item)
+		batchEvents = append(batchEvents, NewCreatedEvent(util.GenerateID(), item))
+
+		if len(batch) == importBatchSize {
+			flush()
+		}
+	}
+
+	flush()
+
+	report := &ImportReport{DryRun: dryRun, Total: len(rows), Results: results}
+	for _, result := range results {
+		if result.Err != nil {
+			report.Failed++
+		} else {
+			report.Succeeded++
+		}
+	}
+
+	return report, nil
+}
@@ -1,6 +1,9 @@
 package item
 
-import "time"
+import (
+	"sample-order/business/money"
+	"time"
+)
 
 //Item product item that available to rent or sell
 type Item struct {
@@ -8,6 +11,8 @@ type Item struct {
 	Name        string
 	Description string
 	Tags        []string
+	SalePrice   *money.Money //nil when item is not for sale
+	RentalRate  *money.Money //price per day, nil when item is not for rent
 	CreatedAt   time.Time
 	CreatedBy   string
 	ModifiedAt  time.Time
@@ -21,6 +26,8 @@ func NewItem(
 	name string,
 	description string,
 	tags []string,
+	salePrice *money.Money,
+	rentalRate *money.Money,
 	creator string,
 	createdAt time.Time) Item {
 
@@ -29,6 +36,8 @@ func NewItem(
 		Name:        name,
 		Description: description,
 		Tags:        tags,
+		SalePrice:   salePrice,
+		RentalRate:  rentalRate,
 		CreatedAt:   createdAt,
 		CreatedBy:   creator,
 		ModifiedAt:  createdAt,
@@ -38,12 +47,14 @@ func NewItem(
 }
 
 //ModifyItem update existing item data
-func (oldItem *Item) ModifyItem(newName string, newDescription string, newTags []string, updater string, modifiedAt time.Time) Item {
+func (oldItem *Item) ModifyItem(newName string, newDescription string, newTags []string, newSalePrice *money.Money, newRentalRate *money.Money, updater string, modifiedAt time.Time) Item {
 	return Item{
 		ID:          oldItem.ID,
 		Name:        newName,
 		Description: newDescription,
 		Tags:        newTags,
+		SalePrice:   newSalePrice,
+		RentalRate:  newRentalRate,
 		CreatedAt:   oldItem.CreatedAt,
 		CreatedBy:   oldItem.CreatedBy,
 		ModifiedAt:  modifiedAt,
@@ -51,3 +62,38 @@ func (oldItem *Item) ModifyItem(newName string, newDescription string, newTags [
 		Version:     oldItem.Version + 1,
 	}
 }
+
+//PriceRange filter of item sale price, nil boundary means unbounded.
+//Both boundary must have the same currency
+type PriceRange struct {
+	Min *money.Money
+	Max *money.Money
+}
+
+//IsEmpty Return true if there is no boundary
+func (priceRange PriceRange) IsEmpty() bool {
+	return priceRange.Min == nil && priceRange.Max == nil
+}
+
+//Contains Return true if given price is inside the range. Price with other currency or nil price is outside the range
+func (priceRange PriceRange) Contains(price *money.Money) bool {
+	if priceRange.IsEmpty() {
+		return true
+	} else if price == nil {
+		return false
+	}
+
+	if priceRange.Min != nil {
+		if result, err := price.Compare(*priceRange.Min); err != nil || result < 0 {
+			return false
+		}
+	}
+
+	if priceRange.Max != nil {
+		if result, err := price.Compare(*priceRange.Max); err != nil || result > 0 {
+			return false
+		}
+	}
+
+	return true
+}
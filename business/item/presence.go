@@ -0,0 +1,236 @@
+package item
+
+import (
+	"sample-order/util"
+	"sort"
+	"sync"
+	"time"
+)
+
+//Mode what the participant is doing with the item
+type Mode string
+
+const (
+	//Viewing participant only read the item
+	Viewing Mode = "viewing"
+	//Editing participant has unsaved changes of the item
+	Editing Mode = "editing"
+)
+
+//IsValid Return true if the mode is known
+func (mode Mode) IsValid() bool {
+	return mode == Viewing || mode == Editing
+}
+
+//PresenceMessageType kind of message sent to participants of an item
+type PresenceMessageType string
+
+const (
+	//PresenceChanged someone join, leave or change mode, the message has every participant
+	PresenceChanged PresenceMessageType = "presence"
+	//ItemSaved someone save the item, the message has the new version and who save it
+	ItemSaved PresenceMessageType = "saved"
+	//ItemRemoved someone delete the item
+	ItemRemoved PresenceMessageType = "deleted"
+)
+
+//Participant user who has the item opened
+type Participant struct {
+	SessionID string
+	User      string
+	Mode      Mode
+	JoinedAt  time.Time
+}
+
+//PresenceMessage message sent to every participant of an item
+type PresenceMessage struct {
+	Type         PresenceMessageType
+	ItemID       string
+	Participants []Participant
+	Version      int
+	Actor        string
+	At           time.Time
+}
+
+//Presence Listener which track who has each item opened and tell them as soon as the item is saved,
+//so editor can be warned before the save is rejected as conflict
+type Presence struct {
+	lock   sync.Mutex
+	rooms  map[string]map[*Session]struct{}
+	closed bool
+}
+
+//Session single participant connected into the item room. The channel is closed when the session leave,
+//can not keep up or the presence is closed
+type Session struct {
+	participant Participant
+	itemID      string
+	messages    chan PresenceMessage
+	presence    *Presence
+}
+
+//NewPresence Generate presence without any participant
+func NewPresence() *Presence {
+	return &Presence{
+		rooms: make(map[string]map[*Session]struct{}),
+	}
+}
+
+//Join Add the user into the item room and tell every participant including the new one
+func (presence *Presence) Join(itemID string, user string, mode Mode) *Session {
+	presence.lock.Lock()
+	defer presence.lock.Unlock()
+
+	session := &Session{
+		Participant{util.GenerateID(), user, mode, time.Now()},
+		itemID,
+		make(chan PresenceMessage, subscriberBufferSize),
+		presence,
+	}
+
+	if presence.closed {
+		close(session.messages)
+		return session
+	}
+
+	room, ok := presence.rooms[itemID]
+	if !ok {
+		room = make(map[*Session]struct{})
+		presence.rooms[itemID] = room
+	}
+
+	room[session] = struct{}{}
+	presence.announce(itemID)
+
+	return session
+}
+
+//ItemChanged Tell participants of the item that it has been saved or deleted
+func (presence *Presence) ItemChanged(event Event) {
+	presence.lock.Lock()
+	defer presence.lock.Unlock()
+
+	message := PresenceMessage{
+		Type:    ItemSaved,
+		ItemID:  event.ItemID,
+		Version: event.Version,
+		Actor:   event.Actor,
+		At:      event.OccurredAt,
+	}
+
+	if event.Type == ItemDeleted {
+		message.Type = ItemRemoved
+	}
+
+	presence.send(event.ItemID, message)
+}
+
+//Close Disconnect every session and stop accepting new one, used when the server shutting down
+func (presence *Presence) Close() {
+	presence.lock.Lock()
+	defer presence.lock.Unlock()
+
+	presence.closed = true
+
+	for itemID, room := range presence.rooms {
+		for session := range room {
+			close(session.messages)
+		}
+
+		delete(presence.rooms, itemID)
+	}
+}
+
+//send must be called while holding the lock, slow session is removed instead of blocking the sender
+func (presence *Presence) send(itemID string, message PresenceMessage) {
+	var slowSessions []*Session
+
+	for session := range presence.rooms[itemID] {
+		select {
+		case session.messages <- message:
+		default:
+			slowSessions = append(slowSessions, session)
+		}
+	}
+
+	for _, session := range slowSessions {
+		presence.remove(session)
+	}
+
+	if len(slowSessions) > 0 {
+		presence.announce(itemID)
+	}
+}
+
+//announce must be called while holding the lock
+func (presence *Presence) announce(itemID string) {
+	room := presence.rooms[itemID]
+	if len(room) == 0 {
+		return
+	}
+
+	participants := make([]Participant, 0, len(room))
+	for session := range room {
+		participants = append(participants, session.participant)
+	}
+
+	sort.Slice(participants, func(i, j int) bool {
+		if participants[i].JoinedAt.Equal(participants[j].JoinedAt) {
+			return participants[i].SessionID < participants[j].SessionID
+		}
+
+		return participants[i].JoinedAt.Before(participants[j].JoinedAt)
+	})
+
+	presence.send(itemID, PresenceMessage{
+		Type:         PresenceChanged,
+		ItemID:       itemID,
+		Participants: participants,
+		At:           time.Now(),
+	})
+}
+
+//remove must be called while holding the lock, return false if the session already left
+func (presence *Presence) remove(session *Session) bool {
+	room := presence.rooms[session.itemID]
+	if _, ok := room[session]; !ok {
+		return false
+	}
+
+	delete(room, session)
+	close(session.messages)
+
+	if len(room) == 0 {
+		delete(presence.rooms, session.itemID)
+	}
+
+	return true
+}
+
+//Messages Return channel of messages, it is closed when the session end
+func (session *Session) Messages() <-chan PresenceMessage {
+	return session.messages
+}
+
+//ChangeMode Tell other participants that the user start or stop editing
+func (session *Session) ChangeMode(mode Mode) {
+	session.presence.lock.Lock()
+	defer session.presence.lock.Unlock()
+
+	if _, ok := session.presence.rooms[session.itemID][session]; !ok || session.participant.Mode == mode {
+		return
+	}
+
+	session.participant.Mode = mode
+	session.presence.announce(session.itemID)
+}
+
+//Leave Remove the user from the item room and tell the rest of participants
+func (session *Session) Leave() {
+	session.presence.lock.Lock()
+	defer session.presence.lock.Unlock()
+
+	if session.presence.remove(session) {
+		session.presence.announce(session.itemID)
+	}
+}
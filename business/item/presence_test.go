@@ -0,0 +1,117 @@
+package item_test
+
+import (
+	"sample-order/business/item"
+	"testing"
+	"time"
+)
+
+func TestPresence(t *testing.T) {
+	t.Run("Expect participants are told who join, change mode and leave", func(t *testing.T) {
+		presence := item.NewPresence()
+
+		alice := presence.Join(item1.ID, "alice", item.Viewing)
+		bob := presence.Join(item1.ID, "bob", item.Viewing)
+		presence.Join(item2.ID, "carol", item.Editing)
+
+		messages := drainPresence(alice)
+		if len(messages) != 2 || len(messages[1].Participants) != 2 {
+			t.Error("Expect alice see herself then bob join", messages)
+			t.FailNow()
+		}
+
+		if messages[1].Participants[0].User != "alice" || messages[1].Participants[1].User != "bob" {
+			t.Error("Expect participants ordered by join time", messages[1].Participants)
+		}
+
+		bob.ChangeMode(item.Editing)
+
+		messages = drainPresence(alice)
+		if len(messages) != 1 || messages[0].Participants[1].Mode != item.Editing {
+			t.Error("Expect alice see bob is editing", messages)
+		}
+
+		bob.ChangeMode(item.Editing)
+		if messages = drainPresence(alice); len(messages) != 0 {
+			t.Error("Expect nothing sent when mode does not change")
+		}
+
+		bob.Leave()
+		bob.Leave()
+
+		messages = drainPresence(alice)
+		if len(messages) != 1 || len(messages[0].Participants) != 1 || messages[0].Participants[0].User != "alice" {
+			t.Error("Expect alice see bob leave once", messages)
+		}
+
+		drainPresence(bob)
+		if _, ok := <-bob.Messages(); ok {
+			t.Error("Expect messages of bob is closed after leave")
+		}
+	})
+
+	t.Run("Expect participants are told the item is saved or deleted", func(t *testing.T) {
+		presence := item.NewPresence()
+
+		alice := presence.Join(item1.ID, "alice", item.Editing)
+		other := presence.Join(item2.ID, "carol", item.Viewing)
+		drainPresence(alice)
+		drainPresence(other)
+
+		saved := item1.ModifyItem(item1.Name, item1.Description, item1.Tags, item1.SalePrice, item1.RentalRate, "bob", time.Now())
+		presence.ItemChanged(item.NewUpdatedEvent("event-1", item1, saved))
+		presence.ItemChanged(item.NewDeletedEvent("event-2", saved, "bob", time.Now()))
+
+		messages := drainPresence(alice)
+		if len(messages) != 2 {
+			t.Error("Expect saved and deleted message", messages)
+			t.FailNow()
+		}
+
+		if messages[0].Type != item.ItemSaved || messages[0].Version != item1.Version+1 || messages[0].Actor != "bob" {
+			t.Error("Expect saved message has the new version and who save it", messages[0])
+		}
+
+		if messages[1].Type != item.ItemRemoved {
+			t.Error("Expect deleted message", messages[1])
+		}
+
+		if messages = drainPresence(other); len(messages) != 0 {
+			t.Error("Expect participant of other item is not told", messages)
+		}
+	})
+
+	t.Run("Expect close end every session", func(t *testing.T) {
+		presence := item.NewPresence()
+		alice := presence.Join(item1.ID, "alice", item.Viewing)
+
+		presence.Close()
+		alice.Leave()
+
+		drainPresence(alice)
+		if _, ok := <-alice.Messages(); ok {
+			t.Error("Expect messages is closed")
+		}
+
+		late := presence.Join(item1.ID, "bob", item.Viewing)
+		if _, ok := <-late.Messages(); ok {
+			t.Error("Expect session after close is ended immediately")
+		}
+	})
+}
+
+//drainPresence Return the messages waiting in the session without blocking
+func drainPresence(session *item.Session) []item.PresenceMessage {
+	var messages []item.PresenceMessage
+	for {
+		select {
+		case message, ok := <-session.Messages():
+			if !ok {
+				return messages
+			}
+			messages = append(messages, message)
+		default:
+			return messages
+		}
+	}
+}
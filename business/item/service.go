@@ -1,64 +1,125 @@
 package item
 
 import (
+	"context"
 	"sample-order/business"
 	"sample-order/business/item/spec"
+	"sample-order/business/money"
 	"sample-order/util"
 	"time"
 
 	validator "github.com/go-playground/validator/v10"
 )
 
+//changeSettleTime how long change is held back from the change feed. Transaction which commit later may store its change
+//before the ones already committed, so the newest changes are only returned after the in-flight transactions are finished.
+//MySQL give the change seq in commit order so it is not affected, on MongoDB the change is ordered by its modified time
+//which is taken before the transaction, so change of transaction which take longer than this can still be skipped by
+//the clients which already read the newer ones
+const changeSettleTime = 2 * time.Second
+
 //Repository ingoing port for item
 type Repository interface {
 	//FindItemByID If data not found will return nil without error
-	FindItemByID(ID string) (*Item, error)
+	FindItemByID(ctx context.Context, ID string) (*Item, error)
+
+	//FindAllByTag If no data match with the given tag and sale price range, will return empty slice instead of nil.
+	//Empty price range means no price filter
+	FindAllByTag(ctx context.Context, tag string, priceRange PriceRange) ([]Item, error)
+
+	//InsertItem Insert new item and write its event into the outbox atomically
+	InsertItem(ctx context.Context, item Item, event Event) error
+
+	//UpdateItem Update item and write its event into the outbox atomically.
+	//If data not found or version is not match will return business.ErrZeroAffected
+	UpdateItem(ctx context.Context, item Item, currentVersion int, event Event) error
+
+	//DeleteItem Delete item with its rental rule and write its event into the outbox atomically, the stock movements,
+	//finished reservations and finished bookings are kept as history. If data not found or version is not match
+	//will return business.ErrZeroAffected, if item still has stock on hand, active reservation or active booking
+	//will return business.ErrItemInUse
+	DeleteItem(ctx context.Context, ID string, currentVersion int, event Event) error
 
-	//FindAllByTag If no data match with the given tag, will return empty slice instead of nil
-	FindAllByTag(tag string) ([]Item, error)
+	//InsertItems Insert multiple items into storage at once together with their events, nil events write nothing into the outbox
+	InsertItems(ctx context.Context, items []Item, events []Event) error
 
-	//InsertItem Insert new item into storage
-	InsertItem(item Item) error
+	//StreamItems Iterate items ordered by ID and call fn for each of them without loading all into memory.
+	//Empty tag means all items. Iteration stop when fn return error
+	StreamItems(ctx context.Context, tag string, fn func(item Item) error) error
 
-	//UpdateItem if data not found will return core.ErrZeroAffected
-	UpdateItem(item Item, currentVersion int) error
+	//FindItemsAfterID Find at most limit items with ID greater than given ID ordered by ID. Empty ID means from the beginning
+	FindItemsAfterID(ctx context.Context, afterID string, limit int) ([]Item, error)
+
+	//FindChangesAfter Find at most limit changes after given token in modification order, deleted item is returned as tombstone.
+	//Only the latest change of each item is kept, zero token means from the beginning
+	FindChangesAfter(ctx context.Context, token ChangeToken, limit int) ([]Change, error)
+}
+
+//Listener outgoing port notified after item change is stored
+type Listener interface {
+	ItemChanged(event Event)
 }
 
 //Service outgoing port for item
 type Service interface {
-	GetItemByID(ID string) (*Item, error)
+	GetItemByID(ctx context.Context, ID string) (*Item, error)
+
+	GetItemsByTag(ctx context.Context, tag string) ([]Item, error)
+
+	GetItemsByTagAndPrice(ctx context.Context, tag string, priceRange PriceRange) ([]Item, error)
 
-	GetItemsByTag(tag string) ([]Item, error)
+	CreateItem(ctx context.Context, upsertitemSpec spec.UpsertItemSpec, createdBy string) (string, error)
 
-	CreateItem(upsertitemSpec spec.UpsertItemSpec, createdBy string) (string, error)
+	UpdateItem(ctx context.Context, ID string, upsertitemSpec spec.UpsertItemSpec, currentVersion int, modifiedBy string) error
 
-	UpdateItem(ID string, upsertitemSpec spec.UpsertItemSpec, currentVersion int, modifiedBy string) error
+	DeleteItem(ctx context.Context, ID string, currentVersion int, deletedBy string) error
+
+	ImportItems(ctx context.Context, rows []ImportRow, createdBy string, dryRun bool) (*ImportReport, error)
+
+	ExportItems(ctx context.Context, tag string, fn func(item Item) error) error
+
+	GetChanges(ctx context.Context, since string, limit int) (*ChangeFeed, error)
 }
 
 //=============== The implementation of those interface put below =======================
 
 type service struct {
 	repository Repository
+	listeners  []Listener
 	validate   *validator.Validate
 }
 
-//NewService Construct item service object
-func NewService(repository Repository) Service {
+//NewService Construct item service object, the listeners are notified in the given order after every stored change
+func NewService(repository Repository, listeners ...Listener) Service {
+	validate := validator.New()
+	validate.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return money.IsValidCurrency(fl.Field().String())
+	})
+
 	return &service{
 		repository,
-		validator.New(),
+		listeners,
+		validate,
 	}
 }
 
 //GetItemByID Get item by given ID, return nil if not exist
-func (s *service) GetItemByID(ID string) (*Item, error) {
-	return s.repository.FindItemByID(ID)
+func (s *service) GetItemByID(ctx context.Context, ID string) (*Item, error) {
+	return s.repository.FindItemByID(ctx, ID)
 }
 
 //GetItemsByTag Get all items by given tag, return zero array if not match
-func (s *service) GetItemsByTag(tag string) ([]Item, error) {
+func (s *service) GetItemsByTag(ctx context.Context, tag string) ([]Item, error) {
+	return s.GetItemsByTagAndPrice(ctx, tag, PriceRange{})
+}
 
-	items, err := s.repository.FindAllByTag(tag)
+//GetItemsByTagAndPrice Get all items by given tag with sale price inside the range, return zero array if not match
+func (s *service) GetItemsByTagAndPrice(ctx context.Context, tag string, priceRange PriceRange) ([]Item, error) {
+	if !isValidPriceRange(priceRange) {
+		return []Item{}, business.ErrInvalidSpec
+	}
+
+	items, err := s.repository.FindAllByTag(ctx, tag, priceRange)
 	if err != nil || items == nil {
 		return []Item{}, err
 	}
@@ -67,7 +128,7 @@ func (s *service) GetItemsByTag(tag string) ([]Item, error) {
 }
 
 //CreateItem Create new item and store into database
-func (s *service) CreateItem(upsertitemSpec spec.UpsertItemSpec, createdBy string) (string, error) {
+func (s *service) CreateItem(ctx context.Context, upsertitemSpec spec.UpsertItemSpec, createdBy string) (string, error) {
 	err := s.validate.Struct(upsertitemSpec)
 
 	if err != nil {
@@ -80,21 +141,27 @@ func (s *service) CreateItem(upsertitemSpec spec.UpsertItemSpec, createdBy strin
 		upsertitemSpec.Name,
 		upsertitemSpec.Description,
 		upsertitemSpec.Tags,
+		toMoney(upsertitemSpec.SalePrice),
+		toMoney(upsertitemSpec.RentalRate),
 		createdBy,
 		time.Now(),
 	)
 
-	err = s.repository.InsertItem(item)
+	event := NewCreatedEvent(util.GenerateID(), item)
+
+	err = s.repository.InsertItem(ctx, item, event)
 	if err != nil {
 		return "", err
 	}
 
+	s.notify(event)
+
 	return ID, nil
 }
 
 //UpdateItem Update existing item in the database.
 //Will return ErrNotFound when item is not exists or ErrConflict if data version is not match
-func (s *service) UpdateItem(ID string, upsertitemSpec spec.UpsertItemSpec, currentVersion int, modifiedBy string) error {
+func (s *service) UpdateItem(ctx context.Context, ID string, upsertitemSpec spec.UpsertItemSpec, currentVersion int, modifiedBy string) error {
 	err := s.validate.Struct(upsertitemSpec)
 
 	if err != nil || len(ID) == 0 {
@@ -102,7 +169,48 @@ func (s *service) UpdateItem(ID string, upsertitemSpec spec.UpsertItemSpec, curr
 	}
 
 	//get the item first to make sure data is exist
-	item, err := s.repository.FindItemByID(ID)
+	item, err := s.repository.FindItemByID(ctx, ID)
+
+	if err != nil {
+		return err
+	} else if item == nil {
+		return business.ErrNotFound
+	} else if item.Version != currentVersion {
+		return business.ErrHasBeenModified
+	}
+
+	newItem := item.ModifyItem(
+		upsertitemSpec.Name,
+		upsertitemSpec.Description,
+		upsertitemSpec.Tags,
+		toMoney(upsertitemSpec.SalePrice),
+		toMoney(upsertitemSpec.RentalRate),
+		modifiedBy,
+		time.Now())
+
+	event := NewUpdatedEvent(util.GenerateID(), *item, newItem)
+
+	err = s.repository.UpdateItem(ctx, newItem, currentVersion, event)
+	if err == business.ErrZeroAffected {
+		return business.ErrHasBeenModified
+	} else if err != nil {
+		return err
+	}
+
+	s.notify(event)
+
+	return nil
+}
+
+//DeleteItem Delete existing item.
+//Will return ErrNotFound when item is not exists, ErrHasBeenModified if data version is not match
+//or ErrItemInUse if item still has stock on hand, active reservation or active booking
+func (s *service) DeleteItem(ctx context.Context, ID string, currentVersion int, deletedBy string) error {
+	if len(ID) == 0 || len(deletedBy) == 0 {
+		return business.ErrInvalidSpec
+	}
+
+	item, err := s.repository.FindItemByID(ctx, ID)
 
 	if err != nil {
 		return err
@@ -112,7 +220,94 @@ func (s *service) UpdateItem(ID string, upsertitemSpec spec.UpsertItemSpec, curr
 		return business.ErrHasBeenModified
 	}
 
-	newItem := item.ModifyItem(upsertitemSpec.Name, upsertitemSpec.Description, upsertitemSpec.Tags, modifiedBy, time.Now())
+	event := NewDeletedEvent(util.GenerateID(), *item, deletedBy, time.Now())
+
+	err = s.repository.DeleteItem(ctx, ID, currentVersion, event)
+	if err == business.ErrZeroAffected {
+		return business.ErrHasBeenModified
+	} else if err != nil {
+		return err
+	}
+
+	s.notify(event)
+
+	return nil
+}
+
+//ExportItems Iterate all items, or only items with given tag when not empty, and pass it into fn
+func (s *service) ExportItems(ctx context.Context, tag string, fn func(item Item) error) error {
+	return s.repository.StreamItems(ctx, tag, fn)
+}
+
+//GetChanges Get at most limit changes after the since token, empty token means from the beginning.
+//Will return ErrInvalidSpec when the token is malformed or limit is not positive
+func (s *service) GetChanges(ctx context.Context, since string, limit int) (*ChangeFeed, error) {
+	token, err := ParseChangeToken(since)
+	if err != nil || limit <= 0 {
+		return nil, business.ErrInvalidSpec
+	}
+
+	//one more change tell whether there is next page
+	changes, err := s.repository.FindChangesAfter(ctx, token, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := ChangeFeed{
+		Changes: []Change{},
+		Next:    token,
+	}
+
+	settledAt := time.Now().Add(-changeSettleTime)
+
+	for _, change := range changes {
+		if len(feed.Changes) == limit {
+			feed.HasMore = true
+			break
+		}
+
+		//the following changes are not settled either, they are returned on the next poll
+		if change.ModifiedAt.After(settledAt) {
+			break
+		}
+
+		feed.Changes = append(feed.Changes, change)
+		feed.Next = change.Token
+	}
+
+	return &feed, nil
+}
+
+func (s *service) notify(events ...Event) {
+	for _, listener := range s.listeners {
+		for _, event := range events {
+			listener.ItemChanged(event)
+		}
+	}
+}
+
+func toMoney(moneySpec *spec.MoneySpec) *money.Money {
+	if moneySpec == nil {
+		return nil
+	}
+
+	return &money.Money{
+		Amount:   moneySpec.Amount,
+		Currency: moneySpec.Currency,
+	}
+}
+
+func isValidPriceRange(priceRange PriceRange) bool {
+	for _, boundary := range []*money.Money{priceRange.Min, priceRange.Max} {
+		if boundary != nil && (!money.IsValidCurrency(boundary.Currency) || boundary.IsNegative()) {
+			return false
+		}
+	}
+
+	if priceRange.Min != nil && priceRange.Max != nil {
+		result, err := priceRange.Min.Compare(*priceRange.Max)
+		return err == nil && result <= 0
+	}
 
-	return s.repository.UpdateItem(newItem, currentVersion)
+	return true
 }
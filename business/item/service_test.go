@@ -1,6 +1,7 @@
 package item_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -8,11 +9,15 @@ import (
 	"sample-order/business"
 	"sample-order/business/item"
 	"sample-order/business/item/spec"
+	"sample-order/business/money"
+	"sort"
 	"testing"
 	"time"
 )
 
+var ctx = context.Background()
 var service item.Service
+var repository inMemoryRepository
 var item1, item2 item.Item
 var insertSpec, updateSpec, failedSpec, errorSpec spec.UpsertItemSpec
 var creator, updater, errorFindID string
@@ -26,14 +31,14 @@ func TestMain(m *testing.M) {
 
 func TestGetItemByID(t *testing.T) {
 	t.Run("Expect found the item", func(t *testing.T) {
-		foundItem, _ := service.GetItemByID(item1.ID)
+		foundItem, _ := service.GetItemByID(ctx, item1.ID)
 		if !reflect.DeepEqual(*foundItem, item1) {
 			t.Error("Expect item has to be equal with item1", foundItem, item1)
 		}
 	})
 
 	t.Run("Expect not found the item", func(t *testing.T) {
-		item, err := service.GetItemByID("random")
+		item, err := service.GetItemByID(ctx, "random")
 
 		if err != nil {
 			t.Error("Expect error is nil. Error: ", err)
@@ -45,7 +50,7 @@ func TestGetItemByID(t *testing.T) {
 
 func TestGetItemByTags(t *testing.T) {
 	t.Run("Expect found the items", func(t *testing.T) {
-		items, _ := service.GetItemsByTag("tag2")
+		items, _ := service.GetItemsByTag(ctx, "tag2")
 
 		if len(items) != 2 {
 			t.Error("Expect item length must be two")
@@ -66,7 +71,7 @@ func TestGetItemByTags(t *testing.T) {
 	})
 
 	t.Run("Expect not found the items", func(t *testing.T) {
-		items, err := service.GetItemsByTag("not-found-tag")
+		items, err := service.GetItemsByTag(ctx, "not-found-tag")
 
 		if err != nil {
 			t.Error("Expect error is nil", err)
@@ -78,9 +83,50 @@ func TestGetItemByTags(t *testing.T) {
 	})
 }
 
+func TestGetItemsByTagAndPrice(t *testing.T) {
+	t.Run("Expect found the items inside the price range", func(t *testing.T) {
+		priceRange := item.PriceRange{Min: &money.Money{Amount: 10000000, Currency: "IDR"}}
+		items, err := service.GetItemsByTagAndPrice(ctx, "tag2", priceRange)
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if len(items) != 1 || items[0].ID != item1.ID {
+			t.Error("Expect only item1 is found", items)
+		}
+	})
+
+	t.Run("Expect not found the items outside the price range", func(t *testing.T) {
+		priceRange := item.PriceRange{Max: &money.Money{Amount: 10000000, Currency: "IDR"}}
+		items, err := service.GetItemsByTagAndPrice(ctx, "tag2", priceRange)
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if items == nil || len(items) != 0 {
+			t.Error("Expect items is empty")
+		}
+	})
+
+	t.Run("Expect failed on invalid price range", func(t *testing.T) {
+		priceRange := item.PriceRange{
+			Min: &money.Money{Amount: 200, Currency: "IDR"},
+			Max: &money.Money{Amount: 100, Currency: "IDR"},
+		}
+
+		if _, err := service.GetItemsByTagAndPrice(ctx, "tag2", priceRange); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec when min greater than max. Error is: ", err)
+		}
+
+		priceRange.Max = &money.Money{Amount: 300, Currency: "USD"}
+
+		if _, err := service.GetItemsByTagAndPrice(ctx, "tag2", priceRange); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec on different currency. Error is: ", err)
+		}
+	})
+}
+
 func TestCreateItem(t *testing.T) {
 	t.Run("Expect success create item", func(t *testing.T) {
-		id, err := service.CreateItem(insertSpec, creator)
+		id, err := service.CreateItem(ctx, insertSpec, creator)
 
 		if err != nil {
 			t.Error("Expext error is not nil. Error: ", err)
@@ -88,7 +134,7 @@ func TestCreateItem(t *testing.T) {
 		}
 
 		for _, tag := range insertSpec.Tags {
-			items, _ := service.GetItemsByTag(tag)
+			items, _ := service.GetItemsByTag(ctx, tag)
 
 			if len(items) == 0 {
 				t.Error("Expect at least one item when search by given tag: ", tag)
@@ -108,7 +154,7 @@ func TestCreateItem(t *testing.T) {
 			}
 		}
 
-		newItem, _ := service.GetItemByID(id)
+		newItem, _ := service.GetItemByID(ctx, id)
 
 		if newItem == nil {
 			t.Error("Expect item is not nil after inserted")
@@ -127,6 +173,14 @@ func TestCreateItem(t *testing.T) {
 			t.Error("Expect tags is equal as given")
 		}
 
+		if newItem.SalePrice == nil || *newItem.SalePrice != (money.Money{Amount: 1250, Currency: "USD"}) {
+			t.Error("Expect sale price is equal as given")
+		}
+
+		if newItem.RentalRate != nil {
+			t.Error("Expect rental rate is not set")
+		}
+
 		if newItem.CreatedBy != creator {
 			t.Error("Expect created by is equal to " + creator)
 		}
@@ -142,10 +196,19 @@ func TestCreateItem(t *testing.T) {
 		if newItem.Version != 1 {
 			t.Error("Expect version is equal to 1")
 		}
+
+		event := repository.events[len(repository.events)-1]
+		if event.Type != item.ItemCreated || event.ItemID != id || event.Version != 1 {
+			t.Error("Expect item created event is written", event)
+		}
+
+		if event.Before != nil || event.After == nil || !reflect.DeepEqual(*event.After, *newItem) {
+			t.Error("Expect created event has only the after state")
+		}
 	})
 
 	t.Run("Expect failed create item on spec", func(t *testing.T) {
-		_, err := service.CreateItem(failedSpec, creator)
+		_, err := service.CreateItem(ctx, failedSpec, creator)
 
 		if err == nil {
 			t.Error("Expect error is not nil")
@@ -154,8 +217,23 @@ func TestCreateItem(t *testing.T) {
 		}
 	})
 
+	t.Run("Expect failed create item on invalid price", func(t *testing.T) {
+		invalidPriceSpec := insertSpec
+		invalidPriceSpec.RentalRate = &spec.MoneySpec{Amount: 100, Currency: "XXX"}
+
+		if _, err := service.CreateItem(ctx, invalidPriceSpec, creator); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec on unknown currency. Error is: ", err)
+		}
+
+		invalidPriceSpec.RentalRate = &spec.MoneySpec{Amount: -100, Currency: "USD"}
+
+		if _, err := service.CreateItem(ctx, invalidPriceSpec, creator); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec on negative price. Error is: ", err)
+		}
+	})
+
 	t.Run("Expect failed create item on repository", func(t *testing.T) {
-		_, err := service.CreateItem(errorSpec, creator)
+		_, err := service.CreateItem(ctx, errorSpec, creator)
 
 		if err == nil {
 			t.Error("Expect error is not nil")
@@ -171,7 +249,7 @@ func TestUpdateItem(t *testing.T) {
 		version := item2.Version
 		oldTags := item2.Tags
 
-		service.UpdateItem(id, updateSpec, version, updater)
+		service.UpdateItem(ctx, id, updateSpec, version, updater)
 
 		//find the old tag that doesn't exist in new updated tags
 		var invalidateTags []string
@@ -192,7 +270,7 @@ func TestUpdateItem(t *testing.T) {
 
 		//verify the invalidated tag is not contain the item anymore
 		for _, invalidateTag := range invalidateTags {
-			tagItems, _ := service.GetItemsByTag(invalidateTag)
+			tagItems, _ := service.GetItemsByTag(ctx, invalidateTag)
 			isFound := false
 
 			for _, tagItem := range tagItems {
@@ -207,7 +285,7 @@ func TestUpdateItem(t *testing.T) {
 			}
 		}
 
-		items, _ := service.GetItemsByTag(updateSpec.Tags[0])
+		items, _ := service.GetItemsByTag(ctx, updateSpec.Tags[0])
 
 		isFound := false
 		for _, item := range items {
@@ -221,7 +299,7 @@ func TestUpdateItem(t *testing.T) {
 			t.Error("Expect found inserted item when search by given tag: ", updateSpec.Tags[0])
 		}
 
-		updatedItem, _ := service.GetItemByID(item2.ID)
+		updatedItem, _ := service.GetItemByID(ctx, item2.ID)
 
 		if updatedItem == nil {
 			t.Error("Expect item is not nil after updated")
@@ -258,7 +336,7 @@ func TestUpdateItem(t *testing.T) {
 	})
 
 	t.Run("Expect failed update item on spec", func(t *testing.T) {
-		err := service.UpdateItem(item2.ID, failedSpec, item2.Version, updater)
+		err := service.UpdateItem(ctx, item2.ID, failedSpec, item2.Version, updater)
 
 		if err == nil {
 			t.Error("Expect error is not nil")
@@ -268,7 +346,7 @@ func TestUpdateItem(t *testing.T) {
 	})
 
 	t.Run("Expect failed update item on not found", func(t *testing.T) {
-		err := service.UpdateItem("not-found", updateSpec, 1, updater)
+		err := service.UpdateItem(ctx, "not-found", updateSpec, 1, updater)
 
 		if err == nil {
 			t.Error("Expect error is not nil")
@@ -278,7 +356,7 @@ func TestUpdateItem(t *testing.T) {
 	})
 
 	t.Run("Expect failed update item on wrong version", func(t *testing.T) {
-		err := service.UpdateItem(item1.ID, updateSpec, item1.Version+1, updater)
+		err := service.UpdateItem(ctx, item1.ID, updateSpec, item1.Version+1, updater)
 
 		if err == nil {
 			t.Error("Expect error is not nil")
@@ -288,7 +366,7 @@ func TestUpdateItem(t *testing.T) {
 	})
 
 	t.Run("Expect failed update item on repository", func(t *testing.T) {
-		err := service.UpdateItem(errorFindID, updateSpec, 1, updater)
+		err := service.UpdateItem(ctx, errorFindID, updateSpec, 1, updater)
 
 		if err == nil {
 			t.Error("Expect error is not nil")
@@ -296,6 +374,318 @@ func TestUpdateItem(t *testing.T) {
 			t.Error("Expect error on insert. Error is: ", err)
 		}
 	})
+
+	t.Run("Expect item updated event has before and after state", func(t *testing.T) {
+		id, _ := service.CreateItem(ctx, insertSpec, creator)
+		before, _ := service.GetItemByID(ctx, id)
+
+		if err := service.UpdateItem(ctx, id, updateSpec, before.Version, updater); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		event := repository.events[len(repository.events)-1]
+		if event.Type != item.ItemUpdated || event.ItemID != id || event.Version != before.Version+1 || event.Actor != updater {
+			t.Error("Expect item updated event is written", event)
+		}
+
+		if event.Before == nil || !reflect.DeepEqual(*event.Before, *before) {
+			t.Error("Expect before state is the item before update")
+		}
+
+		if event.After == nil || event.After.Name != updateSpec.Name {
+			t.Error("Expect after state is the updated item")
+		}
+	})
+}
+
+func TestListener(t *testing.T) {
+	t.Run("Expect listener notified after stored change only", func(t *testing.T) {
+		repo := newInMemoryRepository()
+		listener := &inMemoryListener{}
+		service := item.NewService(&repo, listener)
+
+		id, _ := service.CreateItem(ctx, insertSpec, creator)
+		service.UpdateItem(ctx, id, updateSpec, 2, updater)
+		service.CreateItem(ctx, errorSpec, creator)
+		service.DeleteItem(ctx, id, 1, updater)
+
+		if len(listener.events) != 2 {
+			t.Error("Expect two events notified", listener.events)
+			t.FailNow()
+		}
+
+		if listener.events[0].Type != item.ItemCreated || listener.events[1].Type != item.ItemDeleted || listener.events[1].ItemID != id {
+			t.Error("Expect created and deleted event in order", listener.events)
+		}
+
+		if listener.events[0].ID != repo.events[0].ID {
+			t.Error("Expect notified event is the stored event")
+		}
+	})
+}
+
+func TestGetChanges(t *testing.T) {
+	repo := newInMemoryRepository()
+	service := item.NewService(&repo)
+
+	past := time.Now().Add(-time.Hour)
+	first := item.NewItem("5f350b7d21148431abc65301", "First", "First description", []string{"tag1"}, nil, nil, creator, past)
+	second := item.NewItem("5f350b7d21148431abc65302", "Second", "Second description", []string{"tag1"}, nil, nil, creator, past)
+	third := item.NewItem("5f350b7d21148431abc65303", "Third", "Third description", []string{"tag1"}, nil, nil, creator, past)
+
+	repo.InsertItems(ctx, []item.Item{first, second, third}, []item.Event{
+		item.NewCreatedEvent("event-1", first),
+		item.NewCreatedEvent("event-2", second),
+		item.NewCreatedEvent("event-3", third),
+	})
+
+	modified := first.ModifyItem("First updated", first.Description, first.Tags, nil, nil, updater, past.Add(time.Minute))
+	repo.UpdateItem(ctx, modified, first.Version, item.NewUpdatedEvent("event-4", first, modified))
+	repo.DeleteItem(ctx, second.ID, second.Version, item.NewDeletedEvent("event-5", second, updater, past.Add(2*time.Minute)))
+
+	var next string
+
+	t.Run("Expect latest change of each item in modification order", func(t *testing.T) {
+		feed, err := service.GetChanges(ctx, "", 2)
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if len(feed.Changes) != 2 || !feed.HasMore {
+			t.Error("Expect first page has two changes and more to come", feed)
+			t.FailNow()
+		}
+
+		if feed.Changes[0].ItemID != third.ID || feed.Changes[1].ItemID != first.ID || feed.Changes[1].Item.Name != "First updated" {
+			t.Error("Expect untouched third item then the updated first item", feed.Changes)
+		}
+
+		next = feed.Next.String()
+	})
+
+	t.Run("Expect deleted item is returned as tombstone", func(t *testing.T) {
+		feed, _ := service.GetChanges(ctx, next, 2)
+
+		if len(feed.Changes) != 1 || feed.HasMore {
+			t.Error("Expect last page has single change", feed)
+			t.FailNow()
+		}
+
+		tombstone := feed.Changes[0]
+		if tombstone.ItemID != second.ID || !tombstone.Deleted || tombstone.Item != nil || tombstone.ModifiedBy != updater {
+			t.Error("Expect tombstone of second item", tombstone)
+		}
+
+		next = feed.Next.String()
+	})
+
+	t.Run("Expect token is kept when there is no settled change", func(t *testing.T) {
+		service.CreateItem(ctx, insertSpec, creator)
+
+		feed, err := service.GetChanges(ctx, next, 2)
+		if err != nil || len(feed.Changes) != 0 || feed.HasMore {
+			t.Error("Expect change made just now is held back", feed, err)
+		}
+
+		if feed.Next.String() != next {
+			t.Error("Expect next token is the given token")
+		}
+	})
+
+	t.Run("Expect invalid spec", func(t *testing.T) {
+		if _, err := service.GetChanges(ctx, "not a token", 10); err != business.ErrInvalidSpec {
+			t.Error("Expect malformed token is rejected. Error is: ", err)
+		}
+
+		if _, err := service.GetChanges(ctx, "", 0); err != business.ErrInvalidSpec {
+			t.Error("Expect zero limit is rejected. Error is: ", err)
+		}
+	})
+}
+
+func TestChangeToken(t *testing.T) {
+	t.Run("Expect token survive encoding", func(t *testing.T) {
+		token := item.ChangeToken{Seq: 42, ModifiedAt: time.Unix(0, 1600000000123000000).UTC(), ID: item1.ID}
+
+		parsed, err := item.ParseChangeToken(token.String())
+		if err != nil || !reflect.DeepEqual(parsed, token) {
+			t.Error("Expect parsed token equal to the original", parsed, err)
+		}
+	})
+
+	t.Run("Expect empty token is the beginning", func(t *testing.T) {
+		token, err := item.ParseChangeToken("")
+		if err != nil || !token.IsZero() || token.String() != "" {
+			t.Error("Expect zero token", token, err)
+		}
+	})
+}
+
+func TestDeleteItem(t *testing.T) {
+	t.Run("Expect success delete item", func(t *testing.T) {
+		id, _ := service.CreateItem(ctx, insertSpec, creator)
+		deleted, _ := service.GetItemByID(ctx, id)
+
+		if err := service.DeleteItem(ctx, id, deleted.Version, updater); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if found, _ := service.GetItemByID(ctx, id); found != nil {
+			t.Error("Expect item is not found after deleted")
+		}
+
+		event := repository.events[len(repository.events)-1]
+		if event.Type != item.ItemDeleted || event.ItemID != id || event.Version != deleted.Version || event.Actor != updater {
+			t.Error("Expect item deleted event is written", event)
+		}
+
+		if event.After != nil || event.Before == nil || !reflect.DeepEqual(*event.Before, *deleted) {
+			t.Error("Expect deleted event has only the before state")
+		}
+	})
+
+	t.Run("Expect failed delete item on not found", func(t *testing.T) {
+		if err := service.DeleteItem(ctx, "not-found", 1, updater); err != business.ErrNotFound {
+			t.Error("Expect error item not found. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed delete item on wrong version", func(t *testing.T) {
+		if err := service.DeleteItem(ctx, item1.ID, item1.Version+1, updater); err != business.ErrHasBeenModified {
+			t.Error("Expect error item has been modified. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed delete item still in use", func(t *testing.T) {
+		id, _ := service.CreateItem(ctx, insertSpec, creator)
+		repository.inUse = map[string]bool{id: true}
+		defer func() { repository.inUse = nil }()
+
+		if err := service.DeleteItem(ctx, id, 1, updater); err != business.ErrItemInUse {
+			t.Error("Expect error item in use. Error is: ", err)
+		}
+
+		if found, _ := service.GetItemByID(ctx, id); found == nil {
+			t.Error("Expect item in use is kept")
+		}
+	})
+
+	t.Run("Expect failed delete item without deleter", func(t *testing.T) {
+		if err := service.DeleteItem(ctx, item1.ID, item1.Version, ""); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec. Error is: ", err)
+		}
+	})
+}
+
+func TestImportItems(t *testing.T) {
+	errorParse := errors.New("error on parse")
+	rows := []item.ImportRow{
+		{Line: 1, Spec: insertSpec},
+		{Line: 2, Spec: failedSpec},
+		{Line: 3, Err: errorParse},
+	}
+
+	t.Run("Expect dry run only validate the rows", func(t *testing.T) {
+		report, err := service.ImportItems(ctx, rows, creator, true)
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if !report.DryRun || report.Total != 3 || report.Succeeded != 1 || report.Failed != 2 {
+			t.Error("Expect one valid and two invalid rows on dry run", report)
+		}
+
+		if report.Results[0].ID != "" {
+			t.Error("Expect no ID generated on dry run")
+		}
+	})
+
+	t.Run("Expect success import valid rows and report the invalid one", func(t *testing.T) {
+		report, err := service.ImportItems(ctx, rows, creator, false)
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if report.Succeeded != 1 || report.Failed != 2 {
+			t.Error("Expect one success and two failed rows", report)
+			t.FailNow()
+		}
+
+		importedItem, _ := service.GetItemByID(ctx, report.Results[0].ID)
+		if importedItem == nil || importedItem.Name != insertSpec.Name || importedItem.CreatedBy != creator {
+			t.Error("Expect imported item is stored")
+		}
+
+		event := repository.events[len(repository.events)-1]
+		if event.Type != item.ItemCreated || event.ItemID != report.Results[0].ID {
+			t.Error("Expect item created event is written for imported item", event)
+		}
+
+		if report.Results[1].Err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec. Error is: ", report.Results[1].Err)
+		}
+
+		if report.Results[2].Err != errorParse {
+			t.Error("Expect error on parse. Error is: ", report.Results[2].Err)
+		}
+	})
+
+	t.Run("Expect only the row failed on repository is reported", func(t *testing.T) {
+		report, _ := service.ImportItems(ctx, []item.ImportRow{{Line: 1, Spec: insertSpec}, {Line: 2, Spec: errorSpec}}, creator, false)
+
+		if report.Succeeded != 1 || report.Failed != 1 {
+			t.Error("Expect the other row in the batch is still imported", report)
+			t.FailNow()
+		}
+
+		if report.Results[0].Err != nil || report.Results[0].ID == "" {
+			t.Error("Expect first row is imported. Error is: ", report.Results[0].Err)
+		}
+
+		if report.Results[1].Err != errorInsert {
+			t.Error("Expect error on insert. Error is: ", report.Results[1].Err)
+		}
+	})
+}
+
+func TestExportItems(t *testing.T) {
+	t.Run("Expect export items by tag", func(t *testing.T) {
+		var exported []item.Item
+		err := service.ExportItems(ctx, "tag1", func(item item.Item) error {
+			exported = append(exported, item)
+			return nil
+		})
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if len(exported) != 1 || !reflect.DeepEqual(exported[0], item1) {
+			t.Error("Expect only item1 is exported", exported)
+		}
+	})
+
+	t.Run("Expect export stop when callback return error", func(t *testing.T) {
+		errorWrite := errors.New("error on write")
+		count := 0
+		err := service.ExportItems(ctx, "", func(item item.Item) error {
+			count++
+			return errorWrite
+		})
+
+		if err != errorWrite {
+			t.Error("Expect error on write. Error is: ", err)
+		}
+
+		if count != 1 {
+			t.Error("Expect export stopped after first item")
+		}
+	})
 }
 
 func setup() {
@@ -304,6 +694,8 @@ func setup() {
 	item1.Name = "Item one"
 	item1.Description = "Description one"
 	item1.Tags = []string{"tag1", "tag2"}
+	item1.SalePrice = &money.Money{Amount: 15000000, Currency: "IDR"}
+	item1.RentalRate = &money.Money{Amount: 500000, Currency: "IDR"}
 	item1.Version = 1
 	item1.CreatedAt = time.Now()
 	item1.CreatedBy = "creator one"
@@ -321,12 +713,13 @@ func setup() {
 	item2.ModifiedAt = time.Now()
 	item2.ModifiedBy = "updater two"
 
-	repo := newInMemoryRepository()
-	service = item.NewService(&repo)
+	repository = newInMemoryRepository()
+	service = item.NewService(&repository)
 
 	insertSpec.Name = "New Item"
 	insertSpec.Description = "New Description"
 	insertSpec.Tags = []string{"tag99"}
+	insertSpec.SalePrice = &spec.MoneySpec{Amount: 1250, Currency: "USD"}
 
 	updateSpec.Name = "Update Item"
 	updateSpec.Description = "Update Description"
@@ -346,9 +739,21 @@ func setup() {
 	errorFindID = "error-find-id"
 }
 
+type inMemoryListener struct {
+	events []item.Event
+}
+
+func (listener *inMemoryListener) ItemChanged(event item.Event) {
+	listener.events = append(listener.events, event)
+}
+
 type inMemoryRepository struct {
 	itemByID  map[string]item.Item
 	itemByTag map[string][]item.Item
+	events    []item.Event
+
+	//inUse items which still have stock, reservation or booking
+	inUse map[string]bool
 }
 
 func newInMemoryRepository() inMemoryRepository {
@@ -372,7 +777,7 @@ func newInMemoryRepository() inMemoryRepository {
 	return repo
 }
 
-func (repo *inMemoryRepository) FindItemByID(ID string) (*item.Item, error) {
+func (repo *inMemoryRepository) FindItemByID(ctx context.Context, ID string) (*item.Item, error) {
 	if ID == errorFindID {
 		return nil, errorFind
 	}
@@ -385,7 +790,7 @@ func (repo *inMemoryRepository) FindItemByID(ID string) (*item.Item, error) {
 	return &item, nil
 }
 
-func (repo *inMemoryRepository) FindAllByTag(tag string) ([]item.Item, error) {
+func (repo *inMemoryRepository) FindAllByTag(ctx context.Context, tag string, priceRange item.PriceRange) ([]item.Item, error) {
 	var items []item.Item
 	items, ok := repo.itemByTag[tag]
 
@@ -394,30 +799,164 @@ func (repo *inMemoryRepository) FindAllByTag(tag string) ([]item.Item, error) {
 		return items, nil
 	}
 
-	return items, nil
+	if priceRange.IsEmpty() {
+		return items, nil
+	}
+
+	var filtered []item.Item
+	for _, item := range items {
+		if priceRange.Contains(item.SalePrice) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered, nil
 }
 
-func (repo *inMemoryRepository) InsertItem(item item.Item) error {
+func (repo *inMemoryRepository) InsertItem(ctx context.Context, item item.Item, event item.Event) error {
 	if item.Name == errorSpec.Name {
 		return errorInsert
 	}
 
+	repo.events = append(repo.events, event)
+	repo.insertItem(item)
+	return nil
+}
+
+func (repo *inMemoryRepository) insertItem(item item.Item) {
+
 	repo.itemByID[item.ID] = item
 
 	for _, tag := range item.Tags {
 		items := repo.itemByTag[tag]
 		repo.itemByTag[tag] = append(items, item)
 	}
+}
+
+func (repo *inMemoryRepository) InsertItems(ctx context.Context, items []item.Item, events []item.Event) error {
+	for _, item := range items {
+		if item.Name == errorSpec.Name {
+			return errorInsert
+		}
+	}
+
+	for _, item := range items {
+		repo.insertItem(item)
+	}
+
+	repo.events = append(repo.events, events...)
+	return nil
+}
+
+func (repo *inMemoryRepository) StreamItems(ctx context.Context, tag string, fn func(item item.Item) error) error {
+	var items []item.Item
+	if tag == "" {
+		for _, item := range repo.itemByID {
+			items = append(items, item)
+		}
+	} else {
+		items, _ = repo.FindAllByTag(ctx, tag, item.PriceRange{})
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+
+	for _, item := range items {
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (repo *inMemoryRepository) UpdateItem(item item.Item, currentVersion int) error {
-	//cleanup old tag first
-	oldItem := repo.itemByID[item.ID]
+func (repo *inMemoryRepository) FindItemsAfterID(ctx context.Context, afterID string, limit int) ([]item.Item, error) {
+	var items []item.Item
+	for _, item := range repo.itemByID {
+		if item.ID > afterID {
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (repo *inMemoryRepository) UpdateItem(ctx context.Context, item item.Item, currentVersion int, event item.Event) error {
+	oldItem, ok := repo.itemByID[item.ID]
+	if !ok || oldItem.Version != currentVersion {
+		return business.ErrZeroAffected
+	}
 
 	//cleanup the old tags first
-	for _, tag := range oldItem.Tags {
-		tagItems, _ := repo.FindAllByTag(tag)
+	repo.removeTags(oldItem)
+
+	repo.itemByID[item.ID] = item
+
+	//adding the new tag
+	for _, tag := range item.Tags {
+		items := repo.itemByTag[tag]
+		repo.itemByTag[tag] = append(items, item)
+	}
+
+	repo.events = append(repo.events, event)
+	return nil
+}
+
+func (repo *inMemoryRepository) DeleteItem(ctx context.Context, ID string, currentVersion int, event item.Event) error {
+	oldItem, ok := repo.itemByID[ID]
+	if !ok || oldItem.Version != currentVersion {
+		return business.ErrZeroAffected
+	}
+
+	if repo.inUse[ID] {
+		return business.ErrItemInUse
+	}
+
+	repo.removeTags(oldItem)
+	delete(repo.itemByID, ID)
+
+	repo.events = append(repo.events, event)
+	return nil
+}
+
+func (repo *inMemoryRepository) FindChangesAfter(ctx context.Context, token item.ChangeToken, limit int) ([]item.Change, error) {
+	//the stored events play the change rows, only the latest event of each item is kept
+	latest := make(map[string]int)
+	for idx, event := range repo.events {
+		latest[event.ItemID] = idx
+	}
+
+	var changes []item.Change
+	for idx, event := range repo.events {
+		seq := int64(idx + 1)
+		if latest[event.ItemID] != idx || seq <= token.Seq {
+			continue
+		}
+
+		if len(changes) == limit {
+			break
+		}
+
+		changes = append(changes, item.Change{
+			ItemID:     event.ItemID,
+			Deleted:    event.Type == item.ItemDeleted,
+			Item:       event.After,
+			Version:    event.Version,
+			ModifiedAt: event.OccurredAt,
+			ModifiedBy: event.Actor,
+			Token:      item.ChangeToken{Seq: seq},
+		})
+	}
+
+	return changes, nil
+}
+
+func (repo *inMemoryRepository) removeTags(item item.Item) {
+	for _, tag := range item.Tags {
+		tagItems := repo.itemByTag[tag]
 
 		itemIndex := -1
 		for idx, tagItem := range tagItems {
@@ -433,13 +972,4 @@ func (repo *inMemoryRepository) UpdateItem(item item.Item, currentVersion int) e
 
 		repo.itemByTag[tag] = tagItems
 	}
-
-	repo.itemByID[item.ID] = item
-
-	//adding the new tag
-	for _, tag := range item.Tags {
-		items := repo.itemByTag[tag]
-		repo.itemByTag[tag] = append(items, item)
-	}
-	return nil
 }
@@ -5,4 +5,12 @@ type UpsertItemSpec struct {
 	Name        string   `validate:"required"`
 	Description string   `validate:"required,min=3"`
 	Tags        []string `validate:"required"`
+	SalePrice   *MoneySpec
+	RentalRate  *MoneySpec
+}
+
+//MoneySpec money in minor unit of ISO 4217 currency, optional price will be nil
+type MoneySpec struct {
+	Amount   int64  `validate:"gte=0"`
+	Currency string `validate:"required,currency"`
 }
@@ -0,0 +1,125 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	//ErrInvalidCurrency Error when currency is not a supported ISO 4217 code
+	ErrInvalidCurrency = errors.New("Invalid currency")
+
+	//ErrCurrencyMismatch Error when operate two money with different currency
+	ErrCurrencyMismatch = errors.New("Currency mismatch")
+)
+
+//minorUnits number of decimal digits of the supported ISO 4217 currencies
+var minorUnits = map[string]int{
+	"AUD": 2, "BHD": 3, "CAD": 2, "CHF": 2, "CNY": 2,
+	"EUR": 2, "GBP": 2, "HKD": 2, "IDR": 2, "INR": 2,
+	"JPY": 0, "KRW": 0, "KWD": 3, "MYR": 2, "NZD": 2,
+	"PHP": 2, "SGD": 2, "THB": 2, "USD": 2, "VND": 0,
+}
+
+//Money amount of money in the smallest unit of its currency (e.g. cent for USD) to avoid floating point error
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+//New Create money with given amount in minor unit and ISO 4217 currency code
+func New(amount int64, currency string) (Money, error) {
+	if !IsValidCurrency(currency) {
+		return Money{}, ErrInvalidCurrency
+	}
+
+	return Money{amount, currency}, nil
+}
+
+//IsValidCurrency Return true if given code is supported ISO 4217 currency code
+func IsValidCurrency(currency string) bool {
+	_, ok := minorUnits[currency]
+	return ok
+}
+
+//Add Sum two money with the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return Money{m.Amount + other.Amount, m.Currency}, nil
+}
+
+//Subtract Subtract other money from this money with the same currency
+func (m Money) Subtract(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return Money{m.Amount - other.Amount, m.Currency}, nil
+}
+
+//Multiply Multiply money by given quantity
+func (m Money) Multiply(quantity int64) Money {
+	return Money{m.Amount * quantity, m.Currency}
+}
+
+//Percent Return given percentage of the money, rounded half up to the minor unit
+func (m Money) Percent(percent int64) Money {
+	amount := m.Amount * percent
+	if amount >= 0 {
+		amount = (amount + 50) / 100
+	} else {
+		amount = (amount - 50) / 100
+	}
+
+	return Money{amount, m.Currency}
+}
+
+//Compare Return -1, 0 or 1 when this money is less than, equal or greater than other money
+func (m Money) Compare(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, ErrCurrencyMismatch
+	}
+
+	if m.Amount < other.Amount {
+		return -1, nil
+	} else if m.Amount > other.Amount {
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+//IsNegative Return true if amount is less than zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+//String Format money in major unit, e.g. "USD 12.50"
+func (m Money) String() string {
+	digits := minorUnits[m.Currency]
+	amount := m.Amount
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	if digits == 0 {
+		return fmt.Sprintf("%s %s%d", m.Currency, sign, amount)
+	}
+
+	divisor := int64(1)
+	for i := 0; i < digits; i++ {
+		divisor *= 10
+	}
+
+	fraction := fmt.Sprintf("%d", amount%divisor)
+	fraction = strings.Repeat("0", digits-len(fraction)) + fraction
+
+	return fmt.Sprintf("%s %s%d.%s", m.Currency, sign, amount/divisor, fraction)
+}
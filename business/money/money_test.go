@@ -0,0 +1,85 @@
+package money_test
+
+import (
+	"sample-order/business/money"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Expect success create money", func(t *testing.T) {
+		m, err := money.New(1250, "USD")
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if m.Amount != 1250 || m.Currency != "USD" {
+			t.Error("Expect money is equal as given", m)
+		}
+	})
+
+	t.Run("Expect failed create money on invalid currency", func(t *testing.T) {
+		_, err := money.New(1250, "usd")
+
+		if err != money.ErrInvalidCurrency {
+			t.Error("Expect error invalid currency. Error is: ", err)
+		}
+	})
+}
+
+func TestArithmetic(t *testing.T) {
+	usd := money.Money{Amount: 1250, Currency: "USD"}
+	idr := money.Money{Amount: 1250, Currency: "IDR"}
+
+	t.Run("Expect add and subtract money with same currency", func(t *testing.T) {
+		sum, err := usd.Add(usd)
+		if err != nil || sum.Amount != 2500 {
+			t.Error("Expect sum is 2500", sum, err)
+		}
+
+		diff, err := usd.Subtract(money.Money{Amount: 2000, Currency: "USD"})
+		if err != nil || diff.Amount != -750 || !diff.IsNegative() {
+			t.Error("Expect difference is -750", diff, err)
+		}
+	})
+
+	t.Run("Expect failed operate money with different currency", func(t *testing.T) {
+		if _, err := usd.Add(idr); err != money.ErrCurrencyMismatch {
+			t.Error("Expect error currency mismatch on add. Error is: ", err)
+		}
+
+		if _, err := usd.Compare(idr); err != money.ErrCurrencyMismatch {
+			t.Error("Expect error currency mismatch on compare. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect multiply and percent rounded to minor unit", func(t *testing.T) {
+		if usd.Multiply(3).Amount != 3750 {
+			t.Error("Expect multiply result is 3750")
+		}
+
+		if usd.Percent(15).Amount != 188 {
+			t.Error("Expect 15 percent of 1250 is rounded to 188", usd.Percent(15))
+		}
+	})
+
+	t.Run("Expect compare money", func(t *testing.T) {
+		result, _ := usd.Compare(money.Money{Amount: 2000, Currency: "USD"})
+		if result != -1 {
+			t.Error("Expect 1250 is less than 2000")
+		}
+	})
+}
+
+func TestString(t *testing.T) {
+	cases := map[string]money.Money{
+		"USD 12.05": {Amount: 1205, Currency: "USD"},
+		"USD -0.50": {Amount: -50, Currency: "USD"},
+		"JPY 1500":  {Amount: 1500, Currency: "JPY"},
+		"KWD 1.005": {Amount: 1005, Currency: "KWD"},
+	}
+
+	for expected, m := range cases {
+		if m.String() != expected {
+			t.Error("Expect formatted money is "+expected+" but got ", m.String())
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package order
+
+import (
+	"sample-order/business/money"
+	"time"
+)
+
+//Line ordered item with the name and price captured when the order is placed
+type Line struct {
+	ItemID    string
+	ItemName  string
+	Quantity  int
+	UnitPrice money.Money
+	Subtotal  money.Money
+}
+
+//NewLine create order line, subtotal is unit price multiplied by quantity
+func NewLine(itemID string, itemName string, quantity int, unitPrice money.Money) Line {
+	return Line{
+		ItemID:    itemID,
+		ItemName:  itemName,
+		Quantity:  quantity,
+		UnitPrice: unitPrice,
+		Subtotal:  unitPrice.Multiply(int64(quantity)),
+	}
+}
+
+//Order sales order of a customer
+type Order struct {
+	ID            string
+	Customer      string
+	Lines         []Line
+	Subtotal      money.Money //sum of the line subtotals
+	Discount      money.Money //zero when the order has no promotion
+	PromotionCode string      //code of the applied promotion, empty when there is none
+	Total         money.Money //subtotal minus discount
+	Status        Status
+	Transitions   []Transition
+	CreatedAt     time.Time
+	CreatedBy     string
+	ModifiedAt    time.Time
+	ModifiedBy    string
+	Version       int
+}
+
+//NewOrder create new draft order, every line must have the same currency
+func NewOrder(
+	id string,
+	customer string,
+	lines []Line,
+	creator string,
+	createdAt time.Time) (Order, error) {
+
+	total := money.Money{Currency: lines[0].Subtotal.Currency}
+
+	for _, line := range lines {
+		var err error
+		if total, err = total.Add(line.Subtotal); err != nil {
+			return Order{}, err
+		}
+	}
+
+	return Order{
+		ID:         id,
+		Customer:   customer,
+		Lines:      lines,
+		Subtotal:   total,
+		Discount:   money.Money{Currency: total.Currency},
+		Total:      total,
+		Status:     Draft,
+		CreatedAt:  createdAt,
+		CreatedBy:  creator,
+		ModifiedAt: createdAt,
+		ModifiedBy: creator,
+		Version:    1,
+	}, nil
+}
+
+//ApplyDiscount reduce the total of new order by the promotion discount
+func (order *Order) ApplyDiscount(code string, discount money.Money) error {
+	total, err := order.Subtotal.Subtract(discount)
+	if err != nil {
+		return err
+	}
+
+	order.Discount = discount
+	order.PromotionCode = code
+	order.Total = total
+
+	return nil
+}
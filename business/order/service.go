@@ -0,0 +1,279 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"sample-order/business"
+	"sample-order/business/item"
+	"sample-order/business/order/spec"
+	"sample-order/business/promotion"
+	"sample-order/business/stock"
+	stockSpec "sample-order/business/stock/spec"
+	"sample-order/util"
+	"time"
+
+	validator "github.com/go-playground/validator/v10"
+)
+
+//Repository ingoing port for order
+type Repository interface {
+	//FindOrderByID If data not found will return nil without error
+	FindOrderByID(ID string) (*Order, error)
+
+	//FindOrdersByCustomer Return orders of the customer from the newest, empty slice if there is none
+	FindOrdersByCustomer(customer string) ([]Order, error)
+
+	//InsertOrder Insert new order together with its lines
+	InsertOrder(order Order) error
+
+	//UpdateOrder Update the status and append the latest transition of the order.
+	//If data not found or version is not match will return business.ErrZeroAffected
+	UpdateOrder(order Order, currentVersion int) error
+
+	//RevertOrder Update the status and drop the transitions after the last one of the given order, used to undo claimed transition.
+	//If data not found or version is not match will return business.ErrZeroAffected
+	RevertOrder(order Order, currentVersion int) error
+}
+
+//ItemService outgoing port to get the ordered item and its sale price
+type ItemService interface {
+	GetItemByID(ctx context.Context, ID string) (*item.Item, error)
+}
+
+//StockService outgoing port to hold the ordered stock from the order is placed until it is paid
+type StockService interface {
+	ReserveStock(itemID string, reserveStockSpec stockSpec.ReserveStockSpec, actor string) (*stock.Reservation, error)
+
+	ReleaseReservation(ID string, actor string) error
+
+	ReleaseReservations(reference string, actor string) error
+
+	ConvertReservations(reference string, actor string) error
+}
+
+//PromotionService outgoing port to apply promotion code into the order
+type PromotionService interface {
+	RedeemPromotion(code string, user string, orderID string, lines []promotion.Line) (*promotion.Discount, error)
+
+	ReleaseRedemption(orderID string) error
+}
+
+//Service outgoing port for order
+type Service interface {
+	GetOrderByID(ID string) (*Order, error)
+
+	GetOrdersByCustomer(customer string) ([]Order, error)
+
+	CreateOrder(createOrderSpec spec.CreateOrderSpec, createdBy string) (string, error)
+
+	TransitionOrder(ID string, status Status, currentVersion int, modifiedBy string) error
+
+	CancelOrder(ID string, currentVersion int, modifiedBy string) error
+}
+
+//=============== The implementation of those interface put below =======================
+
+type service struct {
+	repository       Repository
+	itemService      ItemService
+	stockService     StockService
+	promotionService PromotionService
+	validate         *validator.Validate
+}
+
+//NewService Construct order service object
+func NewService(repository Repository, itemService ItemService, stockService StockService, promotionService PromotionService) Service {
+	return &service{
+		repository,
+		itemService,
+		stockService,
+		promotionService,
+		validator.New(),
+	}
+}
+
+//GetOrderByID Get order by given ID, return nil if not exist
+func (s *service) GetOrderByID(ID string) (*Order, error) {
+	return s.repository.FindOrderByID(ID)
+}
+
+//GetOrdersByCustomer Get orders of the customer, return zero array if there is no order
+func (s *service) GetOrdersByCustomer(customer string) ([]Order, error) {
+	orders, err := s.repository.FindOrdersByCustomer(customer)
+	if err != nil || orders == nil {
+		return []Order{}, err
+	}
+
+	return orders, nil
+}
+
+//CreateOrder Create draft order with the current sale price of each item and the discount of the promotion code.
+//Will return ErrInvalidSpec when item is not exists or price currency is mixed, ErrNotForSale when item has no sale price
+//or business.PromotionError when the promotion can not be applied
+func (s *service) CreateOrder(createOrderSpec spec.CreateOrderSpec, createdBy string) (string, error) {
+	if err := s.validate.Struct(createOrderSpec); err != nil {
+		return "", business.ErrInvalidSpec
+	}
+
+	var lines []Line
+	var promotionLines []promotion.Line
+
+	for _, lineSpec := range mergeLines(createOrderSpec.Lines) {
+		item, err := s.itemService.GetItemByID(context.TODO(), lineSpec.ItemID)
+		if err != nil {
+			return "", err
+		} else if item == nil {
+			return "", business.ErrInvalidSpec
+		} else if item.SalePrice == nil {
+			return "", business.ErrNotForSale
+		}
+
+		line := NewLine(item.ID, item.Name, lineSpec.Quantity, *item.SalePrice)
+		lines = append(lines, line)
+		promotionLines = append(promotionLines, promotion.Line{ItemID: item.ID, Tags: item.Tags, Subtotal: line.Subtotal})
+	}
+
+	order, err := NewOrder(
+		util.GenerateID(),
+		createOrderSpec.Customer,
+		lines,
+		createdBy,
+		time.Now(),
+	)
+
+	if err != nil {
+		return "", business.ErrInvalidSpec
+	}
+
+	if len(createOrderSpec.PromotionCode) > 0 {
+		discount, err := s.promotionService.RedeemPromotion(createOrderSpec.PromotionCode, order.Customer, order.ID, promotionLines)
+		if err != nil {
+			return "", err
+		}
+
+		if err := order.ApplyDiscount(discount.Code, discount.Amount); err != nil {
+			s.promotionService.ReleaseRedemption(order.ID)
+			return "", err
+		}
+	}
+
+	if err := s.repository.InsertOrder(order); err != nil {
+		s.promotionService.ReleaseRedemption(order.ID)
+		return "", err
+	}
+
+	return order.ID, nil
+}
+
+//TransitionOrder Move order into next lifecycle status and record who did it. Placing the order reserve the stock of every line,
+//paying convert the reservations into sale and cancelling release them. Cancelled or refunded order give back its promotion use.
+//The transition is claimed before its side effects, when they fail the claim is undone without being recorded.
+//Will return business.TransitionError when the status can not be reached, ErrInsufficientStock when the stock can not be reserved,
+//ErrReservationExpired when paying after the reservations expired or ErrHasBeenModified if data version is not match
+func (s *service) TransitionOrder(ID string, status Status, currentVersion int, modifiedBy string) error {
+	if len(modifiedBy) == 0 {
+		return business.ErrInvalidSpec
+	}
+
+	order, err := s.repository.FindOrderByID(ID)
+
+	if err != nil {
+		return err
+	} else if order == nil {
+		return business.ErrNotFound
+	} else if order.Version != currentVersion {
+		return business.ErrHasBeenModified
+	}
+
+	newOrder, err := order.TransitionTo(status, modifiedBy, time.Now())
+	if err != nil {
+		return err
+	}
+
+	//claim the transition before the side effects, so only one of the concurrent transitions apply them
+	if err := s.repository.UpdateOrder(newOrder, currentVersion); err != nil {
+		if err == business.ErrZeroAffected {
+			return business.ErrHasBeenModified
+		}
+
+		return err
+	}
+
+	if err := s.handleTransition(newOrder, modifiedBy); err != nil {
+		//nothing is applied when the side effect fail, so the order is put back into its previous status
+		if revertErr := s.repository.RevertOrder(newOrder.Revert(modifiedBy, time.Now()), newOrder.Version); revertErr != nil {
+			return fmt.Errorf("%w, and order %s can not be moved back into %s: %v", err, newOrder.ID, order.Status, revertErr)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+//CancelOrder Cancel draft or placed order
+func (s *service) CancelOrder(ID string, currentVersion int, modifiedBy string) error {
+	return s.TransitionOrder(ID, Cancelled, currentVersion, modifiedBy)
+}
+
+//reserveLines reserve the stock of every line with the order ID as reference, all or nothing.
+//Only the reservations made here are released on failure
+func (s *service) reserveLines(order Order, actor string) error {
+	var reservationIDs []string
+
+	for _, line := range order.Lines {
+		reserveStockSpec := stockSpec.ReserveStockSpec{Quantity: line.Quantity, Reference: order.ID}
+
+		reservation, err := s.stockService.ReserveStock(line.ItemID, reserveStockSpec, actor)
+		if err != nil {
+			for _, ID := range reservationIDs {
+				s.stockService.ReleaseReservation(ID, actor)
+			}
+
+			return err
+		}
+
+		reservationIDs = append(reservationIDs, reservation.ID)
+	}
+
+	return nil
+}
+
+//handleTransition apply the side effects of the claimed order based on its new status, either all of them or none.
+//Releasing the stock of cancelled order is the last step, when it fail the reservations are released by the sweeper once expired
+func (s *service) handleTransition(order Order, actor string) error {
+	switch order.Status {
+	case Placed:
+		return s.reserveLines(order, actor)
+	case Paid:
+		return s.stockService.ConvertReservations(order.ID, actor)
+	case Cancelled:
+		if err := s.promotionService.ReleaseRedemption(order.ID); err != nil {
+			return err
+		}
+
+		s.stockService.ReleaseReservations(order.ID, actor)
+	case Refunded:
+		return s.promotionService.ReleaseRedemption(order.ID)
+	}
+
+	return nil
+}
+
+//mergeLines sum quantity of lines with the same item, keep the order of first occurrence
+func mergeLines(lineSpecs []spec.LineSpec) []spec.LineSpec {
+	var merged []spec.LineSpec
+	indexByItemID := make(map[string]int)
+
+	for _, lineSpec := range lineSpecs {
+		if index, ok := indexByItemID[lineSpec.ItemID]; ok {
+			merged[index].Quantity += lineSpec.Quantity
+			continue
+		}
+
+		indexByItemID[lineSpec.ItemID] = len(merged)
+		merged = append(merged, lineSpec)
+	}
+
+	return merged
+}
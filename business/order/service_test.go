@@ -0,0 +1,663 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sample-order/business"
+	"sample-order/business/item"
+	"sample-order/business/money"
+	"sample-order/business/order"
+	"sample-order/business/order/spec"
+	"sample-order/business/promotion"
+	"sample-order/business/stock"
+	stockSpec "sample-order/business/stock/spec"
+	"sort"
+	"testing"
+	"time"
+)
+
+var cameraItemID = "5f350b7d21148431abc65290"
+var tripodItemID = "5f350b7d21148431abc65291"
+var rupiahItemID = "5f350b7d21148431abc65292"
+var notForSaleItemID = "5f350b7d21148431abc65293"
+var notFoundItemID = "5f350b7d21148431abc65299"
+var errorItemID = "error-item-id"
+var errorFind = errors.New("error on find")
+
+func TestCreateOrder(t *testing.T) {
+	t.Run("Expect draft order created with captured price", func(t *testing.T) {
+		service := newService()
+		ID, err := service.CreateOrder(spec.CreateOrderSpec{
+			Customer: "john",
+			Lines: []spec.LineSpec{
+				{ItemID: cameraItemID, Quantity: 1},
+				{ItemID: tripodItemID, Quantity: 2},
+				{ItemID: cameraItemID, Quantity: 1},
+			},
+		}, "cashier")
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		current, _ := service.GetOrderByID(ID)
+
+		if current.Status != order.Draft || current.Version != 1 || current.Customer != "john" {
+			t.Error("Expect draft order of the customer", current)
+		}
+
+		if len(current.Lines) != 2 || current.Lines[0].Quantity != 2 || current.Lines[0].ItemName != "Camera" || current.Lines[1].Subtotal.Amount != 5000 {
+			t.Error("Expect camera lines merged and subtotal computed", current.Lines)
+		}
+
+		if current.Total.Amount != 105000 || current.Total.Currency != "USD" {
+			t.Error("Expect total is USD 1050.00 but got ", current.Total)
+		}
+	})
+
+	t.Run("Expect failed on invalid spec", func(t *testing.T) {
+		invalidSpecs := []spec.CreateOrderSpec{
+			{Customer: "", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}}},
+			{Customer: "john", Lines: []spec.LineSpec{}},
+			{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 0}}},
+			{Customer: "john", Lines: []spec.LineSpec{{ItemID: "", Quantity: 1}}},
+			{Customer: "john", Lines: []spec.LineSpec{{ItemID: notFoundItemID, Quantity: 1}}},
+			{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}, {ItemID: rupiahItemID, Quantity: 1}}},
+		}
+
+		for _, invalidSpec := range invalidSpecs {
+			if _, err := newService().CreateOrder(invalidSpec, "cashier"); err != business.ErrInvalidSpec {
+				t.Error("Expect error invalid spec. Error is: ", err, invalidSpec)
+			}
+		}
+	})
+
+	t.Run("Expect failed on item without sale price", func(t *testing.T) {
+		_, err := newService().CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: notForSaleItemID, Quantity: 1}}}, "cashier")
+
+		if err != business.ErrNotForSale {
+			t.Error("Expect error not for sale. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed on item service error", func(t *testing.T) {
+		_, err := newService().CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: errorItemID, Quantity: 1}}}, "cashier")
+
+		if err != errorFind {
+			t.Error("Expect error on find. Error is: ", err)
+		}
+	})
+}
+
+func TestGetOrdersByCustomer(t *testing.T) {
+	t.Run("Expect only orders of the customer", func(t *testing.T) {
+		service := newService()
+		service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}}}, "cashier")
+		service.CreateOrder(spec.CreateOrderSpec{Customer: "jane", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}}}, "cashier")
+		service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: tripodItemID, Quantity: 1}}}, "cashier")
+
+		orders, err := service.GetOrdersByCustomer("john")
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if len(orders) != 2 {
+			t.Error("Expect two orders of john but got ", len(orders))
+		}
+	})
+
+	t.Run("Expect empty orders when customer has no order", func(t *testing.T) {
+		orders, err := newService().GetOrdersByCustomer("nobody")
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if orders == nil || len(orders) != 0 {
+			t.Error("Expect orders is empty")
+		}
+	})
+}
+
+func TestCancelOrder(t *testing.T) {
+	t.Run("Expect draft order cancelled", func(t *testing.T) {
+		service := newService()
+		ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}}}, "cashier")
+
+		if err := service.CancelOrder(ID, 1, "john"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		current, _ := service.GetOrderByID(ID)
+		if current.Status != order.Cancelled || current.Version != 2 || current.ModifiedBy != "john" {
+			t.Error("Expect cancelled order with version 2", current)
+		}
+
+		if err := service.CancelOrder(ID, 2, "john"); !errors.Is(err, business.ErrInvalidTransition) {
+			t.Error("Expect error invalid transition on cancelled order. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed on version mismatch", func(t *testing.T) {
+		service := newService()
+		ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}}}, "cashier")
+
+		if err := service.CancelOrder(ID, 2, "john"); err != business.ErrHasBeenModified {
+			t.Error("Expect error has been modified. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed on order not found", func(t *testing.T) {
+		if err := newService().CancelOrder("5f350b7d21148431abc65000", 1, "john"); err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+	})
+}
+
+func TestTransitionOrder(t *testing.T) {
+	t.Run("Expect order follow the lifecycle and record the transitions", func(t *testing.T) {
+		service := newService()
+		ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}}}, "cashier")
+
+		steps := []struct {
+			status order.Status
+			actor  string
+		}{
+			{order.Placed, "john"},
+			{order.Paid, "payment"},
+			{order.Fulfilled, "warehouse"},
+			{order.Completed, "cashier"},
+		}
+
+		for i, step := range steps {
+			if err := service.TransitionOrder(ID, step.status, i+1, step.actor); err != nil {
+				t.Error("Expect error is nil. Error: ", err, step.status)
+			}
+		}
+
+		current, _ := service.GetOrderByID(ID)
+		if current.Status != order.Completed || current.Version != 5 || len(current.Transitions) != 4 {
+			t.Error("Expect completed order with four transitions", current)
+			t.FailNow()
+		}
+
+		if current.Transitions[1].From != order.Placed || current.Transitions[1].To != order.Paid || current.Transitions[1].Actor != "payment" || current.Transitions[1].At.IsZero() {
+			t.Error("Expect paid transition recorded", current.Transitions[1])
+		}
+	})
+
+	t.Run("Expect paid order refunded but not cancelled", func(t *testing.T) {
+		service := newService()
+		ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}}}, "cashier")
+		service.TransitionOrder(ID, order.Placed, 1, "john")
+		service.TransitionOrder(ID, order.Paid, 2, "payment")
+
+		err := service.TransitionOrder(ID, order.Cancelled, 3, "john")
+
+		var transitionErr *business.TransitionError
+		if !errors.As(err, &transitionErr) || transitionErr.From != "paid" || transitionErr.To != "cancelled" {
+			t.Error("Expect transition error from paid to cancelled. Error is: ", err)
+		}
+
+		if err := service.TransitionOrder(ID, order.Refunded, 3, "cashier"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+	})
+
+	t.Run("Expect failed on illegal transition", func(t *testing.T) {
+		illegalSteps := []order.Status{order.Paid, order.Fulfilled, order.Completed, order.Refunded, order.Draft, "unknown"}
+
+		for _, status := range illegalSteps {
+			service := newService()
+			ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}}}, "cashier")
+
+			if err := service.TransitionOrder(ID, status, 1, "john"); !errors.Is(err, business.ErrInvalidTransition) {
+				t.Error("Expect error invalid transition from draft. Error is: ", err, status)
+			}
+		}
+	})
+
+	t.Run("Expect failed on empty actor", func(t *testing.T) {
+		service := newService()
+		ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}}}, "cashier")
+
+		if err := service.TransitionOrder(ID, order.Placed, 1, ""); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec. Error is: ", err)
+		}
+	})
+}
+
+func TestOrderStock(t *testing.T) {
+	t.Run("Expect placed order reserve the stock and paid order sell it", func(t *testing.T) {
+		service, stockService := newServiceWithStock()
+		ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}, {ItemID: tripodItemID, Quantity: 3}}}, "cashier")
+
+		if err := service.TransitionOrder(ID, order.Placed, 1, "john"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		if stockService.availableByItemID[cameraItemID] != 1 || stockService.reservedByReference[ID][tripodItemID] != 3 {
+			t.Error("Expect stock of every line reserved with order ID as reference", stockService.reservedByReference)
+		}
+
+		if err := service.TransitionOrder(ID, order.Paid, 2, "payment"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		if stockService.soldByItemID[cameraItemID] != 1 || stockService.soldByItemID[tripodItemID] != 3 || len(stockService.reservedByReference[ID]) != 0 {
+			t.Error("Expect reservations converted into sale", stockService.soldByItemID)
+		}
+	})
+
+	t.Run("Expect two customers can not place order for the last unit", func(t *testing.T) {
+		service, stockService := newServiceWithStock()
+		firstID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}}}, "cashier")
+		secondID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "jane", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 2}}}, "cashier")
+
+		service.TransitionOrder(firstID, order.Placed, 1, "john")
+		err := service.TransitionOrder(secondID, order.Placed, 1, "jane")
+
+		if err != business.ErrInsufficientStock {
+			t.Error("Expect error insufficient stock. Error is: ", err)
+		}
+
+		current, _ := service.GetOrderByID(secondID)
+		if current.Status != order.Draft || len(current.Transitions) != 0 {
+			t.Error("Expect order stay as draft without transition", current)
+		}
+
+		if stockService.availableByItemID[cameraItemID] != 1 {
+			t.Error("Expect only the first order hold the stock", stockService.availableByItemID)
+		}
+	})
+
+	t.Run("Expect nothing reserved when one of the lines is insufficient", func(t *testing.T) {
+		service, stockService := newServiceWithStock()
+		ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: tripodItemID, Quantity: 1}, {ItemID: cameraItemID, Quantity: 3}}}, "cashier")
+
+		if err := service.TransitionOrder(ID, order.Placed, 1, "john"); err != business.ErrInsufficientStock {
+			t.Error("Expect error insufficient stock. Error is: ", err)
+		}
+
+		if stockService.availableByItemID[tripodItemID] != 10 {
+			t.Error("Expect reserved line released", stockService.availableByItemID)
+		}
+	})
+
+	t.Run("Expect cancelled order release the stock", func(t *testing.T) {
+		service, stockService := newServiceWithStock()
+		ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 2}}}, "cashier")
+		service.TransitionOrder(ID, order.Placed, 1, "john")
+
+		if err := service.CancelOrder(ID, 2, "john"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		if stockService.availableByItemID[cameraItemID] != 2 {
+			t.Error("Expect stock released", stockService.availableByItemID)
+		}
+	})
+
+	t.Run("Expect payment failed after the reservations expired", func(t *testing.T) {
+		service, stockService := newServiceWithStock()
+		ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}}}, "cashier")
+		service.TransitionOrder(ID, order.Placed, 1, "john")
+		stockService.expired = true
+
+		if err := service.TransitionOrder(ID, order.Paid, 2, "payment"); err != business.ErrReservationExpired {
+			t.Error("Expect error reservation expired. Error is: ", err)
+		}
+
+		current, _ := service.GetOrderByID(ID)
+		if current.Status != order.Placed {
+			t.Error("Expect order stay as placed", current.Status)
+		}
+
+		latest := current.Transitions[len(current.Transitions)-1]
+		if current.Version != 4 || len(current.Transitions) != 1 || latest.To != order.Placed {
+			t.Error("Expect the payment is not recorded", current.Version, current.Transitions)
+		}
+	})
+
+	t.Run("Expect losing place keep the reservations of the winning order", func(t *testing.T) {
+		repo := newInMemoryRepository()
+		stockService := newInMemoryStockService()
+		service := order.NewService(repo, &inMemoryItemService{}, stockService, newInMemoryPromotionService())
+
+		ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}}}, "cashier")
+		service.TransitionOrder(ID, order.Placed, 1, "john")
+
+		repo.modifiedConcurrently = true
+		if err := service.TransitionOrder(ID, order.Placed, 1, "john"); err != business.ErrHasBeenModified {
+			t.Error("Expect error has been modified. Error is: ", err)
+		}
+
+		if stockService.reservedByReference[ID][cameraItemID] != 1 || stockService.availableByItemID[cameraItemID] != 1 {
+			t.Error("Expect only the winning order reserved the stock", stockService.reservedByReference)
+		}
+	})
+
+	t.Run("Expect cancel keep the stock when the promotion can not be given back", func(t *testing.T) {
+		service, stockService, promotionService := newServiceWithPromotion()
+		ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: tripodItemID, Quantity: 1}}, PromotionCode: "ACCESSORY20"}, "cashier")
+		service.TransitionOrder(ID, order.Placed, 1, "john")
+		promotionService.releaseErr = errors.New("promotion unavailable")
+
+		if err := service.CancelOrder(ID, 2, "john"); err != promotionService.releaseErr {
+			t.Error("Expect error of the promotion. Error is: ", err)
+		}
+
+		current, _ := service.GetOrderByID(ID)
+		if current.Status != order.Placed || stockService.reservedByReference[ID][tripodItemID] != 1 {
+			t.Error("Expect order stay placed with its reservation", current.Status, stockService.reservedByReference)
+		}
+	})
+
+	t.Run("Expect cancel losing to concurrent transition keep the stock and promotion", func(t *testing.T) {
+		repo := newInMemoryRepository()
+		stockService := newInMemoryStockService()
+		promotionService := newInMemoryPromotionService()
+		service := order.NewService(repo, &inMemoryItemService{}, stockService, promotionService)
+
+		ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: tripodItemID, Quantity: 1}}, PromotionCode: "ACCESSORY20"}, "cashier")
+		service.TransitionOrder(ID, order.Placed, 1, "john")
+
+		repo.modifiedConcurrently = true
+		if err := service.CancelOrder(ID, 2, "john"); err != business.ErrHasBeenModified {
+			t.Error("Expect error has been modified. Error is: ", err)
+		}
+
+		if stockService.reservedByReference[ID][tripodItemID] != 1 {
+			t.Error("Expect reservation kept for the winning transition", stockService.reservedByReference)
+		}
+
+		if promotionService.redeemedByOrderID[ID] != "ACCESSORY20" {
+			t.Error("Expect redemption kept for the winning transition", promotionService.redeemedByOrderID)
+		}
+	})
+}
+
+func TestOrderPromotion(t *testing.T) {
+	t.Run("Expect promotion discount the applicable lines", func(t *testing.T) {
+		service, _, promotionService := newServiceWithPromotion()
+		ID, err := service.CreateOrder(spec.CreateOrderSpec{
+			Customer:      "john",
+			Lines:         []spec.LineSpec{{ItemID: cameraItemID, Quantity: 1}, {ItemID: tripodItemID, Quantity: 2}},
+			PromotionCode: "ACCESSORY20",
+		}, "cashier")
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		current, _ := service.GetOrderByID(ID)
+		if current.Subtotal.Amount != 55000 || current.Discount.Amount != 1000 || current.Total.Amount != 54000 || current.PromotionCode != "ACCESSORY20" {
+			t.Error("Expect 20 percent discount of the tripod lines", current.Subtotal, current.Discount, current.Total)
+		}
+
+		if promotionService.redeemedByOrderID[ID] != "ACCESSORY20" {
+			t.Error("Expect promotion redeemed by the order", promotionService.redeemedByOrderID)
+		}
+	})
+
+	t.Run("Expect order not created when the promotion is used up", func(t *testing.T) {
+		service, _, _ := newServiceWithPromotion()
+		service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: tripodItemID, Quantity: 1}}, PromotionCode: "ACCESSORY20"}, "cashier")
+		ID, err := service.CreateOrder(spec.CreateOrderSpec{Customer: "jane", Lines: []spec.LineSpec{{ItemID: tripodItemID, Quantity: 1}}, PromotionCode: "ACCESSORY20"}, "cashier")
+
+		var promotionError *business.PromotionError
+		if !errors.As(err, &promotionError) || promotionError.Reason != promotion.ReasonUsageLimit || ID != "" {
+			t.Error("Expect error usage limit is reached. Error is: ", err)
+		}
+
+		orders, _ := service.GetOrdersByCustomer("jane")
+		if len(orders) != 0 {
+			t.Error("Expect no order created", orders)
+		}
+	})
+
+	t.Run("Expect cancelled order give back the promotion use", func(t *testing.T) {
+		service, _, promotionService := newServiceWithPromotion()
+		ID, _ := service.CreateOrder(spec.CreateOrderSpec{Customer: "john", Lines: []spec.LineSpec{{ItemID: tripodItemID, Quantity: 1}}, PromotionCode: "ACCESSORY20"}, "cashier")
+
+		if err := service.CancelOrder(ID, 1, "john"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		if _, ok := promotionService.redeemedByOrderID[ID]; ok {
+			t.Error("Expect redemption released", promotionService.redeemedByOrderID)
+		}
+
+		if _, err := service.CreateOrder(spec.CreateOrderSpec{Customer: "jane", Lines: []spec.LineSpec{{ItemID: tripodItemID, Quantity: 1}}, PromotionCode: "ACCESSORY20"}, "cashier"); err != nil {
+			t.Error("Expect promotion can be used again. Error: ", err)
+		}
+	})
+}
+
+func newService() order.Service {
+	service, _ := newServiceWithStock()
+	return service
+}
+
+func newServiceWithStock() (order.Service, *inMemoryStockService) {
+	service, stockService, _ := newServiceWithPromotion()
+	return service, stockService
+}
+
+func newServiceWithPromotion() (order.Service, *inMemoryStockService, *inMemoryPromotionService) {
+	repo := newInMemoryRepository()
+	stockService := newInMemoryStockService()
+	promotionService := newInMemoryPromotionService()
+	return order.NewService(repo, &inMemoryItemService{}, stockService, promotionService), stockService, promotionService
+}
+
+//inMemoryPromotionService hold promotions by code and the used code per order
+type inMemoryPromotionService struct {
+	promotionByCode   map[string]*promotion.Promotion
+	redeemedByOrderID map[string]string
+	releaseErr        error
+}
+
+func newInMemoryPromotionService() *inMemoryPromotionService {
+	return &inMemoryPromotionService{
+		map[string]*promotion.Promotion{
+			"ACCESSORY20": {
+				ID:         "promotion-1",
+				Code:       "ACCESSORY20",
+				Type:       promotion.Percentage,
+				Percent:    20,
+				Tags:       []string{"accessory"},
+				ValidFrom:  time.Now().AddDate(0, 0, -1),
+				UsageLimit: 1,
+			},
+		},
+		make(map[string]string),
+		nil,
+	}
+}
+
+func (s *inMemoryPromotionService) RedeemPromotion(code string, user string, orderID string, lines []promotion.Line) (*promotion.Discount, error) {
+	current, ok := s.promotionByCode[code]
+	if !ok {
+		return nil, &business.PromotionError{Code: code, Reason: promotion.ReasonUnknownCode}
+	}
+
+	discount, err := current.Evaluate(lines, 0, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	current.UsedCount++
+	s.redeemedByOrderID[orderID] = code
+
+	return &discount, nil
+}
+
+func (s *inMemoryPromotionService) ReleaseRedemption(orderID string) error {
+	if s.releaseErr != nil {
+		return s.releaseErr
+	}
+
+	if code, ok := s.redeemedByOrderID[orderID]; ok {
+		s.promotionByCode[code].UsedCount--
+		delete(s.redeemedByOrderID, orderID)
+	}
+
+	return nil
+}
+
+//inMemoryStockService hold available units per item and reserved units per reference and item
+type inMemoryStockService struct {
+	availableByItemID   map[string]int
+	soldByItemID        map[string]int
+	reservedByReference map[string]map[string]int
+	reservationByID     map[string]stock.Reservation
+	expired             bool
+}
+
+func newInMemoryStockService() *inMemoryStockService {
+	return &inMemoryStockService{
+		map[string]int{cameraItemID: 2, tripodItemID: 10},
+		make(map[string]int),
+		make(map[string]map[string]int),
+		make(map[string]stock.Reservation),
+		false,
+	}
+}
+
+func (s *inMemoryStockService) ReserveStock(itemID string, reserveStockSpec stockSpec.ReserveStockSpec, actor string) (*stock.Reservation, error) {
+	if s.availableByItemID[itemID] < reserveStockSpec.Quantity {
+		return nil, business.ErrInsufficientStock
+	}
+
+	if s.reservedByReference[reserveStockSpec.Reference] == nil {
+		s.reservedByReference[reserveStockSpec.Reference] = make(map[string]int)
+	}
+
+	s.availableByItemID[itemID] -= reserveStockSpec.Quantity
+	s.reservedByReference[reserveStockSpec.Reference][itemID] += reserveStockSpec.Quantity
+
+	reservation := stock.Reservation{
+		ID:        fmt.Sprintf("reservation-%d", len(s.reservationByID)+1),
+		ItemID:    itemID,
+		Quantity:  reserveStockSpec.Quantity,
+		Reference: reserveStockSpec.Reference,
+		Status:    stock.Active,
+	}
+	s.reservationByID[reservation.ID] = reservation
+
+	return &reservation, nil
+}
+
+func (s *inMemoryStockService) ReleaseReservation(ID string, actor string) error {
+	reservation, ok := s.reservationByID[ID]
+	if !ok || s.reservedByReference[reservation.Reference][reservation.ItemID] < reservation.Quantity {
+		return business.ErrNotFound
+	}
+
+	s.availableByItemID[reservation.ItemID] += reservation.Quantity
+	s.reservedByReference[reservation.Reference][reservation.ItemID] -= reservation.Quantity
+	if s.reservedByReference[reservation.Reference][reservation.ItemID] == 0 {
+		delete(s.reservedByReference[reservation.Reference], reservation.ItemID)
+	}
+
+	delete(s.reservationByID, ID)
+	return nil
+}
+
+func (s *inMemoryStockService) ReleaseReservations(reference string, actor string) error {
+	for itemID, quantity := range s.reservedByReference[reference] {
+		s.availableByItemID[itemID] += quantity
+	}
+
+	delete(s.reservedByReference, reference)
+	return nil
+}
+
+func (s *inMemoryStockService) ConvertReservations(reference string, actor string) error {
+	if s.expired {
+		s.ReleaseReservations(reference, actor)
+		return business.ErrReservationExpired
+	}
+
+	for itemID, quantity := range s.reservedByReference[reference] {
+		s.soldByItemID[itemID] += quantity
+	}
+
+	delete(s.reservedByReference, reference)
+	return nil
+}
+
+type inMemoryItemService struct{}
+
+func (s *inMemoryItemService) GetItemByID(ctx context.Context, ID string) (*item.Item, error) {
+	switch ID {
+	case errorItemID:
+		return nil, errorFind
+	case cameraItemID:
+		return &item.Item{ID: ID, Name: "Camera", SalePrice: &money.Money{Amount: 50000, Currency: "USD"}, Version: 1}, nil
+	case tripodItemID:
+		return &item.Item{ID: ID, Name: "Tripod", Tags: []string{"accessory"}, SalePrice: &money.Money{Amount: 2500, Currency: "USD"}, Version: 1}, nil
+	case rupiahItemID:
+		return &item.Item{ID: ID, Name: "Bag", SalePrice: &money.Money{Amount: 150000, Currency: "IDR"}, Version: 1}, nil
+	case notForSaleItemID:
+		return &item.Item{ID: ID, Name: "Lens", Version: 1}, nil
+	}
+
+	return nil, nil
+}
+
+type inMemoryRepository struct {
+	orderByID map[string]order.Order
+
+	//modifiedConcurrently make the next update fail as if other request claimed the version first
+	modifiedConcurrently bool
+}
+
+func newInMemoryRepository() *inMemoryRepository {
+	return &inMemoryRepository{make(map[string]order.Order), false}
+}
+
+func (repo *inMemoryRepository) FindOrderByID(ID string) (*order.Order, error) {
+	current, ok := repo.orderByID[ID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &current, nil
+}
+
+func (repo *inMemoryRepository) FindOrdersByCustomer(customer string) ([]order.Order, error) {
+	var orders []order.Order
+
+	for _, current := range repo.orderByID {
+		if current.Customer == customer {
+			orders = append(orders, current)
+		}
+	}
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].CreatedAt.After(orders[j].CreatedAt) })
+	return orders, nil
+}
+
+func (repo *inMemoryRepository) InsertOrder(order order.Order) error {
+	//make sure the orders have different time to keep the order
+	order.CreatedAt = order.CreatedAt.Add(time.Duration(len(repo.orderByID)) * time.Millisecond)
+	repo.orderByID[order.ID] = order
+	return nil
+}
+
+func (repo *inMemoryRepository) RevertOrder(order order.Order, currentVersion int) error {
+	return repo.UpdateOrder(order, currentVersion)
+}
+
+func (repo *inMemoryRepository) UpdateOrder(order order.Order, currentVersion int) error {
+	current, ok := repo.orderByID[order.ID]
+	if !ok || current.Version != currentVersion || repo.modifiedConcurrently {
+		repo.modifiedConcurrently = false
+		return business.ErrZeroAffected
+	}
+
+	repo.orderByID[order.ID] = order
+	return nil
+}
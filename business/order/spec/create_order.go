@@ -0,0 +1,14 @@
+package spec
+
+//CreateOrderSpec create order spec, lines with the same item are merged. Promotion code is optional
+type CreateOrderSpec struct {
+	Customer      string     `validate:"required"`
+	Lines         []LineSpec `validate:"required,min=1,dive"`
+	PromotionCode string
+}
+
+//LineSpec ordered item and its quantity
+type LineSpec struct {
+	ItemID   string `validate:"required"`
+	Quantity int    `validate:"required,gt=0"`
+}
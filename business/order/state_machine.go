@@ -0,0 +1,85 @@
+package order
+
+import (
+	"sample-order/business"
+	"time"
+)
+
+//Status lifecycle state of an order
+type Status string
+
+const (
+	//Draft order is created but not confirmed by customer
+	Draft Status = "draft"
+	//Placed order is confirmed by customer and waiting for payment
+	Placed Status = "placed"
+	//Paid payment of the order is received
+	Paid Status = "paid"
+	//Fulfilled ordered items are handed over to customer
+	Fulfilled Status = "fulfilled"
+	//Completed order is finished
+	Completed Status = "completed"
+	//Cancelled order is stopped before payment
+	Cancelled Status = "cancelled"
+	//Refunded payment is returned to customer
+	Refunded Status = "refunded"
+)
+
+//transitions next status allowed from each status, status without entry is final
+var transitions = map[Status][]Status{
+	Draft:     {Placed, Cancelled},
+	Placed:    {Paid, Cancelled},
+	Paid:      {Fulfilled, Refunded},
+	Fulfilled: {Completed, Refunded},
+}
+
+//Transition record of a status change
+type Transition struct {
+	From  Status
+	To    Status
+	Actor string
+	At    time.Time
+}
+
+//CanTransitionTo Return true if order with this status may move into next status
+func (status Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range transitions[status] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+//TransitionTo move order into next status and record the transition.
+//Return business.TransitionError when the next status is not allowed
+func (oldOrder *Order) TransitionTo(next Status, actor string, at time.Time) (Order, error) {
+	if !oldOrder.Status.CanTransitionTo(next) {
+		return Order{}, &business.TransitionError{From: string(oldOrder.Status), To: string(next)}
+	}
+
+	order := *oldOrder
+	order.Status = next
+	order.Transitions = append(append([]Transition{}, oldOrder.Transitions...), Transition{oldOrder.Status, next, actor, at})
+	order.ModifiedAt = at
+	order.ModifiedBy = actor
+	order.Version = oldOrder.Version + 1
+
+	return order, nil
+}
+
+//Revert Return the order before its latest transition with the next version, used to undo claimed transition
+//whose side effect failed. The transition is dropped instead of recorded, the state machine never go backward
+func (claimedOrder *Order) Revert(actor string, at time.Time) Order {
+	latest := claimedOrder.Transitions[len(claimedOrder.Transitions)-1]
+
+	order := *claimedOrder
+	order.Status = latest.From
+	order.Transitions = append([]Transition{}, claimedOrder.Transitions[:len(claimedOrder.Transitions)-1]...)
+	order.ModifiedAt = at
+	order.ModifiedBy = actor
+	order.Version = claimedOrder.Version + 1
+
+	return order
+}
@@ -0,0 +1,18 @@
+package outbox
+
+import "time"
+
+//Message event waiting in the outbox until it is published. Payload is the JSON encoded event.
+//Message can be published more than once, consumer should ignore the ID it already received
+type Message struct {
+	ID            string
+	AggregateType string
+	AggregateID   string
+	EventType     string
+	Version       int
+	Payload       []byte
+	OccurredAt    time.Time
+	Attempts      int
+	LastError     string
+	PublishedAt   *time.Time
+}
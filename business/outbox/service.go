@@ -0,0 +1,72 @@
+package outbox
+
+import (
+	"time"
+)
+
+//Repository ingoing port for outbox
+type Repository interface {
+	//FindPendingMessages Return at most limit unpublished messages from the oldest, empty slice if there is none
+	FindPendingMessages(limit int) ([]Message, error)
+
+	//MarkPublished Set the published time of the message so it is not published again
+	MarkPublished(ID string, publishedAt time.Time) error
+
+	//MarkFailed Increase the attempts of the message and keep the reason of the latest failure
+	MarkFailed(ID string, reason string) error
+}
+
+//Publisher outgoing port to deliver message into other systems
+type Publisher interface {
+	Publish(message Message) error
+}
+
+//Service outgoing port for outbox
+type Service interface {
+	RelayPending(limit int) (int, error)
+}
+
+//=============== The implementation of those interface put below =======================
+
+type service struct {
+	repository Repository
+	publisher  Publisher
+}
+
+//NewService Construct outbox service object
+func NewService(repository Repository, publisher Publisher) Service {
+	return &service{
+		repository,
+		publisher,
+	}
+}
+
+//RelayPending Publish pending messages in the order they occurred and return the number of published messages.
+//The relay stop on the first failed message so the order is kept, it is retried on the next relay.
+//Message is marked after it is published, so it is published again when the mark failed
+func (s *service) RelayPending(limit int) (int, error) {
+	messages, err := s.repository.FindPendingMessages(limit)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+
+	for _, message := range messages {
+		if err := s.publisher.Publish(message); err != nil {
+			if markErr := s.repository.MarkFailed(message.ID, err.Error()); markErr != nil {
+				return published, markErr
+			}
+
+			return published, err
+		}
+
+		if err := s.repository.MarkPublished(message.ID, time.Now()); err != nil {
+			return published, err
+		}
+
+		published++
+	}
+
+	return published, nil
+}
@@ -0,0 +1,155 @@
+package outbox_test
+
+import (
+	"errors"
+	"sample-order/business/outbox"
+	"sort"
+	"testing"
+	"time"
+)
+
+var errorPublish error = errors.New("error on publish")
+
+func TestRelayPending(t *testing.T) {
+	t.Run("Expect publish pending messages in order", func(t *testing.T) {
+		repo := newInMemoryRepository()
+		publisher := &inMemoryPublisher{}
+		service := outbox.NewService(repo, publisher)
+
+		published, err := service.RelayPending(10)
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if published != 3 || len(publisher.published) != 3 {
+			t.Error("Expect three messages published", published)
+			t.FailNow()
+		}
+
+		for idx, ID := range []string{"message-1", "message-2", "message-3"} {
+			if publisher.published[idx].ID != ID {
+				t.Error("Expect message published in the order it occurred", publisher.published[idx].ID)
+			}
+		}
+
+		if published, _ := service.RelayPending(10); published != 0 {
+			t.Error("Expect published messages are not published again")
+		}
+	})
+
+	t.Run("Expect publish at most limit messages", func(t *testing.T) {
+		repo := newInMemoryRepository()
+		publisher := &inMemoryPublisher{}
+		service := outbox.NewService(repo, publisher)
+
+		if published, _ := service.RelayPending(2); published != 2 {
+			t.Error("Expect only two messages published", published)
+		}
+
+		if published, _ := service.RelayPending(2); published != 1 {
+			t.Error("Expect the rest is published on next relay", published)
+		}
+	})
+
+	t.Run("Expect stop on failed message and retry it later", func(t *testing.T) {
+		repo := newInMemoryRepository()
+		publisher := &inMemoryPublisher{failedID: "message-2"}
+		service := outbox.NewService(repo, publisher)
+
+		published, err := service.RelayPending(10)
+
+		if err != errorPublish {
+			t.Error("Expect error on publish. Error is: ", err)
+		}
+
+		if published != 1 {
+			t.Error("Expect only message before the failed one is published", published)
+		}
+
+		failed := repo.messageByID["message-2"]
+		if failed.Attempts != 1 || failed.LastError != errorPublish.Error() || failed.PublishedAt != nil {
+			t.Error("Expect failure is recorded on the message", failed)
+		}
+
+		if repo.messageByID["message-3"].PublishedAt != nil {
+			t.Error("Expect message after the failed one is not published")
+		}
+
+		publisher.failedID = ""
+		published, err = service.RelayPending(10)
+
+		if err != nil || published != 2 {
+			t.Error("Expect failed message is published on retry", published, err)
+		}
+	})
+}
+
+type inMemoryRepository struct {
+	messageByID map[string]outbox.Message
+}
+
+func newInMemoryRepository() *inMemoryRepository {
+	repo := &inMemoryRepository{make(map[string]outbox.Message)}
+	occurredAt := time.Now().Add(-time.Minute)
+
+	for idx, ID := range []string{"message-1", "message-2", "message-3"} {
+		repo.messageByID[ID] = outbox.Message{
+			ID:            ID,
+			AggregateType: "item",
+			AggregateID:   "item-1",
+			EventType:     "ItemUpdated",
+			Version:       idx + 1,
+			Payload:       []byte("{}"),
+			OccurredAt:    occurredAt.Add(time.Duration(idx) * time.Second),
+		}
+	}
+
+	return repo
+}
+
+func (repo *inMemoryRepository) FindPendingMessages(limit int) ([]outbox.Message, error) {
+	messages := []outbox.Message{}
+	for _, message := range repo.messageByID {
+		if message.PublishedAt == nil {
+			messages = append(messages, message)
+		}
+	}
+
+	sort.Slice(messages, func(i, j int) bool { return messages[i].OccurredAt.Before(messages[j].OccurredAt) })
+
+	if len(messages) > limit {
+		messages = messages[:limit]
+	}
+	return messages, nil
+}
+
+func (repo *inMemoryRepository) MarkPublished(ID string, publishedAt time.Time) error {
+	message := repo.messageByID[ID]
+	message.PublishedAt = &publishedAt
+	repo.messageByID[ID] = message
+	return nil
+}
+
+func (repo *inMemoryRepository) MarkFailed(ID string, reason string) error {
+	message := repo.messageByID[ID]
+	message.Attempts++
+	message.LastError = reason
+	repo.messageByID[ID] = message
+	return nil
+}
+
+type inMemoryPublisher struct {
+	failedID  string
+	published []outbox.Message
+}
+
+func (publisher *inMemoryPublisher) Publish(message outbox.Message) error {
+	if message.ID == publisher.failedID {
+		return errorPublish
+	}
+
+	publisher.published = append(publisher.published, message)
+	return nil
+}
@@ -0,0 +1,209 @@
+package pricing
+
+import (
+	"sample-order/business/money"
+	"time"
+)
+
+//daysInWeek number of days charged by weekly rate
+const daysInWeek = 7
+
+//Rule rental pricing of an item. Every money of the rule must have the same currency as the daily rate
+type Rule struct {
+	ItemID string
+
+	//DailyRate price of one unit for one day
+	DailyRate money.Money
+
+	//WeeklyRate optional price of one unit for seven days, used for every full week of the rental
+	WeeklyRate *money.Money
+
+	//MinDays rental shorter than this is charged as this number of days
+	MinDays int
+
+	//WeekendSurchargePercent extra percentage of daily rate for every Saturday and Sunday of the rental
+	WeekendSurchargePercent int
+
+	//Deposit optional refundable amount held for one unit during the rental
+	Deposit *money.Money
+
+	//LateFeePerDay optional fee of one unit for every day returned late, daily rate is used when not set
+	LateFeePerDay *money.Money
+
+	ModifiedAt time.Time
+	ModifiedBy string
+}
+
+//NewRule create pricing rule of an item
+func NewRule(
+	itemID string,
+	dailyRate money.Money,
+	weeklyRate *money.Money,
+	minDays int,
+	weekendSurchargePercent int,
+	deposit *money.Money,
+	lateFeePerDay *money.Money,
+	updater string,
+	modifiedAt time.Time) Rule {
+
+	return Rule{
+		ItemID:                  itemID,
+		DailyRate:               dailyRate,
+		WeeklyRate:              weeklyRate,
+		MinDays:                 minDays,
+		WeekendSurchargePercent: weekendSurchargePercent,
+		Deposit:                 deposit,
+		LateFeePerDay:           lateFeePerDay,
+		ModifiedAt:              modifiedAt,
+		ModifiedBy:              updater,
+	}
+}
+
+//NewDefaultRule create rule which only charge the daily rate, used for item without stored rule
+func NewDefaultRule(itemID string, dailyRate money.Money) Rule {
+	return Rule{
+		ItemID:    itemID,
+		DailyRate: dailyRate,
+		MinDays:   1,
+	}
+}
+
+//IsSameCurrency Return true if every money of the rule use the currency of the daily rate
+func (rule *Rule) IsSameCurrency() bool {
+	for _, price := range []*money.Money{rule.WeeklyRate, rule.Deposit, rule.LateFeePerDay} {
+		if price != nil && price.Currency != rule.DailyRate.Currency {
+			return false
+		}
+	}
+
+	return true
+}
+
+//Quote price of renting units of an item for a period. From is inclusive and To is exclusive
+type Quote struct {
+	ItemID           string
+	Quantity         int
+	From             time.Time
+	To               time.Time
+	Days             int
+	BillableDays     int
+	RentalCharge     money.Money
+	WeekendSurcharge money.Money
+	Deposit          money.Money
+	Total            money.Money
+}
+
+//Charge final price of a returned rental, the deposit is refunded and late fee is added
+type Charge struct {
+	Quote         Quote
+	ReturnedAt    time.Time
+	LateDays      int
+	LateFee       money.Money
+	Total         money.Money
+	DepositRefund money.Money
+}
+
+//NewQuote compute the price of renting quantity units in range [from, to), only date part of from and to is used
+func NewQuote(rule Rule, quantity int, from time.Time, to time.Time) Quote {
+	from, to = day(from), day(to)
+	days := daysBetween(from, to)
+
+	billableDays := days
+	if billableDays < rule.MinDays {
+		billableDays = rule.MinDays
+	}
+
+	units := int64(quantity)
+	rentalCharge := rentalCharge(rule, billableDays).Multiply(units)
+	weekendSurcharge := rule.DailyRate.
+		Multiply(int64(weekendDays(from, to))).
+		Percent(int64(rule.WeekendSurchargePercent)).
+		Multiply(units)
+
+	deposit := money.Money{Currency: rule.DailyRate.Currency}
+	if rule.Deposit != nil {
+		deposit = rule.Deposit.Multiply(units)
+	}
+
+	//all money share the rule currency so the sum never fail
+	total, _ := rentalCharge.Add(weekendSurcharge)
+	total, _ = total.Add(deposit)
+
+	return Quote{
+		ItemID:           rule.ItemID,
+		Quantity:         quantity,
+		From:             from,
+		To:               to,
+		Days:             days,
+		BillableDays:     billableDays,
+		RentalCharge:     rentalCharge,
+		WeekendSurcharge: weekendSurcharge,
+		Deposit:          deposit,
+		Total:            total,
+	}
+}
+
+//NewCharge compute the final price of rental returned at given time. Returning on the To date is on time
+func NewCharge(rule Rule, quantity int, from time.Time, to time.Time, returnedAt time.Time) Charge {
+	quote := NewQuote(rule, quantity, from, to)
+
+	lateDays := daysBetween(quote.To, day(returnedAt))
+	if lateDays < 0 {
+		lateDays = 0
+	}
+
+	lateFeePerDay := rule.DailyRate
+	if rule.LateFeePerDay != nil {
+		lateFeePerDay = *rule.LateFeePerDay
+	}
+
+	lateFee := lateFeePerDay.Multiply(int64(lateDays * quantity))
+
+	total, _ := quote.RentalCharge.Add(quote.WeekendSurcharge)
+	total, _ = total.Add(lateFee)
+
+	return Charge{
+		Quote:         quote,
+		ReturnedAt:    returnedAt,
+		LateDays:      lateDays,
+		LateFee:       lateFee,
+		Total:         total,
+		DepositRefund: quote.Deposit,
+	}
+}
+
+//rentalCharge price of one unit, each full week use the weekly rate and the remaining days never cost more than a week
+func rentalCharge(rule Rule, days int) money.Money {
+	if rule.WeeklyRate == nil {
+		return rule.DailyRate.Multiply(int64(days))
+	}
+
+	remaining := rule.DailyRate.Multiply(int64(days % daysInWeek))
+	if result, _ := remaining.Compare(*rule.WeeklyRate); result > 0 {
+		remaining = *rule.WeeklyRate
+	}
+
+	charge, _ := rule.WeeklyRate.Multiply(int64(days / daysInWeek)).Add(remaining)
+	return charge
+}
+
+func weekendDays(from time.Time, to time.Time) int {
+	count := 0
+
+	for current := from; current.Before(to); current = current.AddDate(0, 0, 1) {
+		if current.Weekday() == time.Saturday || current.Weekday() == time.Sunday {
+			count++
+		}
+	}
+
+	return count
+}
+
+func daysBetween(from time.Time, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func day(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
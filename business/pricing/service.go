@@ -0,0 +1,161 @@
+package pricing
+
+import (
+	"context"
+	"sample-order/business"
+	"sample-order/business/item"
+	"sample-order/business/money"
+	"sample-order/business/pricing/spec"
+	"time"
+
+	validator "github.com/go-playground/validator/v10"
+)
+
+//maxQuoteDays longest rental period that can be quoted
+const maxQuoteDays = 366
+
+//Repository ingoing port for pricing rule
+type Repository interface {
+	//FindRuleByItemID If item has no stored rule will return nil without error
+	FindRuleByItemID(itemID string) (*Rule, error)
+
+	//UpsertRule Insert rule of the item or replace the existing one
+	UpsertRule(rule Rule) error
+}
+
+//ItemService outgoing port to get the item and its rental rate
+type ItemService interface {
+	GetItemByID(ctx context.Context, ID string) (*item.Item, error)
+}
+
+//Service outgoing port for pricing
+type Service interface {
+	GetRule(itemID string) (*Rule, error)
+
+	UpdateRule(itemID string, upsertRuleSpec spec.UpsertRuleSpec, modifiedBy string) error
+
+	GetQuote(itemID string, quoteSpec spec.QuoteSpec) (*Quote, error)
+
+	GetFinalCharge(itemID string, quantity int, from time.Time, to time.Time, returnedAt time.Time) (*Charge, error)
+}
+
+//=============== The implementation of those interface put below =======================
+
+type service struct {
+	repository  Repository
+	itemService ItemService
+	validate    *validator.Validate
+}
+
+//NewService Construct pricing service object
+func NewService(repository Repository, itemService ItemService) Service {
+	validate := validator.New()
+	validate.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return money.IsValidCurrency(fl.Field().String())
+	})
+
+	return &service{
+		repository,
+		itemService,
+		validate,
+	}
+}
+
+//GetRule Get pricing rule of the item. Item without stored rule is charged by its rental rate only.
+//Will return ErrNotFound when item is not exists or ErrNotRentable when there is no rule and no rental rate
+func (s *service) GetRule(itemID string) (*Rule, error) {
+	item, err := s.itemService.GetItemByID(context.TODO(), itemID)
+	if err != nil {
+		return nil, err
+	} else if item == nil {
+		return nil, business.ErrNotFound
+	}
+
+	rule, err := s.repository.FindRuleByItemID(itemID)
+	if err != nil {
+		return nil, err
+	} else if rule != nil {
+		return rule, nil
+	}
+
+	if item.RentalRate == nil {
+		return nil, business.ErrNotRentable
+	}
+
+	defaultRule := NewDefaultRule(itemID, *item.RentalRate)
+	return &defaultRule, nil
+}
+
+//UpdateRule Replace pricing rule of the item, every money must have the same currency
+func (s *service) UpdateRule(itemID string, upsertRuleSpec spec.UpsertRuleSpec, modifiedBy string) error {
+	if err := s.validate.Struct(upsertRuleSpec); err != nil {
+		return business.ErrInvalidSpec
+	}
+
+	item, err := s.itemService.GetItemByID(context.TODO(), itemID)
+	if err != nil {
+		return err
+	} else if item == nil {
+		return business.ErrNotFound
+	}
+
+	rule := NewRule(
+		itemID,
+		*toMoney(&upsertRuleSpec.DailyRate),
+		toMoney(upsertRuleSpec.WeeklyRate),
+		upsertRuleSpec.MinDays,
+		upsertRuleSpec.WeekendSurchargePercent,
+		toMoney(upsertRuleSpec.Deposit),
+		toMoney(upsertRuleSpec.LateFeePerDay),
+		modifiedBy,
+		time.Now(),
+	)
+
+	if !rule.IsSameCurrency() {
+		return business.ErrInvalidSpec
+	}
+
+	return s.repository.UpsertRule(rule)
+}
+
+//GetQuote Compute price of renting the item for the period
+func (s *service) GetQuote(itemID string, quoteSpec spec.QuoteSpec) (*Quote, error) {
+	if err := s.validate.Struct(quoteSpec); err != nil {
+		return nil, business.ErrInvalidSpec
+	}
+
+	from, to := day(quoteSpec.From), day(quoteSpec.To)
+	if !from.Before(to) || to.After(from.AddDate(0, 0, maxQuoteDays)) {
+		return nil, business.ErrInvalidSpec
+	}
+
+	rule, err := s.GetRule(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := NewQuote(*rule, quoteSpec.Quantity, from, to)
+	return &quote, nil
+}
+
+//GetFinalCharge Compute price of rental returned at given time including the late fee
+func (s *service) GetFinalCharge(itemID string, quantity int, from time.Time, to time.Time, returnedAt time.Time) (*Charge, error) {
+	rule, err := s.GetRule(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	charge := NewCharge(*rule, quantity, from, to, returnedAt)
+	return &charge, nil
+}
+
+func toMoney(moneySpec *spec.MoneySpec) *money.Money {
+	if moneySpec == nil {
+		return nil
+	}
+
+	return &money.Money{
+		Amount:   moneySpec.Amount,
+		Currency: moneySpec.Currency,
+	}
+}
@@ -0,0 +1,224 @@
+package pricing_test
+
+import (
+	"context"
+	"errors"
+	"sample-order/business"
+	"sample-order/business/item"
+	"sample-order/business/money"
+	"sample-order/business/pricing"
+	"sample-order/business/pricing/spec"
+	"testing"
+	"time"
+)
+
+var ruleItemID = "5f350b7d21148431abc65290"
+var rentalRateItemID = "5f350b7d21148431abc65291"
+var notRentableItemID = "5f350b7d21148431abc65292"
+var notFoundItemID = "5f350b7d21148431abc65299"
+var errorItemID = "error-item-id"
+var errorFind = errors.New("error on find")
+
+//monday first day of the quotes, 2020-08-08 and 2020-08-09 is the weekend
+var monday = time.Date(2020, 8, 3, 0, 0, 0, 0, time.UTC)
+
+var ruleSpec = spec.UpsertRuleSpec{
+	DailyRate:               spec.MoneySpec{Amount: 10000, Currency: "USD"},
+	WeeklyRate:              &spec.MoneySpec{Amount: 50000, Currency: "USD"},
+	MinDays:                 2,
+	WeekendSurchargePercent: 50,
+	Deposit:                 &spec.MoneySpec{Amount: 20000, Currency: "USD"},
+	LateFeePerDay:           &spec.MoneySpec{Amount: 15000, Currency: "USD"},
+}
+
+func day(offset int) time.Time {
+	return monday.AddDate(0, 0, offset)
+}
+
+func TestGetQuote(t *testing.T) {
+	tests := []struct {
+		name             string
+		quantity         int
+		days             int
+		rentalCharge     int64
+		weekendSurcharge int64
+		deposit          int64
+		total            int64
+	}{
+		{"Expect minimum days charged on short rental", 1, 1, 20000, 0, 20000, 40000},
+		{"Expect weekly rate and weekend surcharge", 2, 10, 160000, 20000, 40000, 220000},
+		{"Expect remaining days never cost more than a week", 1, 13, 100000, 15000, 20000, 135000},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := newService()
+			service.UpdateRule(ruleItemID, ruleSpec, "admin")
+
+			quote, err := service.GetQuote(ruleItemID, spec.QuoteSpec{Quantity: test.quantity, From: day(0), To: day(test.days)})
+
+			if err != nil {
+				t.Error("Expect error is nil. Error: ", err)
+				t.FailNow()
+			}
+
+			if quote.RentalCharge.Amount != test.rentalCharge || quote.WeekendSurcharge.Amount != test.weekendSurcharge ||
+				quote.Deposit.Amount != test.deposit || quote.Total.Amount != test.total || quote.Total.Currency != "USD" {
+				t.Error("Expect quote ", test, " but got ", quote)
+			}
+		})
+	}
+
+	t.Run("Expect item without rule charged by its rental rate", func(t *testing.T) {
+		quote, err := newService().GetQuote(rentalRateItemID, spec.QuoteSpec{Quantity: 1, From: day(0), To: day(7)})
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if quote.Total.Amount != 70000 || quote.Total.Currency != "IDR" {
+			t.Error("Expect seven days of rental rate", quote.Total)
+		}
+	})
+
+	t.Run("Expect failed on item without rental rate", func(t *testing.T) {
+		if _, err := newService().GetQuote(notRentableItemID, spec.QuoteSpec{Quantity: 1, From: day(0), To: day(1)}); err != business.ErrNotRentable {
+			t.Error("Expect error not rentable. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed on item not found", func(t *testing.T) {
+		if _, err := newService().GetQuote(notFoundItemID, spec.QuoteSpec{Quantity: 1, From: day(0), To: day(1)}); err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+
+		if _, err := newService().GetQuote(errorItemID, spec.QuoteSpec{Quantity: 1, From: day(0), To: day(1)}); err != errorFind {
+			t.Error("Expect error on find. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed on invalid spec", func(t *testing.T) {
+		invalidSpecs := []spec.QuoteSpec{
+			{Quantity: 0, From: day(0), To: day(1)},
+			{Quantity: 1, From: day(1), To: day(0)},
+			{Quantity: 1, From: day(0), To: day(0).Add(time.Hour)},
+			{Quantity: 1, From: day(0), To: day(367)},
+		}
+
+		for _, invalidSpec := range invalidSpecs {
+			if _, err := newService().GetQuote(ruleItemID, invalidSpec); err != business.ErrInvalidSpec {
+				t.Error("Expect error invalid spec. Error is: ", err, invalidSpec)
+			}
+		}
+	})
+}
+
+func TestGetFinalCharge(t *testing.T) {
+	t.Run("Expect late fee added and deposit refunded", func(t *testing.T) {
+		service := newService()
+		service.UpdateRule(ruleItemID, ruleSpec, "admin")
+
+		charge, err := service.GetFinalCharge(ruleItemID, 1, day(0), day(2), day(4).Add(10*time.Hour))
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if charge.LateDays != 2 || charge.LateFee.Amount != 30000 || charge.Total.Amount != 50000 || charge.DepositRefund.Amount != 20000 {
+			t.Error("Expect two late days charged", charge)
+		}
+	})
+
+	t.Run("Expect no late fee when returned on the end date", func(t *testing.T) {
+		service := newService()
+		service.UpdateRule(ruleItemID, ruleSpec, "admin")
+
+		charge, _ := service.GetFinalCharge(ruleItemID, 1, day(0), day(2), day(2).Add(23*time.Hour))
+
+		if charge.LateDays != 0 || charge.LateFee.Amount != 0 || charge.Total.Amount != 20000 {
+			t.Error("Expect no late fee", charge)
+		}
+	})
+
+	t.Run("Expect daily rate used as late fee when not set", func(t *testing.T) {
+		charge, _ := newService().GetFinalCharge(rentalRateItemID, 2, day(0), day(1), day(2))
+
+		if charge.LateFee.Amount != 20000 || charge.Total.Amount != 40000 {
+			t.Error("Expect one late day of daily rate for two units", charge)
+		}
+	})
+}
+
+func TestUpdateRule(t *testing.T) {
+	t.Run("Expect stored rule replace the rental rate", func(t *testing.T) {
+		service := newService()
+
+		if err := service.UpdateRule(rentalRateItemID, ruleSpec, "admin"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		rule, _ := service.GetRule(rentalRateItemID)
+		if rule.DailyRate.Currency != "USD" || rule.MinDays != 2 || rule.ModifiedBy != "admin" {
+			t.Error("Expect stored rule", rule)
+		}
+	})
+
+	t.Run("Expect failed on invalid spec", func(t *testing.T) {
+		mismatchCurrency := ruleSpec
+		mismatchCurrency.Deposit = &spec.MoneySpec{Amount: 20000, Currency: "IDR"}
+
+		zeroMinDays := ruleSpec
+		zeroMinDays.MinDays = 0
+
+		unknownCurrency := ruleSpec
+		unknownCurrency.DailyRate = spec.MoneySpec{Amount: 100, Currency: "XYZ"}
+
+		for _, invalidSpec := range []spec.UpsertRuleSpec{mismatchCurrency, zeroMinDays, unknownCurrency} {
+			if err := newService().UpdateRule(ruleItemID, invalidSpec, "admin"); err != business.ErrInvalidSpec {
+				t.Error("Expect error invalid spec. Error is: ", err, invalidSpec)
+			}
+		}
+	})
+
+	t.Run("Expect failed on item not found", func(t *testing.T) {
+		if err := newService().UpdateRule(notFoundItemID, ruleSpec, "admin"); err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+	})
+}
+
+func newService() pricing.Service {
+	return pricing.NewService(&inMemoryRepository{make(map[string]pricing.Rule)}, &inMemoryItemService{})
+}
+
+type inMemoryItemService struct{}
+
+func (s *inMemoryItemService) GetItemByID(ctx context.Context, ID string) (*item.Item, error) {
+	switch ID {
+	case errorItemID:
+		return nil, errorFind
+	case ruleItemID, notRentableItemID:
+		return &item.Item{ID: ID, Name: "Item", Version: 1}, nil
+	case rentalRateItemID:
+		return &item.Item{ID: ID, Name: "Item", RentalRate: &money.Money{Amount: 10000, Currency: "IDR"}, Version: 1}, nil
+	}
+
+	return nil, nil
+}
+
+type inMemoryRepository struct {
+	ruleByItemID map[string]pricing.Rule
+}
+
+func (repo *inMemoryRepository) FindRuleByItemID(itemID string) (*pricing.Rule, error) {
+	rule, ok := repo.ruleByItemID[itemID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &rule, nil
+}
+
+func (repo *inMemoryRepository) UpsertRule(rule pricing.Rule) error {
+	repo.ruleByItemID[rule.ItemID] = rule
+	return nil
+}
@@ -0,0 +1,10 @@
+package spec
+
+import "time"
+
+//QuoteSpec rental quote spec. Only the date part of From and To is used, To is exclusive
+type QuoteSpec struct {
+	Quantity int       `validate:"required,gt=0"`
+	From     time.Time `validate:"required"`
+	To       time.Time `validate:"required,gtfield=From"`
+}
@@ -0,0 +1,17 @@
+package spec
+
+//UpsertRuleSpec create or replace rental pricing rule spec
+type UpsertRuleSpec struct {
+	DailyRate               MoneySpec  `validate:"required"`
+	WeeklyRate              *MoneySpec `validate:"omitempty"`
+	MinDays                 int        `validate:"gte=1,lte=366"`
+	WeekendSurchargePercent int        `validate:"gte=0,lte=1000"`
+	Deposit                 *MoneySpec `validate:"omitempty"`
+	LateFeePerDay           *MoneySpec `validate:"omitempty"`
+}
+
+//MoneySpec money in minor unit of ISO 4217 currency
+type MoneySpec struct {
+	Amount   int64  `validate:"gte=0"`
+	Currency string `validate:"required,currency"`
+}
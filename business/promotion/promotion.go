@@ -0,0 +1,199 @@
+package promotion
+
+import (
+	"sample-order/business"
+	"sample-order/business/money"
+	"strings"
+	"time"
+)
+
+//Type how the discount amount is computed
+type Type string
+
+const (
+	//Percentage discount is percent of the applicable subtotal
+	Percentage Type = "percentage"
+	//Fixed discount is fixed amount, never more than the applicable subtotal
+	Fixed Type = "fixed"
+)
+
+//Reasons of promotion which can not be applied
+const (
+	ReasonUnknownCode      = "code does not exist"
+	ReasonNotStarted       = "promotion has not started"
+	ReasonExpired          = "promotion has expired"
+	ReasonUsageLimit       = "usage limit is reached"
+	ReasonUserUsageLimit   = "usage limit per user is reached"
+	ReasonNoItem           = "no applicable item"
+	ReasonCurrencyMismatch = "currency does not match"
+)
+
+//Promotion discount code with its rule. Promotion without item IDs and tags apply to every item.
+//Zero usage limit means unlimited
+type Promotion struct {
+	ID           string
+	Code         string
+	Description  string
+	Type         Type
+	Percent      int64        //used by percentage promotion, 1 until 100
+	Amount       *money.Money //used by fixed promotion
+	ItemIDs      []string
+	Tags         []string
+	ValidFrom    time.Time
+	ValidUntil   *time.Time //nil when the promotion never expire
+	UsageLimit   int
+	PerUserLimit int
+	UsedCount    int
+	CreatedAt    time.Time
+	CreatedBy    string
+	ModifiedAt   time.Time
+	ModifiedBy   string
+	Version      int
+}
+
+//NormalizeCode Return the code in upper case without surrounding space, so the code is case insensitive
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+//NewPromotion create new promotion
+func NewPromotion(
+	id string,
+	code string,
+	description string,
+	promotionType Type,
+	percent int64,
+	amount *money.Money,
+	itemIDs []string,
+	tags []string,
+	validFrom time.Time,
+	validUntil *time.Time,
+	usageLimit int,
+	perUserLimit int,
+	creator string,
+	createdAt time.Time) Promotion {
+
+	return Promotion{
+		ID:           id,
+		Code:         NormalizeCode(code),
+		Description:  description,
+		Type:         promotionType,
+		Percent:      percent,
+		Amount:       amount,
+		ItemIDs:      itemIDs,
+		Tags:         tags,
+		ValidFrom:    validFrom,
+		ValidUntil:   validUntil,
+		UsageLimit:   usageLimit,
+		PerUserLimit: perUserLimit,
+		CreatedAt:    createdAt,
+		CreatedBy:    creator,
+		ModifiedAt:   createdAt,
+		ModifiedBy:   creator,
+		Version:      1,
+	}
+}
+
+//ModifyPromotion update the rule of existing promotion, the usage count is kept
+func (oldPromotion *Promotion) ModifyPromotion(newPromotion Promotion, updater string, modifiedAt time.Time) Promotion {
+	promotion := newPromotion
+	promotion.ID = oldPromotion.ID
+	promotion.UsedCount = oldPromotion.UsedCount
+	promotion.CreatedAt = oldPromotion.CreatedAt
+	promotion.CreatedBy = oldPromotion.CreatedBy
+	promotion.ModifiedAt = modifiedAt
+	promotion.ModifiedBy = updater
+	promotion.Version = oldPromotion.Version + 1
+
+	return promotion
+}
+
+//Line priced item evaluated by the promotion
+type Line struct {
+	ItemID   string
+	Tags     []string
+	Subtotal money.Money
+}
+
+//Discount result of applying promotion into lines
+type Discount struct {
+	PromotionID string
+	Code        string
+	Amount      money.Money
+}
+
+//AppliesTo Return true if the promotion apply to the line
+func (promotion *Promotion) AppliesTo(line Line) bool {
+	if len(promotion.ItemIDs) == 0 && len(promotion.Tags) == 0 {
+		return true
+	}
+
+	for _, itemID := range promotion.ItemIDs {
+		if itemID == line.ItemID {
+			return true
+		}
+	}
+
+	for _, tag := range promotion.Tags {
+		for _, lineTag := range line.Tags {
+			if tag == lineTag {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+//Evaluate compute the discount of the lines. usedByUser is number of times the user already used the promotion.
+//Return business.PromotionError when the promotion can not be applied
+func (promotion *Promotion) Evaluate(lines []Line, usedByUser int, now time.Time) (Discount, error) {
+	if now.Before(promotion.ValidFrom) {
+		return Discount{}, promotion.notApplicable(ReasonNotStarted)
+	} else if promotion.ValidUntil != nil && !now.Before(*promotion.ValidUntil) {
+		return Discount{}, promotion.notApplicable(ReasonExpired)
+	} else if promotion.UsageLimit > 0 && promotion.UsedCount >= promotion.UsageLimit {
+		return Discount{}, promotion.notApplicable(ReasonUsageLimit)
+	} else if promotion.PerUserLimit > 0 && usedByUser >= promotion.PerUserLimit {
+		return Discount{}, promotion.notApplicable(ReasonUserUsageLimit)
+	}
+
+	var applicable *money.Money
+
+	for _, line := range lines {
+		if !promotion.AppliesTo(line) {
+			continue
+		}
+
+		if applicable == nil {
+			subtotal := line.Subtotal
+			applicable = &subtotal
+		} else if sum, err := applicable.Add(line.Subtotal); err == nil {
+			//line with other currency is not discounted
+			applicable = &sum
+		}
+	}
+
+	if applicable == nil {
+		return Discount{}, promotion.notApplicable(ReasonNoItem)
+	}
+
+	amount := applicable.Percent(promotion.Percent)
+
+	if promotion.Type == Fixed {
+		if promotion.Amount.Currency != applicable.Currency {
+			return Discount{}, promotion.notApplicable(ReasonCurrencyMismatch)
+		}
+
+		amount = *promotion.Amount
+		if amount.Amount > applicable.Amount {
+			amount = *applicable
+		}
+	}
+
+	return Discount{promotion.ID, promotion.Code, amount}, nil
+}
+
+func (promotion *Promotion) notApplicable(reason string) error {
+	return &business.PromotionError{Code: promotion.Code, Reason: reason}
+}
@@ -0,0 +1,317 @@
+package promotion
+
+import (
+	"sample-order/business"
+	"sample-order/business/money"
+	"sample-order/business/promotion/spec"
+	"sample-order/util"
+	"time"
+
+	validator "github.com/go-playground/validator/v10"
+)
+
+//Repository ingoing port for promotion
+type Repository interface {
+	//FindPromotionByID If data not found will return nil without error
+	FindPromotionByID(ID string) (*Promotion, error)
+
+	//FindPromotionByCode If data not found will return nil without error
+	FindPromotionByCode(code string) (*Promotion, error)
+
+	//FindAllPromotions Return promotions ordered by code, empty slice if there is none
+	FindAllPromotions() ([]Promotion, error)
+
+	//InsertPromotion Insert new promotion
+	InsertPromotion(promotion Promotion) error
+
+	//UpdatePromotion Update the rule of existing promotion. If data not found or version is not match will return business.ErrZeroAffected
+	UpdatePromotion(promotion Promotion, currentVersion int) error
+
+	//DeletePromotion Delete promotion and its redemptions. If data not found or version is not match will return business.ErrZeroAffected
+	DeletePromotion(ID string, currentVersion int) error
+
+	//CountRedemptionsByUser Return number of times the user used the promotion
+	CountRedemptionsByUser(promotionID string, user string) (int, error)
+
+	//InsertRedemption Atomically increase the used count of the promotion and record the redemption, only when the usage limit
+	//and the usage limit per user of given promotion still allow it. Return business.ErrZeroAffected otherwise
+	InsertRedemption(promotion Promotion, redemption Redemption) error
+
+	//DeleteRedemptionByOrderID Delete redemption of the order and decrease the used count of its promotion.
+	//If the order has no redemption will return business.ErrZeroAffected
+	DeleteRedemptionByOrderID(orderID string) error
+}
+
+//Service outgoing port for promotion
+type Service interface {
+	GetPromotionByID(ID string) (*Promotion, error)
+
+	GetPromotions() ([]Promotion, error)
+
+	CreatePromotion(upsertPromotionSpec spec.UpsertPromotionSpec, createdBy string) (string, error)
+
+	UpdatePromotion(ID string, upsertPromotionSpec spec.UpsertPromotionSpec, currentVersion int, modifiedBy string) error
+
+	DeletePromotion(ID string, currentVersion int) error
+
+	EvaluatePromotion(code string, user string, lines []Line) (*Discount, error)
+
+	RedeemPromotion(code string, user string, orderID string, lines []Line) (*Discount, error)
+
+	ReleaseRedemption(orderID string) error
+}
+
+//Redemption single use of a promotion by a user for an order
+type Redemption struct {
+	PromotionID string
+	User        string
+	OrderID     string
+	Amount      money.Money
+	RedeemedAt  time.Time
+}
+
+//=============== The implementation of those interface put below =======================
+
+type service struct {
+	repository Repository
+	validate   *validator.Validate
+}
+
+//NewService Construct promotion service object
+func NewService(repository Repository) Service {
+	validate := validator.New()
+	validate.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return money.IsValidCurrency(fl.Field().String())
+	})
+
+	return &service{
+		repository,
+		validate,
+	}
+}
+
+//GetPromotionByID Get promotion by given ID, return nil if not exist
+func (s *service) GetPromotionByID(ID string) (*Promotion, error) {
+	return s.repository.FindPromotionByID(ID)
+}
+
+//GetPromotions Get all promotions, return zero array if there is none
+func (s *service) GetPromotions() ([]Promotion, error) {
+	promotions, err := s.repository.FindAllPromotions()
+	if err != nil || promotions == nil {
+		return []Promotion{}, err
+	}
+
+	return promotions, nil
+}
+
+//CreatePromotion Create new promotion. Will return ErrDuplicateCode when the code is used by other promotion
+func (s *service) CreatePromotion(upsertPromotionSpec spec.UpsertPromotionSpec, createdBy string) (string, error) {
+	promotion, err := s.newPromotion(upsertPromotionSpec, createdBy)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.ensureCodeAvailable(promotion.Code, ""); err != nil {
+		return "", err
+	}
+
+	if err := s.repository.InsertPromotion(promotion); err != nil {
+		return "", err
+	}
+
+	return promotion.ID, nil
+}
+
+//UpdatePromotion Replace the rule of existing promotion, the usage count is kept
+func (s *service) UpdatePromotion(ID string, upsertPromotionSpec spec.UpsertPromotionSpec, currentVersion int, modifiedBy string) error {
+	newPromotion, err := s.newPromotion(upsertPromotionSpec, modifiedBy)
+	if err != nil {
+		return err
+	}
+
+	promotion, err := s.repository.FindPromotionByID(ID)
+	if err != nil {
+		return err
+	} else if promotion == nil {
+		return business.ErrNotFound
+	} else if promotion.Version != currentVersion {
+		return business.ErrHasBeenModified
+	}
+
+	if err := s.ensureCodeAvailable(newPromotion.Code, ID); err != nil {
+		return err
+	}
+
+	modifiedPromotion := promotion.ModifyPromotion(newPromotion, modifiedBy, time.Now())
+
+	return s.mapZeroAffected(s.repository.UpdatePromotion(modifiedPromotion, currentVersion))
+}
+
+//DeletePromotion Delete promotion and its redemptions, order which already used it keep its discount
+func (s *service) DeletePromotion(ID string, currentVersion int) error {
+	promotion, err := s.repository.FindPromotionByID(ID)
+	if err != nil {
+		return err
+	} else if promotion == nil {
+		return business.ErrNotFound
+	} else if promotion.Version != currentVersion {
+		return business.ErrHasBeenModified
+	}
+
+	return s.mapZeroAffected(s.repository.DeletePromotion(ID, currentVersion))
+}
+
+//EvaluatePromotion Compute the discount of the lines without using the promotion. Empty user is never limited per user.
+//Return business.PromotionError when the promotion can not be applied
+func (s *service) EvaluatePromotion(code string, user string, lines []Line) (*Discount, error) {
+	promotion, err := s.findByCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	discount, err := s.evaluate(*promotion, user, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	return &discount, nil
+}
+
+//RedeemPromotion Compute the discount of the order lines and record the use of the promotion.
+//Return business.PromotionError when the promotion can not be applied
+func (s *service) RedeemPromotion(code string, user string, orderID string, lines []Line) (*Discount, error) {
+	if len(user) == 0 || len(orderID) == 0 {
+		return nil, business.ErrInvalidSpec
+	}
+
+	promotion, err := s.findByCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	discount, err := s.evaluate(*promotion, user, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	redemption := Redemption{promotion.ID, user, orderID, discount.Amount, time.Now()}
+
+	if err := s.repository.InsertRedemption(*promotion, redemption); err != nil {
+		if err == business.ErrZeroAffected {
+			//the last use is taken by other order in the meantime
+			return nil, promotion.notApplicable(ReasonUsageLimit)
+		}
+
+		return nil, err
+	}
+
+	return &discount, nil
+}
+
+//ReleaseRedemption Give back the use of promotion by the order, order without promotion is ignored
+func (s *service) ReleaseRedemption(orderID string) error {
+	if err := s.repository.DeleteRedemptionByOrderID(orderID); err != nil && err != business.ErrZeroAffected {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) newPromotion(upsertPromotionSpec spec.UpsertPromotionSpec, actor string) (Promotion, error) {
+	if err := s.validate.Struct(upsertPromotionSpec); err != nil || len(actor) == 0 {
+		return Promotion{}, business.ErrInvalidSpec
+	}
+
+	promotionType := Type(upsertPromotionSpec.Type)
+
+	if promotionType == Percentage && (upsertPromotionSpec.Percent == 0 || upsertPromotionSpec.Amount != nil) {
+		return Promotion{}, business.ErrInvalidSpec
+	} else if promotionType == Fixed && (upsertPromotionSpec.Amount == nil || upsertPromotionSpec.Percent != 0) {
+		return Promotion{}, business.ErrInvalidSpec
+	}
+
+	if upsertPromotionSpec.ValidUntil != nil && !upsertPromotionSpec.ValidUntil.After(upsertPromotionSpec.ValidFrom) {
+		return Promotion{}, business.ErrInvalidSpec
+	}
+
+	var amount *money.Money
+	if upsertPromotionSpec.Amount != nil {
+		amount = &money.Money{Amount: upsertPromotionSpec.Amount.Amount, Currency: upsertPromotionSpec.Amount.Currency}
+	}
+
+	itemIDs := upsertPromotionSpec.ItemIDs
+	if itemIDs == nil {
+		itemIDs = []string{}
+	}
+
+	tags := upsertPromotionSpec.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return NewPromotion(
+		util.GenerateID(),
+		upsertPromotionSpec.Code,
+		upsertPromotionSpec.Description,
+		promotionType,
+		upsertPromotionSpec.Percent,
+		amount,
+		itemIDs,
+		tags,
+		upsertPromotionSpec.ValidFrom,
+		upsertPromotionSpec.ValidUntil,
+		upsertPromotionSpec.UsageLimit,
+		upsertPromotionSpec.PerUserLimit,
+		actor,
+		time.Now(),
+	), nil
+}
+
+func (s *service) ensureCodeAvailable(code string, exceptID string) error {
+	promotion, err := s.repository.FindPromotionByCode(code)
+	if err != nil {
+		return err
+	} else if promotion != nil && promotion.ID != exceptID {
+		return business.ErrDuplicateCode
+	}
+
+	return nil
+}
+
+func (s *service) findByCode(code string) (*Promotion, error) {
+	code = NormalizeCode(code)
+	if len(code) == 0 {
+		return nil, business.ErrInvalidSpec
+	}
+
+	promotion, err := s.repository.FindPromotionByCode(code)
+	if err != nil {
+		return nil, err
+	} else if promotion == nil {
+		return nil, &business.PromotionError{Code: code, Reason: ReasonUnknownCode}
+	}
+
+	return promotion, nil
+}
+
+func (s *service) evaluate(promotion Promotion, user string, lines []Line) (Discount, error) {
+	usedByUser := 0
+
+	if len(user) > 0 && promotion.PerUserLimit > 0 {
+		var err error
+		if usedByUser, err = s.repository.CountRedemptionsByUser(promotion.ID, user); err != nil {
+			return Discount{}, err
+		}
+	}
+
+	return promotion.Evaluate(lines, usedByUser, time.Now())
+}
+
+func (s *service) mapZeroAffected(err error) error {
+	if err == business.ErrZeroAffected {
+		return business.ErrHasBeenModified
+	}
+
+	return err
+}
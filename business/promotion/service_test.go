@@ -0,0 +1,301 @@
+package promotion_test
+
+import (
+	"errors"
+	"sample-order/business"
+	"sample-order/business/money"
+	"sample-order/business/promotion"
+	"sample-order/business/promotion/spec"
+	"sort"
+	"testing"
+	"time"
+)
+
+var cameraItemID = "5f350b7d21148431abc65290"
+var tripodItemID = "5f350b7d21148431abc65291"
+
+var lastWeek = time.Now().AddDate(0, 0, -7)
+
+func TestCreatePromotion(t *testing.T) {
+	t.Run("Expect success create promotion with normalized code", func(t *testing.T) {
+		service := promotion.NewService(newInMemoryRepository())
+
+		ID, err := service.CreatePromotion(spec.UpsertPromotionSpec{Code: " summer10 ", Type: "percentage", Percent: 10, ValidFrom: lastWeek}, "admin")
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		created, _ := service.GetPromotionByID(ID)
+		if created == nil || created.Code != "SUMMER10" || created.Percent != 10 || created.CreatedBy != "admin" || created.Version != 1 {
+			t.Error("Expect promotion is equal as given", created)
+		}
+	})
+
+	t.Run("Expect failed create promotion on invalid rule", func(t *testing.T) {
+		service := promotion.NewService(newInMemoryRepository())
+		yesterday := time.Now().AddDate(0, 0, -1)
+
+		invalidSpecs := []spec.UpsertPromotionSpec{
+			{Code: "NOPERCENT", Type: "percentage", ValidFrom: lastWeek},
+			{Code: "NOAMOUNT", Type: "fixed", ValidFrom: lastWeek},
+			{Code: "BOTH", Type: "fixed", Percent: 10, Amount: &spec.MoneySpec{Amount: 500, Currency: "USD"}, ValidFrom: lastWeek},
+			{Code: "CURRENCY", Type: "fixed", Amount: &spec.MoneySpec{Amount: 500, Currency: "XXX"}, ValidFrom: lastWeek},
+			{Code: "WINDOW", Type: "percentage", Percent: 10, ValidFrom: time.Now(), ValidUntil: &yesterday},
+			{Code: "TYPE", Type: "bogus", Percent: 10, ValidFrom: lastWeek},
+		}
+
+		for _, invalidSpec := range invalidSpecs {
+			if _, err := service.CreatePromotion(invalidSpec, "admin"); err != business.ErrInvalidSpec {
+				t.Error("Expect error invalid spec on", invalidSpec.Code, "Error is: ", err)
+			}
+		}
+	})
+
+	t.Run("Expect failed create promotion with used code", func(t *testing.T) {
+		service := promotion.NewService(newInMemoryRepository())
+		service.CreatePromotion(spec.UpsertPromotionSpec{Code: "SUMMER10", Type: "percentage", Percent: 10, ValidFrom: lastWeek}, "admin")
+
+		_, err := service.CreatePromotion(spec.UpsertPromotionSpec{Code: "summer10", Type: "percentage", Percent: 20, ValidFrom: lastWeek}, "admin")
+		if err != business.ErrDuplicateCode {
+			t.Error("Expect error duplicate code. Error is: ", err)
+		}
+	})
+}
+
+func TestUpdatePromotion(t *testing.T) {
+	t.Run("Expect rule replaced and used count kept", func(t *testing.T) {
+		repo := newInMemoryRepository()
+		service := promotion.NewService(repo)
+		ID, _ := service.CreatePromotion(spec.UpsertPromotionSpec{Code: "SUMMER10", Type: "percentage", Percent: 10, ValidFrom: lastWeek}, "admin")
+		service.RedeemPromotion("SUMMER10", "john", "order-1", []promotion.Line{cameraLine(1)})
+
+		err := service.UpdatePromotion(ID, spec.UpsertPromotionSpec{Code: "SUMMER15", Type: "percentage", Percent: 15, ValidFrom: lastWeek}, 1, "editor")
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		updated, _ := service.GetPromotionByID(ID)
+		if updated.Code != "SUMMER15" || updated.Percent != 15 || updated.UsedCount != 1 || updated.Version != 2 || updated.ModifiedBy != "editor" {
+			t.Error("Expect promotion updated", updated)
+		}
+
+		if err := service.UpdatePromotion(ID, spec.UpsertPromotionSpec{Code: "SUMMER20", Type: "percentage", Percent: 20, ValidFrom: lastWeek}, 1, "editor"); err != business.ErrHasBeenModified {
+			t.Error("Expect error has been modified. Error is: ", err)
+		}
+
+		if err := service.DeletePromotion(ID, 2); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		if err := service.DeletePromotion(ID, 2); err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+	})
+}
+
+func TestEvaluatePromotion(t *testing.T) {
+	service := promotion.NewService(newInMemoryRepository())
+	nextWeek := time.Now().AddDate(0, 0, 7)
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	service.CreatePromotion(spec.UpsertPromotionSpec{Code: "ACCESSORY20", Type: "percentage", Percent: 20, Tags: []string{"accessory"}, ValidFrom: lastWeek}, "admin")
+	service.CreatePromotion(spec.UpsertPromotionSpec{Code: "CAMERA", Type: "fixed", Amount: &spec.MoneySpec{Amount: 1000, Currency: "USD"}, ItemIDs: []string{cameraItemID}, ValidFrom: lastWeek}, "admin")
+	service.CreatePromotion(spec.UpsertPromotionSpec{Code: "BIG", Type: "fixed", Amount: &spec.MoneySpec{Amount: 900000, Currency: "USD"}, ValidFrom: lastWeek}, "admin")
+	service.CreatePromotion(spec.UpsertPromotionSpec{Code: "RUPIAH", Type: "fixed", Amount: &spec.MoneySpec{Amount: 1000, Currency: "IDR"}, ValidFrom: lastWeek}, "admin")
+	service.CreatePromotion(spec.UpsertPromotionSpec{Code: "LATER", Type: "percentage", Percent: 10, ValidFrom: nextWeek}, "admin")
+	service.CreatePromotion(spec.UpsertPromotionSpec{Code: "OVER", Type: "percentage", Percent: 10, ValidFrom: lastWeek, ValidUntil: &yesterday}, "admin")
+
+	t.Run("Expect discount computed from applicable lines", func(t *testing.T) {
+		lines := []promotion.Line{cameraLine(1), tripodLine(2)}
+
+		discount, err := service.EvaluatePromotion("accessory20", "", lines)
+		if err != nil || discount.Amount.Amount != 1000 || discount.Code != "ACCESSORY20" {
+			t.Error("Expect 20 percent of the tripod lines", discount, err)
+		}
+
+		discount, err = service.EvaluatePromotion("CAMERA", "", lines)
+		if err != nil || discount.Amount.Amount != 1000 {
+			t.Error("Expect fixed discount of the camera line", discount, err)
+		}
+
+		discount, err = service.EvaluatePromotion("BIG", "", lines)
+		if err != nil || discount.Amount.Amount != 55000 {
+			t.Error("Expect fixed discount never more than the subtotal", discount, err)
+		}
+	})
+
+	t.Run("Expect promotion error with the reason", func(t *testing.T) {
+		reasonByCode := map[string]string{
+			"UNKNOWN": promotion.ReasonUnknownCode,
+			"LATER":   promotion.ReasonNotStarted,
+			"OVER":    promotion.ReasonExpired,
+			"CAMERA":  promotion.ReasonNoItem,
+			"RUPIAH":  promotion.ReasonCurrencyMismatch,
+		}
+
+		for code, reason := range reasonByCode {
+			_, err := service.EvaluatePromotion(code, "", []promotion.Line{tripodLine(1)})
+
+			var promotionError *business.PromotionError
+			if !errors.As(err, &promotionError) || promotionError.Reason != reason || !errors.Is(err, business.ErrPromotionNotApplicable) {
+				t.Error("Expect promotion error", reason, "Error is: ", err)
+			}
+		}
+	})
+}
+
+func TestRedeemPromotion(t *testing.T) {
+	t.Run("Expect usage limited per code and per user", func(t *testing.T) {
+		service := promotion.NewService(newInMemoryRepository())
+		service.CreatePromotion(spec.UpsertPromotionSpec{Code: "LIMITED", Type: "percentage", Percent: 10, ValidFrom: lastWeek, UsageLimit: 2, PerUserLimit: 1}, "admin")
+		lines := []promotion.Line{cameraLine(1)}
+
+		if _, err := service.RedeemPromotion("LIMITED", "john", "order-1", lines); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		if _, err := service.RedeemPromotion("LIMITED", "john", "order-2", lines); !isReason(err, promotion.ReasonUserUsageLimit) {
+			t.Error("Expect error usage limit per user. Error is: ", err)
+		}
+
+		if _, err := service.EvaluatePromotion("LIMITED", "john", lines); !isReason(err, promotion.ReasonUserUsageLimit) {
+			t.Error("Expect evaluation count the usage of the user. Error is: ", err)
+		}
+
+		service.RedeemPromotion("LIMITED", "jane", "order-3", lines)
+
+		if _, err := service.RedeemPromotion("LIMITED", "bob", "order-4", lines); !isReason(err, promotion.ReasonUsageLimit) {
+			t.Error("Expect error usage limit. Error is: ", err)
+		}
+
+		if err := service.ReleaseRedemption("order-1"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		if _, err := service.RedeemPromotion("LIMITED", "bob", "order-4", lines); err != nil {
+			t.Error("Expect released use can be redeemed again. Error: ", err)
+		}
+
+		if err := service.ReleaseRedemption("order-without-promotion"); err != nil {
+			t.Error("Expect order without promotion is ignored. Error: ", err)
+		}
+	})
+}
+
+func isReason(err error, reason string) bool {
+	var promotionError *business.PromotionError
+	return errors.As(err, &promotionError) && promotionError.Reason == reason
+}
+
+func cameraLine(quantity int64) promotion.Line {
+	return promotion.Line{ItemID: cameraItemID, Subtotal: money.Money{Amount: 50000 * quantity, Currency: "USD"}}
+}
+
+func tripodLine(quantity int64) promotion.Line {
+	return promotion.Line{ItemID: tripodItemID, Tags: []string{"accessory"}, Subtotal: money.Money{Amount: 2500 * quantity, Currency: "USD"}}
+}
+
+type inMemoryRepository struct {
+	promotionByID       map[string]promotion.Promotion
+	redemptionByOrderID map[string]promotion.Redemption
+}
+
+func newInMemoryRepository() *inMemoryRepository {
+	return &inMemoryRepository{make(map[string]promotion.Promotion), make(map[string]promotion.Redemption)}
+}
+
+func (repo *inMemoryRepository) FindPromotionByID(ID string) (*promotion.Promotion, error) {
+	promotion, ok := repo.promotionByID[ID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &promotion, nil
+}
+
+func (repo *inMemoryRepository) FindPromotionByCode(code string) (*promotion.Promotion, error) {
+	for _, promotion := range repo.promotionByID {
+		if promotion.Code == code {
+			return &promotion, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (repo *inMemoryRepository) FindAllPromotions() ([]promotion.Promotion, error) {
+	var promotions []promotion.Promotion
+	for _, promotion := range repo.promotionByID {
+		promotions = append(promotions, promotion)
+	}
+
+	sort.Slice(promotions, func(i, j int) bool { return promotions[i].Code < promotions[j].Code })
+	return promotions, nil
+}
+
+func (repo *inMemoryRepository) InsertPromotion(promotion promotion.Promotion) error {
+	repo.promotionByID[promotion.ID] = promotion
+	return nil
+}
+
+func (repo *inMemoryRepository) UpdatePromotion(promotion promotion.Promotion, currentVersion int) error {
+	current, ok := repo.promotionByID[promotion.ID]
+	if !ok || current.Version != currentVersion {
+		return business.ErrZeroAffected
+	}
+
+	promotion.UsedCount = current.UsedCount
+	repo.promotionByID[promotion.ID] = promotion
+	return nil
+}
+
+func (repo *inMemoryRepository) DeletePromotion(ID string, currentVersion int) error {
+	current, ok := repo.promotionByID[ID]
+	if !ok || current.Version != currentVersion {
+		return business.ErrZeroAffected
+	}
+
+	delete(repo.promotionByID, ID)
+	return nil
+}
+
+func (repo *inMemoryRepository) CountRedemptionsByUser(promotionID string, user string) (int, error) {
+	count := 0
+	for _, redemption := range repo.redemptionByOrderID {
+		if redemption.PromotionID == promotionID && redemption.User == user {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (repo *inMemoryRepository) InsertRedemption(promotion promotion.Promotion, redemption promotion.Redemption) error {
+	current := repo.promotionByID[promotion.ID]
+	if current.UsageLimit > 0 && current.UsedCount >= current.UsageLimit {
+		return business.ErrZeroAffected
+	}
+
+	if count, _ := repo.CountRedemptionsByUser(promotion.ID, redemption.User); current.PerUserLimit > 0 && count >= current.PerUserLimit {
+		return business.ErrZeroAffected
+	}
+
+	current.UsedCount++
+	repo.promotionByID[promotion.ID] = current
+	repo.redemptionByOrderID[redemption.OrderID] = redemption
+	return nil
+}
+
+func (repo *inMemoryRepository) DeleteRedemptionByOrderID(orderID string) error {
+	redemption, ok := repo.redemptionByOrderID[orderID]
+	if !ok {
+		return business.ErrZeroAffected
+	}
+
+	current := repo.promotionByID[redemption.PromotionID]
+	current.UsedCount--
+	repo.promotionByID[redemption.PromotionID] = current
+	delete(repo.redemptionByOrderID, orderID)
+	return nil
+}
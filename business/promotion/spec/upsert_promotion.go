@@ -0,0 +1,25 @@
+package spec
+
+import "time"
+
+//UpsertPromotionSpec create and update promotion spec. Percentage promotion require percent, fixed promotion require amount.
+//Empty item IDs and tags means the promotion apply to every item
+type UpsertPromotionSpec struct {
+	Code         string `validate:"required,max=50"`
+	Description  string
+	Type         string     `validate:"required,oneof=percentage fixed"`
+	Percent      int64      `validate:"gte=0,lte=100"`
+	Amount       *MoneySpec `validate:"omitempty"`
+	ItemIDs      []string   `validate:"dive,required"`
+	Tags         []string   `validate:"dive,required,max=50"`
+	ValidFrom    time.Time  `validate:"required"`
+	ValidUntil   *time.Time
+	UsageLimit   int `validate:"gte=0"`
+	PerUserLimit int `validate:"gte=0"`
+}
+
+//MoneySpec money in minor unit of ISO 4217 currency
+type MoneySpec struct {
+	Amount   int64  `validate:"gt=0"`
+	Currency string `validate:"required,currency"`
+}
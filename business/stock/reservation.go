@@ -0,0 +1,97 @@
+package stock
+
+import (
+	"sample-order/business"
+	"time"
+)
+
+//ReservationStatus lifecycle state of a stock reservation
+type ReservationStatus string
+
+const (
+	//Active units are held and not available for other customers
+	Active ReservationStatus = "active"
+	//Released units are given back before the reservation expired
+	Released ReservationStatus = "released"
+	//Expired units are given back by the sweeper after the reservation passed its expiry time
+	Expired ReservationStatus = "expired"
+	//Converted held units are sold
+	Converted ReservationStatus = "converted"
+)
+
+//reservationTransitions next status allowed from each status, status without entry is final
+var reservationTransitions = map[ReservationStatus][]ReservationStatus{
+	Active: {Released, Expired, Converted},
+}
+
+//CanChangeTo Return true if reservation with this status may move into next status
+func (status ReservationStatus) CanChangeTo(next ReservationStatus) bool {
+	for _, allowed := range reservationTransitions[status] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Reservation units of an item in a warehouse held for a limited time, usually during checkout.
+//Reference is free text to group reservations, e.g. the order ID
+type Reservation struct {
+	ID          string
+	ItemID      string
+	WarehouseID string
+	Quantity    int
+	Reference   string
+	Status      ReservationStatus
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+	CreatedBy   string
+	ModifiedAt  time.Time
+	ModifiedBy  string
+}
+
+//NewReservation create new active reservation which expire after given ttl
+func NewReservation(
+	id string,
+	itemID string,
+	warehouseID string,
+	quantity int,
+	reference string,
+	ttl time.Duration,
+	creator string,
+	createdAt time.Time) Reservation {
+
+	return Reservation{
+		ID:          id,
+		ItemID:      itemID,
+		WarehouseID: warehouseID,
+		Quantity:    quantity,
+		Reference:   reference,
+		Status:      Active,
+		ExpiresAt:   createdAt.Add(ttl),
+		CreatedAt:   createdAt,
+		CreatedBy:   creator,
+		ModifiedAt:  createdAt,
+		ModifiedBy:  creator,
+	}
+}
+
+//IsExpired Return true if the reservation is still active but already passed its expiry time
+func (reservation *Reservation) IsExpired(now time.Time) bool {
+	return reservation.Status == Active && !now.Before(reservation.ExpiresAt)
+}
+
+//ChangeStatus move reservation into next status. Return business.TransitionError when the next status is not allowed
+func (oldReservation *Reservation) ChangeStatus(next ReservationStatus, modifier string, modifiedAt time.Time) (Reservation, error) {
+	if !oldReservation.Status.CanChangeTo(next) {
+		return Reservation{}, &business.TransitionError{From: string(oldReservation.Status), To: string(next)}
+	}
+
+	reservation := *oldReservation
+	reservation.Status = next
+	reservation.ModifiedAt = modifiedAt
+	reservation.ModifiedBy = modifier
+
+	return reservation, nil
+}
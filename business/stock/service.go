@@ -0,0 +1,531 @@
+package stock
+
+import (
+	"context"
+	"sample-order/business"
+	"sample-order/business/item"
+	"sample-order/business/stock/spec"
+	"sample-order/business/warehouse"
+	"sample-order/util"
+	"time"
+
+	validator "github.com/go-playground/validator/v10"
+)
+
+//Repository ingoing port for stock
+type Repository interface {
+	//FindStocksByItemID Return stock of every warehouse that ever had movement of the item, empty slice if there is none
+	FindStocksByItemID(itemID string) ([]Stock, error)
+
+	//ApplyMovements Atomically add each movement quantity into its item and warehouse stock and record the movements.
+	//Either all movements applied or none of them. Return business.ErrInsufficientStock when any stock would become negative
+	//or business.ErrNotFound when the item has been deleted meanwhile
+	ApplyMovements(movements []Movement) ([]Stock, error)
+
+	//FindMovementsByItemID Return movements ordered from the newest, empty slice if there is no movement
+	FindMovementsByItemID(itemID string) ([]Movement, error)
+
+	//FindReservationByID If data not found will return nil without error
+	FindReservationByID(ID string) (*Reservation, error)
+
+	//FindReservationsByReference Return reservations with given reference, empty slice if there is none
+	FindReservationsByReference(reference string) ([]Reservation, error)
+
+	//FindExpiredReservations Return active reservations which expiry time is not after given time
+	FindExpiredReservations(now time.Time) ([]Reservation, error)
+
+	//InsertReservation Atomically add the reservation quantity into reserved units of the stock and insert the reservation.
+	//Return business.ErrInsufficientStock when available units of the stock is not enough or business.ErrNotFound when the item has been deleted
+	InsertReservation(reservation Reservation) error
+
+	//ReleaseReservation Update the status of active reservation and give its units back to the stock.
+	//If reservation is not active anymore will return business.ErrZeroAffected
+	ReleaseReservation(reservation Reservation) error
+
+	//ConvertReservations Update the status of every active reservation, give their units back and apply the sale movement of each
+	//in single transaction, either all of them are converted or none. Return the stock after each movement.
+	//If any reservation is not active anymore will return business.ErrZeroAffected
+	ConvertReservations(reservations []Reservation, movements []Movement) ([]Stock, error)
+}
+
+//ItemService outgoing port to make sure the item exists
+type ItemService interface {
+	GetItemByID(ctx context.Context, ID string) (*item.Item, error)
+}
+
+//WarehouseService outgoing port to make sure the warehouse exists
+type WarehouseService interface {
+	GetWarehouseByID(ID string) (*warehouse.Warehouse, error)
+}
+
+//Service outgoing port for stock
+type Service interface {
+	GetAvailability(itemID string) (*Availability, error)
+
+	RecordMovement(itemID string, recordMovementSpec spec.RecordMovementSpec, actor string) (*Stock, error)
+
+	TransferStock(itemID string, transferStockSpec spec.TransferStockSpec, actor string) ([]Stock, error)
+
+	GetMovements(itemID string) ([]Movement, error)
+
+	GetReservationByID(ID string) (*Reservation, error)
+
+	ReserveStock(itemID string, reserveStockSpec spec.ReserveStockSpec, actor string) (*Reservation, error)
+
+	ReleaseReservation(ID string, actor string) error
+
+	ConvertReservation(ID string, actor string) (*Stock, error)
+
+	ReleaseReservations(reference string, actor string) error
+
+	ConvertReservations(reference string, actor string) error
+
+	ExpireReservations(now time.Time) (int, error)
+}
+
+//SystemActor actor recorded when reservation is expired by the sweeper
+const SystemActor = "system"
+
+//=============== The implementation of those interface put below =======================
+
+type service struct {
+	repository       Repository
+	itemService      ItemService
+	warehouseService WarehouseService
+	reservationTTL   time.Duration
+	validate         *validator.Validate
+}
+
+//NewService Construct stock service object, new reservation will expire after given ttl
+func NewService(repository Repository, itemService ItemService, warehouseService WarehouseService, reservationTTL time.Duration) Service {
+	return &service{
+		repository,
+		itemService,
+		warehouseService,
+		reservationTTL,
+		validator.New(),
+	}
+}
+
+//GetAvailability Get stock of given item in every warehouse and its total, return zero total if item never had any movement.
+//Will return ErrNotFound when item is not exists
+func (s *service) GetAvailability(itemID string) (*Availability, error) {
+	if err := s.ensureItemExists(itemID); err != nil {
+		return nil, err
+	}
+
+	stocks, err := s.repository.FindStocksByItemID(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	availability := NewAvailability(itemID, stocks)
+	return &availability, nil
+}
+
+//RecordMovement Change the stock quantity of a warehouse and record the movement.
+//Will return ErrInsufficientStock when the quantity is not enough for outgoing movement
+func (s *service) RecordMovement(itemID string, recordMovementSpec spec.RecordMovementSpec, actor string) (*Stock, error) {
+	err := s.validate.Struct(recordMovementSpec)
+	movementType := MovementType(recordMovementSpec.Type)
+
+	if err != nil || len(actor) == 0 || (recordMovementSpec.Quantity < 0 && movementType != Adjustment) {
+		return nil, business.ErrInvalidSpec
+	}
+
+	if err := s.ensureItemExists(itemID); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureWarehouseExists(recordMovementSpec.WarehouseID); err != nil {
+		return nil, err
+	}
+
+	movement := NewMovement(
+		util.GenerateID(),
+		itemID,
+		recordMovementSpec.WarehouseID,
+		movementType,
+		recordMovementSpec.Quantity,
+		recordMovementSpec.Reason,
+		actor,
+		time.Now(),
+	)
+
+	stocks, err := s.repository.ApplyMovements([]Movement{movement})
+	if err != nil {
+		return nil, err
+	}
+
+	return &stocks[0], nil
+}
+
+//TransferStock Move units of item from one warehouse into another in single transaction.
+//Return stock of the source and destination warehouse
+func (s *service) TransferStock(itemID string, transferStockSpec spec.TransferStockSpec, actor string) ([]Stock, error) {
+	if err := s.validate.Struct(transferStockSpec); err != nil || len(actor) == 0 {
+		return nil, business.ErrInvalidSpec
+	}
+
+	if err := s.ensureItemExists(itemID); err != nil {
+		return nil, err
+	}
+
+	for _, warehouseID := range []string{transferStockSpec.FromWarehouseID, transferStockSpec.ToWarehouseID} {
+		if err := s.ensureWarehouseExists(warehouseID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	movements := []Movement{
+		NewMovement(
+			util.GenerateID(),
+			itemID,
+			transferStockSpec.FromWarehouseID,
+			TransferOut,
+			transferStockSpec.Quantity,
+			transferStockSpec.Reason,
+			actor,
+			now,
+		),
+		NewMovement(
+			util.GenerateID(),
+			itemID,
+			transferStockSpec.ToWarehouseID,
+			TransferIn,
+			transferStockSpec.Quantity,
+			transferStockSpec.Reason,
+			actor,
+			now,
+		),
+	}
+
+	return s.repository.ApplyMovements(movements)
+}
+
+//GetMovements Get movement history of given item from the newest
+func (s *service) GetMovements(itemID string) ([]Movement, error) {
+	if err := s.ensureItemExists(itemID); err != nil {
+		return nil, err
+	}
+
+	movements, err := s.repository.FindMovementsByItemID(itemID)
+	if err != nil || movements == nil {
+		return []Movement{}, err
+	}
+
+	return movements, nil
+}
+
+//GetReservationByID Get reservation by given ID, return nil if not exist
+func (s *service) GetReservationByID(ID string) (*Reservation, error) {
+	return s.repository.FindReservationByID(ID)
+}
+
+//ReserveStock Hold units of item so they are not available for other customers until the reservation expired.
+//Will return ErrInsufficientStock when the warehouse does not have enough available units
+func (s *service) ReserveStock(itemID string, reserveStockSpec spec.ReserveStockSpec, actor string) (*Reservation, error) {
+	if err := s.validate.Struct(reserveStockSpec); err != nil || len(actor) == 0 {
+		return nil, business.ErrInvalidSpec
+	}
+
+	if err := s.ensureItemExists(itemID); err != nil {
+		return nil, err
+	}
+
+	warehouseID := reserveStockSpec.WarehouseID
+
+	if len(warehouseID) == 0 {
+		stocks, err := s.repository.FindStocksByItemID(itemID)
+		if err != nil {
+			return nil, err
+		}
+
+		if warehouseID = mostAvailableWarehouseID(stocks); len(warehouseID) == 0 {
+			return nil, business.ErrInsufficientStock
+		}
+	} else if err := s.ensureWarehouseExists(warehouseID); err != nil {
+		return nil, err
+	}
+
+	reservation := NewReservation(
+		util.GenerateID(),
+		itemID,
+		warehouseID,
+		reserveStockSpec.Quantity,
+		reserveStockSpec.Reference,
+		s.reservationTTL,
+		actor,
+		time.Now(),
+	)
+
+	if err := s.repository.InsertReservation(reservation); err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+//ReleaseReservation Give the held units back before the reservation expired
+func (s *service) ReleaseReservation(ID string, actor string) error {
+	if len(actor) == 0 {
+		return business.ErrInvalidSpec
+	}
+
+	reservation, err := s.findReservation(ID)
+	if err != nil {
+		return err
+	}
+
+	return s.releaseReservation(*reservation, actor, time.Now())
+}
+
+//ConvertReservation Sell the held units. Will return ErrReservationExpired when the reservation passed its expiry time
+func (s *service) ConvertReservation(ID string, actor string) (*Stock, error) {
+	if len(actor) == 0 {
+		return nil, business.ErrInvalidSpec
+	}
+
+	reservation, err := s.findReservation(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.convertReservation(*reservation, actor, time.Now())
+}
+
+//ReleaseReservations Give the held units of every active reservation with given reference back
+func (s *service) ReleaseReservations(reference string, actor string) error {
+	if len(reference) == 0 || len(actor) == 0 {
+		return business.ErrInvalidSpec
+	}
+
+	reservations, err := s.repository.FindReservationsByReference(reference)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, reservation := range reservations {
+		if reservation.Status != Active {
+			continue
+		}
+
+		if err := s.releaseReservation(reservation, actor, now); err != nil && err != business.ErrHasBeenModified {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//ConvertReservations Sell the held units of every reservation with given reference in single transaction. When any of them
+//has expired, including by the sweeper in the meantime, the others are released and ErrReservationExpired is returned,
+//so the units are sold all or none
+func (s *service) ConvertReservations(reference string, actor string) error {
+	if len(reference) == 0 || len(actor) == 0 {
+		return business.ErrInvalidSpec
+	}
+
+	reservations, err := s.repository.FindReservationsByReference(reference)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	var newReservations []Reservation
+	var movements []Movement
+
+	for _, reservation := range reservations {
+		if reservation.Status == Expired || reservation.Status == Released || reservation.IsExpired(now) {
+			return s.releaseExpired(reference, actor)
+		}
+
+		if reservation.Status != Active {
+			continue
+		}
+
+		newReservation, movement, err := newConversion(reservation, actor, now)
+		if err != nil {
+			return err
+		}
+
+		newReservations = append(newReservations, newReservation)
+		movements = append(movements, movement)
+	}
+
+	if len(newReservations) == 0 {
+		return nil
+	}
+
+	if _, err := s.repository.ConvertReservations(newReservations, movements); err != nil {
+		if err == business.ErrZeroAffected {
+			//one of them is not active anymore, nothing is converted
+			return s.releaseExpired(reference, actor)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+//ExpireReservations Give the held units of every reservation which passed its expiry time back.
+//Return number of expired reservations
+func (s *service) ExpireReservations(now time.Time) (int, error) {
+	reservations, err := s.repository.FindExpiredReservations(now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+
+	for _, reservation := range reservations {
+		err := s.releaseReservation(reservation, SystemActor, now)
+
+		if err == business.ErrHasBeenModified {
+			//converted or released in the meantime
+			continue
+		} else if err != nil {
+			return expired, err
+		}
+
+		expired++
+	}
+
+	return expired, nil
+}
+
+func (s *service) findReservation(ID string) (*Reservation, error) {
+	reservation, err := s.repository.FindReservationByID(ID)
+	if err != nil {
+		return nil, err
+	} else if reservation == nil {
+		return nil, business.ErrNotFound
+	}
+
+	return reservation, nil
+}
+
+//releaseReservation mark the reservation released, or expired when it passed its expiry time
+func (s *service) releaseReservation(reservation Reservation, actor string, now time.Time) error {
+	status := Released
+	if reservation.IsExpired(now) {
+		status = Expired
+	}
+
+	newReservation, err := reservation.ChangeStatus(status, actor, now)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repository.ReleaseReservation(newReservation); err != nil {
+		if err == business.ErrZeroAffected {
+			return business.ErrHasBeenModified
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) convertReservation(reservation Reservation, actor string, now time.Time) (*Stock, error) {
+	if reservation.Status == Expired {
+		return nil, business.ErrReservationExpired
+	}
+
+	if reservation.IsExpired(now) {
+		if err := s.releaseReservation(reservation, actor, now); err != nil {
+			return nil, err
+		}
+
+		return nil, business.ErrReservationExpired
+	}
+
+	newReservation, movement, err := newConversion(reservation, actor, now)
+	if err != nil {
+		return nil, err
+	}
+
+	stocks, err := s.repository.ConvertReservations([]Reservation{newReservation}, []Movement{movement})
+	if err != nil {
+		if err == business.ErrZeroAffected {
+			return nil, business.ErrHasBeenModified
+		}
+
+		return nil, err
+	}
+
+	return &stocks[0], nil
+}
+
+//releaseExpired release the reservations of the reference which are still active and return ErrReservationExpired
+func (s *service) releaseExpired(reference string, actor string) error {
+	if err := s.ReleaseReservations(reference, actor); err != nil {
+		return err
+	}
+
+	return business.ErrReservationExpired
+}
+
+//newConversion Return the converted reservation and its sale movement
+func newConversion(reservation Reservation, actor string, now time.Time) (Reservation, Movement, error) {
+	newReservation, err := reservation.ChangeStatus(Converted, actor, now)
+	if err != nil {
+		return Reservation{}, Movement{}, err
+	}
+
+	movement := NewMovement(
+		util.GenerateID(),
+		reservation.ItemID,
+		reservation.WarehouseID,
+		Sale,
+		reservation.Quantity,
+		"reservation "+reservation.ID,
+		actor,
+		now,
+	)
+
+	return newReservation, movement, nil
+}
+
+//mostAvailableWarehouseID Return ID of the warehouse with the most available units, empty if nothing is available
+func mostAvailableWarehouseID(stocks []Stock) string {
+	warehouseID := ""
+	mostAvailable := 0
+
+	for _, stock := range stocks {
+		if available := stock.Available(); available > mostAvailable {
+			warehouseID = stock.WarehouseID
+			mostAvailable = available
+		}
+	}
+
+	return warehouseID
+}
+
+func (s *service) ensureItemExists(itemID string) error {
+	item, err := s.itemService.GetItemByID(context.TODO(), itemID)
+	if err != nil {
+		return err
+	} else if item == nil {
+		return business.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *service) ensureWarehouseExists(warehouseID string) error {
+	warehouse, err := s.warehouseService.GetWarehouseByID(warehouseID)
+	if err != nil {
+		return err
+	} else if warehouse == nil {
+		return business.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,635 @@
+package stock_test
+
+import (
+	"context"
+	"errors"
+	"sample-order/business"
+	"sample-order/business/item"
+	"sample-order/business/stock"
+	"sample-order/business/stock/spec"
+	"sample-order/business/warehouse"
+	"sort"
+	"testing"
+	"time"
+)
+
+var existingItemID = "5f350b7d21148431abc65290"
+var mainWarehouseID = warehouse.DefaultWarehouseID
+var storeWarehouseID = "5f350b7d21148431abc65300"
+var notFoundWarehouseID = "5f350b7d21148431abc65309"
+var notFoundItemID = "5f350b7d21148431abc65299"
+var errorItemID = "error-item-id"
+var errorFind = errors.New("error on find")
+
+func TestRecordMovement(t *testing.T) {
+	t.Run("Expect receipt increase the stock", func(t *testing.T) {
+		service := newService()
+		current, err := service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 10, Reason: "purchase order"}, "warehouse")
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if current.Quantity != 10 {
+			t.Error("Expect quantity is 10 but got ", current.Quantity)
+		}
+	})
+
+	t.Run("Expect sale and rental out decrease the stock", func(t *testing.T) {
+		service := newService()
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 10, Reason: "purchase order"}, "warehouse")
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "sale", Quantity: 3, Reason: "order"}, "cashier")
+		current, _ := service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "rental_out", Quantity: 2, Reason: "booking"}, "cashier")
+
+		if current.Quantity != 5 {
+			t.Error("Expect quantity is 5 but got ", current.Quantity)
+		}
+
+		movements, _ := service.GetMovements(existingItemID)
+		if len(movements) != 3 {
+			t.Error("Expect three movements recorded")
+			t.FailNow()
+		}
+
+		if movements[0].Type != stock.RentalOut || movements[0].Quantity != -2 || movements[0].Actor != "cashier" {
+			t.Error("Expect newest movement is the rental out", movements[0])
+		}
+	})
+
+	t.Run("Expect negative adjustment decrease the stock", func(t *testing.T) {
+		service := newService()
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 10, Reason: "purchase order"}, "warehouse")
+		current, _ := service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "adjustment", Quantity: -4, Reason: "broken"}, "auditor")
+
+		if current.Quantity != 6 {
+			t.Error("Expect quantity is 6 but got ", current.Quantity)
+		}
+	})
+
+	t.Run("Expect failed when stock become negative", func(t *testing.T) {
+		service := newService()
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 1, Reason: "purchase order"}, "warehouse")
+		_, err := service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "sale", Quantity: 2, Reason: "order"}, "cashier")
+
+		if err != business.ErrInsufficientStock {
+			t.Error("Expect error insufficient stock. Error is: ", err)
+		}
+
+		availability, _ := service.GetAvailability(existingItemID)
+		if availability.Total != 1 {
+			t.Error("Expect quantity is unchanged")
+		}
+	})
+
+	t.Run("Expect failed on invalid spec", func(t *testing.T) {
+		service := newService()
+		invalidSpecs := []spec.RecordMovementSpec{
+			{Type: "unknown", Quantity: 1, Reason: "reason"},
+			{Type: "receipt", Quantity: 0, Reason: "reason"},
+			{Type: "sale", Quantity: -1, Reason: "reason"},
+			{Type: "receipt", Quantity: 1, Reason: ""},
+		}
+
+		for _, invalidSpec := range invalidSpecs {
+			if _, err := service.RecordMovement(existingItemID, invalidSpec, "actor"); err != business.ErrInvalidSpec {
+				t.Error("Expect error invalid spec. Error is: ", err, invalidSpec)
+			}
+		}
+
+		if _, err := service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 1, Reason: "reason"}, ""); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec on empty actor. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed on item not found", func(t *testing.T) {
+		service := newService()
+		_, err := service.RecordMovement(notFoundItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 1, Reason: "reason"}, "actor")
+
+		if err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed on warehouse not found", func(t *testing.T) {
+		service := newService()
+		_, err := service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: notFoundWarehouseID, Type: "receipt", Quantity: 1, Reason: "reason"}, "actor")
+
+		if err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+	})
+}
+
+func TestTransferStock(t *testing.T) {
+	t.Run("Expect stock moved between warehouses", func(t *testing.T) {
+		service := newService()
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 10, Reason: "purchase order"}, "warehouse")
+
+		stocks, err := service.TransferStock(existingItemID, spec.TransferStockSpec{FromWarehouseID: mainWarehouseID, ToWarehouseID: storeWarehouseID, Quantity: 4, Reason: "restock"}, "logistic")
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if len(stocks) != 2 || stocks[0].Quantity != 6 || stocks[1].WarehouseID != storeWarehouseID || stocks[1].Quantity != 4 {
+			t.Error("Expect source has 6 and destination has 4", stocks)
+		}
+
+		availability, _ := service.GetAvailability(existingItemID)
+		if availability.Total != 10 || len(availability.Warehouses) != 2 {
+			t.Error("Expect total is unchanged across two warehouses", availability)
+		}
+
+		movements, _ := service.GetMovements(existingItemID)
+		if len(movements) != 3 || movements[0].Type != stock.TransferIn || movements[1].Type != stock.TransferOut || movements[1].Quantity != -4 {
+			t.Error("Expect transfer recorded as out and in movements", movements)
+		}
+	})
+
+	t.Run("Expect nothing changed when source stock is insufficient", func(t *testing.T) {
+		service := newService()
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 2, Reason: "purchase order"}, "warehouse")
+
+		_, err := service.TransferStock(existingItemID, spec.TransferStockSpec{FromWarehouseID: mainWarehouseID, ToWarehouseID: storeWarehouseID, Quantity: 3, Reason: "restock"}, "logistic")
+
+		if err != business.ErrInsufficientStock {
+			t.Error("Expect error insufficient stock. Error is: ", err)
+		}
+
+		availability, _ := service.GetAvailability(existingItemID)
+		if availability.Total != 2 || len(availability.Warehouses) != 1 {
+			t.Error("Expect stock is unchanged", availability)
+		}
+	})
+
+	t.Run("Expect failed on invalid spec", func(t *testing.T) {
+		service := newService()
+		invalidSpecs := []spec.TransferStockSpec{
+			{FromWarehouseID: mainWarehouseID, ToWarehouseID: mainWarehouseID, Quantity: 1, Reason: "reason"},
+			{FromWarehouseID: mainWarehouseID, ToWarehouseID: storeWarehouseID, Quantity: 0, Reason: "reason"},
+			{FromWarehouseID: mainWarehouseID, ToWarehouseID: storeWarehouseID, Quantity: -1, Reason: "reason"},
+			{FromWarehouseID: mainWarehouseID, ToWarehouseID: storeWarehouseID, Quantity: 1, Reason: ""},
+		}
+
+		for _, invalidSpec := range invalidSpecs {
+			if _, err := service.TransferStock(existingItemID, invalidSpec, "actor"); err != business.ErrInvalidSpec {
+				t.Error("Expect error invalid spec. Error is: ", err, invalidSpec)
+			}
+		}
+	})
+
+	t.Run("Expect failed on warehouse not found", func(t *testing.T) {
+		service := newService()
+		_, err := service.TransferStock(existingItemID, spec.TransferStockSpec{FromWarehouseID: mainWarehouseID, ToWarehouseID: notFoundWarehouseID, Quantity: 1, Reason: "restock"}, "logistic")
+
+		if err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+	})
+}
+
+func TestGetAvailability(t *testing.T) {
+	t.Run("Expect zero total when item has no movement", func(t *testing.T) {
+		availability, err := newService().GetAvailability(existingItemID)
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if availability.ItemID != existingItemID || availability.Total != 0 || len(availability.Warehouses) != 0 {
+			t.Error("Expect zero stock of the item", availability)
+		}
+	})
+
+	t.Run("Expect failed on item not found", func(t *testing.T) {
+		if _, err := newService().GetAvailability(notFoundItemID); err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+
+		if _, err := newService().GetMovements(notFoundItemID); err != business.ErrNotFound {
+			t.Error("Expect error not found on movements. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed on item service error", func(t *testing.T) {
+		if _, err := newService().GetAvailability(errorItemID); err != errorFind {
+			t.Error("Expect error on find. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect empty movements when item has no movement", func(t *testing.T) {
+		movements, err := newService().GetMovements(existingItemID)
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if movements == nil || len(movements) != 0 {
+			t.Error("Expect movements is empty")
+		}
+	})
+}
+
+func TestReserveStock(t *testing.T) {
+	t.Run("Expect reservation decrease available but not on hand quantity", func(t *testing.T) {
+		service := newService()
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 5, Reason: "purchase order"}, "warehouse")
+
+		reservation, err := service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 2, Reference: "order-1"}, "john")
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if reservation.Status != stock.Active || reservation.ExpiresAt.Sub(reservation.CreatedAt) != 15*time.Minute {
+			t.Error("Expect active reservation which expire after the ttl", reservation)
+		}
+
+		availability, _ := service.GetAvailability(existingItemID)
+		if availability.Total != 5 || availability.Reserved != 2 || availability.Available != 3 {
+			t.Error("Expect 5 on hand with 3 available", availability)
+		}
+	})
+
+	t.Run("Expect two customers can not reserve the last unit", func(t *testing.T) {
+		service := newService()
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 1, Reason: "purchase order"}, "warehouse")
+		service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 1}, "john")
+
+		if _, err := service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 1}, "jane"); err != business.ErrInsufficientStock {
+			t.Error("Expect error insufficient stock. Error is: ", err)
+		}
+
+		if _, err := service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "sale", Quantity: 1, Reason: "walk in"}, "cashier"); err != business.ErrInsufficientStock {
+			t.Error("Expect sale can not take the reserved unit. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect warehouse with the most available units used when not given", func(t *testing.T) {
+		service := newService()
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 2, Reason: "purchase order"}, "warehouse")
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: storeWarehouseID, Type: "receipt", Quantity: 3, Reason: "purchase order"}, "warehouse")
+
+		reservation, err := service.ReserveStock(existingItemID, spec.ReserveStockSpec{Quantity: 1}, "john")
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if reservation.WarehouseID != storeWarehouseID {
+			t.Error("Expect store warehouse used", reservation.WarehouseID)
+		}
+
+		if _, err := service.ReserveStock(existingItemID, spec.ReserveStockSpec{Quantity: 3}, "jane"); err != business.ErrInsufficientStock {
+			t.Error("Expect error insufficient stock when no warehouse has enough units. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed on invalid spec", func(t *testing.T) {
+		service := newService()
+
+		if _, err := service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 0}, "john"); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec. Error is: ", err)
+		}
+
+		if _, err := service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 1}, ""); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec on empty actor. Error is: ", err)
+		}
+
+		if _, err := service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: notFoundWarehouseID, Quantity: 1}, "john"); err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+	})
+}
+
+func TestReservationLifecycle(t *testing.T) {
+	t.Run("Expect released reservation give the units back", func(t *testing.T) {
+		service := newService()
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 1, Reason: "purchase order"}, "warehouse")
+		reservation, _ := service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 1}, "john")
+
+		if err := service.ReleaseReservation(reservation.ID, "john"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		}
+
+		availability, _ := service.GetAvailability(existingItemID)
+		if availability.Available != 1 {
+			t.Error("Expect unit available again", availability)
+		}
+
+		if err := service.ReleaseReservation(reservation.ID, "john"); !errors.Is(err, business.ErrInvalidTransition) {
+			t.Error("Expect error invalid transition on released reservation. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect converted reservation sell the units", func(t *testing.T) {
+		service := newService()
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 3, Reason: "purchase order"}, "warehouse")
+		reservation, _ := service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 2}, "john")
+
+		current, err := service.ConvertReservation(reservation.ID, "payment")
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if current.Quantity != 1 || current.Reserved != 0 {
+			t.Error("Expect one unit left without reservation", current)
+		}
+
+		movements, _ := service.GetMovements(existingItemID)
+		if movements[0].Type != stock.Sale || movements[0].Quantity != -2 || movements[0].Actor != "payment" {
+			t.Error("Expect sale movement recorded", movements[0])
+		}
+
+		stored, _ := service.GetReservationByID(reservation.ID)
+		if stored.Status != stock.Converted || stored.ModifiedBy != "payment" {
+			t.Error("Expect reservation converted", stored)
+		}
+	})
+
+	t.Run("Expect expired reservation can not be converted", func(t *testing.T) {
+		service := newServiceWithTTL(-time.Minute)
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 1, Reason: "purchase order"}, "warehouse")
+		reservation, _ := service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 1}, "john")
+
+		if _, err := service.ConvertReservation(reservation.ID, "payment"); err != business.ErrReservationExpired {
+			t.Error("Expect error reservation expired. Error is: ", err)
+		}
+
+		stored, _ := service.GetReservationByID(reservation.ID)
+		availability, _ := service.GetAvailability(existingItemID)
+		if stored.Status != stock.Expired || availability.Total != 1 || availability.Available != 1 {
+			t.Error("Expect reservation expired and unit available again", stored, availability)
+		}
+	})
+
+	t.Run("Expect sweeper expire only the reservations passed their expiry time", func(t *testing.T) {
+		service := newService()
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 3, Reason: "purchase order"}, "warehouse")
+		first, _ := service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 1}, "john")
+		service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 1}, "jane")
+		service.ConvertReservation(first.ID, "payment")
+
+		if expired, err := service.ExpireReservations(time.Now()); err != nil || expired != 0 {
+			t.Error("Expect nothing expired yet", expired, err)
+		}
+
+		expired, err := service.ExpireReservations(time.Now().Add(16 * time.Minute))
+		if err != nil || expired != 1 {
+			t.Error("Expect one reservation expired", expired, err)
+		}
+
+		availability, _ := service.GetAvailability(existingItemID)
+		if availability.Total != 2 || availability.Reserved != 0 {
+			t.Error("Expect sold unit stay sold and expired unit available again", availability)
+		}
+	})
+
+	t.Run("Expect reservations of the reference converted all or none", func(t *testing.T) {
+		service := newService()
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 3, Reason: "purchase order"}, "warehouse")
+		service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 1, Reference: "order-1"}, "john")
+		service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 1, Reference: "order-1"}, "john")
+		service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 1, Reference: "order-2"}, "jane")
+		service.ExpireReservations(time.Now().Add(16 * time.Minute))
+
+		if err := service.ConvertReservations("order-1", "payment"); err != business.ErrReservationExpired {
+			t.Error("Expect error reservation expired. Error is: ", err)
+		}
+
+		availability, _ := service.GetAvailability(existingItemID)
+		if availability.Total != 3 || availability.Reserved != 0 {
+			t.Error("Expect nothing sold", availability)
+		}
+	})
+
+	t.Run("Expect nothing sold when reservation expired while converting", func(t *testing.T) {
+		repo := newInMemoryRepository()
+		service := stock.NewService(&repo, &inMemoryItemService{}, &inMemoryWarehouseService{}, 15*time.Minute)
+		service.RecordMovement(existingItemID, spec.RecordMovementSpec{WarehouseID: mainWarehouseID, Type: "receipt", Quantity: 3, Reason: "purchase order"}, "warehouse")
+		service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 1, Reference: "order-1"}, "john")
+		service.ReserveStock(existingItemID, spec.ReserveStockSpec{WarehouseID: mainWarehouseID, Quantity: 1, Reference: "order-1"}, "john")
+		repo.expireOnConvert = true
+
+		if err := service.ConvertReservations("order-1", "payment"); err != business.ErrReservationExpired {
+			t.Error("Expect error reservation expired. Error is: ", err)
+		}
+
+		availability, _ := service.GetAvailability(existingItemID)
+		if availability.Total != 3 || availability.Reserved != 0 {
+			t.Error("Expect nothing sold and the other reservation released", availability)
+		}
+	})
+
+	t.Run("Expect failed on reservation not found", func(t *testing.T) {
+		if err := newService().ReleaseReservation("5f350b7d21148431abc65000", "john"); err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+	})
+}
+
+func newService() stock.Service {
+	return newServiceWithTTL(15 * time.Minute)
+}
+
+func newServiceWithTTL(reservationTTL time.Duration) stock.Service {
+	repo := newInMemoryRepository()
+	return stock.NewService(&repo, &inMemoryItemService{}, &inMemoryWarehouseService{}, reservationTTL)
+}
+
+type inMemoryItemService struct{}
+
+func (s *inMemoryItemService) GetItemByID(ctx context.Context, ID string) (*item.Item, error) {
+	if ID == errorItemID {
+		return nil, errorFind
+	} else if ID != existingItemID {
+		return nil, nil
+	}
+
+	return &item.Item{ID: ID, Name: "Item one", Version: 1}, nil
+}
+
+type inMemoryWarehouseService struct{}
+
+func (s *inMemoryWarehouseService) GetWarehouseByID(ID string) (*warehouse.Warehouse, error) {
+	if ID != mainWarehouseID && ID != storeWarehouseID {
+		return nil, nil
+	}
+
+	return &warehouse.Warehouse{ID: ID, Name: "Warehouse"}, nil
+}
+
+type inMemoryRepository struct {
+	stocks            []stock.Stock
+	movementsByItemID map[string][]stock.Movement
+	reservations      []stock.Reservation
+
+	//expireOnConvert simulate the sweeper expiring the last reservation right before they are converted
+	expireOnConvert bool
+}
+
+func newInMemoryRepository() inMemoryRepository {
+	var repo inMemoryRepository
+	repo.movementsByItemID = make(map[string][]stock.Movement)
+
+	return repo
+}
+
+func (repo *inMemoryRepository) FindStocksByItemID(itemID string) ([]stock.Stock, error) {
+	var stocks []stock.Stock
+
+	for _, current := range repo.stocks {
+		if current.ItemID == itemID {
+			stocks = append(stocks, current)
+		}
+	}
+
+	return stocks, nil
+}
+
+func (repo *inMemoryRepository) ApplyMovements(movements []stock.Movement) ([]stock.Stock, error) {
+	//work on copy so nothing changed when one of the movements failed
+	stocks := append([]stock.Stock{}, repo.stocks...)
+	var result []stock.Stock
+
+	for _, movement := range movements {
+		index := -1
+		for i, current := range stocks {
+			if current.ItemID == movement.ItemID && current.WarehouseID == movement.WarehouseID {
+				index = i
+			}
+		}
+
+		if index < 0 {
+			stocks = append(stocks, stock.Stock{ItemID: movement.ItemID, WarehouseID: movement.WarehouseID})
+			index = len(stocks) - 1
+		}
+
+		if stocks[index].Quantity+movement.Quantity < stocks[index].Reserved {
+			return nil, business.ErrInsufficientStock
+		}
+
+		stocks[index].Quantity += movement.Quantity
+		stocks[index].ModifiedAt = movement.CreatedAt
+		result = append(result, stocks[index])
+	}
+
+	repo.stocks = stocks
+
+	for _, movement := range movements {
+		//make sure the movements have different time to keep the order
+		movement.CreatedAt = movement.CreatedAt.Add(time.Duration(len(repo.movementsByItemID[movement.ItemID])) * time.Millisecond)
+		repo.movementsByItemID[movement.ItemID] = append(repo.movementsByItemID[movement.ItemID], movement)
+	}
+
+	return result, nil
+}
+
+func (repo *inMemoryRepository) FindMovementsByItemID(itemID string) ([]stock.Movement, error) {
+	movements := append([]stock.Movement{}, repo.movementsByItemID[itemID]...)
+	sort.Slice(movements, func(i, j int) bool { return movements[i].CreatedAt.After(movements[j].CreatedAt) })
+
+	if len(movements) == 0 {
+		return nil, nil
+	}
+
+	return movements, nil
+}
+
+func (repo *inMemoryRepository) FindReservationByID(ID string) (*stock.Reservation, error) {
+	for _, reservation := range repo.reservations {
+		if reservation.ID == ID {
+			return &reservation, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (repo *inMemoryRepository) FindReservationsByReference(reference string) ([]stock.Reservation, error) {
+	var reservations []stock.Reservation
+
+	for _, reservation := range repo.reservations {
+		if reservation.Reference == reference {
+			reservations = append(reservations, reservation)
+		}
+	}
+
+	return reservations, nil
+}
+
+func (repo *inMemoryRepository) FindExpiredReservations(now time.Time) ([]stock.Reservation, error) {
+	var reservations []stock.Reservation
+
+	for _, reservation := range repo.reservations {
+		if reservation.Status == stock.Active && !reservation.ExpiresAt.After(now) {
+			reservations = append(reservations, reservation)
+		}
+	}
+
+	return reservations, nil
+}
+
+func (repo *inMemoryRepository) InsertReservation(reservation stock.Reservation) error {
+	if err := repo.changeReserved(reservation, reservation.Quantity); err != nil {
+		return err
+	}
+
+	repo.reservations = append(repo.reservations, reservation)
+	return nil
+}
+
+func (repo *inMemoryRepository) ReleaseReservation(reservation stock.Reservation) error {
+	index := repo.activeReservationIndex(reservation.ID)
+	if index < 0 {
+		return business.ErrZeroAffected
+	}
+
+	if err := repo.changeReserved(reservation, -reservation.Quantity); err != nil {
+		return err
+	}
+
+	repo.reservations[index] = reservation
+	return nil
+}
+
+func (repo *inMemoryRepository) ConvertReservations(reservations []stock.Reservation, movements []stock.Movement) ([]stock.Stock, error) {
+	if repo.expireOnConvert && len(reservations) > 0 {
+		expired := reservations[len(reservations)-1]
+		expired.Status = stock.Expired
+		repo.ReleaseReservation(expired)
+	}
+
+	//check every reservation first, so nothing is converted when one of them is not active
+	for _, reservation := range reservations {
+		if repo.activeReservationIndex(reservation.ID) < 0 {
+			return nil, business.ErrZeroAffected
+		}
+	}
+
+	for _, reservation := range reservations {
+		if err := repo.ReleaseReservation(reservation); err != nil {
+			return nil, err
+		}
+	}
+
+	return repo.ApplyMovements(movements)
+}
+
+func (repo *inMemoryRepository) activeReservationIndex(ID string) int {
+	for i, reservation := range repo.reservations {
+		if reservation.ID == ID && reservation.Status == stock.Active {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (repo *inMemoryRepository) changeReserved(reservation stock.Reservation, delta int) error {
+	for i, current := range repo.stocks {
+		if current.ItemID == reservation.ItemID && current.WarehouseID == reservation.WarehouseID {
+			if current.Reserved+delta < 0 || current.Reserved+delta > current.Quantity {
+				return business.ErrInsufficientStock
+			}
+
+			repo.stocks[i].Reserved += delta
+			return nil
+		}
+	}
+
+	return business.ErrInsufficientStock
+}
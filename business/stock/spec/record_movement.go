@@ -0,0 +1,10 @@
+package spec
+
+//RecordMovementSpec record stock movement spec. Quantity is number of units moved,
+//only adjustment accept negative quantity to decrease the stock
+type RecordMovementSpec struct {
+	WarehouseID string `validate:"required"`
+	Type        string `validate:"required,oneof=receipt adjustment sale rental_out rental_return"`
+	Quantity    int    `validate:"required"`
+	Reason      string `validate:"required"`
+}
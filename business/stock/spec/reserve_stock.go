@@ -0,0 +1,8 @@
+package spec
+
+//ReserveStockSpec hold stock spec. When warehouse ID is empty the warehouse with the most available units is used
+type ReserveStockSpec struct {
+	WarehouseID string
+	Quantity    int `validate:"required,gt=0"`
+	Reference   string
+}
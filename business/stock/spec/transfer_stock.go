@@ -0,0 +1,9 @@
+package spec
+
+//TransferStockSpec move stock between two warehouses spec
+type TransferStockSpec struct {
+	FromWarehouseID string `validate:"required"`
+	ToWarehouseID   string `validate:"required,nefield=FromWarehouseID"`
+	Quantity        int    `validate:"required,gt=0"`
+	Reason          string `validate:"required"`
+}
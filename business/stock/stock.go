@@ -0,0 +1,114 @@
+package stock
+
+import "time"
+
+//MovementType reason category of stock quantity change
+type MovementType string
+
+const (
+	//Receipt new units received into inventory
+	Receipt MovementType = "receipt"
+	//Adjustment manual correction, could increase or decrease the quantity
+	Adjustment MovementType = "adjustment"
+	//Sale units sold to customer
+	Sale MovementType = "sale"
+	//RentalOut units handed over to renter
+	RentalOut MovementType = "rental_out"
+	//RentalReturn rented units returned by renter
+	RentalReturn MovementType = "rental_return"
+	//TransferOut units sent to another warehouse
+	TransferOut MovementType = "transfer_out"
+	//TransferIn units received from another warehouse
+	TransferIn MovementType = "transfer_in"
+)
+
+//IsValid Return true if movement type is known
+func (movementType MovementType) IsValid() bool {
+	switch movementType {
+	case Receipt, Adjustment, Sale, RentalOut, RentalReturn, TransferOut, TransferIn:
+		return true
+	}
+
+	return false
+}
+
+//Stock number of units of an item currently in a warehouse. Quantity is the units on hand,
+//Reserved is the part of them held by active reservations
+type Stock struct {
+	ItemID      string
+	WarehouseID string
+	Quantity    int
+	Reserved    int
+	ModifiedAt  time.Time
+}
+
+//Available Return number of units on hand which are not reserved
+func (stock *Stock) Available() int {
+	return stock.Quantity - stock.Reserved
+}
+
+//Availability aggregated stock of an item across all warehouses
+type Availability struct {
+	ItemID     string
+	Total      int
+	Reserved   int
+	Available  int
+	Warehouses []Stock
+}
+
+//NewAvailability sum the stock of each warehouse
+func NewAvailability(itemID string, stocks []Stock) Availability {
+	availability := Availability{
+		ItemID:     itemID,
+		Warehouses: make([]Stock, 0, len(stocks)),
+	}
+
+	for _, stock := range stocks {
+		availability.Total += stock.Quantity
+		availability.Reserved += stock.Reserved
+		availability.Available += stock.Available()
+		availability.Warehouses = append(availability.Warehouses, stock)
+	}
+
+	return availability
+}
+
+//Movement single change of stock quantity in a warehouse
+type Movement struct {
+	ID          string
+	ItemID      string
+	WarehouseID string
+	Type        MovementType
+	Quantity    int //signed change, negative means units leaving the inventory
+	Reason      string
+	Actor       string
+	CreatedAt   time.Time
+}
+
+//NewMovement create new stock movement. Quantity of outgoing movement type will be turned into negative,
+//while adjustment keep the sign as given
+func NewMovement(
+	id string,
+	itemID string,
+	warehouseID string,
+	movementType MovementType,
+	quantity int,
+	reason string,
+	actor string,
+	createdAt time.Time) Movement {
+
+	if movementType == Sale || movementType == RentalOut || movementType == TransferOut {
+		quantity = -quantity
+	}
+
+	return Movement{
+		ID:          id,
+		ItemID:      itemID,
+		WarehouseID: warehouseID,
+		Type:        movementType,
+		Quantity:    quantity,
+		Reason:      reason,
+		Actor:       actor,
+		CreatedAt:   createdAt,
+	}
+}
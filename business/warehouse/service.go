@@ -0,0 +1,83 @@
+package warehouse
+
+import (
+	"sample-order/business"
+	"sample-order/business/warehouse/spec"
+	"sample-order/util"
+	"time"
+
+	validator "github.com/go-playground/validator/v10"
+)
+
+//Repository ingoing port for warehouse
+type Repository interface {
+	//FindWarehouseByID If data not found will return nil without error
+	FindWarehouseByID(ID string) (*Warehouse, error)
+
+	//FindAllWarehouses If there is no warehouse, will return empty slice instead of nil
+	FindAllWarehouses() ([]Warehouse, error)
+
+	//InsertWarehouse Insert new warehouse into storage
+	InsertWarehouse(warehouse Warehouse) error
+}
+
+//Service outgoing port for warehouse
+type Service interface {
+	GetWarehouseByID(ID string) (*Warehouse, error)
+
+	GetWarehouses() ([]Warehouse, error)
+
+	CreateWarehouse(createWarehouseSpec spec.CreateWarehouseSpec, createdBy string) (string, error)
+}
+
+//=============== The implementation of those interface put below =======================
+
+type service struct {
+	repository Repository
+	validate   *validator.Validate
+}
+
+//NewService Construct warehouse service object
+func NewService(repository Repository) Service {
+	return &service{
+		repository,
+		validator.New(),
+	}
+}
+
+//GetWarehouseByID Get warehouse by given ID, return nil if not exist
+func (s *service) GetWarehouseByID(ID string) (*Warehouse, error) {
+	return s.repository.FindWarehouseByID(ID)
+}
+
+//GetWarehouses Get all warehouses, return zero array if there is no warehouse
+func (s *service) GetWarehouses() ([]Warehouse, error) {
+	warehouses, err := s.repository.FindAllWarehouses()
+	if err != nil || warehouses == nil {
+		return []Warehouse{}, err
+	}
+
+	return warehouses, nil
+}
+
+//CreateWarehouse Create new warehouse and store into database
+func (s *service) CreateWarehouse(createWarehouseSpec spec.CreateWarehouseSpec, createdBy string) (string, error) {
+	if err := s.validate.Struct(createWarehouseSpec); err != nil {
+		return "", business.ErrInvalidSpec
+	}
+
+	ID := util.GenerateID()
+	warehouse := NewWarehouse(
+		ID,
+		createWarehouseSpec.Name,
+		createWarehouseSpec.Address,
+		createdBy,
+		time.Now(),
+	)
+
+	if err := s.repository.InsertWarehouse(warehouse); err != nil {
+		return "", err
+	}
+
+	return ID, nil
+}
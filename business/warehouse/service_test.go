@@ -0,0 +1,105 @@
+package warehouse_test
+
+import (
+	"errors"
+	"sample-order/business"
+	"sample-order/business/warehouse"
+	"sample-order/business/warehouse/spec"
+	"testing"
+)
+
+var errorInsert = errors.New("error on insert")
+
+func TestCreateWarehouse(t *testing.T) {
+	t.Run("Expect success create warehouse", func(t *testing.T) {
+		repo := newInMemoryRepository()
+		service := warehouse.NewService(&repo)
+
+		ID, err := service.CreateWarehouse(spec.CreateWarehouseSpec{Name: "North store", Address: "North street 1"}, "creator")
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		created, _ := service.GetWarehouseByID(ID)
+		if created == nil {
+			t.Error("Expect warehouse is not nil after inserted")
+			t.FailNow()
+		}
+
+		if created.Name != "North store" || created.Address != "North street 1" || created.CreatedBy != "creator" {
+			t.Error("Expect warehouse is equal as given", created)
+		}
+
+		warehouses, _ := service.GetWarehouses()
+		if len(warehouses) != 1 {
+			t.Error("Expect one warehouse is found")
+		}
+	})
+
+	t.Run("Expect failed create warehouse on spec", func(t *testing.T) {
+		repo := newInMemoryRepository()
+		_, err := warehouse.NewService(&repo).CreateWarehouse(spec.CreateWarehouseSpec{Name: "North store"}, "creator")
+
+		if err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed create warehouse on repository", func(t *testing.T) {
+		repo := newInMemoryRepository()
+		_, err := warehouse.NewService(&repo).CreateWarehouse(spec.CreateWarehouseSpec{Name: "Error", Address: "Error street"}, "creator")
+
+		if err != errorInsert {
+			t.Error("Expect error on insert. Error is: ", err)
+		}
+	})
+}
+
+func TestGetWarehouses(t *testing.T) {
+	t.Run("Expect empty warehouses instead of nil", func(t *testing.T) {
+		repo := newInMemoryRepository()
+		warehouses, err := warehouse.NewService(&repo).GetWarehouses()
+
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+		} else if warehouses == nil || len(warehouses) != 0 {
+			t.Error("Expect warehouses is empty")
+		}
+	})
+}
+
+type inMemoryRepository struct {
+	warehouseByID map[string]warehouse.Warehouse
+}
+
+func newInMemoryRepository() inMemoryRepository {
+	return inMemoryRepository{make(map[string]warehouse.Warehouse)}
+}
+
+func (repo *inMemoryRepository) FindWarehouseByID(ID string) (*warehouse.Warehouse, error) {
+	warehouse, ok := repo.warehouseByID[ID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &warehouse, nil
+}
+
+func (repo *inMemoryRepository) FindAllWarehouses() ([]warehouse.Warehouse, error) {
+	var warehouses []warehouse.Warehouse
+	for _, warehouse := range repo.warehouseByID {
+		warehouses = append(warehouses, warehouse)
+	}
+
+	return warehouses, nil
+}
+
+func (repo *inMemoryRepository) InsertWarehouse(warehouse warehouse.Warehouse) error {
+	if warehouse.Name == "Error" {
+		return errorInsert
+	}
+
+	repo.warehouseByID[warehouse.ID] = warehouse
+	return nil
+}
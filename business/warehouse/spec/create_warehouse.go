@@ -0,0 +1,7 @@
+package spec
+
+//CreateWarehouseSpec create warehouse spec
+type CreateWarehouseSpec struct {
+	Name    string `validate:"required"`
+	Address string `validate:"required"`
+}
@@ -0,0 +1,33 @@
+package warehouse
+
+import "time"
+
+//DefaultWarehouseID ID of the main warehouse created by migration, stock recorded before
+//multi warehouse support belong to this warehouse
+const DefaultWarehouseID = "000000000000000000000001"
+
+//Warehouse store location that keep the item stock
+type Warehouse struct {
+	ID        string
+	Name      string
+	Address   string
+	CreatedAt time.Time
+	CreatedBy string
+}
+
+//NewWarehouse create new warehouse
+func NewWarehouse(
+	id string,
+	name string,
+	address string,
+	creator string,
+	createdAt time.Time) Warehouse {
+
+	return Warehouse{
+		ID:        id,
+		Name:      name,
+		Address:   address,
+		CreatedAt: createdAt,
+		CreatedBy: creator,
+	}
+}
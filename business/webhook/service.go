@@ -0,0 +1,302 @@
+package webhook
+
+import (
+	"fmt"
+	"sample-order/business"
+	"sample-order/business/outbox"
+	"sample-order/business/webhook/spec"
+	"sample-order/util"
+	"strconv"
+	"time"
+
+	validator "github.com/go-playground/validator/v10"
+)
+
+//Repository ingoing port for webhook
+type Repository interface {
+	//FindSubscriptionByID If data not found will return nil without error
+	FindSubscriptionByID(ID string) (*Subscription, error)
+
+	//FindAllSubscriptions Return subscriptions from the oldest, empty slice if there is none
+	FindAllSubscriptions() ([]Subscription, error)
+
+	//InsertSubscription Insert new subscription
+	InsertSubscription(subscription Subscription) error
+
+	//UpdateSubscription Update existing subscription. If data not found or version is not match will return business.ErrZeroAffected
+	UpdateSubscription(subscription Subscription, currentVersion int) error
+
+	//DeleteSubscription Delete subscription and its deliveries. If data not found or version is not match will return business.ErrZeroAffected
+	DeleteSubscription(ID string, currentVersion int) error
+
+	//InsertDeliveries Insert new deliveries, delivery of the same message into the same subscription is skipped
+	//because the outbox may publish the message more than once
+	InsertDeliveries(deliveries []Delivery) error
+
+	//FindDeliveryByID If data not found will return nil without error
+	FindDeliveryByID(ID string) (*Delivery, error)
+
+	//FindDueDeliveries Return at most limit pending deliveries which next attempt is not after now, from the earliest
+	FindDueDeliveries(now time.Time, limit int) ([]Delivery, error)
+
+	//FindDeliveriesByStatus Return at most limit deliveries with given status from the newest
+	FindDeliveriesByStatus(status DeliveryStatus, limit int) ([]Delivery, error)
+
+	//UpdateDelivery Update the attempt state of delivery. If data not found or its status is not the current status
+	//will return business.ErrZeroAffected
+	UpdateDelivery(delivery Delivery, currentStatus DeliveryStatus) error
+}
+
+//Request signed HTTP request of single delivery attempt
+type Request struct {
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+//Sender outgoing port to POST the request into partner endpoint. Return the HTTP status code of the response,
+//error is only returned when there is no response
+type Sender interface {
+	Send(request Request) (int, error)
+}
+
+//Service outgoing port for webhook
+type Service interface {
+	GetSubscriptionByID(ID string) (*Subscription, error)
+
+	GetSubscriptions() ([]Subscription, error)
+
+	CreateSubscription(upsertSubscriptionSpec spec.UpsertSubscriptionSpec, createdBy string) (string, error)
+
+	UpdateSubscription(ID string, upsertSubscriptionSpec spec.UpsertSubscriptionSpec, currentVersion int, modifiedBy string) error
+
+	DeleteSubscription(ID string, currentVersion int) error
+
+	Publish(message outbox.Message) error
+
+	DeliverDue(now time.Time, limit int) (int, error)
+
+	GetDeadLetters(limit int) ([]Delivery, error)
+
+	Redeliver(deliveryID string) error
+}
+
+//=============== The implementation of those interface put below =======================
+
+type service struct {
+	repository Repository
+	sender     Sender
+	policy     RetryPolicy
+	validate   *validator.Validate
+}
+
+//NewService Construct webhook service object, failed delivery is retried according to the policy
+func NewService(repository Repository, sender Sender, policy RetryPolicy) Service {
+	return &service{
+		repository,
+		sender,
+		policy,
+		validator.New(),
+	}
+}
+
+//GetSubscriptionByID Get subscription by given ID, return nil if not exist
+func (s *service) GetSubscriptionByID(ID string) (*Subscription, error) {
+	return s.repository.FindSubscriptionByID(ID)
+}
+
+//GetSubscriptions Get all subscriptions, return zero array if there is none
+func (s *service) GetSubscriptions() ([]Subscription, error) {
+	subscriptions, err := s.repository.FindAllSubscriptions()
+	if err != nil || subscriptions == nil {
+		return []Subscription{}, err
+	}
+
+	return subscriptions, nil
+}
+
+//CreateSubscription Create new subscription, the secret is required
+func (s *service) CreateSubscription(upsertSubscriptionSpec spec.UpsertSubscriptionSpec, createdBy string) (string, error) {
+	err := s.validate.Struct(upsertSubscriptionSpec)
+	if err != nil || len(upsertSubscriptionSpec.Secret) == 0 {
+		return "", business.ErrInvalidSpec
+	}
+
+	subscription := NewSubscription(
+		util.GenerateID(),
+		upsertSubscriptionSpec.URL,
+		upsertSubscriptionSpec.EventTypes,
+		upsertSubscriptionSpec.Tag,
+		upsertSubscriptionSpec.Secret,
+		createdBy,
+		time.Now(),
+	)
+
+	if err := s.repository.InsertSubscription(subscription); err != nil {
+		return "", err
+	}
+
+	return subscription.ID, nil
+}
+
+//UpdateSubscription Update existing subscription, pending deliveries are sent with the new URL and secret
+func (s *service) UpdateSubscription(ID string, upsertSubscriptionSpec spec.UpsertSubscriptionSpec, currentVersion int, modifiedBy string) error {
+	if err := s.validate.Struct(upsertSubscriptionSpec); err != nil {
+		return business.ErrInvalidSpec
+	}
+
+	subscription, err := s.repository.FindSubscriptionByID(ID)
+	if err != nil {
+		return err
+	} else if subscription == nil {
+		return business.ErrNotFound
+	} else if subscription.Version != currentVersion {
+		return business.ErrHasBeenModified
+	}
+
+	modifiedSubscription := subscription.ModifySubscription(
+		upsertSubscriptionSpec.URL,
+		upsertSubscriptionSpec.EventTypes,
+		upsertSubscriptionSpec.Tag,
+		upsertSubscriptionSpec.Secret,
+		modifiedBy,
+		time.Now())
+
+	return s.mapZeroAffected(s.repository.UpdateSubscription(modifiedSubscription, currentVersion))
+}
+
+//DeleteSubscription Delete subscription together with its pending and dead deliveries
+func (s *service) DeleteSubscription(ID string, currentVersion int) error {
+	subscription, err := s.repository.FindSubscriptionByID(ID)
+	if err != nil {
+		return err
+	} else if subscription == nil {
+		return business.ErrNotFound
+	} else if subscription.Version != currentVersion {
+		return business.ErrHasBeenModified
+	}
+
+	return s.mapZeroAffected(s.repository.DeleteSubscription(ID, currentVersion))
+}
+
+//Publish Queue delivery of the outbox message into every subscription which match its event type and item tags.
+//It implement outbox.Publisher, the message is sent later by DeliverDue so slow partner never block the outbox
+func (s *service) Publish(message outbox.Message) error {
+	subscriptions, err := s.repository.FindAllSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	tags := eventTags(message.Payload)
+	now := time.Now()
+
+	var deliveries []Delivery
+	for _, subscription := range subscriptions {
+		if subscription.Matches(message.EventType, tags) {
+			deliveries = append(deliveries, NewDelivery(util.GenerateID(), subscription, message, now))
+		}
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return s.repository.InsertDeliveries(deliveries)
+}
+
+//DeliverDue Attempt at most limit deliveries which are due at given time and return the number of delivered ones.
+//Failed attempt is retried later with exponential backoff until it run out of attempts and become dead letter
+func (s *service) DeliverDue(now time.Time, limit int) (int, error) {
+	deliveries, err := s.repository.FindDueDeliveries(now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+
+	for _, delivery := range deliveries {
+		result, err := s.attempt(delivery)
+		if err != nil {
+			return delivered, err
+		}
+
+		if result.Status == Delivered {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+//GetDeadLetters Get at most limit deliveries which run out of attempts from the newest
+func (s *service) GetDeadLetters(limit int) ([]Delivery, error) {
+	deliveries, err := s.repository.FindDeliveriesByStatus(Dead, limit)
+	if err != nil || deliveries == nil {
+		return []Delivery{}, err
+	}
+
+	return deliveries, nil
+}
+
+//Redeliver Send dead or delivered delivery again with full attempts. Pending delivery is rejected with business.TransitionError
+func (s *service) Redeliver(deliveryID string) error {
+	delivery, err := s.repository.FindDeliveryByID(deliveryID)
+	if err != nil {
+		return err
+	} else if delivery == nil {
+		return business.ErrNotFound
+	} else if delivery.Status == Pending {
+		return &business.TransitionError{From: string(delivery.Status), To: string(Pending)}
+	}
+
+	return s.mapZeroAffected(s.repository.UpdateDelivery(delivery.Redeliver(time.Now()), delivery.Status))
+}
+
+//attempt send the delivery once and store the result. Deleted subscription leave its delivery untouched,
+//the delivery is deleted together with the subscription
+func (s *service) attempt(delivery Delivery) (Delivery, error) {
+	subscription, err := s.repository.FindSubscriptionByID(delivery.SubscriptionID)
+	if err != nil || subscription == nil {
+		return delivery, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	request := Request{
+		URL: subscription.URL,
+		Headers: map[string]string{
+			"Content-Type":  "application/json",
+			SignatureHeader: Sign(subscription.Secret, timestamp, delivery.Payload),
+			TimestampHeader: strconv.FormatInt(timestamp, 10),
+			EventHeader:     delivery.EventType,
+			DeliveryHeader:  delivery.ID,
+		},
+		Body: delivery.Payload,
+	}
+
+	var result Delivery
+
+	statusCode, err := s.sender.Send(request)
+	if err != nil {
+		result = delivery.Fail(0, err.Error(), time.Now(), s.policy)
+	} else if statusCode < 200 || statusCode > 299 {
+		result = delivery.Fail(statusCode, fmt.Sprintf("unexpected status code %d", statusCode), time.Now(), s.policy)
+	} else {
+		result = delivery.Succeed(statusCode, time.Now())
+	}
+
+	//redelivered or deleted in the meantime, the other change win
+	if err := s.repository.UpdateDelivery(result, delivery.Status); err != nil && err != business.ErrZeroAffected {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (s *service) mapZeroAffected(err error) error {
+	if err == business.ErrZeroAffected {
+		return business.ErrHasBeenModified
+	}
+
+	return err
+}
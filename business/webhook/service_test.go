@@ -0,0 +1,480 @@
+package webhook_test
+
+import (
+	"errors"
+	"sample-order/business"
+	"sample-order/business/outbox"
+	"sample-order/business/webhook"
+	"sample-order/business/webhook/spec"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var policy = webhook.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: 90 * time.Second}
+var errorSend error = errors.New("connection refused")
+
+const secret = "0123456789abcdef"
+
+func TestCreateSubscription(t *testing.T) {
+	t.Run("Expect success create subscription", func(t *testing.T) {
+		service, repo, _ := newService()
+
+		ID, err := service.CreateSubscription(newSpec("accessory"), "creator")
+		if err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		subscription := repo.subscriptionByID[ID]
+		if subscription.URL != "http://partner.local/hook" || subscription.Secret != secret || subscription.Version != 1 {
+			t.Error("Expect subscription is stored as given", subscription)
+		}
+	})
+
+	t.Run("Expect failed create subscription without secret", func(t *testing.T) {
+		service, _, _ := newService()
+
+		upsertSpec := newSpec("")
+		upsertSpec.Secret = ""
+
+		if _, err := service.CreateSubscription(upsertSpec, "creator"); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed create subscription on invalid URL and event type", func(t *testing.T) {
+		service, _, _ := newService()
+
+		upsertSpec := newSpec("")
+		upsertSpec.URL = "ftp://partner.local/hook"
+
+		if _, err := service.CreateSubscription(upsertSpec, "creator"); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec on non HTTP URL. Error is: ", err)
+		}
+
+		upsertSpec = newSpec("")
+		upsertSpec.EventTypes = []string{"ItemRenamed"}
+
+		if _, err := service.CreateSubscription(upsertSpec, "creator"); err != business.ErrInvalidSpec {
+			t.Error("Expect error invalid spec on unknown event type. Error is: ", err)
+		}
+	})
+}
+
+func TestUpdateSubscription(t *testing.T) {
+	t.Run("Expect empty secret keep the old one", func(t *testing.T) {
+		service, repo, _ := newService()
+		ID, _ := service.CreateSubscription(newSpec(""), "creator")
+
+		upsertSpec := newSpec("camera")
+		upsertSpec.Secret = ""
+
+		if err := service.UpdateSubscription(ID, upsertSpec, 1, "updater"); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		subscription := repo.subscriptionByID[ID]
+		if subscription.Tag != "camera" || subscription.Secret != secret || subscription.Version != 2 {
+			t.Error("Expect subscription is updated with the old secret", subscription)
+		}
+	})
+
+	t.Run("Expect failed update subscription on wrong version", func(t *testing.T) {
+		service, _, _ := newService()
+		ID, _ := service.CreateSubscription(newSpec(""), "creator")
+
+		if err := service.UpdateSubscription(ID, newSpec(""), 2, "updater"); err != business.ErrHasBeenModified {
+			t.Error("Expect error has been modified. Error is: ", err)
+		}
+	})
+}
+
+func TestDeleteSubscription(t *testing.T) {
+	t.Run("Expect delete subscription and its deliveries", func(t *testing.T) {
+		service, repo, _ := newService()
+		ID, _ := service.CreateSubscription(newSpec(""), "creator")
+		service.Publish(newMessage("message-1", "ItemCreated", "accessory"))
+
+		if err := service.DeleteSubscription(ID, 1); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if len(repo.subscriptionByID) != 0 || len(repo.deliveryByID) != 0 {
+			t.Error("Expect subscription and its deliveries are deleted")
+		}
+	})
+
+	t.Run("Expect failed delete subscription on not found", func(t *testing.T) {
+		service, _, _ := newService()
+
+		if err := service.DeleteSubscription("not-found", 1); err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+	})
+}
+
+func TestPublish(t *testing.T) {
+	t.Run("Expect delivery queued only for matching subscriptions", func(t *testing.T) {
+		service, repo, _ := newService()
+		allID, _ := service.CreateSubscription(newSpec(""), "creator")
+		accessoryID, _ := service.CreateSubscription(newSpec("accessory"), "creator")
+		cameraID, _ := service.CreateSubscription(newSpec("camera"), "creator")
+
+		deletedOnly := newSpec("")
+		deletedOnly.EventTypes = []string{"ItemDeleted"}
+		deletedOnlyID, _ := service.CreateSubscription(deletedOnly, "creator")
+
+		if err := service.Publish(newMessage("message-1", "ItemCreated", "accessory")); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		subscribers := map[string]bool{}
+		for _, delivery := range repo.deliveryByID {
+			subscribers[delivery.SubscriptionID] = true
+
+			if delivery.Status != webhook.Pending || delivery.MessageID != "message-1" || delivery.EventType != "ItemCreated" {
+				t.Error("Expect pending delivery of the message", delivery)
+			}
+		}
+
+		if len(subscribers) != 2 || !subscribers[allID] || !subscribers[accessoryID] {
+			t.Error("Expect only subscription without tag and with item tag receive the event", subscribers)
+		}
+
+		if subscribers[cameraID] || subscribers[deletedOnlyID] {
+			t.Error("Expect subscription with other tag or event type is skipped")
+		}
+	})
+
+	t.Run("Expect the same message published twice is delivered once", func(t *testing.T) {
+		service, repo, _ := newService()
+		service.CreateSubscription(newSpec(""), "creator")
+
+		service.Publish(newMessage("message-1", "ItemCreated", "accessory"))
+		service.Publish(newMessage("message-1", "ItemCreated", "accessory"))
+
+		if len(repo.deliveryByID) != 1 {
+			t.Error("Expect single delivery", len(repo.deliveryByID))
+		}
+	})
+}
+
+func TestDeliverDue(t *testing.T) {
+	t.Run("Expect signed delivery is sent and marked delivered", func(t *testing.T) {
+		service, repo, sender := newService()
+		service.CreateSubscription(newSpec(""), "creator")
+		service.Publish(newMessage("message-1", "ItemCreated", "accessory"))
+
+		delivered, err := service.DeliverDue(time.Now(), 10)
+		if err != nil || delivered != 1 {
+			t.Error("Expect one delivery is delivered", delivered, err)
+			t.FailNow()
+		}
+
+		request := sender.requests[0]
+		timestamp, _ := strconv.ParseInt(request.Headers[webhook.TimestampHeader], 10, 64)
+
+		if !webhook.Verify(secret, timestamp, request.Body, request.Headers[webhook.SignatureHeader]) {
+			t.Error("Expect request is signed with the subscription secret")
+		}
+
+		if request.Headers[webhook.EventHeader] != "ItemCreated" || request.URL != "http://partner.local/hook" {
+			t.Error("Expect request is sent into subscription URL with event type", request)
+		}
+
+		for _, delivery := range repo.deliveryByID {
+			if delivery.Status != webhook.Delivered || delivery.Attempts != 1 || delivery.DeliveredAt == nil {
+				t.Error("Expect delivery is delivered", delivery)
+			}
+
+			if request.Headers[webhook.DeliveryHeader] != delivery.ID {
+				t.Error("Expect delivery ID is sent")
+			}
+		}
+
+		if delivered, _ := service.DeliverDue(time.Now(), 10); delivered != 0 || len(sender.requests) != 1 {
+			t.Error("Expect delivered delivery is not sent again")
+		}
+	})
+
+	t.Run("Expect failed delivery is retried with backoff until dead", func(t *testing.T) {
+		service, repo, sender := newService()
+		service.CreateSubscription(newSpec(""), "creator")
+		service.Publish(newMessage("message-1", "ItemCreated", "accessory"))
+		sender.statusCode = 500
+
+		now := time.Now()
+		service.DeliverDue(now, 10)
+
+		delivery := repo.onlyDelivery()
+		if delivery.Status != webhook.Pending || delivery.Attempts != 1 || delivery.LastStatusCode != 500 {
+			t.Error("Expect failed attempt is recorded", delivery)
+		}
+
+		if delivery.NextAttemptAt.Before(now.Add(time.Minute)) {
+			t.Error("Expect next attempt is delayed by base delay", delivery.NextAttemptAt)
+		}
+
+		if service.DeliverDue(now, 10); len(sender.requests) != 1 {
+			t.Error("Expect delivery is not sent again before its next attempt")
+		}
+
+		sender.err = errorSend
+		service.DeliverDue(delivery.NextAttemptAt, 10)
+
+		delivery = repo.onlyDelivery()
+		if delivery.Attempts != 2 || delivery.LastError != errorSend.Error() || delivery.LastStatusCode != 0 {
+			t.Error("Expect failed connection is recorded", delivery)
+		}
+
+		service.DeliverDue(delivery.NextAttemptAt, 10)
+
+		if delivery = repo.onlyDelivery(); delivery.Status != webhook.Dead || delivery.Attempts != 3 {
+			t.Error("Expect delivery is dead after max attempts", delivery)
+		}
+
+		deadLetters, _ := service.GetDeadLetters(10)
+		if len(deadLetters) != 1 || deadLetters[0].ID != delivery.ID {
+			t.Error("Expect dead delivery is in dead letter list", deadLetters)
+		}
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	t.Run("Expect dead delivery is sent again", func(t *testing.T) {
+		service, repo, sender := newService()
+		service.CreateSubscription(newSpec(""), "creator")
+		service.Publish(newMessage("message-1", "ItemCreated", "accessory"))
+
+		delivery := repo.onlyDelivery()
+		delivery.Status = webhook.Dead
+		delivery.Attempts = policy.MaxAttempts
+		repo.deliveryByID[delivery.ID] = delivery
+
+		if err := service.Redeliver(delivery.ID); err != nil {
+			t.Error("Expect error is nil. Error: ", err)
+			t.FailNow()
+		}
+
+		if delivered, _ := service.DeliverDue(time.Now(), 10); delivered != 1 || len(sender.requests) != 1 {
+			t.Error("Expect redelivered delivery is sent", delivered)
+		}
+
+		if deadLetters, _ := service.GetDeadLetters(10); len(deadLetters) != 0 {
+			t.Error("Expect dead letter list is empty")
+		}
+	})
+
+	t.Run("Expect failed redeliver pending delivery", func(t *testing.T) {
+		service, repo, _ := newService()
+		service.CreateSubscription(newSpec(""), "creator")
+		service.Publish(newMessage("message-1", "ItemCreated", "accessory"))
+
+		if err := service.Redeliver(repo.onlyDelivery().ID); !errors.Is(err, business.ErrInvalidTransition) {
+			t.Error("Expect error invalid transition. Error is: ", err)
+		}
+	})
+
+	t.Run("Expect failed redeliver on not found", func(t *testing.T) {
+		service, _, _ := newService()
+
+		if err := service.Redeliver("not-found"); err != business.ErrNotFound {
+			t.Error("Expect error not found. Error is: ", err)
+		}
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("Expect delay doubled and capped", func(t *testing.T) {
+		expected := []time.Duration{time.Minute, 90 * time.Second, 90 * time.Second}
+
+		for idx, delay := range expected {
+			if backoff := policy.Backoff(idx + 1); backoff != delay {
+				t.Error("Expect backoff after attempt", idx+1, "is", delay, "got", backoff)
+			}
+		}
+	})
+}
+
+func newService() (webhook.Service, *inMemoryRepository, *inMemorySender) {
+	repo := &inMemoryRepository{
+		make(map[string]webhook.Subscription),
+		make(map[string]webhook.Delivery),
+	}
+	sender := &inMemorySender{statusCode: 204}
+
+	return webhook.NewService(repo, sender, policy), repo, sender
+}
+
+func newSpec(tag string) spec.UpsertSubscriptionSpec {
+	return spec.UpsertSubscriptionSpec{
+		URL:        "http://partner.local/hook",
+		EventTypes: []string{"ItemCreated", "ItemUpdated"},
+		Tag:        tag,
+		Secret:     secret,
+	}
+}
+
+func newMessage(ID string, eventType string, tag string) outbox.Message {
+	return outbox.Message{
+		ID:            ID,
+		AggregateType: "item",
+		AggregateID:   "item-1",
+		EventType:     eventType,
+		Version:       1,
+		Payload:       []byte(`{"type":"` + eventType + `","before":null,"after":{"id":"item-1","tags":["` + tag + `"]}}`),
+		OccurredAt:    time.Now(),
+	}
+}
+
+type inMemoryRepository struct {
+	subscriptionByID map[string]webhook.Subscription
+	deliveryByID     map[string]webhook.Delivery
+}
+
+func (repo *inMemoryRepository) onlyDelivery() webhook.Delivery {
+	for _, delivery := range repo.deliveryByID {
+		return delivery
+	}
+
+	return webhook.Delivery{}
+}
+
+func (repo *inMemoryRepository) FindSubscriptionByID(ID string) (*webhook.Subscription, error) {
+	subscription, ok := repo.subscriptionByID[ID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &subscription, nil
+}
+
+func (repo *inMemoryRepository) FindAllSubscriptions() ([]webhook.Subscription, error) {
+	var subscriptions []webhook.Subscription
+	for _, subscription := range repo.subscriptionByID {
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool { return subscriptions[i].ID < subscriptions[j].ID })
+	return subscriptions, nil
+}
+
+func (repo *inMemoryRepository) InsertSubscription(subscription webhook.Subscription) error {
+	repo.subscriptionByID[subscription.ID] = subscription
+	return nil
+}
+
+func (repo *inMemoryRepository) UpdateSubscription(subscription webhook.Subscription, currentVersion int) error {
+	old, ok := repo.subscriptionByID[subscription.ID]
+	if !ok || old.Version != currentVersion {
+		return business.ErrZeroAffected
+	}
+
+	repo.subscriptionByID[subscription.ID] = subscription
+	return nil
+}
+
+func (repo *inMemoryRepository) DeleteSubscription(ID string, currentVersion int) error {
+	old, ok := repo.subscriptionByID[ID]
+	if !ok || old.Version != currentVersion {
+		return business.ErrZeroAffected
+	}
+
+	delete(repo.subscriptionByID, ID)
+
+	for deliveryID, delivery := range repo.deliveryByID {
+		if delivery.SubscriptionID == ID {
+			delete(repo.deliveryByID, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (repo *inMemoryRepository) InsertDeliveries(deliveries []webhook.Delivery) error {
+	for _, delivery := range deliveries {
+		isExist := false
+		for _, existing := range repo.deliveryByID {
+			if existing.SubscriptionID == delivery.SubscriptionID && existing.MessageID == delivery.MessageID {
+				isExist = true
+				break
+			}
+		}
+
+		if !isExist {
+			repo.deliveryByID[delivery.ID] = delivery
+		}
+	}
+	return nil
+}
+
+func (repo *inMemoryRepository) FindDeliveryByID(ID string) (*webhook.Delivery, error) {
+	delivery, ok := repo.deliveryByID[ID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &delivery, nil
+}
+
+func (repo *inMemoryRepository) FindDueDeliveries(now time.Time, limit int) ([]webhook.Delivery, error) {
+	var deliveries []webhook.Delivery
+	for _, delivery := range repo.deliveryByID {
+		if delivery.Status == webhook.Pending && !delivery.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt) })
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (repo *inMemoryRepository) FindDeliveriesByStatus(status webhook.DeliveryStatus, limit int) ([]webhook.Delivery, error) {
+	var deliveries []webhook.Delivery
+	for _, delivery := range repo.deliveryByID {
+		if delivery.Status == status {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt) })
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (repo *inMemoryRepository) UpdateDelivery(delivery webhook.Delivery, currentStatus webhook.DeliveryStatus) error {
+	old, ok := repo.deliveryByID[delivery.ID]
+	if !ok || old.Status != currentStatus {
+		return business.ErrZeroAffected
+	}
+
+	repo.deliveryByID[delivery.ID] = delivery
+	return nil
+}
+
+type inMemorySender struct {
+	statusCode int
+	err        error
+	requests   []webhook.Request
+}
+
+func (sender *inMemorySender) Send(request webhook.Request) (int, error) {
+	sender.requests = append(sender.requests, request)
+
+	if sender.err != nil {
+		return 0, sender.err
+	}
+
+	return sender.statusCode, nil
+}
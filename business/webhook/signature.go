@@ -0,0 +1,34 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+//Headers of every delivery request
+const (
+	//SignatureHeader HMAC-SHA256 of the timestamp and the body, see Sign
+	SignatureHeader = "X-Webhook-Signature"
+	//TimestampHeader unix time when the request is signed, partner should reject old timestamp to prevent replay
+	TimestampHeader = "X-Webhook-Timestamp"
+	//EventHeader event type of the payload
+	EventHeader = "X-Webhook-Event"
+	//DeliveryHeader delivery ID, the same on every attempt so partner can ignore duplicate
+	DeliveryHeader = "X-Webhook-Delivery"
+)
+
+//Sign Return the signature of the body as "sha256=" followed by hex encoded HMAC-SHA256 of "<timestamp>.<body>" using the secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+//Verify Return true if the signature is made from the timestamp and body using the secret
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,10 @@
+package spec
+
+//UpsertSubscriptionSpec create and update webhook subscription spec. The secret is required on create,
+//empty secret on update keep the old one
+type UpsertSubscriptionSpec struct {
+	URL        string   `validate:"required,url,startswith=http,max=500"`
+	EventTypes []string `validate:"required,min=1,dive,oneof=ItemCreated ItemUpdated ItemDeleted"`
+	Tag        string   `validate:"max=50"`
+	Secret     string   `validate:"omitempty,min=16,max=100"`
+}
@@ -0,0 +1,222 @@
+package webhook
+
+import (
+	"encoding/json"
+	"sample-order/business/outbox"
+	"time"
+)
+
+//Subscription partner endpoint which receive item events. Empty tag means events of every item
+type Subscription struct {
+	ID         string
+	URL        string
+	EventTypes []string
+	Tag        string
+	Secret     string
+	CreatedAt  time.Time
+	CreatedBy  string
+	ModifiedAt time.Time
+	ModifiedBy string
+	Version    int
+}
+
+//NewSubscription create new subscription
+func NewSubscription(
+	id string,
+	url string,
+	eventTypes []string,
+	tag string,
+	secret string,
+	creator string,
+	createdAt time.Time) Subscription {
+
+	return Subscription{
+		ID:         id,
+		URL:        url,
+		EventTypes: eventTypes,
+		Tag:        tag,
+		Secret:     secret,
+		CreatedAt:  createdAt,
+		CreatedBy:  creator,
+		ModifiedAt: createdAt,
+		ModifiedBy: creator,
+		Version:    1,
+	}
+}
+
+//ModifySubscription update existing subscription, empty secret keep the old one
+func (oldSubscription *Subscription) ModifySubscription(url string, eventTypes []string, tag string, secret string, updater string, modifiedAt time.Time) Subscription {
+	if len(secret) == 0 {
+		secret = oldSubscription.Secret
+	}
+
+	return Subscription{
+		ID:         oldSubscription.ID,
+		URL:        url,
+		EventTypes: eventTypes,
+		Tag:        tag,
+		Secret:     secret,
+		CreatedAt:  oldSubscription.CreatedAt,
+		CreatedBy:  oldSubscription.CreatedBy,
+		ModifiedAt: modifiedAt,
+		ModifiedBy: updater,
+		Version:    oldSubscription.Version + 1,
+	}
+}
+
+//Matches Return true if the subscription want the event of item with given tags
+func (subscription *Subscription) Matches(eventType string, tags []string) bool {
+	isSubscribed := false
+	for _, subscribed := range subscription.EventTypes {
+		if subscribed == eventType {
+			isSubscribed = true
+			break
+		}
+	}
+
+	if !isSubscribed {
+		return false
+	}
+
+	if len(subscription.Tag) == 0 {
+		return true
+	}
+
+	for _, tag := range tags {
+		if tag == subscription.Tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+//DeliveryStatus state of event delivery into single subscription
+type DeliveryStatus string
+
+const (
+	//Pending delivery is waiting for its next attempt
+	Pending DeliveryStatus = "pending"
+	//Delivered partner accepted the event
+	Delivered DeliveryStatus = "delivered"
+	//Dead every attempt failed, the delivery is kept in the dead letter list until it is redelivered
+	Dead DeliveryStatus = "dead"
+)
+
+//Delivery outbox message sent into single subscription
+type Delivery struct {
+	ID             string
+	SubscriptionID string
+	MessageID      string
+	EventType      string
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+//NewDelivery create pending delivery of the message which is attempted immediately
+func NewDelivery(id string, subscription Subscription, message outbox.Message, createdAt time.Time) Delivery {
+	return Delivery{
+		ID:             id,
+		SubscriptionID: subscription.ID,
+		MessageID:      message.ID,
+		EventType:      message.EventType,
+		Payload:        message.Payload,
+		Status:         Pending,
+		NextAttemptAt:  createdAt,
+		CreatedAt:      createdAt,
+	}
+}
+
+//Succeed mark the delivery as accepted by the partner
+func (oldDelivery *Delivery) Succeed(statusCode int, deliveredAt time.Time) Delivery {
+	delivery := *oldDelivery
+	delivery.Status = Delivered
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+	delivery.DeliveredAt = &deliveredAt
+
+	return delivery
+}
+
+//Fail record failed attempt and schedule the next one, the delivery is dead when it run out of attempts
+func (oldDelivery *Delivery) Fail(statusCode int, reason string, failedAt time.Time, policy RetryPolicy) Delivery {
+	delivery := *oldDelivery
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = reason
+
+	if delivery.Attempts >= policy.MaxAttempts {
+		delivery.Status = Dead
+	} else {
+		delivery.NextAttemptAt = failedAt.Add(policy.Backoff(delivery.Attempts))
+	}
+
+	return delivery
+}
+
+//Redeliver reset the delivery so it is attempted again immediately with full attempts
+func (oldDelivery *Delivery) Redeliver(at time.Time) Delivery {
+	delivery := *oldDelivery
+	delivery.Status = Pending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = at
+	delivery.DeliveredAt = nil
+
+	return delivery
+}
+
+//RetryPolicy how many times and how long to wait before failed delivery is attempted again
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+//Backoff Return the delay after given number of failed attempts, doubled on every attempt and never more than max delay
+func (policy RetryPolicy) Backoff(attempts int) time.Duration {
+	delay := policy.BaseDelay
+
+	for i := 1; i < attempts && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > policy.MaxDelay {
+		return policy.MaxDelay
+	}
+
+	return delay
+}
+
+//eventTags Return the tags of item before and after the change carried by the event payload
+func eventTags(payload []byte) []string {
+	var event struct {
+		Before *struct {
+			Tags []string `json:"tags"`
+		} `json:"before"`
+		After *struct {
+			Tags []string `json:"tags"`
+		} `json:"after"`
+	}
+
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil
+	}
+
+	var tags []string
+	if event.Before != nil {
+		tags = append(tags, event.Before.Tags...)
+	}
+
+	if event.After != nil {
+		tags = append(tags, event.After.Tags...)
+	}
+
+	return tags
+}
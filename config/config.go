@@ -2,8 +2,9 @@ package config
 
 import (
 	"sync"
+	"time"
 
-	"github.com/labstack/gommon/log"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
 
@@ -17,6 +18,88 @@ type AppConfig struct {
 		Port     int    `yaml:"port"`
 		Username string `yaml:"username"`
 		Password string `yaml:"password"`
+
+		//AutoMigrate apply pending schema migration when server start
+		AutoMigrate bool `yaml:"automigrate"`
+	}
+	Reservation struct {
+		//TTL how long the stock is held before the reservation expire
+		TTL time.Duration `yaml:"ttl"`
+
+		//SweepInterval how often the sweeper look for expired reservations
+		SweepInterval time.Duration `yaml:"sweepinterval"`
+	}
+	Outbox struct {
+		//RelayInterval how often the relay publish pending outbox messages
+		RelayInterval time.Duration `yaml:"relayinterval"`
+
+		//BatchSize maximum number of messages published in single relay run
+		BatchSize int `yaml:"batchsize"`
+	}
+	Stream struct {
+		//ReplaySize number of latest item events kept for client which reconnect to the event stream
+		ReplaySize int `yaml:"replaysize"`
+	}
+	Webhook struct {
+		//DeliveryInterval how often due deliveries are sent
+		DeliveryInterval time.Duration `yaml:"deliveryinterval"`
+
+		//BatchSize maximum number of deliveries sent in single run
+		BatchSize int `yaml:"batchsize"`
+
+		//Timeout how long to wait for the partner response
+		Timeout time.Duration `yaml:"timeout"`
+
+		//MaxAttempts number of attempts before the delivery become dead letter
+		MaxAttempts int `yaml:"maxattempts"`
+
+		//BackoffBase delay after the first failed attempt, doubled on every next failure
+		BackoffBase time.Duration `yaml:"backoffbase"`
+
+		//BackoffMax longest delay between attempts
+		BackoffMax time.Duration `yaml:"backoffmax"`
+	}
+	Tracing struct {
+		//Exporter where the spans are sent, possible value are none, stdout or otlp
+		Exporter string `yaml:"exporter"`
+
+		//Endpoint host and port of the OTLP gRPC collector
+		Endpoint string `yaml:"endpoint"`
+
+		//Insecure connect into the collector without TLS
+		Insecure bool `yaml:"insecure"`
+
+		//ServiceName name of this service in the traces
+		ServiceName string `yaml:"servicename"`
+
+		//SampleRatio fraction of new traces which are recorded, traces started by the caller follow its decision
+		SampleRatio float64 `yaml:"sampleratio"`
+	}
+	Log struct {
+		//Level minimum level written, possible value are debug, info, warn or error
+		Level string `yaml:"level"`
+
+		//Format json for log collector or text for human reader
+		Format string `yaml:"format"`
+	}
+	Health struct {
+		//Timeout how long the readiness probe wait for the dependencies
+		Timeout time.Duration `yaml:"timeout"`
+
+		//TotalTimeout how long the whole shutdown including the drain period may take, it cut the stage timeout
+		//so the server finish before it is killed by the orchestrator grace period
+		TotalTimeout time.Duration `yaml:"totaltimeout"`
+	}
+	Shutdown struct {
+		//DrainPeriod how long the server keep serving after the readiness is turned off, so the load balancer stop routing into it
+		DrainPeriod time.Duration `yaml:"drainperiod"`
+
+		//Timeout how long each stage after the drain period is waited, e.g. the running requests or single background worker
+		Timeout time.Duration `yaml:"timeout"`
+
+		//TotalTimeout how long the whole shutdown including the drain period may take, it cut the stage timeout
+		//so the server finish before it is killed by the orchestrator grace period
+		TotalTimeout time.Duration `yaml:"totaltimeout"`
 	}
 }
 
@@ -44,22 +127,130 @@ func initConfig() *AppConfig {
 	defaultConfig.Database.Port = 27017
 	defaultConfig.Database.Username = ""
 	defaultConfig.Database.Password = ""
+	defaultConfig.Database.AutoMigrate = false
+	defaultConfig.Reservation.TTL = 15 * time.Minute
+	defaultConfig.Reservation.SweepInterval = time.Minute
+	defaultConfig.Outbox.RelayInterval = 5 * time.Second
+	defaultConfig.Outbox.BatchSize = 100
+	defaultConfig.Stream.ReplaySize = 1000
+	defaultConfig.Webhook.DeliveryInterval = 5 * time.Second
+	defaultConfig.Webhook.BatchSize = 50
+	defaultConfig.Webhook.Timeout = 10 * time.Second
+	defaultConfig.Webhook.MaxAttempts = 8
+	defaultConfig.Webhook.BackoffBase = 30 * time.Second
+	defaultConfig.Webhook.BackoffMax = time.Hour
+	defaultConfig.Tracing.Exporter = "none"
+	defaultConfig.Tracing.Endpoint = "localhost:4317"
+	defaultConfig.Tracing.Insecure = true
+	defaultConfig.Tracing.ServiceName = "sample-order"
+	defaultConfig.Tracing.SampleRatio = 1
+	defaultConfig.Log.Level = "info"
+	defaultConfig.Log.Format = "json"
+	defaultConfig.Health.Timeout = 2 * time.Second
+	defaultConfig.Shutdown.DrainPeriod = 5 * time.Second
+	defaultConfig.Shutdown.Timeout = 10 * time.Second
+	defaultConfig.Shutdown.TotalTimeout = 25 * time.Second
 
 	viper.SetConfigType("yaml")
 	viper.SetConfigName("config")
 	viper.AddConfigPath("./config/")
 
 	if err := viper.ReadInConfig(); err != nil {
-		log.Info("error to load config file, will use default value ", err)
+		log.Info().Err(err).Msg("error to load config file, will use default value")
 		return &defaultConfig
 	}
 
 	var finalConfig AppConfig
 	err := viper.Unmarshal(&finalConfig)
 	if err != nil {
-		log.Info("failed to extract config, will use default value")
+		log.Info().Err(err).Msg("failed to extract config, will use default value")
 		return &defaultConfig
 	}
 
+	//reservation section is optional in existing config file
+	if finalConfig.Reservation.TTL <= 0 {
+		finalConfig.Reservation.TTL = defaultConfig.Reservation.TTL
+	}
+
+	if finalConfig.Reservation.SweepInterval <= 0 {
+		finalConfig.Reservation.SweepInterval = defaultConfig.Reservation.SweepInterval
+	}
+
+	if finalConfig.Outbox.RelayInterval <= 0 {
+		finalConfig.Outbox.RelayInterval = defaultConfig.Outbox.RelayInterval
+	}
+
+	if finalConfig.Outbox.BatchSize <= 0 {
+		finalConfig.Outbox.BatchSize = defaultConfig.Outbox.BatchSize
+	}
+
+	if finalConfig.Stream.ReplaySize <= 0 {
+		finalConfig.Stream.ReplaySize = defaultConfig.Stream.ReplaySize
+	}
+
+	if finalConfig.Webhook.DeliveryInterval <= 0 {
+		finalConfig.Webhook.DeliveryInterval = defaultConfig.Webhook.DeliveryInterval
+	}
+
+	if finalConfig.Webhook.BatchSize <= 0 {
+		finalConfig.Webhook.BatchSize = defaultConfig.Webhook.BatchSize
+	}
+
+	if finalConfig.Webhook.Timeout <= 0 {
+		finalConfig.Webhook.Timeout = defaultConfig.Webhook.Timeout
+	}
+
+	if finalConfig.Webhook.MaxAttempts <= 0 {
+		finalConfig.Webhook.MaxAttempts = defaultConfig.Webhook.MaxAttempts
+	}
+
+	if finalConfig.Webhook.BackoffBase <= 0 {
+		finalConfig.Webhook.BackoffBase = defaultConfig.Webhook.BackoffBase
+	}
+
+	if finalConfig.Webhook.BackoffMax < finalConfig.Webhook.BackoffBase {
+		finalConfig.Webhook.BackoffMax = defaultConfig.Webhook.BackoffMax
+	}
+
+	if finalConfig.Tracing.Exporter == "" {
+		finalConfig.Tracing.Exporter = defaultConfig.Tracing.Exporter
+	}
+
+	if finalConfig.Tracing.Endpoint == "" {
+		finalConfig.Tracing.Endpoint = defaultConfig.Tracing.Endpoint
+	}
+
+	if finalConfig.Tracing.ServiceName == "" {
+		finalConfig.Tracing.ServiceName = defaultConfig.Tracing.ServiceName
+	}
+
+	if finalConfig.Tracing.SampleRatio <= 0 || finalConfig.Tracing.SampleRatio > 1 {
+		finalConfig.Tracing.SampleRatio = defaultConfig.Tracing.SampleRatio
+	}
+
+	if finalConfig.Log.Level == "" {
+		finalConfig.Log.Level = defaultConfig.Log.Level
+	}
+
+	if finalConfig.Log.Format == "" {
+		finalConfig.Log.Format = defaultConfig.Log.Format
+	}
+
+	if finalConfig.Health.Timeout <= 0 {
+		finalConfig.Health.Timeout = defaultConfig.Health.Timeout
+	}
+
+	if finalConfig.Shutdown.DrainPeriod <= 0 {
+		finalConfig.Shutdown.DrainPeriod = defaultConfig.Shutdown.DrainPeriod
+	}
+
+	if finalConfig.Shutdown.Timeout <= 0 {
+		finalConfig.Shutdown.Timeout = defaultConfig.Shutdown.Timeout
+	}
+
+	if finalConfig.Shutdown.TotalTimeout <= 0 {
+		finalConfig.Shutdown.TotalTimeout = defaultConfig.Shutdown.TotalTimeout
+	}
+
 	return &finalConfig
 }
@@ -6,6 +6,14 @@ require (
 	github.com/go-playground/validator/v10 v10.3.0
 	github.com/labstack/echo v3.3.10+incompatible
 	github.com/labstack/gommon v0.3.0
+	github.com/prometheus/client_golang v1.7.1
+	github.com/rs/zerolog v1.20.0
 	github.com/spf13/viper v1.7.1
 	go.mongodb.org/mongo-driver v1.4.0
+	go.opentelemetry.io/otel v0.20.0
+	go.opentelemetry.io/otel/exporters/otlp v0.20.0
+	go.opentelemetry.io/otel/exporters/stdout v0.20.0
+	go.opentelemetry.io/otel/sdk v0.20.0
+	go.opentelemetry.io/otel/trace v0.20.0
+	golang.org/x/net v0.0.0-20200822124328-c89045814202
 )
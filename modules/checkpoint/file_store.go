@@ -0,0 +1,76 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sample-order/business/item"
+	"time"
+)
+
+//FileStore The implementation of item.CheckpointStore object using JSON file
+type FileStore struct {
+	path string
+}
+
+type checkpointFile struct {
+	Source    string    `json:"source"`
+	Target    string    `json:"target"`
+	LastID    string    `json:"lastId"`
+	Copied    int       `json:"copied"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+//NewFileStore Generate checkpoint store on given file path
+func NewFileStore(path string) *FileStore {
+	return &FileStore{
+		path,
+	}
+}
+
+//Load Read checkpoint from file. Its return nil if file doesn't exist
+func (store *FileStore) Load() (*item.Checkpoint, error) {
+	data, err := ioutil.ReadFile(store.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var file checkpointFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	return &item.Checkpoint{
+		Source:    file.Source,
+		Target:    file.Target,
+		LastID:    file.LastID,
+		Copied:    file.Copied,
+		UpdatedAt: file.UpdatedAt,
+	}, nil
+}
+
+//Save Write checkpoint into temporary file first then rename it, so the file never half written
+func (store *FileStore) Save(checkpoint item.Checkpoint) error {
+	data, err := json.Marshal(checkpointFile{
+		checkpoint.Source,
+		checkpoint.Target,
+		checkpoint.LastID,
+		checkpoint.Copied,
+		checkpoint.UpdatedAt,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	tempPath := store.path + ".tmp"
+	if err = ioutil.WriteFile(tempPath, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, store.path)
+}
@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+const (
+	//StatusUp dependency or service is able to handle requests
+	StatusUp = "up"
+
+	//StatusDown dependency or service is not able to handle requests
+	StatusDown = "down"
+)
+
+//Check report error when the dependency is not usable, it must return once ctx is done
+type Check func(ctx context.Context) error
+
+//Health Liveness and readiness probes of the service. Readiness run every registered check with shared timeout
+type Health struct {
+	timeout      time.Duration
+	names        []string
+	checks       []Check
+	shuttingDown int32
+}
+
+//Report readiness payload with status of each dependency
+type Report struct {
+	Status       string                      `json:"status"`
+	ShuttingDown bool                        `json:"shuttingDown,omitempty"`
+	Checks       map[string]DependencyReport `json:"checks"`
+}
+
+//DependencyReport status of single dependency, Error is only filled when it is down
+type DependencyReport struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+//New Construct health whose readiness checks are canceled after given timeout
+func New(timeout time.Duration) *Health {
+	return &Health{timeout: timeout}
+}
+
+//AddCheck Register dependency which must be up before the service is ready. Call it before the server start
+func (health *Health) AddCheck(name string, check Check) {
+	health.names = append(health.names, name)
+	health.checks = append(health.checks, check)
+}
+
+//MarkShuttingDown Report the service as not ready from now on, so no new traffic is routed into it while shutting down
+func (health *Health) MarkShuttingDown() {
+	atomic.StoreInt32(&health.shuttingDown, 1)
+}
+
+//IsShuttingDown Tell whether MarkShuttingDown has been called
+func (health *Health) IsShuttingDown() bool {
+	return atomic.LoadInt32(&health.shuttingDown) == 1
+}
+
+//Ready Run every check concurrently and report their status. The service is up only when all of them are up
+func (health *Health) Ready(ctx context.Context) Report {
+	report := Report{
+		Status: StatusUp,
+		Checks: make(map[string]DependencyReport, len(health.checks)),
+	}
+
+	if health.IsShuttingDown() {
+		report.Status = StatusDown
+		report.ShuttingDown = true
+		return report
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, health.timeout)
+	defer cancel()
+
+	results := make([]DependencyReport, len(health.checks))
+
+	var wg sync.WaitGroup
+	for idx, check := range health.checks {
+		wg.Add(1)
+		go func(idx int, check Check) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := check(ctx)
+
+			results[idx] = DependencyReport{
+				Status:    StatusUp,
+				LatencyMs: float64(time.Since(start)) / float64(time.Millisecond),
+			}
+			if err != nil {
+				results[idx].Status = StatusDown
+				results[idx].Error = err.Error()
+			}
+		}(idx, check)
+	}
+	wg.Wait()
+
+	for idx, result := range results {
+		report.Checks[health.names[idx]] = result
+		if result.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+//LiveHandler Return echo handler which answer 200 as long as the process is able to serve HTTP, dependencies are not checked
+//so the orchestrator does not restart the service only because the database is down
+func (health *Health) LiveHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"status": StatusUp})
+	}
+}
+
+//ReadyHandler Return echo handler which answer the readiness report, with 503 when the service is not ready
+func (health *Health) ReadyHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		report := health.Ready(c.Request().Context())
+
+		if report.Status != StatusUp {
+			return c.JSON(http.StatusServiceUnavailable, report)
+		}
+
+		return c.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,122 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sample-order/modules/health"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+func TestReady(t *testing.T) {
+	t.Run("Expect ready when every dependency is up", func(t *testing.T) {
+		appHealth := health.New(time.Second)
+		appHealth.AddCheck("mongodb", func(ctx context.Context) error { return nil })
+
+		status, report := callReady(t, appHealth)
+
+		if status != http.StatusOK || report.Status != health.StatusUp {
+			t.Error("Expect service is ready, got", status, report.Status)
+		}
+
+		if report.Checks["mongodb"].Status != health.StatusUp {
+			t.Error("Expect mongodb is reported up")
+		}
+	})
+
+	t.Run("Expect unavailable with the error of the failed dependency", func(t *testing.T) {
+		appHealth := health.New(time.Second)
+		appHealth.AddCheck("mysql", func(ctx context.Context) error { return errors.New("connection refused") })
+		appHealth.AddCheck("cache", func(ctx context.Context) error { return nil })
+
+		status, report := callReady(t, appHealth)
+
+		if status != http.StatusServiceUnavailable || report.Status != health.StatusDown {
+			t.Error("Expect service is not ready, got", status, report.Status)
+		}
+
+		if report.Checks["mysql"].Status != health.StatusDown || report.Checks["mysql"].Error != "connection refused" {
+			t.Error("Expect mysql is reported down with its error, got", report.Checks["mysql"])
+		}
+
+		if report.Checks["cache"].Status != health.StatusUp {
+			t.Error("Expect cache is still reported up")
+		}
+	})
+
+	t.Run("Expect hanging dependency is canceled after the timeout", func(t *testing.T) {
+		appHealth := health.New(50 * time.Millisecond)
+		appHealth.AddCheck("mongodb", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		start := time.Now()
+		status, report := callReady(t, appHealth)
+
+		if time.Since(start) > time.Second {
+			t.Error("Expect readiness answer after the timeout")
+		}
+
+		if status != http.StatusServiceUnavailable || report.Checks["mongodb"].Status != health.StatusDown {
+			t.Error("Expect timed out dependency is reported down")
+		}
+	})
+
+	t.Run("Expect unavailable while shutting down without checking dependencies", func(t *testing.T) {
+		checked := false
+		appHealth := health.New(time.Second)
+		appHealth.AddCheck("mongodb", func(ctx context.Context) error {
+			checked = true
+			return nil
+		})
+
+		appHealth.MarkShuttingDown()
+		status, report := callReady(t, appHealth)
+
+		if status != http.StatusServiceUnavailable || !report.ShuttingDown {
+			t.Error("Expect service is not ready while shutting down, got", status)
+		}
+
+		if checked {
+			t.Error("Expect dependencies are not checked while shutting down")
+		}
+	})
+}
+
+func TestLive(t *testing.T) {
+	t.Run("Expect live even when dependency is down", func(t *testing.T) {
+		appHealth := health.New(time.Second)
+		appHealth.AddCheck("mongodb", func(ctx context.Context) error { return errors.New("down") })
+
+		e := echo.New()
+		e.GET("/health/live", appHealth.LiveHandler())
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Error("Expect live probe answer 200, got", rec.Code)
+		}
+	})
+}
+
+func callReady(t *testing.T, appHealth *health.Health) (int, health.Report) {
+	e := echo.New()
+	e.GET("/health/ready", appHealth.ReadyHandler())
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	var report health.Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatal("Expect JSON readiness report, got", rec.Body.String())
+	}
+
+	return rec.Code, report
+}
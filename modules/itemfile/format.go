@@ -0,0 +1,54 @@
+package itemfile
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+//Format item file format
+type Format string
+
+const (
+	//NDJSON newline delimited JSON, one item per line
+	NDJSON Format = "ndjson"
+	//CSV comma separated value with header row
+	CSV Format = "csv"
+	//JSON single JSON array of items
+	JSON Format = "json"
+)
+
+//ErrUnsupportedFormat Error when given format is not supported
+var ErrUnsupportedFormat = errors.New("Unsupported file format")
+
+//tagSeparator separator of multiple tags inside single CSV column
+const tagSeparator = "|"
+
+//ParseFormat Convert given name into Format
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(name)))
+
+	switch format {
+	case NDJSON, CSV, JSON:
+		return format, nil
+	}
+
+	return "", ErrUnsupportedFormat
+}
+
+//ContentType MIME type of the format
+func (format Format) ContentType() string {
+	switch format {
+	case NDJSON:
+		return "application/x-ndjson"
+	case CSV:
+		return "text/csv"
+	}
+
+	return "application/json"
+}
+
+//FormatFromFilename Detect format based on file extension
+func FormatFromFilename(filename string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(filename), "."))
+}
@@ -0,0 +1,31 @@
+package itemfile
+
+import (
+	"sample-order/business/item/spec"
+	"sample-order/business/money"
+)
+
+//moneyJSON money representation in JSON based file
+type moneyJSON struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func newMoneyJSON(price *money.Money) *moneyJSON {
+	if price == nil {
+		return nil
+	}
+
+	return &moneyJSON{price.Amount, price.Currency}
+}
+
+func (m *moneyJSON) toMoneySpec() *spec.MoneySpec {
+	if m == nil {
+		return nil
+	}
+
+	return &spec.MoneySpec{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+	}
+}
@@ -0,0 +1,179 @@
+package itemfile
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sample-order/business/item"
+	"sample-order/business/item/spec"
+	"strconv"
+	"strings"
+)
+
+//maxLineSize maximum size of single NDJSON line
+const maxLineSize = 1024 * 1024
+
+//ErrMissingColumn Error when CSV header doesn't have the required column
+var ErrMissingColumn = errors.New("CSV header must contain name, description and tags column")
+
+type ndjsonRow struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Tags        []string   `json:"tags"`
+	SalePrice   *moneyJSON `json:"salePrice"`
+	RentalRate  *moneyJSON `json:"rentalRate"`
+}
+
+//ReadRows Parse all rows from given reader. Row that cannot be parsed is returned with its error
+//so the caller can report it, while error on the whole source is returned as error
+func ReadRows(reader io.Reader, format Format) ([]item.ImportRow, error) {
+	switch format {
+	case NDJSON:
+		return readNDJSON(reader)
+	case CSV:
+		return readCSV(reader)
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+func readNDJSON(reader io.Reader) ([]item.ImportRow, error) {
+	var rows []item.ImportRow
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		var row item.ImportRow
+		row.Line = line
+
+		var parsed ndjsonRow
+		if err := json.Unmarshal(scanner.Bytes(), &parsed); err != nil {
+			row.Err = err
+		} else {
+			row.Spec.Name = parsed.Name
+			row.Spec.Description = parsed.Description
+			row.Spec.Tags = uniqueTags(parsed.Tags)
+			row.Spec.SalePrice = parsed.SalePrice.toMoneySpec()
+			row.Spec.RentalRate = parsed.RentalRate.toMoneySpec()
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, scanner.Err()
+}
+
+func readCSV(reader io.Reader) ([]item.ImportRow, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for idx, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = idx
+	}
+
+	nameIdx, hasName := columns["name"]
+	descriptionIdx, hasDescription := columns["description"]
+	tagsIdx, hasTags := columns["tags"]
+
+	if !hasName || !hasDescription || !hasTags {
+		return nil, ErrMissingColumn
+	}
+
+	var rows []item.ImportRow
+
+	for line := 2; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		} else if _, isParseError := err.(*csv.ParseError); err != nil && !isParseError {
+			return rows, err
+		}
+
+		var row item.ImportRow
+		row.Line = line
+
+		if err != nil {
+			row.Err = err
+		} else if len(record) != len(header) {
+			row.Err = csv.ErrFieldCount
+		} else {
+			row.Spec.Name = record[nameIdx]
+			row.Spec.Description = record[descriptionIdx]
+			row.Spec.Tags = splitTags(record[tagsIdx])
+			row.Spec.SalePrice, row.Err = readCSVMoney(record, columns, "sale_price")
+
+			if row.Err == nil {
+				row.Spec.RentalRate, row.Err = readCSVMoney(record, columns, "rental_rate")
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+//readCSVMoney Read optional <prefix>_amount and <prefix>_currency column, empty amount means no price
+func readCSVMoney(record []string, columns map[string]int, prefix string) (*spec.MoneySpec, error) {
+	amountIdx, hasAmount := columns[prefix+"_amount"]
+	currencyIdx, hasCurrency := columns[prefix+"_currency"]
+
+	if !hasAmount || !hasCurrency || strings.TrimSpace(record[amountIdx]) == "" {
+		return nil, nil
+	}
+
+	amount, err := strconv.ParseInt(strings.TrimSpace(record[amountIdx]), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &spec.MoneySpec{
+		Amount:   amount,
+		Currency: strings.TrimSpace(record[currencyIdx]),
+	}, nil
+}
+
+func splitTags(tags string) []string {
+	result := make([]string, 0)
+
+	for _, tag := range strings.Split(tags, tagSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+
+	return uniqueTags(result)
+}
+
+//uniqueTags remove repeated tag keeping the first occurrence, the same tag twice would violate the item tag key on MySQL
+func uniqueTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
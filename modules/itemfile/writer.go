@@ -0,0 +1,171 @@
+package itemfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sample-order/business/item"
+	"sample-order/business/money"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Writer write items one by one into underlying writer without buffering all of them
+type Writer interface {
+	Write(item item.Item) error
+
+	//Close Finish the output, it doesn't close the underlying writer
+	Close() error
+}
+
+//exportItem item representation in exported file
+type exportItem struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Tags        []string   `json:"tags"`
+	SalePrice   *moneyJSON `json:"salePrice,omitempty"`
+	RentalRate  *moneyJSON `json:"rentalRate,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CreatedBy   string     `json:"createdBy"`
+	ModifiedAt  time.Time  `json:"modifiedAt"`
+	ModifiedBy  string     `json:"modifiedBy"`
+	Version     int        `json:"version"`
+}
+
+func newExportItem(item item.Item) exportItem {
+	return exportItem{
+		item.ID,
+		item.Name,
+		item.Description,
+		item.Tags,
+		newMoneyJSON(item.SalePrice),
+		newMoneyJSON(item.RentalRate),
+		item.CreatedAt,
+		item.CreatedBy,
+		item.ModifiedAt,
+		item.ModifiedBy,
+		item.Version,
+	}
+}
+
+var csvHeader = []string{"id", "name", "description", "tags",
+	"sale_price_amount", "sale_price_currency", "rental_rate_amount", "rental_rate_currency", "created_at", "created_by", "modified_at", "modified_by", "version"}
+
+//NewWriter Create writer of given format
+func NewWriter(writer io.Writer, format Format) (Writer, error) {
+	switch format {
+	case NDJSON:
+		return &ndjsonWriter{json.NewEncoder(writer)}, nil
+	case CSV:
+		return &csvWriter{csv.NewWriter(writer), false}, nil
+	case JSON:
+		return &jsonWriter{writer, 0}, nil
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(item item.Item) error {
+	return w.encoder.Encode(newExportItem(item))
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvWriter) Write(item item.Item) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	saleAmount, saleCurrency := csvMoney(item.SalePrice)
+	rentalAmount, rentalCurrency := csvMoney(item.RentalRate)
+
+	return w.writer.Write([]string{
+		item.ID,
+		item.Name,
+		item.Description,
+		strings.Join(item.Tags, tagSeparator),
+		saleAmount,
+		saleCurrency,
+		rentalAmount,
+		rentalCurrency,
+		item.CreatedAt.Format(time.RFC3339),
+		item.CreatedBy,
+		item.ModifiedAt.Format(time.RFC3339),
+		item.ModifiedBy,
+		strconv.Itoa(item.Version),
+	})
+}
+
+func csvMoney(price *money.Money) (string, string) {
+	if price == nil {
+		return "", ""
+	}
+
+	return strconv.FormatInt(price.Amount, 10), price.Currency
+}
+
+func (w *csvWriter) Close() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+
+	w.headerWritten = true
+	return w.writer.Write(csvHeader)
+}
+
+type jsonWriter struct {
+	writer io.Writer
+	count  int
+}
+
+func (w *jsonWriter) Write(item item.Item) error {
+	prefix := ","
+	if w.count == 0 {
+		prefix = "["
+	}
+
+	if _, err := io.WriteString(w.writer, prefix); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(newExportItem(item))
+	if err != nil {
+		return err
+	}
+
+	w.count++
+	_, err = w.writer.Write(data)
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	closing := "]"
+	if w.count == 0 {
+		closing = "[]"
+	}
+
+	_, err := io.WriteString(w.writer, closing)
+	return err
+}
@@ -0,0 +1,134 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+//Hook stop single component, it must return once ctx is done even when the component is not stopped yet
+type Hook func(ctx context.Context) error
+
+//Manager Run the registered shutdown hooks one by one in the order they are registered
+type Manager struct {
+	names   []string
+	budgets []time.Duration
+	hooks   []Hook
+
+	total    time.Duration
+	deadline time.Time
+}
+
+//New Construct lifecycle manager without any hook. The whole shutdown must finish within total,
+//the stage budgets are cut to the time left. Zero total means every stage get its whole budget
+func New(total time.Duration) *Manager {
+	return &Manager{total: total}
+}
+
+//OnShutdown Register hook which is run after the hooks registered before it. The hook get its own budget,
+//so slow stage does not use up the time of the next ones. Zero budget means the hook is only bounded by the Shutdown ctx
+func (manager *Manager) OnShutdown(name string, budget time.Duration, hook Hook) {
+	manager.names = append(manager.names, name)
+	manager.budgets = append(manager.budgets, budget)
+	manager.hooks = append(manager.hooks, hook)
+}
+
+//Wait Block until one of the given signals is received and return it. The total shutdown time start from the signal,
+//as the process is killed that long after it. The signals are only caught once, so sending it again while shutting down
+//terminate the process immediately
+func (manager *Manager) Wait(signals ...os.Signal) os.Signal {
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+	defer signal.Stop(received)
+
+	sig := <-received
+	if manager.total > 0 {
+		manager.deadline = time.Now().Add(manager.total)
+	}
+
+	return sig
+}
+
+//Shutdown Run every hook even when the previous one failed and return the first error.
+//Hook which is not finished within its budget is abandoned and the next hook start with its own budget,
+//once the total deadline is passed the remaining hooks get done ctx
+func (manager *Manager) Shutdown(ctx context.Context) error {
+	deadline := manager.deadline
+	if deadline.IsZero() && manager.total > 0 {
+		deadline = time.Now().Add(manager.total)
+	}
+
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	var firstErr error
+
+	for idx, hook := range manager.hooks {
+		start := time.Now()
+		err := runHook(ctx, manager.budgets[idx], hook)
+
+		entry := log.Info()
+		if err != nil {
+			entry = log.Error().Err(err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", manager.names[idx], err)
+			}
+		}
+
+		entry.Str("stage", manager.names[idx]).Dur("duration_ms", time.Since(start)).Msg("shutdown stage finished")
+	}
+
+	return firstErr
+}
+
+func runHook(ctx context.Context, budget time.Duration, hook Hook) error {
+	if budget <= 0 {
+		return hook(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	return hook(ctx)
+}
+
+//Func Convert stop function without context into hook. When ctx is done first the hook return its error
+//and leave the function running
+func Func(stop func()) Hook {
+	return func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			stop()
+		}()
+
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+//Delay Return hook which wait for the given period, e.g. to let the load balancer notice the service is not ready anymore
+func Delay(period time.Duration) Hook {
+	return func(ctx context.Context) error {
+		timer := time.NewTimer(period)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
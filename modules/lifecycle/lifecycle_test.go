@@ -0,0 +1,145 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sample-order/modules/lifecycle"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShutdown(t *testing.T) {
+	t.Run("Expect hooks run in registration order", func(t *testing.T) {
+		var order []string
+		manager := lifecycle.New(0)
+
+		for _, name := range []string{"readiness", "server", "workers", "database"} {
+			name := name
+			manager.OnShutdown(name, 0, func(ctx context.Context) error {
+				order = append(order, name)
+				return nil
+			})
+		}
+
+		if err := manager.Shutdown(context.Background()); err != nil {
+			t.Error("Expect no error", err)
+		}
+
+		if !reflect.DeepEqual(order, []string{"readiness", "server", "workers", "database"}) {
+			t.Error("Expect hooks run in registration order, got", order)
+		}
+	})
+
+	t.Run("Expect failed hook does not stop the next ones and its error is returned", func(t *testing.T) {
+		databaseClosed := false
+		manager := lifecycle.New(0)
+		manager.OnShutdown("server", 0, func(ctx context.Context) error { return errors.New("server stuck") })
+		manager.OnShutdown("tracing", 0, func(ctx context.Context) error { return errors.New("collector down") })
+		manager.OnShutdown("database", 0, func(ctx context.Context) error {
+			databaseClosed = true
+			return nil
+		})
+
+		err := manager.Shutdown(context.Background())
+
+		if err == nil || !strings.Contains(err.Error(), "server") || !strings.Contains(err.Error(), "server stuck") {
+			t.Error("Expect the first error with its stage, got", err)
+		}
+
+		if !databaseClosed {
+			t.Error("Expect the last hook still run")
+		}
+	})
+}
+
+func TestShutdownBudget(t *testing.T) {
+	t.Run("Expect slow stage does not use up the budget of the next stage", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		workerStopped := false
+		manager := lifecycle.New(0)
+		manager.OnShutdown("server", 20*time.Millisecond, lifecycle.Func(func() { <-release }))
+		manager.OnShutdown("worker", 20*time.Millisecond, lifecycle.Func(func() { workerStopped = true }))
+
+		err := manager.Shutdown(context.Background())
+
+		if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "server") {
+			t.Error("Expect the slow stage is reported, got", err)
+		}
+
+		if !workerStopped {
+			t.Error("Expect the next stage still get the time to finish")
+		}
+	})
+
+	t.Run("Expect total deadline cut the stage budgets", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		manager := lifecycle.New(30 * time.Millisecond)
+		manager.OnShutdown("server", time.Hour, lifecycle.Func(func() { <-release }))
+		manager.OnShutdown("worker", time.Hour, lifecycle.Func(func() { <-release }))
+
+		start := time.Now()
+		err := manager.Shutdown(context.Background())
+
+		if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "server") {
+			t.Error("Expect the slow stage is reported, got", err)
+		}
+
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Error("Expect shutdown finish within the total deadline, took", elapsed)
+		}
+	})
+}
+
+func TestFunc(t *testing.T) {
+	t.Run("Expect hook return after the stop function is finished", func(t *testing.T) {
+		stopped := false
+		hook := lifecycle.Func(func() { stopped = true })
+
+		if err := hook(context.Background()); err != nil || !stopped {
+			t.Error("Expect stop function is finished without error")
+		}
+	})
+
+	t.Run("Expect hook give up when ctx is done first", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		hook := lifecycle.Func(func() { <-release })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		if err := hook(ctx); err != context.DeadlineExceeded {
+			t.Error("Expect deadline error, got", err)
+		}
+	})
+}
+
+func TestDelay(t *testing.T) {
+	t.Run("Expect hook wait for the period", func(t *testing.T) {
+		start := time.Now()
+
+		if err := lifecycle.Delay(20 * time.Millisecond)(context.Background()); err != nil {
+			t.Error("Expect no error", err)
+		}
+
+		if time.Since(start) < 20*time.Millisecond {
+			t.Error("Expect hook wait for the whole period")
+		}
+	})
+
+	t.Run("Expect hook is cut short when ctx is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err := lifecycle.Delay(time.Hour)(ctx); err != context.Canceled {
+			t.Error("Expect canceled error, got", err)
+		}
+	})
+}
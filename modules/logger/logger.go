@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+//New Construct structured logger which write the entries of given level and above into out.
+//Format json write one JSON object per line, text write colored line for human reader
+func New(level string, format string, out io.Writer) (zerolog.Logger, error) {
+	parsedLevel, err := zerolog.ParseLevel(level)
+	if err != nil || parsedLevel == zerolog.NoLevel {
+		return zerolog.Nop(), fmt.Errorf("unsupported log level %q", level)
+	}
+
+	switch format {
+	case "json":
+	case "text":
+		out = zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339}
+	default:
+		return zerolog.Nop(), fmt.Errorf("unsupported log format %q", format)
+	}
+
+	return zerolog.New(out).Level(parsedLevel).With().Timestamp().Logger(), nil
+}
@@ -0,0 +1,50 @@
+package logger_test
+
+import (
+	"bytes"
+	"sample-order/modules/logger"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Expect entries below the level are dropped", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		appLogger, err := logger.New("warn", "json", out)
+		if err != nil {
+			t.Fatal("Expect valid config is accepted", err)
+		}
+
+		appLogger.Info().Msg("hidden")
+		appLogger.Warn().Str("key", "value").Msg("shown")
+
+		if strings.Contains(out.String(), "hidden") {
+			t.Error("Expect info entry is dropped")
+		}
+
+		if !strings.Contains(out.String(), `"level":"warn","key":"value"`) {
+			t.Error("Expect warn entry is written as JSON, got", out.String())
+		}
+	})
+
+	t.Run("Expect text format is not JSON", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		appLogger, _ := logger.New("info", "text", out)
+
+		appLogger.Info().Msg("hello")
+
+		if !strings.Contains(out.String(), "hello") || strings.HasPrefix(out.String(), "{") {
+			t.Error("Expect human readable entry, got", out.String())
+		}
+	})
+
+	t.Run("Expect unknown level or format is rejected", func(t *testing.T) {
+		if _, err := logger.New("loud", "json", new(bytes.Buffer)); err == nil {
+			t.Error("Expect unknown level is rejected")
+		}
+
+		if _, err := logger.New("info", "xml", new(bytes.Buffer)); err == nil {
+			t.Error("Expect unknown format is rejected")
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//dbStatsCollector collect the connection pool stats of sql.DB on every scrape
+type dbStatsCollector struct {
+	db                *sql.DB
+	maxOpen           *prometheus.Desc
+	open              *prometheus.Desc
+	inUse             *prometheus.Desc
+	idle              *prometheus.Desc
+	waitCount         *prometheus.Desc
+	waitDuration      *prometheus.Desc
+	maxIdleClosed     *prometheus.Desc
+	maxLifetimeClosed *prometheus.Desc
+}
+
+func newDBStatsCollector(db *sql.DB) *dbStatsCollector {
+	return &dbStatsCollector{
+		db:                db,
+		maxOpen:           prometheus.NewDesc("db_max_open_connections", "Maximum number of open connections to the database.", nil, nil),
+		open:              prometheus.NewDesc("db_open_connections", "Number of established connections both in use and idle.", nil, nil),
+		inUse:             prometheus.NewDesc("db_in_use_connections", "Number of connections currently in use.", nil, nil),
+		idle:              prometheus.NewDesc("db_idle_connections", "Number of idle connections.", nil, nil),
+		waitCount:         prometheus.NewDesc("db_wait_count_total", "Total number of connections waited for.", nil, nil),
+		waitDuration:      prometheus.NewDesc("db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", nil, nil),
+		maxIdleClosed:     prometheus.NewDesc("db_max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.", nil, nil),
+		maxLifetimeClosed: prometheus.NewDesc("db_max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime.", nil, nil),
+	}
+}
+
+//Describe implement prometheus.Collector
+func (collector *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.maxOpen
+	ch <- collector.open
+	ch <- collector.inUse
+	ch <- collector.idle
+	ch <- collector.waitCount
+	ch <- collector.waitDuration
+	ch <- collector.maxIdleClosed
+	ch <- collector.maxLifetimeClosed
+}
+
+//Collect implement prometheus.Collector
+func (collector *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := collector.db.Stats()
+
+	ch <- prometheus.MustNewConstMetric(collector.maxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(collector.open, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(collector.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(collector.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(collector.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(collector.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(collector.maxIdleClosed, prometheus.CounterValue, float64(stats.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(collector.maxLifetimeClosed, prometheus.CounterValue, float64(stats.MaxLifetimeClosed))
+}
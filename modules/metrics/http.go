@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"sample-order/util"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+//unmatchedRoute route label of request which does not match any registered path, so unknown URL does not create new series
+const unmatchedRoute = "unmatched"
+
+//Middleware Return echo middleware which count and time every request by its route template instead of the URL
+func (metrics *Metrics) Middleware() echo.MiddlewareFunc {
+	matcher := util.NewRouteMatcher()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+
+			route := matcher.Route(c)
+			if route == "" {
+				route = unmatchedRoute
+			}
+
+			method := c.Request().Method
+			status := strconv.Itoa(util.ResponseStatus(c, err))
+
+			metrics.requests.WithLabelValues(method, route, status).Inc()
+			metrics.requestDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/labstack/echo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+//Metrics Prometheus collectors of the service, they are exposed by the handler in text format
+type Metrics struct {
+	registry           *prometheus.Registry
+	requests           *prometheus.CounterVec
+	requestDuration    *prometheus.HistogramVec
+	repositoryDuration *prometheus.HistogramVec
+	repositoryErrors   *prometheus.CounterVec
+}
+
+//New Construct metrics with HTTP, repository, Go runtime and process collectors registered
+func New() *Metrics {
+	metrics := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of handled HTTP requests by route and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Latency of handled HTTP requests by route and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "repository_operation_duration_seconds",
+			Help:    "Latency of repository operations by driver and method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"repository", "driver", "method"}),
+		repositoryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "repository_operation_errors_total",
+			Help: "Number of failed repository operations by driver and method.",
+		}, []string{"repository", "driver", "method"}),
+	}
+
+	metrics.registry.MustRegister(
+		metrics.requests,
+		metrics.requestDuration,
+		metrics.repositoryDuration,
+		metrics.repositoryErrors,
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+
+	return metrics
+}
+
+//RegisterSQLDB Expose connection pool stats of the database
+func (metrics *Metrics) RegisterSQLDB(db *sql.DB) {
+	metrics.registry.MustRegister(newDBStatsCollector(db))
+}
+
+//Handler Return echo handler which expose the metrics in Prometheus text format
+func (metrics *Metrics) Handler() echo.HandlerFunc {
+	return echo.WrapHandler(promhttp.HandlerFor(metrics.registry, promhttp.HandlerOpts{}))
+}
@@ -0,0 +1,113 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sample-order/business"
+	"sample-order/business/item"
+	"sample-order/modules/metrics"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Run("Expect requests are counted by route template and status", func(t *testing.T) {
+		appMetrics := metrics.New()
+
+		e := echo.New()
+		e.Use(appMetrics.Middleware())
+		e.GET("/metrics", appMetrics.Handler())
+		e.GET("/items/:id", func(c echo.Context) error {
+			if c.Param("id") == "missing" {
+				return echo.NewHTTPError(http.StatusNotFound)
+			}
+
+			return c.NoContent(http.StatusOK)
+		})
+
+		for _, path := range []string{"/items/1", "/items/2", "/items/missing", "/unknown"} {
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+
+		body := scrape(t, e)
+
+		for _, expected := range []string{
+			`http_requests_total{method="GET",route="/items/:id",status="200"} 2`,
+			`http_requests_total{method="GET",route="/items/:id",status="404"} 1`,
+			`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+			`http_request_duration_seconds_count{method="GET",route="/items/:id",status="200"} 2`,
+			`go_goroutines`,
+		} {
+			if !strings.Contains(body, expected) {
+				t.Error("Expect metrics contain", expected)
+			}
+		}
+
+		if strings.Contains(body, `route="/unknown"`) {
+			t.Error("Expect unknown URL is not used as route label")
+		}
+	})
+}
+
+func TestInstrumentItemRepository(t *testing.T) {
+	t.Run("Expect operations are timed and failures are counted", func(t *testing.T) {
+		appMetrics := metrics.New()
+		repository := appMetrics.InstrumentItemRepository(&failingRepository{}, "mysql")
+		ctx := context.Background()
+
+		repository.FindItemByID(ctx, "5f350b7d21148431abc65290")
+		repository.FindItemsAfterID(ctx, "", 10)
+		repository.UpdateItem(ctx, item.Item{}, 1, item.Event{})
+
+		e := echo.New()
+		e.GET("/metrics", appMetrics.Handler())
+		body := scrape(t, e)
+
+		for _, expected := range []string{
+			`repository_operation_duration_seconds_count{driver="mysql",method="FindItemByID",repository="item"} 1`,
+			`repository_operation_errors_total{driver="mysql",method="FindItemsAfterID",repository="item"} 1`,
+		} {
+			if !strings.Contains(body, expected) {
+				t.Error("Expect metrics contain", expected)
+			}
+		}
+
+		if strings.Contains(body, `repository_operation_errors_total{driver="mysql",method="UpdateItem"`) {
+			t.Error("Expect version conflict is not counted as failure")
+		}
+	})
+}
+
+func scrape(t *testing.T, e *echo.Echo) string {
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, _ := ioutil.ReadAll(recorder.Body)
+	if recorder.Code != http.StatusOK {
+		t.Error("Expect metrics are exposed", recorder.Code)
+	}
+
+	return string(body)
+}
+
+//failingRepository item repository which find nothing, fail to list and always lose the version check
+type failingRepository struct {
+	item.Repository
+}
+
+func (repo *failingRepository) FindItemByID(ctx context.Context, ID string) (*item.Item, error) {
+	return nil, nil
+}
+
+func (repo *failingRepository) FindItemsAfterID(ctx context.Context, afterID string, limit int) ([]item.Item, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (repo *failingRepository) UpdateItem(ctx context.Context, item item.Item, currentVersion int, event item.Event) error {
+	return business.ErrZeroAffected
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"context"
+	"sample-order/business"
+	"sample-order/business/item"
+	"time"
+)
+
+//itemRepository item.Repository decorator which time every operation of the wrapped repository
+type itemRepository struct {
+	next    item.Repository
+	driver  string
+	metrics *Metrics
+}
+
+//InstrumentItemRepository Wrap the item repository so its operations latency and errors are recorded with the driver name
+func (metrics *Metrics) InstrumentItemRepository(repository item.Repository, driver string) item.Repository {
+	return &itemRepository{
+		repository,
+		driver,
+		metrics,
+	}
+}
+
+//observe record the operation started at given time. Version conflict is expected result, not a failure
+func (repo *itemRepository) observe(method string, start time.Time, err error) {
+	repo.metrics.repositoryDuration.WithLabelValues("item", repo.driver, method).Observe(time.Since(start).Seconds())
+
+	if err != nil && err != business.ErrZeroAffected {
+		repo.metrics.repositoryErrors.WithLabelValues("item", repo.driver, method).Inc()
+	}
+}
+
+//FindItemByID implement item.Repository
+func (repo *itemRepository) FindItemByID(ctx context.Context, ID string) (*item.Item, error) {
+	start := time.Now()
+	found, err := repo.next.FindItemByID(ctx, ID)
+	repo.observe("FindItemByID", start, err)
+
+	return found, err
+}
+
+//FindAllByTag implement item.Repository
+func (repo *itemRepository) FindAllByTag(ctx context.Context, tag string, priceRange item.PriceRange) ([]item.Item, error) {
+	start := time.Now()
+	items, err := repo.next.FindAllByTag(ctx, tag, priceRange)
+	repo.observe("FindAllByTag", start, err)
+
+	return items, err
+}
+
+//InsertItem implement item.Repository
+func (repo *itemRepository) InsertItem(ctx context.Context, item item.Item, event item.Event) error {
+	start := time.Now()
+	err := repo.next.InsertItem(ctx, item, event)
+	repo.observe("InsertItem", start, err)
+
+	return err
+}
+
+//UpdateItem implement item.Repository
+func (repo *itemRepository) UpdateItem(ctx context.Context, item item.Item, currentVersion int, event item.Event) error {
+	start := time.Now()
+	err := repo.next.UpdateItem(ctx, item, currentVersion, event)
+	repo.observe("UpdateItem", start, err)
+
+	return err
+}
+
+//DeleteItem implement item.Repository
+func (repo *itemRepository) DeleteItem(ctx context.Context, ID string, currentVersion int, event item.Event) error {
+	start := time.Now()
+	err := repo.next.DeleteItem(ctx, ID, currentVersion, event)
+	repo.observe("DeleteItem", start, err)
+
+	return err
+}
+
+//InsertItems implement item.Repository
+func (repo *itemRepository) InsertItems(ctx context.Context, items []item.Item, events []item.Event) error {
+	start := time.Now()
+	err := repo.next.InsertItems(ctx, items, events)
+	repo.observe("InsertItems", start, err)
+
+	return err
+}
+
+//StreamItems implement item.Repository, the latency include the time spent by fn
+func (repo *itemRepository) StreamItems(ctx context.Context, tag string, fn func(item item.Item) error) error {
+	start := time.Now()
+	err := repo.next.StreamItems(ctx, tag, fn)
+	repo.observe("StreamItems", start, err)
+
+	return err
+}
+
+//FindItemsAfterID implement item.Repository
+func (repo *itemRepository) FindItemsAfterID(ctx context.Context, afterID string, limit int) ([]item.Item, error) {
+	start := time.Now()
+	items, err := repo.next.FindItemsAfterID(ctx, afterID, limit)
+	repo.observe("FindItemsAfterID", start, err)
+
+	return items, err
+}
+
+//FindChangesAfter implement item.Repository
+func (repo *itemRepository) FindChangesAfter(ctx context.Context, token item.ChangeToken, limit int) ([]item.Change, error) {
+	start := time.Now()
+	changes, err := repo.next.FindChangesAfter(ctx, token, limit)
+	repo.observe("FindChangesAfter", start, err)
+
+	return changes, err
+}
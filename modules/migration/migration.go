@@ -0,0 +1,50 @@
+package migration
+
+import (
+	"errors"
+	"sample-order/util"
+	"time"
+)
+
+//ErrUnknownMigration Error when database has applied migration that not known by this binary
+var ErrUnknownMigration = errors.New("Database has migration that is not known by this binary")
+
+//ErrMigrationLocked Error when other process keep running the migration longer than lockTimeout
+var ErrMigrationLocked = errors.New("Other process is running the migration")
+
+//lockTimeout how long Up and Down wait for the migration running in other process,
+//e.g. several server instances started with auto migrate at the same time
+const lockTimeout = time.Minute
+
+//Status migration state on the active database
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+//Migrator run versioned schema migration on specific database driver
+type Migrator interface {
+	//Up Apply all pending migrations in version order under the migration lock. Return list of applied migration
+	Up() ([]Status, error)
+
+	//Down Revert the latest n applied migrations under the migration lock. Return list of reverted migration
+	Down(steps int) ([]Status, error)
+
+	//Status Return all known migrations including whether it was applied or not
+	Status() ([]Status, error)
+}
+
+//MigratorFactory Will return Migrator based on active database connection
+func MigratorFactory(dbCon *util.DatabaseConnection) Migrator {
+	var migrator Migrator
+
+	if dbCon.Driver == util.MySQL {
+		migrator = NewMySQLMigrator(dbCon.MySQLDB)
+	} else if dbCon.Driver == util.MongoDB {
+		migrator = NewMongoDBMigrator(dbCon.MongoDB)
+	}
+
+	return migrator
+}
@@ -0,0 +1,578 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"sample-order/business/warehouse"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoMigration struct {
+	version int
+	name    string
+	up      func(db *mongo.Database) error
+	down    func(db *mongo.Database) error
+}
+
+//mongoMigrations list of MongoDB schema changes, always append new migration at the end with next version
+var mongoMigrations = []mongoMigration{
+	{
+		version: 1,
+		name:    "create_items_tags_index",
+		up:      createIndex("items", "tags_1", bson.D{{Key: "tags", Value: 1}}),
+		down:    dropIndex("items", bson.D{{Key: "tags", Value: 1}}),
+	},
+	{
+		version: 2,
+		name:    "create_items_modified_at_index",
+		up:      createIndex("items", "modified_at_1__id_1", bson.D{{Key: "modified_at", Value: 1}, {Key: "_id", Value: 1}}),
+		down:    dropIndex("items", bson.D{{Key: "modified_at", Value: 1}, {Key: "_id", Value: 1}}),
+	},
+	{
+		version: 3,
+		name:    "create_items_sale_price_index",
+		up:      createIndex("items", "sale_price", bson.D{{Key: "sale_price.currency", Value: 1}, {Key: "sale_price.amount", Value: 1}}),
+		down:    dropIndex("items", bson.D{{Key: "sale_price.currency", Value: 1}, {Key: "sale_price.amount", Value: 1}}),
+	},
+	{
+		version: 4,
+		name:    "create_stock_movements_item_index",
+		up:      createIndex("stock_movements", "item_created_at", bson.D{{Key: "item_id", Value: 1}, {Key: "created_at", Value: -1}}),
+		down:    dropIndex("stock_movements", bson.D{{Key: "item_id", Value: 1}, {Key: "created_at", Value: -1}}),
+	},
+	{
+		version: 5,
+		name:    "move_stocks_into_main_warehouse",
+		up:      moveStocksIntoMainWarehouse,
+		down:    moveStocksOutOfWarehouse,
+	},
+	{
+		version: 6,
+		name:    "create_bookings_item_period_index",
+		up:      createIndex("bookings", "item_period", bson.D{{Key: "item_id", Value: 1}, {Key: "start_date", Value: 1}, {Key: "end_date", Value: 1}}),
+		down:    dropIndex("bookings", bson.D{{Key: "item_id", Value: 1}, {Key: "start_date", Value: 1}, {Key: "end_date", Value: 1}}),
+	},
+	{
+		version: 7,
+		name:    "create_orders_customer_index",
+		up:      createIndex("orders", "customer_created_at", bson.D{{Key: "customer", Value: 1}, {Key: "created_at", Value: -1}}),
+		down:    dropIndex("orders", bson.D{{Key: "customer", Value: 1}, {Key: "created_at", Value: -1}}),
+	},
+	{
+		version: 8,
+		name:    "create_stock_reservations_expiry_index",
+		up:      createIndex("stock_reservations", "status_expires_at", bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}),
+		down:    dropIndex("stock_reservations", bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}),
+	},
+	{
+		version: 9,
+		name:    "create_stock_reservations_reference_index",
+		up:      createIndex("stock_reservations", "reference", bson.D{{Key: "reference", Value: 1}}),
+		down:    dropIndex("stock_reservations", bson.D{{Key: "reference", Value: 1}}),
+	},
+	{
+		version: 10,
+		name:    "create_promotions_code_index",
+		up:      createUniqueIndex("promotions", "code", bson.D{{Key: "code", Value: 1}}),
+		down:    dropIndex("promotions", bson.D{{Key: "code", Value: 1}}),
+	},
+	{
+		version: 11,
+		name:    "create_promotion_redemptions_user_index",
+		up:      createIndex("promotion_redemptions", "promotion_user", bson.D{{Key: "promotion_id", Value: 1}, {Key: "user", Value: 1}}),
+		down:    dropIndex("promotion_redemptions", bson.D{{Key: "promotion_id", Value: 1}, {Key: "user", Value: 1}}),
+	},
+	{
+		version: 12,
+		name:    "set_orders_subtotal",
+		up:      setOrdersSubtotal,
+		down:    unsetOrdersSubtotal,
+	},
+	{
+		version: 13,
+		name:    "create_outbox_pending_index",
+		up:      createIndex("outbox", "published_at_occurred_at", bson.D{{Key: "published_at", Value: 1}, {Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}),
+		down:    dropIndex("outbox", bson.D{{Key: "published_at", Value: 1}, {Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}),
+	},
+	{
+		version: 14,
+		name:    "create_webhook_deliveries_message_index",
+		up:      createUniqueIndex("webhook_deliveries", "subscription_message", bson.D{{Key: "subscription_id", Value: 1}, {Key: "message_id", Value: 1}}),
+		down:    dropIndex("webhook_deliveries", bson.D{{Key: "subscription_id", Value: 1}, {Key: "message_id", Value: 1}}),
+	},
+	{
+		version: 15,
+		name:    "create_webhook_deliveries_due_index",
+		up:      createIndex("webhook_deliveries", "status_next_attempt_at", bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}),
+		down:    dropIndex("webhook_deliveries", bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}),
+	},
+	{
+		version: 16,
+		name:    "create_webhook_deliveries_status_index",
+		up:      createIndex("webhook_deliveries", "status_created_at", bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}),
+		down:    dropIndex("webhook_deliveries", bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}),
+	},
+	{
+		version: 17,
+		name:    "create_item_tombstones_modified_at_index",
+		up:      createIndex("item_tombstones", "modified_at_id", bson.D{{Key: "modified_at", Value: 1}, {Key: "_id", Value: 1}}),
+		down:    dropIndex("item_tombstones", bson.D{{Key: "modified_at", Value: 1}, {Key: "_id", Value: 1}}),
+	},
+}
+
+//duplicateKeyCode MongoDB error code of unique index violation
+const duplicateKeyCode = 11000
+
+//lockStaleAfter lock document older than this is left by crashed process and can be taken over
+const lockStaleAfter = 30 * time.Minute
+
+type migrationCollection struct {
+	Version   int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+//MongoDBMigrator The implementation of Migrator object for MongoDB
+type MongoDBMigrator struct {
+	db      *mongo.Database
+	col     *mongo.Collection
+	lockCol *mongo.Collection
+}
+
+//NewMongoDBMigrator Generate mongo DB migrator
+func NewMongoDBMigrator(db *mongo.Database) *MongoDBMigrator {
+	return &MongoDBMigrator{
+		db,
+		db.Collection("schema_migrations"),
+		db.Collection("schema_migration_lock"),
+	}
+}
+
+//Up Apply all pending migrations
+func (m *MongoDBMigrator) Up() ([]Status, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	//read after the lock is taken, so the migrations applied by the previous holder are skipped
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Status
+
+	for _, migration := range mongoMigrations {
+		if _, ok := applied[migration.version]; ok {
+			continue
+		}
+
+		if err := migration.up(m.db); err != nil {
+			return result, err
+		}
+
+		col := migrationCollection{migration.version, migration.name, time.Now()}
+		if _, err := m.col.InsertOne(context.TODO(), col); err != nil {
+			return result, err
+		}
+
+		result = append(result, Status{col.Version, col.Name, true, col.AppliedAt})
+	}
+
+	return result, nil
+}
+
+//Down Revert the latest applied migrations
+func (m *MongoDBMigrator) Down(steps int) ([]Status, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Status
+
+	for i := len(mongoMigrations) - 1; i >= 0 && len(result) < steps; i-- {
+		migration := mongoMigrations[i]
+
+		if _, ok := applied[migration.version]; !ok {
+			continue
+		}
+
+		if err := migration.down(m.db); err != nil {
+			return result, err
+		}
+
+		if _, err := m.col.DeleteOne(context.TODO(), bson.M{"_id": migration.version}); err != nil {
+			return result, err
+		}
+
+		result = append(result, Status{migration.version, migration.name, false, time.Time{}})
+	}
+
+	return result, nil
+}
+
+//Status Return all known migrations and its state
+func (m *MongoDBMigrator) Status() ([]Status, error) {
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Status
+
+	for _, migration := range mongoMigrations {
+		appliedAt, ok := applied[migration.version]
+		result = append(result, Status{migration.version, migration.name, ok, appliedAt})
+		delete(applied, migration.version)
+	}
+
+	if len(applied) > 0 {
+		return result, ErrUnknownMigration
+	}
+
+	return result, nil
+}
+
+//lock insert the lock document or take over the stale one. While other process hold it, the upsert try to insert
+//the same ID and fail on duplicate key, so it is retried until lockTimeout
+func (m *MongoDBMigrator) lock() (func(), error) {
+	owner := primitive.NewObjectID()
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		now := time.Now()
+		_, err := m.lockCol.UpdateOne(context.TODO(),
+			bson.M{"_id": "schema_migration", "locked_at": bson.M{"$lt": now.Add(-lockStaleAfter)}},
+			bson.M{"$set": bson.M{"owner": owner, "locked_at": now}},
+			options.Update().SetUpsert(true))
+
+		if err == nil {
+			break
+		}
+
+		if !isDuplicateKey(err) {
+			return nil, err
+		}
+
+		if now.After(deadline) {
+			return nil, ErrMigrationLocked
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	return func() {
+		m.lockCol.DeleteOne(context.TODO(), bson.M{"_id": "schema_migration", "owner": owner})
+	}, nil
+}
+
+func isDuplicateKey(err error) bool {
+	var writeErr mongo.WriteException
+	if !errors.As(err, &writeErr) {
+		return false
+	}
+
+	for _, e := range writeErr.WriteErrors {
+		if e.Code == duplicateKeyCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *MongoDBMigrator) appliedMigrations() (map[int]time.Time, error) {
+	cursor, err := m.col.Find(context.TODO(), bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	applied := make(map[int]time.Time)
+
+	for cursor.Next(context.TODO()) {
+		var col migrationCollection
+		if err = cursor.Decode(&col); err != nil {
+			return nil, err
+		}
+
+		applied[col.Version] = col.AppliedAt
+	}
+
+	return applied, cursor.Err()
+}
+
+//moveStocksIntoMainWarehouse create the main warehouse and convert stock keyed by item into stock keyed by item and warehouse
+func moveStocksIntoMainWarehouse(db *mongo.Database) error {
+	mainWarehouseID, _ := primitive.ObjectIDFromHex(warehouse.DefaultWarehouseID)
+
+	_, err := db.Collection("warehouses").UpdateOne(context.TODO(),
+		bson.M{"_id": mainWarehouseID},
+		bson.M{"$setOnInsert": bson.M{
+			"name":       "Main warehouse",
+			"address":    "",
+			"created_at": time.Now(),
+			"created_by": "migration",
+		}},
+		options.Update().SetUpsert(true))
+
+	if err != nil {
+		return err
+	}
+
+	stocks := db.Collection("stocks")
+	cursor, err := stocks.Find(context.TODO(), bson.M{"item_id": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var old bson.M
+		if err = cursor.Decode(&old); err != nil {
+			return err
+		}
+
+		_, err = stocks.InsertOne(context.TODO(), bson.M{
+			"_id":          primitive.NewObjectID(),
+			"item_id":      old["_id"],
+			"warehouse_id": mainWarehouseID,
+			"quantity":     old["quantity"],
+			"modified_at":  old["modified_at"],
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if _, err = stocks.DeleteOne(context.TODO(), bson.M{"_id": old["_id"]}); err != nil {
+			return err
+		}
+	}
+
+	if err = cursor.Err(); err != nil {
+		return err
+	}
+
+	_, err = db.Collection("stock_movements").UpdateMany(context.TODO(),
+		bson.M{"warehouse_id": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"warehouse_id": mainWarehouseID}})
+
+	if err != nil {
+		return err
+	}
+
+	return createUniqueIndex("stocks", "item_warehouse", bson.D{{Key: "item_id", Value: 1}, {Key: "warehouse_id", Value: 1}})(db)
+}
+
+//moveStocksOutOfWarehouse revert stock into keyed by item, only the stock of the main warehouse is kept
+func moveStocksOutOfWarehouse(db *mongo.Database) error {
+	mainWarehouseID, _ := primitive.ObjectIDFromHex(warehouse.DefaultWarehouseID)
+
+	if err := dropIndex("stocks", bson.D{{Key: "item_id", Value: 1}, {Key: "warehouse_id", Value: 1}})(db); err != nil {
+		return err
+	}
+
+	stocks := db.Collection("stocks")
+	if _, err := stocks.DeleteMany(context.TODO(), bson.M{"warehouse_id": bson.M{"$ne": mainWarehouseID}}); err != nil {
+		return err
+	}
+
+	cursor, err := stocks.Find(context.TODO(), bson.M{"item_id": bson.M{"$exists": true}})
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var current bson.M
+		if err = cursor.Decode(&current); err != nil {
+			return err
+		}
+
+		if _, err = stocks.DeleteOne(context.TODO(), bson.M{"_id": current["_id"]}); err != nil {
+			return err
+		}
+
+		_, err = stocks.InsertOne(context.TODO(), bson.M{
+			"_id":         current["item_id"],
+			"quantity":    current["quantity"],
+			"modified_at": current["modified_at"],
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = cursor.Err(); err != nil {
+		return err
+	}
+
+	_, err = db.Collection("stock_movements").UpdateMany(context.TODO(), bson.M{}, bson.M{"$unset": bson.M{"warehouse_id": ""}})
+	if err != nil {
+		return err
+	}
+
+	return db.Collection("warehouses").Drop(context.TODO())
+}
+
+//setOrdersSubtotal fill the subtotal of orders created before promotion, they have no discount
+func setOrdersSubtotal(db *mongo.Database) error {
+	cursor, err := db.Collection("orders").Find(context.TODO(), bson.M{"subtotal": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var current bson.M
+		if err = cursor.Decode(&current); err != nil {
+			return err
+		}
+
+		_, err = db.Collection("orders").UpdateOne(context.TODO(),
+			bson.M{"_id": current["_id"]},
+			bson.M{"$set": bson.M{"subtotal": current["total"], "discount": int64(0), "promotion_code": ""}})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+//unsetOrdersSubtotal remove the promotion fields of orders
+func unsetOrdersSubtotal(db *mongo.Database) error {
+	_, err := db.Collection("orders").UpdateMany(context.TODO(), bson.M{},
+		bson.M{"$unset": bson.M{"subtotal": "", "discount": "", "promotion_code": ""}})
+
+	return err
+}
+
+//createIndex the migration is treated as applied when index with the same keys already exists under any name,
+//e.g. the items indexes created by hand before the migrations are introduced
+func createIndex(collection string, name string, keys bson.D) func(db *mongo.Database) error {
+	return func(db *mongo.Database) error {
+		col := db.Collection(collection)
+
+		existing, err := findIndexName(col, keys)
+		if err != nil || existing != "" {
+			return err
+		}
+
+		index := mongo.IndexModel{
+			Keys:    keys,
+			Options: options.Index().SetName(name),
+		}
+
+		_, err = col.Indexes().CreateOne(context.TODO(), index)
+		return err
+	}
+}
+
+func createUniqueIndex(collection string, name string, keys bson.D) func(db *mongo.Database) error {
+	return func(db *mongo.Database) error {
+		index := mongo.IndexModel{
+			Keys:    keys,
+			Options: options.Index().SetName(name).SetUnique(true),
+		}
+
+		_, err := db.Collection(collection).Indexes().CreateOne(context.TODO(), index)
+		return err
+	}
+}
+
+//findIndexName Return the name of index with exactly the given keys in the same order and direction,
+//empty when the collection has no such index
+func findIndexName(col *mongo.Collection, keys bson.D) (string, error) {
+	cursor, err := col.Indexes().List(context.TODO())
+	if err != nil {
+		return "", err
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var index struct {
+			Name string `bson:"name"`
+			Key  bson.D `bson:"key"`
+		}
+
+		if err := cursor.Decode(&index); err != nil {
+			return "", err
+		}
+
+		if sameIndexKeys(index.Key, keys) {
+			return index.Name, nil
+		}
+	}
+
+	return "", cursor.Err()
+}
+
+//sameIndexKeys compare the direction as number, the shell store it as double while the driver store it as integer
+func sameIndexKeys(existing bson.D, keys bson.D) bool {
+	if len(existing) != len(keys) {
+		return false
+	}
+
+	for i := range keys {
+		if existing[i].Key != keys[i].Key || indexDirection(existing[i].Value) != indexDirection(keys[i].Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func indexDirection(value interface{}) float64 {
+	switch direction := value.(type) {
+	case int:
+		return float64(direction)
+	case int32:
+		return float64(direction)
+	case int64:
+		return float64(direction)
+	case float64:
+		return direction
+	}
+
+	return 0
+}
+
+//dropIndex the index is found by its keys, so index adopted by createIndex under other name is dropped too.
+//Missing index is treated as already dropped
+func dropIndex(collection string, keys bson.D) func(db *mongo.Database) error {
+	return func(db *mongo.Database) error {
+		col := db.Collection(collection)
+
+		name, err := findIndexName(col, keys)
+		if err != nil || name == "" {
+			return err
+		}
+
+		_, err = col.Indexes().DropOne(context.TODO(), name)
+		return err
+	}
+}
@@ -0,0 +1,685 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"sample-order/business/warehouse"
+	"time"
+)
+
+type mysqlMigration struct {
+	version int
+	name    string
+	up      []string
+	down    []string
+}
+
+//mysqlMigrations list of MySQL schema changes, always append new migration at the end with next version
+var mysqlMigrations = []mysqlMigration{
+	{
+		version: 1,
+		name:    "create_item_table",
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS item (
+				id varchar(24) NOT NULL DEFAULT '',
+				name text NOT NULL,
+				description text NOT NULL,
+				created_at datetime NOT NULL,
+				created_by varchar(50) NOT NULL DEFAULT '',
+				modified_at datetime NOT NULL,
+				modified_by varchar(50) NOT NULL DEFAULT '',
+				version int(11) NOT NULL DEFAULT '1',
+				PRIMARY KEY (id),
+				KEY modified_at (modified_at, id)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS item",
+		},
+	},
+	{
+		version: 2,
+		name:    "create_item_tag_table",
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS item_tag (
+				item_id varchar(24) NOT NULL DEFAULT '',
+				tag varchar(50) NOT NULL DEFAULT '',
+				PRIMARY KEY (item_id, tag),
+				KEY tag (tag),
+				CONSTRAINT item_tag_ibfk_1 FOREIGN KEY (item_id) REFERENCES item (id) ON DELETE CASCADE ON UPDATE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS item_tag",
+		},
+	},
+	{
+		version: 3,
+		name:    "add_item_price_columns",
+		up: []string{
+			`ALTER TABLE item
+				ADD COLUMN sale_price_amount bigint(20) NULL AFTER description,
+				ADD COLUMN sale_price_currency char(3) NULL AFTER sale_price_amount,
+				ADD COLUMN rental_rate_amount bigint(20) NULL AFTER sale_price_currency,
+				ADD COLUMN rental_rate_currency char(3) NULL AFTER rental_rate_amount,
+				ADD KEY sale_price (sale_price_currency, sale_price_amount)`,
+		},
+		down: []string{
+			`ALTER TABLE item
+				DROP KEY sale_price,
+				DROP COLUMN sale_price_amount,
+				DROP COLUMN sale_price_currency,
+				DROP COLUMN rental_rate_amount,
+				DROP COLUMN rental_rate_currency`,
+		},
+	},
+	{
+		version: 4,
+		name:    "create_stock_tables",
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS item_stock (
+				item_id varchar(24) NOT NULL DEFAULT '',
+				quantity int(11) NOT NULL DEFAULT '0',
+				modified_at datetime NOT NULL,
+				PRIMARY KEY (item_id),
+				CONSTRAINT item_stock_ibfk_1 FOREIGN KEY (item_id) REFERENCES item (id) ON DELETE CASCADE ON UPDATE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS stock_movement (
+				id varchar(24) NOT NULL DEFAULT '',
+				item_id varchar(24) NOT NULL DEFAULT '',
+				type varchar(20) NOT NULL DEFAULT '',
+				quantity int(11) NOT NULL,
+				reason text NOT NULL,
+				actor varchar(50) NOT NULL DEFAULT '',
+				created_at datetime NOT NULL,
+				PRIMARY KEY (id),
+				KEY item_created_at (item_id, created_at),
+				CONSTRAINT stock_movement_ibfk_1 FOREIGN KEY (item_id) REFERENCES item (id) ON DELETE CASCADE ON UPDATE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS stock_movement",
+			"DROP TABLE IF EXISTS item_stock",
+		},
+	},
+	{
+		version: 5,
+		name:    "create_warehouse_table",
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS warehouse (
+				id varchar(24) NOT NULL DEFAULT '',
+				name varchar(100) NOT NULL DEFAULT '',
+				address text NOT NULL,
+				created_at datetime NOT NULL,
+				created_by varchar(50) NOT NULL DEFAULT '',
+				PRIMARY KEY (id)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`INSERT INTO warehouse (id, name, address, created_at, created_by)
+				VALUES ('` + warehouse.DefaultWarehouseID + `', 'Main warehouse', '', NOW(), 'migration')`,
+			`ALTER TABLE item_stock
+				ADD COLUMN warehouse_id varchar(24) NOT NULL DEFAULT '` + warehouse.DefaultWarehouseID + `' AFTER item_id,
+				DROP PRIMARY KEY,
+				ADD PRIMARY KEY (item_id, warehouse_id),
+				ADD CONSTRAINT item_stock_ibfk_2 FOREIGN KEY (warehouse_id) REFERENCES warehouse (id) ON UPDATE CASCADE`,
+			`ALTER TABLE stock_movement
+				ADD COLUMN warehouse_id varchar(24) NOT NULL DEFAULT '` + warehouse.DefaultWarehouseID + `' AFTER item_id,
+				ADD CONSTRAINT stock_movement_ibfk_2 FOREIGN KEY (warehouse_id) REFERENCES warehouse (id) ON UPDATE CASCADE`,
+		},
+		//revert keep only the stock of the main warehouse
+		down: []string{
+			`ALTER TABLE stock_movement
+				DROP FOREIGN KEY stock_movement_ibfk_2,
+				DROP COLUMN warehouse_id`,
+			"DELETE FROM item_stock WHERE warehouse_id <> '" + warehouse.DefaultWarehouseID + "'",
+			`ALTER TABLE item_stock
+				DROP FOREIGN KEY item_stock_ibfk_2,
+				DROP PRIMARY KEY,
+				ADD PRIMARY KEY (item_id),
+				DROP COLUMN warehouse_id`,
+			"DROP TABLE IF EXISTS warehouse",
+		},
+	},
+	{
+		version: 6,
+		name:    "create_booking_table",
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS booking (
+				id varchar(24) NOT NULL DEFAULT '',
+				item_id varchar(24) NOT NULL DEFAULT '',
+				quantity int(11) NOT NULL,
+				start_date date NOT NULL,
+				end_date date NOT NULL,
+				customer varchar(100) NOT NULL DEFAULT '',
+				status varchar(20) NOT NULL DEFAULT '',
+				created_at datetime NOT NULL,
+				created_by varchar(50) NOT NULL DEFAULT '',
+				modified_at datetime NOT NULL,
+				modified_by varchar(50) NOT NULL DEFAULT '',
+				version int(11) NOT NULL DEFAULT '1',
+				PRIMARY KEY (id),
+				KEY item_period (item_id, start_date, end_date),
+				CONSTRAINT booking_ibfk_1 FOREIGN KEY (item_id) REFERENCES item (id) ON DELETE CASCADE ON UPDATE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS booking",
+		},
+	},
+	{
+		version: 7,
+		name:    "create_rental_rule_table",
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS rental_rule (
+				item_id varchar(24) NOT NULL DEFAULT '',
+				currency char(3) NOT NULL DEFAULT '',
+				daily_rate_amount bigint(20) NOT NULL,
+				weekly_rate_amount bigint(20) NULL,
+				min_days int(11) NOT NULL DEFAULT '1',
+				weekend_surcharge_percent int(11) NOT NULL DEFAULT '0',
+				deposit_amount bigint(20) NULL,
+				late_fee_amount bigint(20) NULL,
+				modified_at datetime NOT NULL,
+				modified_by varchar(50) NOT NULL DEFAULT '',
+				PRIMARY KEY (item_id),
+				CONSTRAINT rental_rule_ibfk_1 FOREIGN KEY (item_id) REFERENCES item (id) ON DELETE CASCADE ON UPDATE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`ALTER TABLE booking
+				ADD COLUMN charge_currency char(3) NULL AFTER status,
+				ADD COLUMN final_charge_amount bigint(20) NULL AFTER charge_currency,
+				ADD COLUMN late_fee_amount bigint(20) NULL AFTER final_charge_amount,
+				ADD COLUMN deposit_refund_amount bigint(20) NULL AFTER late_fee_amount`,
+		},
+		down: []string{
+			`ALTER TABLE booking
+				DROP COLUMN charge_currency,
+				DROP COLUMN final_charge_amount,
+				DROP COLUMN late_fee_amount,
+				DROP COLUMN deposit_refund_amount`,
+			"DROP TABLE IF EXISTS rental_rule",
+		},
+	},
+	{
+		version: 8,
+		name:    "create_sales_order_tables",
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS sales_order (
+				id varchar(24) NOT NULL DEFAULT '',
+				customer varchar(100) NOT NULL DEFAULT '',
+				currency char(3) NOT NULL DEFAULT '',
+				total_amount bigint(20) NOT NULL,
+				status varchar(20) NOT NULL DEFAULT '',
+				created_at datetime NOT NULL,
+				created_by varchar(50) NOT NULL DEFAULT '',
+				modified_at datetime NOT NULL,
+				modified_by varchar(50) NOT NULL DEFAULT '',
+				version int(11) NOT NULL DEFAULT '1',
+				PRIMARY KEY (id),
+				KEY customer_created_at (customer, created_at)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			//line keep the captured item name and price, so it has no foreign key into item
+			`CREATE TABLE IF NOT EXISTS sales_order_line (
+				order_id varchar(24) NOT NULL DEFAULT '',
+				line_no int(11) NOT NULL,
+				item_id varchar(24) NOT NULL DEFAULT '',
+				item_name text NOT NULL,
+				quantity int(11) NOT NULL,
+				unit_price_amount bigint(20) NOT NULL,
+				subtotal_amount bigint(20) NOT NULL,
+				PRIMARY KEY (order_id, line_no),
+				KEY item_id (item_id),
+				CONSTRAINT sales_order_line_ibfk_1 FOREIGN KEY (order_id) REFERENCES sales_order (id) ON DELETE CASCADE ON UPDATE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS sales_order_line",
+			"DROP TABLE IF EXISTS sales_order",
+		},
+	},
+	{
+		version: 9,
+		name:    "create_sales_order_transition_table",
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS sales_order_transition (
+				order_id varchar(24) NOT NULL DEFAULT '',
+				seq int(11) NOT NULL,
+				from_status varchar(20) NOT NULL DEFAULT '',
+				to_status varchar(20) NOT NULL DEFAULT '',
+				actor varchar(50) NOT NULL DEFAULT '',
+				created_at datetime NOT NULL,
+				PRIMARY KEY (order_id, seq),
+				CONSTRAINT sales_order_transition_ibfk_1 FOREIGN KEY (order_id) REFERENCES sales_order (id) ON DELETE CASCADE ON UPDATE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS sales_order_transition",
+		},
+	},
+	{
+		version: 10,
+		name:    "create_stock_reservation_table",
+		up: []string{
+			"ALTER TABLE item_stock ADD COLUMN reserved int(11) NOT NULL DEFAULT '0' AFTER quantity",
+			`CREATE TABLE IF NOT EXISTS stock_reservation (
+				id varchar(24) NOT NULL DEFAULT '',
+				item_id varchar(24) NOT NULL DEFAULT '',
+				warehouse_id varchar(24) NOT NULL DEFAULT '',
+				quantity int(11) NOT NULL,
+				reference varchar(100) NOT NULL DEFAULT '',
+				status varchar(20) NOT NULL DEFAULT '',
+				expires_at datetime NOT NULL,
+				created_at datetime NOT NULL,
+				created_by varchar(50) NOT NULL DEFAULT '',
+				modified_at datetime NOT NULL,
+				modified_by varchar(50) NOT NULL DEFAULT '',
+				PRIMARY KEY (id),
+				KEY reference (reference),
+				KEY status_expires_at (status, expires_at),
+				CONSTRAINT stock_reservation_ibfk_1 FOREIGN KEY (item_id, warehouse_id) REFERENCES item_stock (item_id, warehouse_id) ON DELETE CASCADE ON UPDATE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS stock_reservation",
+			"ALTER TABLE item_stock DROP COLUMN reserved",
+		},
+	},
+	{
+		version: 11,
+		name:    "create_cart_tables",
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS cart (
+				owner varchar(100) NOT NULL DEFAULT '',
+				created_at datetime NOT NULL,
+				modified_at datetime NOT NULL,
+				version int(11) NOT NULL DEFAULT '1',
+				PRIMARY KEY (owner)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			//line keep only the item ID and version, deleted item is detected when the cart is read
+			`CREATE TABLE IF NOT EXISTS cart_line (
+				owner varchar(100) NOT NULL DEFAULT '',
+				line_no int(11) NOT NULL,
+				item_id varchar(24) NOT NULL DEFAULT '',
+				quantity int(11) NOT NULL,
+				item_version int(11) NOT NULL,
+				added_at datetime NOT NULL,
+				PRIMARY KEY (owner, line_no),
+				CONSTRAINT cart_line_ibfk_1 FOREIGN KEY (owner) REFERENCES cart (owner) ON DELETE CASCADE ON UPDATE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS cart_line",
+			"DROP TABLE IF EXISTS cart",
+		},
+	},
+	{
+		version: 12,
+		name:    "create_promotion_tables",
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS promotion (
+				id varchar(24) NOT NULL DEFAULT '',
+				code varchar(50) NOT NULL DEFAULT '',
+				description text NOT NULL,
+				type varchar(20) NOT NULL DEFAULT '',
+				percent int(11) NOT NULL DEFAULT '0',
+				amount bigint(20) DEFAULT NULL,
+				currency char(3) DEFAULT NULL,
+				valid_from datetime NOT NULL,
+				valid_until datetime DEFAULT NULL,
+				usage_limit int(11) NOT NULL DEFAULT '0',
+				per_user_limit int(11) NOT NULL DEFAULT '0',
+				used_count int(11) NOT NULL DEFAULT '0',
+				created_at datetime NOT NULL,
+				created_by varchar(50) NOT NULL DEFAULT '',
+				modified_at datetime NOT NULL,
+				modified_by varchar(50) NOT NULL DEFAULT '',
+				version int(11) NOT NULL DEFAULT '1',
+				PRIMARY KEY (id),
+				UNIQUE KEY code (code)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS promotion_item (
+				promotion_id varchar(24) NOT NULL DEFAULT '',
+				item_id varchar(24) NOT NULL DEFAULT '',
+				PRIMARY KEY (promotion_id, item_id),
+				CONSTRAINT promotion_item_ibfk_1 FOREIGN KEY (promotion_id) REFERENCES promotion (id) ON DELETE CASCADE ON UPDATE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`CREATE TABLE IF NOT EXISTS promotion_tag (
+				promotion_id varchar(24) NOT NULL DEFAULT '',
+				tag_name varchar(50) NOT NULL DEFAULT '',
+				PRIMARY KEY (promotion_id, tag_name),
+				CONSTRAINT promotion_tag_ibfk_1 FOREIGN KEY (promotion_id) REFERENCES promotion (id) ON DELETE CASCADE ON UPDATE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			//single order use the promotion at most once
+			`CREATE TABLE IF NOT EXISTS promotion_redemption (
+				order_id varchar(24) NOT NULL DEFAULT '',
+				promotion_id varchar(24) NOT NULL DEFAULT '',
+				user varchar(100) NOT NULL DEFAULT '',
+				amount bigint(20) NOT NULL,
+				currency char(3) NOT NULL DEFAULT '',
+				redeemed_at datetime NOT NULL,
+				PRIMARY KEY (order_id),
+				KEY promotion_user (promotion_id, user),
+				CONSTRAINT promotion_redemption_ibfk_1 FOREIGN KEY (promotion_id) REFERENCES promotion (id) ON DELETE CASCADE ON UPDATE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`ALTER TABLE sales_order
+				ADD COLUMN subtotal_amount bigint(20) NOT NULL DEFAULT '0' AFTER currency,
+				ADD COLUMN discount_amount bigint(20) NOT NULL DEFAULT '0' AFTER subtotal_amount,
+				ADD COLUMN promotion_code varchar(50) NOT NULL DEFAULT '' AFTER discount_amount`,
+			//existing orders have no discount
+			"UPDATE sales_order SET subtotal_amount = total_amount",
+			"ALTER TABLE cart ADD COLUMN promotion_code varchar(50) NOT NULL DEFAULT '' AFTER owner",
+		},
+		down: []string{
+			"ALTER TABLE cart DROP COLUMN promotion_code",
+			`ALTER TABLE sales_order
+				DROP COLUMN subtotal_amount,
+				DROP COLUMN discount_amount,
+				DROP COLUMN promotion_code`,
+			"DROP TABLE IF EXISTS promotion_redemption",
+			"DROP TABLE IF EXISTS promotion_tag",
+			"DROP TABLE IF EXISTS promotion_item",
+			"DROP TABLE IF EXISTS promotion",
+		},
+	},
+	{
+		version: 13,
+		name:    "create_outbox_table",
+		up: []string{
+			//seq keep the order the messages are written
+			`CREATE TABLE IF NOT EXISTS outbox (
+				seq bigint(20) NOT NULL AUTO_INCREMENT,
+				id varchar(24) NOT NULL DEFAULT '',
+				aggregate_type varchar(50) NOT NULL DEFAULT '',
+				aggregate_id varchar(24) NOT NULL DEFAULT '',
+				event_type varchar(50) NOT NULL DEFAULT '',
+				version int(11) NOT NULL,
+				payload mediumtext NOT NULL,
+				occurred_at datetime NOT NULL,
+				attempts int(11) NOT NULL DEFAULT '0',
+				last_error varchar(1000) NOT NULL DEFAULT '',
+				published_at datetime DEFAULT NULL,
+				PRIMARY KEY (seq),
+				UNIQUE KEY id (id),
+				KEY published_at (published_at, seq)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS outbox",
+		},
+	},
+	{
+		version: 14,
+		name:    "create_webhook_tables",
+		up: []string{
+			`CREATE TABLE IF NOT EXISTS webhook_subscription (
+				id varchar(24) NOT NULL DEFAULT '',
+				url varchar(500) NOT NULL DEFAULT '',
+				event_types varchar(200) NOT NULL DEFAULT '',
+				tag varchar(50) NOT NULL DEFAULT '',
+				secret varchar(100) NOT NULL DEFAULT '',
+				created_at datetime NOT NULL,
+				created_by varchar(50) NOT NULL DEFAULT '',
+				modified_at datetime NOT NULL,
+				modified_by varchar(50) NOT NULL DEFAULT '',
+				version int(11) NOT NULL DEFAULT '1',
+				PRIMARY KEY (id)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			//single outbox message is delivered into the same subscription at most once
+			`CREATE TABLE IF NOT EXISTS webhook_delivery (
+				id varchar(24) NOT NULL DEFAULT '',
+				subscription_id varchar(24) NOT NULL DEFAULT '',
+				message_id varchar(24) NOT NULL DEFAULT '',
+				event_type varchar(50) NOT NULL DEFAULT '',
+				payload mediumtext NOT NULL,
+				status varchar(20) NOT NULL DEFAULT '',
+				attempts int(11) NOT NULL DEFAULT '0',
+				next_attempt_at datetime NOT NULL,
+				last_status_code int(11) NOT NULL DEFAULT '0',
+				last_error varchar(1000) NOT NULL DEFAULT '',
+				created_at datetime NOT NULL,
+				delivered_at datetime DEFAULT NULL,
+				PRIMARY KEY (id),
+				UNIQUE KEY subscription_message (subscription_id, message_id),
+				KEY status_next_attempt_at (status, next_attempt_at),
+				KEY status_created_at (status, created_at),
+				CONSTRAINT webhook_delivery_ibfk_1 FOREIGN KEY (subscription_id) REFERENCES webhook_subscription (id) ON DELETE CASCADE ON UPDATE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS webhook_delivery",
+			"DROP TABLE IF EXISTS webhook_subscription",
+		},
+	},
+	{
+		version: 15,
+		name:    "create_item_change_table",
+		up: []string{
+			//single row per item which get new seq on every change and is kept as tombstone after the item is deleted
+			`CREATE TABLE IF NOT EXISTS item_change (
+				seq bigint(20) NOT NULL AUTO_INCREMENT,
+				item_id varchar(24) NOT NULL DEFAULT '',
+				version int(11) NOT NULL,
+				deleted tinyint(1) NOT NULL DEFAULT '0',
+				modified_at datetime NOT NULL,
+				modified_by varchar(50) NOT NULL DEFAULT '',
+				PRIMARY KEY (seq),
+				UNIQUE KEY item_id (item_id)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			`INSERT INTO item_change (item_id, version, deleted, modified_at, modified_by)
+				SELECT id, version, 0, modified_at, modified_by
+				FROM item
+				ORDER BY modified_at, id`,
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS item_change",
+		},
+	},
+	{
+		version: 16,
+		name:    "keep_item_history_on_delete",
+		up: []string{
+			//deleted item keep its stock, movement and booking rows as history like in MongoDB, only rental rule cascade
+			"ALTER TABLE item_stock DROP FOREIGN KEY item_stock_ibfk_1",
+			"ALTER TABLE stock_movement DROP FOREIGN KEY stock_movement_ibfk_1",
+			"ALTER TABLE booking DROP FOREIGN KEY booking_ibfk_1",
+		},
+		down: []string{
+			//fail when there is history of deleted item, it must be removed by hand before the revert
+			"ALTER TABLE booking ADD CONSTRAINT booking_ibfk_1 FOREIGN KEY (item_id) REFERENCES item (id) ON DELETE CASCADE ON UPDATE CASCADE",
+			"ALTER TABLE stock_movement ADD CONSTRAINT stock_movement_ibfk_1 FOREIGN KEY (item_id) REFERENCES item (id) ON DELETE CASCADE ON UPDATE CASCADE",
+			"ALTER TABLE item_stock ADD CONSTRAINT item_stock_ibfk_1 FOREIGN KEY (item_id) REFERENCES item (id) ON DELETE CASCADE ON UPDATE CASCADE",
+		},
+	},
+	{
+		version: 17,
+		name:    "create_item_change_seq_table",
+		up: []string{
+			//single counter row, its lock is held until commit so the change seq follow the commit order
+			`CREATE TABLE IF NOT EXISTS item_change_seq (
+				id tinyint(1) NOT NULL,
+				seq bigint(20) NOT NULL,
+				PRIMARY KEY (id)
+			) ENGINE=InnoDB DEFAULT CHARSET=latin1`,
+			"INSERT INTO item_change_seq (id, seq) SELECT 1, COALESCE(MAX(seq), 0) FROM item_change",
+		},
+		down: []string{
+			"DROP TABLE IF EXISTS item_change_seq",
+		},
+	},
+}
+
+//MySQLMigrator The implementation of Migrator object for MySQL
+type MySQLMigrator struct {
+	db *sql.DB
+}
+
+//NewMySQLMigrator Generate MySQL migrator
+func NewMySQLMigrator(db *sql.DB) *MySQLMigrator {
+	return &MySQLMigrator{
+		db,
+	}
+}
+
+//Up Apply all pending migrations
+func (m *MySQLMigrator) Up() ([]Status, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	//read after the lock is taken, so the migrations applied by the previous holder are skipped
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Status
+
+	for _, migration := range mysqlMigrations {
+		if _, ok := applied[migration.version]; ok {
+			continue
+		}
+
+		//DDL in MySQL is auto commit, so bookkeeping only written after all statements succeed
+		for _, query := range migration.up {
+			if _, err := m.db.Exec(query); err != nil {
+				return result, err
+			}
+		}
+
+		now := time.Now()
+		insertQuery := "INSERT INTO schema_migration (version, name, applied_at) VALUES (?, ?, ?)"
+
+		if _, err := m.db.Exec(insertQuery, migration.version, migration.name, now); err != nil {
+			return result, err
+		}
+
+		result = append(result, Status{migration.version, migration.name, true, now})
+	}
+
+	return result, nil
+}
+
+//Down Revert the latest applied migrations
+func (m *MySQLMigrator) Down(steps int) ([]Status, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Status
+
+	for i := len(mysqlMigrations) - 1; i >= 0 && len(result) < steps; i-- {
+		migration := mysqlMigrations[i]
+
+		if _, ok := applied[migration.version]; !ok {
+			continue
+		}
+
+		for _, query := range migration.down {
+			if _, err := m.db.Exec(query); err != nil {
+				return result, err
+			}
+		}
+
+		if _, err := m.db.Exec("DELETE FROM schema_migration WHERE version = ?", migration.version); err != nil {
+			return result, err
+		}
+
+		result = append(result, Status{migration.version, migration.name, false, time.Time{}})
+	}
+
+	return result, nil
+}
+
+//Status Return all known migrations and its state
+func (m *MySQLMigrator) Status() ([]Status, error) {
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Status
+
+	for _, migration := range mysqlMigrations {
+		appliedAt, ok := applied[migration.version]
+		result = append(result, Status{migration.version, migration.name, ok, appliedAt})
+		delete(applied, migration.version)
+	}
+
+	if len(applied) > 0 {
+		return result, ErrUnknownMigration
+	}
+
+	return result, nil
+}
+
+//lock take the named lock of the database. The lock belong to the connection, so the connection is kept
+//until unlock and the lock is released by the server when the process die
+func (m *MySQLMigrator) lock() (func(), error) {
+	conn, err := m.db.Conn(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	var acquired sql.NullInt64
+	lockQuery := "SELECT GET_LOCK(CONCAT(DATABASE(), '.schema_migration'), ?)"
+
+	if err = conn.QueryRowContext(context.TODO(), lockQuery, int(lockTimeout.Seconds())).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	//zero when timed out, null on error such as killed query
+	if acquired.Int64 != 1 {
+		conn.Close()
+		return nil, ErrMigrationLocked
+	}
+
+	return func() {
+		conn.ExecContext(context.TODO(), "SELECT RELEASE_LOCK(CONCAT(DATABASE(), '.schema_migration'))")
+		conn.Close()
+	}, nil
+}
+
+func (m *MySQLMigrator) appliedMigrations() (map[int]time.Time, error) {
+	createQuery := `CREATE TABLE IF NOT EXISTS schema_migration (
+			version int(11) NOT NULL,
+			name varchar(100) NOT NULL DEFAULT '',
+			applied_at datetime NOT NULL,
+			PRIMARY KEY (version)
+		) ENGINE=InnoDB DEFAULT CHARSET=latin1`
+
+	if _, err := m.db.Exec(createQuery); err != nil {
+		return nil, err
+	}
+
+	row, err := m.db.Query("SELECT version, applied_at FROM schema_migration")
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	applied := make(map[int]time.Time)
+
+	for row.Next() {
+		var version int
+		var appliedAt time.Time
+
+		if err := row.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+
+		applied[version] = appliedAt
+	}
+
+	return applied, row.Err()
+}
@@ -0,0 +1,66 @@
+package relay
+
+import (
+	"sample-order/business/outbox"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+//OutboxRelay Background worker which periodically publish pending outbox messages
+type OutboxRelay struct {
+	service   outbox.Service
+	interval  time.Duration
+	batchSize int
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+//NewOutboxRelay Generate relay which publish at most batchSize messages every given interval
+func NewOutboxRelay(service outbox.Service, interval time.Duration, batchSize int) *OutboxRelay {
+	return &OutboxRelay{
+		service,
+		interval,
+		batchSize,
+		make(chan struct{}),
+		make(chan struct{}),
+	}
+}
+
+//Start Run the relay in its own goroutine
+func (relay *OutboxRelay) Start() {
+	go relay.run()
+}
+
+//Stop Signal the relay to stop and wait until the running batch is finished
+func (relay *OutboxRelay) Stop() {
+	close(relay.stop)
+	<-relay.done
+}
+
+func (relay *OutboxRelay) run() {
+	defer close(relay.done)
+
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-relay.stop:
+			return
+		case <-ticker.C:
+			relay.relay()
+		}
+	}
+}
+
+func (relay *OutboxRelay) relay() {
+	published, err := relay.service.RelayPending(relay.batchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to publish outbox messages")
+	}
+
+	if published > 0 {
+		log.Info().Int("published", published).Msg("published outbox messages")
+	}
+}
@@ -0,0 +1,66 @@
+package relay
+
+import (
+	"sample-order/business/webhook"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+//WebhookRelay Background worker which periodically send due webhook deliveries
+type WebhookRelay struct {
+	service   webhook.Service
+	interval  time.Duration
+	batchSize int
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+//NewWebhookRelay Generate relay which attempt at most batchSize deliveries every given interval
+func NewWebhookRelay(service webhook.Service, interval time.Duration, batchSize int) *WebhookRelay {
+	return &WebhookRelay{
+		service,
+		interval,
+		batchSize,
+		make(chan struct{}),
+		make(chan struct{}),
+	}
+}
+
+//Start Run the relay in its own goroutine
+func (relay *WebhookRelay) Start() {
+	go relay.run()
+}
+
+//Stop Signal the relay to stop and wait until the running batch is finished
+func (relay *WebhookRelay) Stop() {
+	close(relay.stop)
+	<-relay.done
+}
+
+func (relay *WebhookRelay) run() {
+	defer close(relay.done)
+
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-relay.stop:
+			return
+		case now := <-ticker.C:
+			relay.deliver(now)
+		}
+	}
+}
+
+func (relay *WebhookRelay) deliver(now time.Time) {
+	delivered, err := relay.service.DeliverDue(now, relay.batchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to deliver webhooks")
+	}
+
+	if delivered > 0 {
+		log.Info().Int("delivered", delivered).Msg("delivered webhooks")
+	}
+}
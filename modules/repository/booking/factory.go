@@ -0,0 +1,19 @@
+package booking
+
+import (
+	"sample-order/business/booking"
+	"sample-order/util"
+)
+
+//RepositoryFactory Will return business.booking.Repository based on active database connection
+func RepositoryFactory(dbCon *util.DatabaseConnection) booking.Repository {
+	var bookingRepo booking.Repository
+
+	if dbCon.Driver == util.MySQL {
+		bookingRepo = NewMySQLRepository(dbCon.MySQLDB)
+	} else if dbCon.Driver == util.MongoDB {
+		bookingRepo = NewMongoDBRepository(dbCon.MongoDB)
+	}
+
+	return bookingRepo
+}
@@ -0,0 +1,282 @@
+package booking
+
+import (
+	"context"
+	"sample-order/business"
+	"sample-order/business/booking"
+	"sample-order/business/money"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//MongoDBRepository The implementation of booking.Repository object
+type MongoDBRepository struct {
+	client  *mongo.Client
+	col     *mongo.Collection
+	lockCol *mongo.Collection
+}
+
+type collection struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	ItemID     primitive.ObjectID `bson:"item_id"`
+	Quantity   int                `bson:"quantity"`
+	StartDate  time.Time          `bson:"start_date"`
+	EndDate    time.Time          `bson:"end_date"`
+	Customer   string             `bson:"customer"`
+	Status     string             `bson:"status"`
+	Charge     *chargeDocument    `bson:"charge,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	CreatedBy  string             `bson:"created_by"`
+	ModifiedAt time.Time          `bson:"modified_at"`
+	ModifiedBy string             `bson:"modified_by"`
+	Version    int                `bson:"version"`
+}
+
+//chargeDocument final charge of returned booking in minor unit of the currency
+type chargeDocument struct {
+	Currency      string `bson:"currency"`
+	FinalCharge   int64  `bson:"final_charge"`
+	LateFee       int64  `bson:"late_fee"`
+	DepositRefund int64  `bson:"deposit_refund"`
+}
+
+func newChargeDocument(booking booking.Booking) *chargeDocument {
+	if booking.FinalCharge == nil {
+		return nil
+	}
+
+	return &chargeDocument{
+		booking.FinalCharge.Currency,
+		booking.FinalCharge.Amount,
+		booking.LateFee.Amount,
+		booking.DepositRefund.Amount,
+	}
+}
+
+func newCollection(booking booking.Booking) (*collection, error) {
+	objectID, err := primitive.ObjectIDFromHex(booking.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	itemObjectID, err := primitive.ObjectIDFromHex(booking.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &collection{
+		objectID,
+		itemObjectID,
+		booking.Quantity,
+		booking.From,
+		booking.To,
+		booking.Customer,
+		string(booking.Status),
+		newChargeDocument(booking),
+		booking.CreatedAt,
+		booking.CreatedBy,
+		booking.ModifiedAt,
+		booking.ModifiedBy,
+		booking.Version,
+	}, nil
+}
+
+func (col *collection) ToBooking() booking.Booking {
+	result := booking.Booking{
+		ID:         col.ID.Hex(),
+		ItemID:     col.ItemID.Hex(),
+		Quantity:   col.Quantity,
+		From:       col.StartDate.UTC(),
+		To:         col.EndDate.UTC(),
+		Customer:   col.Customer,
+		Status:     booking.Status(col.Status),
+		CreatedAt:  col.CreatedAt,
+		CreatedBy:  col.CreatedBy,
+		ModifiedAt: col.ModifiedAt,
+		ModifiedBy: col.ModifiedBy,
+		Version:    col.Version,
+	}
+
+	if col.Charge != nil {
+		result.SetCharge(
+			money.Money{Amount: col.Charge.FinalCharge, Currency: col.Charge.Currency},
+			money.Money{Amount: col.Charge.LateFee, Currency: col.Charge.Currency},
+			money.Money{Amount: col.Charge.DepositRefund, Currency: col.Charge.Currency})
+	}
+
+	return result
+}
+
+//NewMongoDBRepository Generate mongo DB booking repository
+func NewMongoDBRepository(db *mongo.Database) *MongoDBRepository {
+	return &MongoDBRepository{
+		db.Client(),
+		db.Collection("bookings"),
+		db.Collection("booking_locks"),
+	}
+}
+
+//FindBookingByID Find booking based on given ID. Its return nil if not found
+func (repo *MongoDBRepository) FindBookingByID(ID string) (*booking.Booking, error) {
+	var col collection
+
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		//if cannot be convert means that ID will be never found
+		return nil, nil
+	}
+
+	if err := repo.col.FindOne(context.TODO(), bson.M{"_id": objectID}).Decode(&col); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	booking := col.ToBooking()
+	return &booking, nil
+}
+
+//FindBookingsByItemID Find all bookings of given item ordered by period
+func (repo *MongoDBRepository) FindBookingsByItemID(itemID string) ([]booking.Booking, error) {
+	objectID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		return nil, nil
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "start_date", Value: 1}, {Key: "_id", Value: 1}})
+
+	return repo.findBookings(context.TODO(), bson.M{"item_id": objectID}, findOptions)
+}
+
+//FindActiveBookings Find reserved and picked up bookings of given item which overlap the range
+func (repo *MongoDBRepository) FindActiveBookings(itemID string, from time.Time, to time.Time) ([]booking.Booking, error) {
+	objectID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		return nil, nil
+	}
+
+	return repo.findBookings(context.TODO(), activeBookingFilter(objectID, from, to))
+}
+
+//InsertBooking Insert booking if it fit into the capacity. Every insert of the same item write into its lock document
+//inside a transaction, so concurrent booking conflict and retried by the driver. Transaction require MongoDB replica set
+func (repo *MongoDBRepository) InsertBooking(newBooking booking.Booking, capacity int) error {
+	col, err := newCollection(newBooking)
+	if err != nil {
+		return err
+	}
+
+	session, err := repo.client.StartSession()
+	if err != nil {
+		return err
+	}
+
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(context.TODO(), func(sessCtx mongo.SessionContext) (interface{}, error) {
+		_, err := repo.lockCol.UpdateOne(sessCtx,
+			bson.M{"_id": col.ItemID},
+			bson.M{"$inc": bson.M{"version": 1}},
+			options.Update().SetUpsert(true))
+
+		if err != nil {
+			return nil, err
+		}
+
+		bookings, err := repo.findBookings(sessCtx, activeBookingFilter(col.ItemID, newBooking.From, newBooking.To))
+		if err != nil {
+			return nil, err
+		}
+
+		if !booking.HasCapacity(capacity, bookings, newBooking) {
+			return nil, business.ErrBookingConflict
+		}
+
+		return repo.col.InsertOne(sessCtx, col)
+	})
+
+	return err
+}
+
+//UpdateBooking Update status and charge of existing booking
+func (repo *MongoDBRepository) UpdateBooking(booking booking.Booking, currentVersion int) error {
+	objectID, err := primitive.ObjectIDFromHex(booking.ID)
+	if err != nil {
+		return business.ErrZeroAffected
+	}
+
+	filter := bson.M{
+		"_id":     objectID,
+		"version": currentVersion,
+	}
+
+	updated := bson.M{
+		"$set": bson.M{
+			"status":      string(booking.Status),
+			"charge":      newChargeDocument(booking),
+			"modified_at": booking.ModifiedAt,
+			"modified_by": booking.ModifiedBy,
+			"version":     booking.Version,
+		},
+	}
+
+	result, err := repo.col.UpdateOne(context.TODO(), filter, updated)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
+
+func (repo *MongoDBRepository) findBookings(ctx context.Context, filter bson.M, findOptions ...*options.FindOptions) ([]booking.Booking, error) {
+	cursor, err := repo.col.Find(ctx, filter, findOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var bookings []booking.Booking
+
+	for cursor.Next(ctx) {
+		var col collection
+		if err = cursor.Decode(&col); err != nil {
+			return nil, err
+		}
+
+		bookings = append(bookings, col.ToBooking())
+	}
+
+	return bookings, cursor.Err()
+}
+
+func activeBookingFilter(itemID primitive.ObjectID, from time.Time, to time.Time) bson.M {
+	return bson.M{
+		"item_id":    itemID,
+		"status":     bson.M{"$in": []string{string(booking.Reserved), string(booking.PickedUp)}},
+		"start_date": bson.M{"$lt": to},
+		"end_date":   bson.M{"$gt": from},
+	}
+}
+
+//HasMongoDBActiveBooking Tell whether item has reserved or picked up booking using the session context of the transaction
+//which delete the item
+func HasMongoDBActiveBooking(ctx context.Context, db *mongo.Database, itemID primitive.ObjectID) (bool, error) {
+	filter := bson.M{
+		"item_id": itemID,
+		"status":  bson.M{"$in": bson.A{string(booking.Reserved), string(booking.PickedUp)}},
+	}
+
+	active, err := db.Collection("bookings").CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	return active > 0, err
+}
@@ -0,0 +1,262 @@
+package booking
+
+import (
+	"context"
+	"database/sql"
+	"sample-order/business"
+	"sample-order/business/booking"
+	"sample-order/business/money"
+	"time"
+)
+
+//MySQLRepository The implementation of booking.Repository object
+type MySQLRepository struct {
+	db *sql.DB
+}
+
+//selectBookingQuery base query of booking, the columns must be read by scanBooking
+const selectBookingQuery = `SELECT id, item_id, quantity, start_date, end_date, customer, status,
+		charge_currency, final_charge_amount, late_fee_amount, deposit_refund_amount,
+		created_at, created_by, modified_at, modified_by, version
+		FROM booking`
+
+//rowScanner is satisfied by both sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+//queryer is satisfied by both sql.DB and sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+//NewMySQLRepository Generate MySQL booking repository
+func NewMySQLRepository(db *sql.DB) *MySQLRepository {
+	return &MySQLRepository{
+		db,
+	}
+}
+
+//FindBookingByID Find booking based on given ID. Its return nil if not found
+func (repo *MySQLRepository) FindBookingByID(ID string) (*booking.Booking, error) {
+	booking, err := scanBooking(repo.db.QueryRow(selectBookingQuery+" WHERE id = ?", ID))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+//FindBookingsByItemID Find all bookings of given item ordered by period
+func (repo *MySQLRepository) FindBookingsByItemID(itemID string) ([]booking.Booking, error) {
+	selectQuery := selectBookingQuery + " WHERE item_id = ? ORDER BY start_date, id"
+
+	return queryBookings(repo.db, selectQuery, itemID)
+}
+
+//FindActiveBookings Find reserved and picked up bookings of given item which overlap the range
+func (repo *MySQLRepository) FindActiveBookings(itemID string, from time.Time, to time.Time) ([]booking.Booking, error) {
+	return findActiveBookings(repo.db, itemID, from, to)
+}
+
+//InsertBooking Insert booking if it fit into the capacity. The item row is locked until commit,
+//so concurrent booking of the same item is checked one after another
+func (repo *MySQLRepository) InsertBooking(newBooking booking.Booking, capacity int) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	var lockedID string
+	if err = tx.QueryRow("SELECT id FROM item WHERE id = ? FOR UPDATE", newBooking.ItemID).Scan(&lockedID); err != nil {
+		tx.Rollback()
+
+		if err == sql.ErrNoRows {
+			return business.ErrNotFound
+		}
+
+		return err
+	}
+
+	bookings, err := findActiveBookings(tx, newBooking.ItemID, newBooking.From, newBooking.To)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if !booking.HasCapacity(capacity, bookings, newBooking) {
+		tx.Rollback()
+		return business.ErrBookingConflict
+	}
+
+	insertQuery := `INSERT INTO booking (
+			id,
+			item_id,
+			quantity,
+			start_date,
+			end_date,
+			customer,
+			status,
+			created_at,
+			created_by,
+			modified_at,
+			modified_by,
+			version
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(insertQuery,
+		newBooking.ID,
+		newBooking.ItemID,
+		newBooking.Quantity,
+		newBooking.From,
+		newBooking.To,
+		newBooking.Customer,
+		newBooking.Status,
+		newBooking.CreatedAt,
+		newBooking.CreatedBy,
+		newBooking.ModifiedAt,
+		newBooking.ModifiedBy,
+		newBooking.Version,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+//UpdateBooking Update status and charge of existing booking
+func (repo *MySQLRepository) UpdateBooking(booking booking.Booking, currentVersion int) error {
+	currency, finalCharge, lateFee, depositRefund := chargeColumns(booking)
+
+	updateQuery := `UPDATE booking
+		SET
+			status = ?,
+			charge_currency = ?,
+			final_charge_amount = ?,
+			late_fee_amount = ?,
+			deposit_refund_amount = ?,
+			modified_at = ?,
+			modified_by = ?,
+			version = ?
+		WHERE id = ? AND version = ?`
+
+	res, err := repo.db.Exec(updateQuery,
+		booking.Status,
+		currency,
+		finalCharge,
+		lateFee,
+		depositRefund,
+		booking.ModifiedAt,
+		booking.ModifiedBy,
+		booking.Version,
+		booking.ID,
+		currentVersion,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
+
+func findActiveBookings(db queryer, itemID string, from time.Time, to time.Time) ([]booking.Booking, error) {
+	selectQuery := selectBookingQuery + `
+		WHERE item_id = ?
+			AND status IN (?, ?)
+			AND start_date < ?
+			AND end_date > ?`
+
+	return queryBookings(db, selectQuery, itemID, booking.Reserved, booking.PickedUp, to, from)
+}
+
+func queryBookings(db queryer, query string, args ...interface{}) ([]booking.Booking, error) {
+	row, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var bookings []booking.Booking
+
+	for row.Next() {
+		booking, err := scanBooking(row)
+		if err != nil {
+			return nil, err
+		}
+
+		bookings = append(bookings, *booking)
+	}
+
+	return bookings, row.Err()
+}
+
+func scanBooking(scanner rowScanner) (*booking.Booking, error) {
+	var booking booking.Booking
+	var currency sql.NullString
+	var finalCharge, lateFee, depositRefund sql.NullInt64
+
+	err := scanner.Scan(
+		&booking.ID, &booking.ItemID, &booking.Quantity,
+		&booking.From, &booking.To,
+		&booking.Customer, &booking.Status,
+		&currency, &finalCharge, &lateFee, &depositRefund,
+		&booking.CreatedAt, &booking.CreatedBy,
+		&booking.ModifiedAt, &booking.ModifiedBy,
+		&booking.Version)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if currency.Valid {
+		booking.SetCharge(
+			money.Money{Amount: finalCharge.Int64, Currency: currency.String},
+			money.Money{Amount: lateFee.Int64, Currency: currency.String},
+			money.Money{Amount: depositRefund.Int64, Currency: currency.String})
+	}
+
+	return &booking, nil
+}
+
+//chargeColumns all charge money share the same currency, so it is stored once
+func chargeColumns(booking booking.Booking) (sql.NullString, sql.NullInt64, sql.NullInt64, sql.NullInt64) {
+	if booking.FinalCharge == nil {
+		return sql.NullString{}, sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}
+	}
+
+	return sql.NullString{String: booking.FinalCharge.Currency, Valid: true},
+		sql.NullInt64{Int64: booking.FinalCharge.Amount, Valid: true},
+		sql.NullInt64{Int64: booking.LateFee.Amount, Valid: true},
+		sql.NullInt64{Int64: booking.DepositRefund.Amount, Valid: true}
+}
+
+//HasMySQLActiveBooking Tell whether item has reserved or picked up booking using the transaction which delete the item
+func HasMySQLActiveBooking(ctx context.Context, tx *sql.Tx, itemID string) (bool, error) {
+	var active int
+
+	countQuery := "SELECT COUNT(*) FROM booking WHERE item_id = ? AND status IN (?, ?)"
+	if err := tx.QueryRowContext(ctx, countQuery, itemID, booking.Reserved, booking.PickedUp).Scan(&active); err != nil {
+		return false, err
+	}
+
+	return active > 0, nil
+}
@@ -0,0 +1,19 @@
+package cart
+
+import (
+	"sample-order/business/cart"
+	"sample-order/util"
+)
+
+//RepositoryFactory Will return business.cart.Repository based on active database connection
+func RepositoryFactory(dbCon *util.DatabaseConnection) cart.Repository {
+	var cartRepo cart.Repository
+
+	if dbCon.Driver == util.MySQL {
+		cartRepo = NewMySQLRepository(dbCon.MySQLDB)
+	} else if dbCon.Driver == util.MongoDB {
+		cartRepo = NewMongoDBRepository(dbCon.MongoDB)
+	}
+
+	return cartRepo
+}
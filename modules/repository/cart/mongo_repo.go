@@ -0,0 +1,160 @@
+package cart
+
+import (
+	"context"
+	"sample-order/business"
+	"sample-order/business/cart"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//MongoDBRepository The implementation of cart.Repository object
+type MongoDBRepository struct {
+	col *mongo.Collection
+}
+
+//lineDocument cart line embedded inside the cart document
+type lineDocument struct {
+	ItemID      string    `bson:"item_id"`
+	Quantity    int       `bson:"quantity"`
+	ItemVersion int       `bson:"item_version"`
+	AddedAt     time.Time `bson:"added_at"`
+}
+
+//collection cart document, the owner is the document ID
+type collection struct {
+	Owner         string         `bson:"_id"`
+	PromotionCode string         `bson:"promotion_code"`
+	Lines         []lineDocument `bson:"lines"`
+	CreatedAt     time.Time      `bson:"created_at"`
+	ModifiedAt    time.Time      `bson:"modified_at"`
+	Version       int            `bson:"version"`
+}
+
+func newLineDocuments(lines []cart.Line) []lineDocument {
+	documents := make([]lineDocument, 0, len(lines))
+
+	for _, line := range lines {
+		documents = append(documents, lineDocument{
+			line.ItemID,
+			line.Quantity,
+			line.ItemVersion,
+			line.AddedAt,
+		})
+	}
+
+	return documents
+}
+
+func (col *collection) ToCart() cart.Cart {
+	var result cart.Cart
+	result.Owner = col.Owner
+	result.PromotionCode = col.PromotionCode
+	result.Lines = make([]cart.Line, 0, len(col.Lines))
+	result.CreatedAt = col.CreatedAt
+	result.ModifiedAt = col.ModifiedAt
+	result.Version = col.Version
+
+	for _, line := range col.Lines {
+		result.Lines = append(result.Lines, cart.Line{
+			ItemID:      line.ItemID,
+			Quantity:    line.Quantity,
+			ItemVersion: line.ItemVersion,
+			AddedAt:     line.AddedAt,
+		})
+	}
+
+	return result
+}
+
+//NewMongoDBRepository Generate mongo DB cart repository
+func NewMongoDBRepository(db *mongo.Database) *MongoDBRepository {
+	return &MongoDBRepository{
+		db.Collection("carts"),
+	}
+}
+
+//FindCartByOwner Find cart of given owner. Its return nil if not found
+func (repo *MongoDBRepository) FindCartByOwner(owner string) (*cart.Cart, error) {
+	var col collection
+
+	if err := repo.col.FindOne(context.TODO(), bson.M{"_id": owner}).Decode(&col); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	cart := col.ToCart()
+	return &cart, nil
+}
+
+//InsertCart Insert cart only when the owner has none, using upsert so concurrent insert never fail with duplicate key
+func (repo *MongoDBRepository) InsertCart(cart cart.Cart) error {
+	inserted := bson.M{
+		"$setOnInsert": bson.M{
+			"promotion_code": cart.PromotionCode,
+			"lines":          newLineDocuments(cart.Lines),
+			"created_at":     cart.CreatedAt,
+			"modified_at":    cart.ModifiedAt,
+			"version":        cart.Version,
+		},
+	}
+
+	res, err := repo.col.UpdateOne(context.TODO(), bson.M{"_id": cart.Owner}, inserted, options.Update().SetUpsert(true))
+	if err != nil {
+		return err
+	}
+
+	if res.UpsertedCount == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
+
+//UpdateCart Replace the lines and promotion code of the cart
+func (repo *MongoDBRepository) UpdateCart(cart cart.Cart, currentVersion int) error {
+	filter := bson.M{
+		"_id":     cart.Owner,
+		"version": currentVersion,
+	}
+
+	updated := bson.M{
+		"$set": bson.M{
+			"promotion_code": cart.PromotionCode,
+			"lines":          newLineDocuments(cart.Lines),
+			"modified_at":    cart.ModifiedAt,
+			"version":        cart.Version,
+		},
+	}
+
+	res, err := repo.col.UpdateOne(context.TODO(), filter, updated)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
+
+//DeleteCart Delete the cart of given owner
+func (repo *MongoDBRepository) DeleteCart(owner string, currentVersion int) error {
+	res, err := repo.col.DeleteOne(context.TODO(), bson.M{"_id": owner, "version": currentVersion})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package cart
+
+import (
+	"database/sql"
+	"sample-order/business"
+	"sample-order/business/cart"
+)
+
+//MySQLRepository The implementation of cart.Repository object
+type MySQLRepository struct {
+	db *sql.DB
+}
+
+//NewMySQLRepository Generate MySQL cart repository
+func NewMySQLRepository(db *sql.DB) *MySQLRepository {
+	return &MySQLRepository{
+		db,
+	}
+}
+
+//FindCartByOwner Find cart and its lines of given owner. Its return nil if not found
+func (repo *MySQLRepository) FindCartByOwner(owner string) (*cart.Cart, error) {
+	var cart cart.Cart
+
+	selectQuery := "SELECT owner, promotion_code, created_at, modified_at, version FROM cart WHERE owner = ?"
+
+	err := repo.db.QueryRow(selectQuery, owner).Scan(&cart.Owner, &cart.PromotionCode, &cart.CreatedAt, &cart.ModifiedAt, &cart.Version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if cart.Lines, err = repo.findLines(owner); err != nil {
+		return nil, err
+	}
+
+	return &cart, nil
+}
+
+//InsertCart Insert cart and its lines in single transaction, existing cart of the owner is not replaced
+func (repo *MySQLRepository) InsertCart(cart cart.Cart) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	insertQuery := `INSERT IGNORE INTO cart (
+			owner,
+			promotion_code,
+			created_at,
+			modified_at,
+			version
+		) VALUES (?, ?, ?, ?, ?)`
+
+	res, err := tx.Exec(insertQuery, cart.Owner, cart.PromotionCode, cart.CreatedAt, cart.ModifiedAt, cart.Version)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkAffected(res); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := insertLines(tx, cart); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+//UpdateCart Update the cart promotion code and version and replace its lines in single transaction
+func (repo *MySQLRepository) UpdateCart(cart cart.Cart, currentVersion int) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	updateQuery := `UPDATE cart
+		SET
+			promotion_code = ?,
+			modified_at = ?,
+			version = ?
+		WHERE owner = ? AND version = ?`
+
+	res, err := tx.Exec(updateQuery, cart.PromotionCode, cart.ModifiedAt, cart.Version, cart.Owner, currentVersion)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkAffected(res); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM cart_line WHERE owner = ?", cart.Owner); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := insertLines(tx, cart); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+//DeleteCart Delete the cart, its lines are deleted by the foreign key
+func (repo *MySQLRepository) DeleteCart(owner string, currentVersion int) error {
+	res, err := repo.db.Exec("DELETE FROM cart WHERE owner = ? AND version = ?", owner, currentVersion)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(res)
+}
+
+func (repo *MySQLRepository) findLines(owner string) ([]cart.Line, error) {
+	selectQuery := `SELECT item_id, quantity, item_version, added_at
+		FROM cart_line
+		WHERE owner = ?
+		ORDER BY line_no`
+
+	row, err := repo.db.Query(selectQuery, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	lines := []cart.Line{}
+
+	for row.Next() {
+		var line cart.Line
+
+		if err := row.Scan(&line.ItemID, &line.Quantity, &line.ItemVersion, &line.AddedAt); err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, row.Err()
+}
+
+func insertLines(tx *sql.Tx, cart cart.Cart) error {
+	lineQuery := `INSERT INTO cart_line (
+			owner,
+			line_no,
+			item_id,
+			quantity,
+			item_version,
+			added_at
+		) VALUES (?, ?, ?, ?, ?, ?)`
+
+	for i, line := range cart.Lines {
+		_, err := tx.Exec(lineQuery,
+			cart.Owner,
+			i+1,
+			line.ItemID,
+			line.Quantity,
+			line.ItemVersion,
+			line.AddedAt,
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
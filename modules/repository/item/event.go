@@ -0,0 +1,105 @@
+package item
+
+import (
+	"encoding/json"
+	"sample-order/business/item"
+	"sample-order/business/money"
+	"sample-order/business/outbox"
+	"time"
+)
+
+//aggregateType outbox aggregate type of item events
+const aggregateType = "item"
+
+//eventPayload JSON payload of item event published through the outbox
+type eventPayload struct {
+	ID         string       `json:"id"`
+	Type       string       `json:"type"`
+	ItemID     string       `json:"itemId"`
+	Version    int          `json:"version"`
+	Before     *itemPayload `json:"before"`
+	After      *itemPayload `json:"after"`
+	Actor      string       `json:"actor"`
+	OccurredAt time.Time    `json:"occurredAt"`
+}
+
+type itemPayload struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Tags        []string      `json:"tags"`
+	SalePrice   *moneyPayload `json:"salePrice"`
+	RentalRate  *moneyPayload `json:"rentalRate"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	CreatedBy   string        `json:"createdBy"`
+	ModifiedAt  time.Time     `json:"modifiedAt"`
+	ModifiedBy  string        `json:"modifiedBy"`
+	Version     int           `json:"version"`
+}
+
+type moneyPayload struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func newItemPayload(item *item.Item) *itemPayload {
+	if item == nil {
+		return nil
+	}
+
+	return &itemPayload{
+		item.ID,
+		item.Name,
+		item.Description,
+		item.Tags,
+		newMoneyPayload(item.SalePrice),
+		newMoneyPayload(item.RentalRate),
+		item.CreatedAt,
+		item.CreatedBy,
+		item.ModifiedAt,
+		item.ModifiedBy,
+		item.Version,
+	}
+}
+
+func newMoneyPayload(price *money.Money) *moneyPayload {
+	if price == nil {
+		return nil
+	}
+
+	return &moneyPayload{price.Amount, price.Currency}
+}
+
+//newOutboxMessages encode the events into outbox messages
+func newOutboxMessages(events ...item.Event) ([]outbox.Message, error) {
+	messages := make([]outbox.Message, 0, len(events))
+
+	for _, event := range events {
+		payload, err := json.Marshal(eventPayload{
+			event.ID,
+			string(event.Type),
+			event.ItemID,
+			event.Version,
+			newItemPayload(event.Before),
+			newItemPayload(event.After),
+			event.Actor,
+			event.OccurredAt,
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, outbox.Message{
+			ID:            event.ID,
+			AggregateType: aggregateType,
+			AggregateID:   event.ItemID,
+			EventType:     string(event.Type),
+			Version:       event.Version,
+			Payload:       payload,
+			OccurredAt:    event.OccurredAt,
+		})
+	}
+
+	return messages, nil
+}
@@ -2,18 +2,35 @@ package item
 
 import (
 	"context"
+	"sample-order/business"
 	"sample-order/business/item"
+	"sample-order/business/money"
+	bookingRepo "sample-order/modules/repository/booking"
+	outboxRepo "sample-order/modules/repository/outbox"
+	pricingRepo "sample-order/modules/repository/pricing"
+	stockRepo "sample-order/modules/repository/stock"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 //MongoDBRepository The implementation of item.Repository object
 type MongoDBRepository struct {
-	col *mongo.Collection
+	db           *mongo.Database
+	col          *mongo.Collection
+	tombstoneCol *mongo.Collection
+}
+
+//tombstoneCollection deleted item kept for the change feed, the ID is the item ID
+type tombstoneCollection struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	Version    int                `bson:"version"`
+	ModifiedAt time.Time          `bson:"modified_at"`
+	ModifiedBy string             `bson:"modified_by"`
 }
 
 type collection struct {
@@ -21,6 +38,8 @@ type collection struct {
 	Name        string             `bson:"name"`
 	Description string             `bson:"description"`
 	Tags        []string           `bson:"tags"`
+	SalePrice   *moneyDocument     `bson:"sale_price,omitempty"`
+	RentalRate  *moneyDocument     `bson:"rental_rate,omitempty"`
 	CreatedAt   time.Time          `bson:"created_at"`
 	CreatedBy   string             `bson:"created_by"`
 	ModifiedAt  time.Time          `bson:"modified_at"`
@@ -28,6 +47,27 @@ type collection struct {
 	Version     int                `bson:"version"`
 }
 
+type moneyDocument struct {
+	Amount   int64  `bson:"amount"`
+	Currency string `bson:"currency"`
+}
+
+func newMoneyDocument(price *money.Money) *moneyDocument {
+	if price == nil {
+		return nil
+	}
+
+	return &moneyDocument{price.Amount, price.Currency}
+}
+
+func (doc *moneyDocument) toMoney() *money.Money {
+	if doc == nil {
+		return nil
+	}
+
+	return &money.Money{Amount: doc.Amount, Currency: doc.Currency}
+}
+
 func newCollection(item item.Item) (*collection, error) {
 	objectID, err := primitive.ObjectIDFromHex(item.ID)
 
@@ -40,6 +80,8 @@ func newCollection(item item.Item) (*collection, error) {
 		item.Name,
 		item.Description,
 		item.Tags,
+		newMoneyDocument(item.SalePrice),
+		newMoneyDocument(item.RentalRate),
 		item.CreatedAt,
 		item.CreatedBy,
 		item.ModifiedAt,
@@ -54,6 +96,8 @@ func (col *collection) ToItem() item.Item {
 	item.Name = col.Name
 	item.Description = col.Description
 	item.Tags = col.Tags
+	item.SalePrice = col.SalePrice.toMoney()
+	item.RentalRate = col.RentalRate.toMoney()
 	item.CreatedAt = col.CreatedAt
 	item.CreatedBy = col.CreatedBy
 	item.ModifiedAt = col.ModifiedAt
@@ -66,12 +110,14 @@ func (col *collection) ToItem() item.Item {
 //NewMongoDBRepository Generate mongo DB item repository
 func NewMongoDBRepository(db *mongo.Database) *MongoDBRepository {
 	return &MongoDBRepository{
+		db,
 		db.Collection("items"),
+		db.Collection("item_tombstones"),
 	}
 }
 
 //FindItemByID Find item based on given ID. Its return nil if not found
-func (repo *MongoDBRepository) FindItemByID(ID string) (*item.Item, error) {
+func (repo *MongoDBRepository) FindItemByID(ctx context.Context, ID string) (*item.Item, error) {
 	var col collection
 
 	objectID, err := primitive.ObjectIDFromHex(ID)
@@ -84,7 +130,7 @@ func (repo *MongoDBRepository) FindItemByID(ID string) (*item.Item, error) {
 		"_id": objectID,
 	}
 
-	if err := repo.col.FindOne(context.TODO(), filter).Decode(&col); err != nil {
+	if err := repo.col.FindOne(ctx, filter).Decode(&col); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
 		}
@@ -96,24 +142,40 @@ func (repo *MongoDBRepository) FindItemByID(ID string) (*item.Item, error) {
 	return &item, nil
 }
 
-//FindAllByTag Find all items based on given tag. Its return empty array if not found
-func (repo *MongoDBRepository) FindAllByTag(tag string) ([]item.Item, error) {
+//FindAllByTag Find all items based on given tag and sale price range. Its return empty array if not found
+func (repo *MongoDBRepository) FindAllByTag(ctx context.Context, tag string, priceRange item.PriceRange) ([]item.Item, error) {
 	filter := bson.M{
 		"tags": bson.M{
 			"$all": [1]string{tag},
 		},
 	}
 
-	cursor, err := repo.col.Find(context.TODO(), filter)
+	if !priceRange.IsEmpty() {
+		amount := bson.M{}
+
+		if priceRange.Min != nil {
+			filter["sale_price.currency"] = priceRange.Min.Currency
+			amount["$gte"] = priceRange.Min.Amount
+		}
+
+		if priceRange.Max != nil {
+			filter["sale_price.currency"] = priceRange.Max.Currency
+			amount["$lte"] = priceRange.Max.Amount
+		}
+
+		filter["sale_price.amount"] = amount
+	}
+
+	cursor, err := repo.col.Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(ctx)
 
 	var items []item.Item
 
-	for cursor.Next(context.TODO()) {
+	for cursor.Next(ctx) {
 		var col collection
 		if err = cursor.Decode(&col); err != nil {
 			return nil, err
@@ -125,27 +187,117 @@ func (repo *MongoDBRepository) FindAllByTag(tag string) ([]item.Item, error) {
 	return items, nil
 }
 
-//InsertItem Insert new item into database. Its return item id if success
-func (repo *MongoDBRepository) InsertItem(item item.Item) error {
-	col, err := newCollection(item)
+//StreamItems Iterate items ordered by ID using cursor, only items with given tag when tag is not empty
+func (repo *MongoDBRepository) StreamItems(ctx context.Context, tag string, fn func(item item.Item) error) error {
+	filter := bson.M{}
+	if tag != "" {
+		filter["tags"] = tag
+	}
 
+	findOptions := options.Find().SetSort(bson.M{"_id": 1})
+
+	cursor, err := repo.col.Find(ctx, filter, findOptions)
 	if err != nil {
 		return err
 	}
 
-	_, err = repo.col.InsertOne(context.TODO(), col)
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var col collection
+		if err = cursor.Decode(&col); err != nil {
+			return err
+		}
+
+		if err = fn(col.ToItem()); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+//FindItemsAfterID Find items with ID greater than given ID ordered by ID
+func (repo *MongoDBRepository) FindItemsAfterID(ctx context.Context, afterID string, limit int) ([]item.Item, error) {
+	filter := bson.M{}
+	if afterID != "" {
+		objectID, err := primitive.ObjectIDFromHex(afterID)
+		if err != nil {
+			return nil, err
+		}
+
+		filter["_id"] = bson.M{"$gt": objectID}
+	}
+
+	findOptions := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(int64(limit))
 
+	cursor, err := repo.col.Find(ctx, filter, findOptions)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	defer cursor.Close(ctx)
+
+	var items []item.Item
+
+	for cursor.Next(ctx) {
+		var col collection
+		if err = cursor.Decode(&col); err != nil {
+			return nil, err
+		}
+
+		items = append(items, col.ToItem())
+	}
+
+	return items, cursor.Err()
 }
 
-//UpdateItem Update existing item in database
-func (repo *MongoDBRepository) UpdateItem(item item.Item, currentVersion int) error {
+//InsertItem Insert new item and its event inside a transaction
+func (repo *MongoDBRepository) InsertItem(ctx context.Context, item item.Item, event item.Event) error {
 	col, err := newCollection(item)
+	if err != nil {
+		return err
+	}
 
+	return repo.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		if _, err := repo.col.InsertOne(sessCtx, col); err != nil {
+			return err
+		}
+
+		return repo.insertEvents(sessCtx, event)
+	})
+}
+
+//InsertItems Insert multiple items at once and their events inside a transaction
+func (repo *MongoDBRepository) InsertItems(ctx context.Context, items []item.Item, events []item.Event) error {
+	documents := make([]interface{}, 0, len(items))
+
+	for _, item := range items {
+		col, err := newCollection(item)
+		if err != nil {
+			return err
+		}
+
+		documents = append(documents, col)
+	}
+
+	if len(events) == 0 {
+		_, err := repo.col.InsertMany(ctx, documents)
+		return err
+	}
+
+	return repo.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		if _, err := repo.col.InsertMany(sessCtx, documents); err != nil {
+			return err
+		}
+
+		return repo.insertEvents(sessCtx, events...)
+	})
+}
+
+//UpdateItem Update existing item and insert its event inside a transaction
+func (repo *MongoDBRepository) UpdateItem(ctx context.Context, item item.Item, currentVersion int, event item.Event) error {
+	col, err := newCollection(item)
 	if err != nil {
 		return err
 	}
@@ -159,10 +311,178 @@ func (repo *MongoDBRepository) UpdateItem(item item.Item, currentVersion int) er
 		"$set": col,
 	}
 
-	_, err = repo.col.UpdateOne(context.TODO(), filter, updated)
+	return repo.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		result, err := repo.col.UpdateOne(sessCtx, filter, updated)
+		if err != nil {
+			return err
+		}
+
+		if result.MatchedCount == 0 {
+			return business.ErrZeroAffected
+		}
+
+		return repo.insertEvents(sessCtx, event)
+	})
+}
+
+//DeleteItem Delete item and insert its event inside a transaction
+func (repo *MongoDBRepository) DeleteItem(ctx context.Context, ID string, currentVersion int, event item.Event) error {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return business.ErrZeroAffected
+	}
+
+	return repo.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		result, err := repo.col.DeleteOne(sessCtx, bson.M{"_id": objectID, "version": currentVersion})
+		if err != nil {
+			return err
+		}
+
+		if result.DeletedCount == 0 {
+			return business.ErrZeroAffected
+		}
+
+		if err := repo.checkItemNotInUse(sessCtx, objectID); err != nil {
+			return err
+		}
+
+		//nothing cascade in MongoDB, the rental rule is removed like in MySQL while the history is kept
+		if err := pricingRepo.DeleteMongoDBRule(sessCtx, repo.db, objectID); err != nil {
+			return err
+		}
+
+		tombstone := tombstoneCollection{objectID, currentVersion, event.OccurredAt, event.Actor}
+		replaceOptions := options.Replace().SetUpsert(true)
+
+		if _, err := repo.tombstoneCol.ReplaceOne(sessCtx, bson.M{"_id": objectID}, tombstone, replaceOptions); err != nil {
+			return err
+		}
+
+		return repo.insertEvents(sessCtx, event)
+	})
+}
+
+//FindChangesAfter Scan items and tombstones modified after the token ordered by modified_at then ID and merge them
+func (repo *MongoDBRepository) FindChangesAfter(ctx context.Context, token item.ChangeToken, limit int) ([]item.Change, error) {
+	filter := bson.M{}
+
+	if !token.IsZero() {
+		objectID, err := primitive.ObjectIDFromHex(token.ID)
+		if err != nil {
+			return nil, business.ErrInvalidSpec
+		}
+
+		filter["$or"] = bson.A{
+			bson.M{"modified_at": bson.M{"$gt": token.ModifiedAt}},
+			bson.M{"modified_at": token.ModifiedAt, "_id": bson.M{"$gt": objectID}},
+		}
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "modified_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	var items []collection
+	if err := repo.findAll(ctx, repo.col, filter, findOptions, &items); err != nil {
+		return nil, err
+	}
+
+	var tombstones []tombstoneCollection
+	if err := repo.findAll(ctx, repo.tombstoneCol, filter, findOptions, &tombstones); err != nil {
+		return nil, err
+	}
+
+	changes := make([]item.Change, 0, limit)
+
+	for len(changes) < limit && (len(items) > 0 || len(tombstones) > 0) {
+		if len(tombstones) == 0 || (len(items) > 0 && isBefore(items[0].ModifiedAt, items[0].ID, tombstones[0].ModifiedAt, tombstones[0].ID)) {
+			found := items[0].ToItem()
+			changes = append(changes, item.Change{
+				ItemID:     found.ID,
+				Item:       &found,
+				Version:    found.Version,
+				ModifiedAt: found.ModifiedAt,
+				ModifiedBy: found.ModifiedBy,
+				Token:      item.ChangeToken{ModifiedAt: found.ModifiedAt, ID: found.ID},
+			})
+
+			items = items[1:]
+			continue
+		}
+
+		tombstone := tombstones[0]
+		changes = append(changes, item.Change{
+			ItemID:     tombstone.ID.Hex(),
+			Deleted:    true,
+			Version:    tombstone.Version,
+			ModifiedAt: tombstone.ModifiedAt,
+			ModifiedBy: tombstone.ModifiedBy,
+			Token:      item.ChangeToken{ModifiedAt: tombstone.ModifiedAt, ID: tombstone.ID.Hex()},
+		})
+
+		tombstones = tombstones[1:]
+	}
+
+	return changes, nil
+}
+
+func (repo *MongoDBRepository) findAll(ctx context.Context, col *mongo.Collection, filter bson.M, findOptions *options.FindOptions, results interface{}) error {
+	cursor, err := col.Find(ctx, filter, findOptions)
+	if err != nil {
+		return err
+	}
+
+	return cursor.All(ctx, results)
+}
+
+//checkItemNotInUse Return business.ErrItemInUse if item has stock on hand, active reservation or active booking
+func (repo *MongoDBRepository) checkItemNotInUse(ctx context.Context, objectID primitive.ObjectID) error {
+	stocked, err := stockRepo.IsMongoDBItemStocked(ctx, repo.db, objectID)
 	if err != nil {
 		return err
 	}
 
+	booked, err := bookingRepo.HasMongoDBActiveBooking(ctx, repo.db, objectID)
+	if err != nil {
+		return err
+	}
+
+	if stocked || booked {
+		return business.ErrItemInUse
+	}
+
 	return nil
 }
+
+func (repo *MongoDBRepository) insertEvents(ctx context.Context, events ...item.Event) error {
+	messages, err := newOutboxMessages(events...)
+	if err != nil {
+		return err
+	}
+
+	return outboxRepo.InsertMongoDBMessages(ctx, repo.db, messages)
+}
+
+func (repo *MongoDBRepository) withTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) error) error {
+	session, err := repo.db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+
+	return err
+}
+
+//isBefore Return true if the first document come before the second one in the change feed order
+func isBefore(modifiedAt time.Time, ID primitive.ObjectID, otherModifiedAt time.Time, otherID primitive.ObjectID) bool {
+	if !modifiedAt.Equal(otherModifiedAt) {
+		return modifiedAt.Before(otherModifiedAt)
+	}
+
+	return ID.Hex() < otherID.Hex()
+}
@@ -1,11 +1,18 @@
 package item
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"strings"
+	"time"
 
 	"sample-order/business"
 	"sample-order/business/item"
+	"sample-order/business/money"
+	bookingRepo "sample-order/modules/repository/booking"
+	outboxRepo "sample-order/modules/repository/outbox"
+	stockRepo "sample-order/modules/repository/stock"
 )
 
 //MySQLRepository The implementation of item.Repository object
@@ -13,6 +20,22 @@ type MySQLRepository struct {
 	db *sql.DB
 }
 
+//selectItemQuery base query of item with its tags, the columns must be read by scanItem
+const selectItemQuery = `SELECT id, name, description,
+		sale_price_amount, sale_price_currency, rental_rate_amount, rental_rate_currency,
+		created_at, created_by, modified_at, modified_by, version, COALESCE(tags, "")
+		FROM item i
+		LEFT JOIN (
+			SELECT item_id,
+			GROUP_CONCAT(tag) as tags
+			FROM item_tag GROUP BY item_id
+		)AS it ON i.id = it.item_id`
+
+//rowScanner is satisfied by both sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
 //NewMySQLRepository Generate mongo DB item repository
 func NewMySQLRepository(db *sql.DB) *MySQLRepository {
 	return &MySQLRepository{
@@ -21,26 +44,10 @@ func NewMySQLRepository(db *sql.DB) *MySQLRepository {
 }
 
 //FindItemByID Find item based on given ID. Its return nil if not found
-func (repo *MySQLRepository) FindItemByID(ID string) (*item.Item, error) {
-	var item item.Item
+func (repo *MySQLRepository) FindItemByID(ctx context.Context, ID string) (*item.Item, error) {
+	selectQuery := selectItemQuery + " WHERE i.id = ?"
 
-	selectQuery := `SELECT id, name, description, created_at, created_by, modified_at, modified_by, version, COALESCE(tags, "")
-		FROM item i
-		LEFT JOIN (
-			SELECT item_id, 
-			GROUP_CONCAT(tag) as tags
-			FROM item_tag GROUP BY item_id
-		)AS it ON i.id = it.item_id
-		WHERE i.id = ?`
-
-	var tags string
-	err := repo.db.
-		QueryRow(selectQuery, ID).
-		Scan(
-			&item.ID, &item.Name, &item.Description,
-			&item.CreatedAt, &item.CreatedBy,
-			&item.ModifiedAt, &item.ModifiedBy,
-			&item.Version, &tags)
+	item, err := scanItem(repo.db.QueryRowContext(ctx, selectQuery, ID))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -50,138 +57,155 @@ func (repo *MySQLRepository) FindItemByID(ID string) (*item.Item, error) {
 		return nil, err
 	}
 
-	item.Tags = constructTagArray(tags)
-
-	return &item, nil
+	return item, nil
 }
 
-//FindAllByTag Find all items based on given tag. Its return empty array if not found
-func (repo *MySQLRepository) FindAllByTag(tag string) ([]item.Item, error) {
+//FindAllByTag Find all items based on given tag and sale price range. Its return empty array if not found
+func (repo *MySQLRepository) FindAllByTag(ctx context.Context, tag string, priceRange item.PriceRange) ([]item.Item, error) {
 	//TODO: if feel have a performance issue in tag grouping, move the logic from db to here
-	selectQuery := `SELECT id, name, description, created_at, created_by, modified_at, modified_by, version, COALESCE(tags, "")
-		FROM item i
-		LEFT JOIN (
-			SELECT item_id, 
-			GROUP_CONCAT(tag) as tags
-			FROM item_tag GROUP BY item_id
-		)AS it ON i.id = it.item_id
+	selectQuery := selectItemQuery + `
 		WHERE i.id IN (
 			SELECT item_id
 			FROM item_tag
-			WHERE tag = ?	
+			WHERE tag = ?
 		)`
 
-	row, err := repo.db.Query(selectQuery, tag)
-	if err != nil {
-		return nil, err
+	args := []interface{}{tag}
+
+	if priceRange.Min != nil {
+		selectQuery += " AND i.sale_price_currency = ? AND i.sale_price_amount >= ?"
+		args = append(args, priceRange.Min.Currency, priceRange.Min.Amount)
 	}
 
-	defer row.Close()
+	if priceRange.Max != nil {
+		selectQuery += " AND i.sale_price_currency = ? AND i.sale_price_amount <= ?"
+		args = append(args, priceRange.Max.Currency, priceRange.Max.Amount)
+	}
 
-	var items []item.Item
+	return repo.queryItems(ctx, selectQuery, args...)
+}
 
-	for row.Next() {
-		var item item.Item
-		var tags string
+//StreamItems Iterate items ordered by ID using rows, only items with given tag when tag is not empty
+func (repo *MySQLRepository) StreamItems(ctx context.Context, tag string, fn func(item item.Item) error) error {
+	selectQuery := selectItemQuery
+
+	var args []interface{}
+	if tag != "" {
+		selectQuery += " WHERE i.id IN (SELECT item_id FROM item_tag WHERE tag = ?)"
+		args = append(args, tag)
+	}
 
-		err := row.Scan(
-			&item.ID, &item.Name, &item.Description,
-			&item.CreatedAt, &item.CreatedBy,
-			&item.ModifiedAt, &item.ModifiedBy,
-			&item.Version, &tags)
+	selectQuery += " ORDER BY i.id"
 
+	row, err := repo.db.QueryContext(ctx, selectQuery, args...)
+	if err != nil {
+		return err
+	}
+
+	defer row.Close()
+
+	for row.Next() {
+		item, err := scanItem(row)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		item.Tags = constructTagArray(tags)
-		items = append(items, item)
+		if err = fn(*item); err != nil {
+			return err
+		}
 	}
 
-	if err != nil {
-		return nil, err
-	}
+	return row.Err()
+}
 
-	return items, nil
+//FindItemsAfterID Find items with ID greater than given ID ordered by ID
+func (repo *MySQLRepository) FindItemsAfterID(ctx context.Context, afterID string, limit int) ([]item.Item, error) {
+	selectQuery := selectItemQuery + `
+		WHERE i.id > ?
+		ORDER BY i.id
+		LIMIT ?`
+
+	return repo.queryItems(ctx, selectQuery, afterID, limit)
 }
 
-//InsertItem Insert new item into database. Its return item id if success
-func (repo *MySQLRepository) InsertItem(item item.Item) error {
-	tx, err := repo.db.Begin()
+//InsertItem Insert new item and its event into database in single transaction
+func (repo *MySQLRepository) InsertItem(ctx context.Context, item item.Item, event item.Event) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	itemQuery := `INSERT INTO item (
-			id, 
-			name, 
-			description, 
-			created_at, 
-			created_by, 
-			modified_at, 
-			modified_by,
-			version
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	if err = insertItem(ctx, tx, item); err != nil {
+		tx.Rollback()
+		return err
+	}
 
-	if err != nil {
+	if err = insertEvents(tx, event); err != nil {
+		tx.Rollback()
 		return err
 	}
 
-	_, err = tx.Exec(itemQuery,
-		item.ID,
-		item.Name,
-		item.Description,
-		item.CreatedAt,
-		item.CreatedBy,
-		item.ModifiedAt,
-		item.ModifiedBy,
-		item.Version,
-	)
+	err = tx.Commit()
 
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
 
-	tagQuery := "INSERT INTO item_tag (item_id, tag) VALUES (?, ?)"
+	return nil
+}
 
-	for _, tag := range item.Tags {
-		_, err = tx.Exec(tagQuery, item.ID, tag)
+//InsertItems Insert multiple items and their events into database in single transaction
+func (repo *MySQLRepository) InsertItems(ctx context.Context, items []item.Item, events []item.Event) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
 
-		if err != nil {
+	for _, item := range items {
+		if err = insertItem(ctx, tx, item); err != nil {
 			tx.Rollback()
 			return err
 		}
 	}
 
-	err = tx.Commit()
-
-	if err != nil {
+	if err = insertEvents(tx, events...); err != nil {
+		tx.Rollback()
 		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
-//UpdateItem Update existing item in database
-func (repo *MySQLRepository) UpdateItem(item item.Item, currentVersion int) error {
-	tx, err := repo.db.Begin()
+//UpdateItem Update existing item and insert its event in single transaction
+func (repo *MySQLRepository) UpdateItem(ctx context.Context, item item.Item, currentVersion int, event item.Event) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	itemInsertQuery := `UPDATE item 
+	saleAmount, saleCurrency := moneyColumns(item.SalePrice)
+	rentalAmount, rentalCurrency := moneyColumns(item.RentalRate)
+
+	itemInsertQuery := `UPDATE item
 		SET
 			name = ?,
 			description = ?,
+			sale_price_amount = ?,
+			sale_price_currency = ?,
+			rental_rate_amount = ?,
+			rental_rate_currency = ?,
 			modified_at = ?,
 			modified_by = ?,
 			version = ?
 		WHERE id = ? AND version = ?`
 
-	res, err := tx.Exec(itemInsertQuery,
+	res, err := tx.ExecContext(ctx, itemInsertQuery,
 		item.Name,
 		item.Description,
+		saleAmount,
+		saleCurrency,
+		rentalAmount,
+		rentalCurrency,
 		item.ModifiedAt,
 		item.ModifiedBy,
 		item.Version,
@@ -209,7 +233,7 @@ func (repo *MySQLRepository) UpdateItem(item item.Item, currentVersion int) erro
 	//TODO: maybe better if we only delete the record that we need to delete
 	//add logic slice to find which deleted and which want to added
 	tagDeleteQuery := "DELETE FROM item_tag WHERE item_id = ?"
-	_, err = tx.Exec(tagDeleteQuery, item.ID)
+	_, err = tx.ExecContext(ctx, tagDeleteQuery, item.ID)
 
 	if err != nil {
 		tx.Rollback()
@@ -219,7 +243,7 @@ func (repo *MySQLRepository) UpdateItem(item item.Item, currentVersion int) erro
 	tagUpsertQuery := "INSERT INTO item_tag (item_id, tag) VALUES (?, ?)"
 
 	for _, tag := range item.Tags {
-		_, err = tx.Exec(tagUpsertQuery, item.ID, tag)
+		_, err = tx.ExecContext(ctx, tagUpsertQuery, item.ID, tag)
 
 		if err != nil {
 			tx.Rollback()
@@ -227,6 +251,16 @@ func (repo *MySQLRepository) UpdateItem(item item.Item, currentVersion int) erro
 		}
 	}
 
+	if err = replaceChange(ctx, tx, item.ID, item.Version, false, item.ModifiedAt, item.ModifiedBy); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = insertEvents(tx, event); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	err = tx.Commit()
 
 	if err != nil {
@@ -236,6 +270,279 @@ func (repo *MySQLRepository) UpdateItem(item item.Item, currentVersion int) erro
 	return nil
 }
 
+//DeleteItem Delete item and insert its event in single transaction, its tags and rule are deleted by the foreign key.
+//Its stock, movements and bookings are kept as history, the stock and booking writes lock the item row so they wait for the delete
+func (repo *MySQLRepository) DeleteItem(ctx context.Context, ID string, currentVersion int, event item.Event) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM item WHERE id = ? AND version = ?", ID, currentVersion)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if affected == 0 {
+		tx.Rollback()
+		return business.ErrZeroAffected
+	}
+
+	//the deleted row stay locked, so no stock or booking can be added until the check is done
+	if err = checkItemNotInUse(ctx, tx, ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = replaceChange(ctx, tx, ID, currentVersion, true, event.OccurredAt, event.Actor); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = insertEvents(tx, event); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+//FindChangesAfter Find changes with seq greater than the token ordered by seq, the items are read after the changes
+//so item modified in the meantime is returned with its newer state and its change is returned again later,
+//item deleted in the meantime has no state until its tombstone is returned
+func (repo *MySQLRepository) FindChangesAfter(ctx context.Context, token item.ChangeToken, limit int) ([]item.Change, error) {
+	selectQuery := `SELECT seq, item_id, version, deleted, modified_at, modified_by
+		FROM item_change
+		WHERE seq > ?
+		ORDER BY seq
+		LIMIT ?`
+
+	row, err := repo.db.QueryContext(ctx, selectQuery, token.Seq, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var changes []item.Change
+	var itemIDs []interface{}
+
+	for row.Next() {
+		var change item.Change
+
+		err = row.Scan(&change.Token.Seq, &change.ItemID, &change.Version, &change.Deleted, &change.ModifiedAt, &change.ModifiedBy)
+		if err != nil {
+			return nil, err
+		}
+
+		change.Token.ModifiedAt = change.ModifiedAt
+		change.Token.ID = change.ItemID
+
+		if !change.Deleted {
+			itemIDs = append(itemIDs, change.ItemID)
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err = row.Err(); err != nil || len(itemIDs) == 0 {
+		return changes, err
+	}
+
+	items, err := repo.queryItems(ctx, selectItemQuery+" WHERE i.id IN (?"+strings.Repeat(", ?", len(itemIDs)-1)+")", itemIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	itemByID := make(map[string]item.Item, len(items))
+	for _, item := range items {
+		itemByID[item.ID] = item
+	}
+
+	for i := range changes {
+		if item, ok := itemByID[changes[i].ItemID]; ok {
+			changes[i].Item = &item
+		}
+	}
+
+	return changes, nil
+}
+
+func (repo *MySQLRepository) queryItems(ctx context.Context, query string, args ...interface{}) ([]item.Item, error) {
+	row, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var items []item.Item
+
+	for row.Next() {
+		item, err := scanItem(row)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, *item)
+	}
+
+	return items, row.Err()
+}
+
+func insertItem(ctx context.Context, tx *sql.Tx, item item.Item) error {
+	saleAmount, saleCurrency := moneyColumns(item.SalePrice)
+	rentalAmount, rentalCurrency := moneyColumns(item.RentalRate)
+
+	itemQuery := `INSERT INTO item (
+			id,
+			name,
+			description,
+			sale_price_amount,
+			sale_price_currency,
+			rental_rate_amount,
+			rental_rate_currency,
+			created_at,
+			created_by,
+			modified_at,
+			modified_by,
+			version
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.ExecContext(ctx, itemQuery,
+		item.ID,
+		item.Name,
+		item.Description,
+		saleAmount,
+		saleCurrency,
+		rentalAmount,
+		rentalCurrency,
+		item.CreatedAt,
+		item.CreatedBy,
+		item.ModifiedAt,
+		item.ModifiedBy,
+		item.Version,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	tagQuery := "INSERT INTO item_tag (item_id, tag) VALUES (?, ?)"
+
+	for _, tag := range item.Tags {
+		_, err = tx.ExecContext(ctx, tagQuery, item.ID, tag)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return replaceChange(ctx, tx, item.ID, item.Version, false, item.ModifiedAt, item.ModifiedBy)
+}
+
+//replaceChange replace the change row of the item, the new row get the next seq so the item move into the end of change feed.
+//AUTO_INCREMENT is taken when the statement run, so transaction which commit later could store lower seq than the change
+//already read by clients. The seq is taken from the counter row instead, its lock is held until commit so the next writer
+//wait for it and the seq follow the commit order
+func replaceChange(ctx context.Context, tx *sql.Tx, itemID string, version int, deleted bool, modifiedAt time.Time, modifiedBy string) error {
+	//LAST_INSERT_ID keep the new value for this connection, which is the one of the transaction
+	seqQuery := "UPDATE item_change_seq SET seq = LAST_INSERT_ID(seq + 1) WHERE id = 1"
+
+	result, err := tx.ExecContext(ctx, seqQuery)
+	if err != nil {
+		return err
+	}
+
+	//without the counter row LAST_INSERT_ID would keep the value of other statement
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return errors.New("item_change_seq has no counter row, apply the migrations")
+	}
+
+	changeQuery := `REPLACE INTO item_change (seq, item_id, version, deleted, modified_at, modified_by)
+		VALUES (LAST_INSERT_ID(), ?, ?, ?, ?, ?)`
+
+	_, err = tx.ExecContext(ctx, changeQuery, itemID, version, deleted, modifiedAt, modifiedBy)
+	return err
+}
+
+//checkItemNotInUse Return business.ErrItemInUse if item has stock on hand, active reservation or active booking
+func checkItemNotInUse(ctx context.Context, tx *sql.Tx, ID string) error {
+	stocked, err := stockRepo.IsMySQLItemStocked(ctx, tx, ID)
+	if err != nil {
+		return err
+	}
+
+	booked, err := bookingRepo.HasMySQLActiveBooking(ctx, tx, ID)
+	if err != nil {
+		return err
+	}
+
+	if stocked || booked {
+		return business.ErrItemInUse
+	}
+
+	return nil
+}
+
+func insertEvents(tx *sql.Tx, events ...item.Event) error {
+	messages, err := newOutboxMessages(events...)
+	if err != nil {
+		return err
+	}
+
+	return outboxRepo.InsertMySQLMessages(tx, messages)
+}
+
+func scanItem(scanner rowScanner) (*item.Item, error) {
+	var item item.Item
+	var tags string
+	var saleAmount, rentalAmount sql.NullInt64
+	var saleCurrency, rentalCurrency sql.NullString
+
+	err := scanner.Scan(
+		&item.ID, &item.Name, &item.Description,
+		&saleAmount, &saleCurrency, &rentalAmount, &rentalCurrency,
+		&item.CreatedAt, &item.CreatedBy,
+		&item.ModifiedAt, &item.ModifiedBy,
+		&item.Version, &tags)
+
+	if err != nil {
+		return nil, err
+	}
+
+	item.Tags = constructTagArray(tags)
+	item.SalePrice = constructMoney(saleAmount, saleCurrency)
+	item.RentalRate = constructMoney(rentalAmount, rentalCurrency)
+
+	return &item, nil
+}
+
+func moneyColumns(price *money.Money) (sql.NullInt64, sql.NullString) {
+	if price == nil {
+		return sql.NullInt64{}, sql.NullString{}
+	}
+
+	return sql.NullInt64{Int64: price.Amount, Valid: true}, sql.NullString{String: price.Currency, Valid: true}
+}
+
+func constructMoney(amount sql.NullInt64, currency sql.NullString) *money.Money {
+	if !amount.Valid || !currency.Valid {
+		return nil
+	}
+
+	return &money.Money{Amount: amount.Int64, Currency: currency.String}
+}
+
 func constructTagArray(tags string) []string {
 	if tags == "" {
 		return make([]string, 0)
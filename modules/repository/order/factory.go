@@ -0,0 +1,19 @@
+package order
+
+import (
+	"sample-order/business/order"
+	"sample-order/util"
+)
+
+//RepositoryFactory Will return business.order.Repository based on active database connection
+func RepositoryFactory(dbCon *util.DatabaseConnection) order.Repository {
+	var orderRepo order.Repository
+
+	if dbCon.Driver == util.MySQL {
+		orderRepo = NewMySQLRepository(dbCon.MySQLDB)
+	} else if dbCon.Driver == util.MongoDB {
+		orderRepo = NewMongoDBRepository(dbCon.MongoDB)
+	}
+
+	return orderRepo
+}
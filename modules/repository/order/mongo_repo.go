@@ -0,0 +1,285 @@
+package order
+
+import (
+	"context"
+	"sample-order/business"
+	"sample-order/business/money"
+	"sample-order/business/order"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//MongoDBRepository The implementation of order.Repository object
+type MongoDBRepository struct {
+	col *mongo.Collection
+}
+
+//collection order with embedded lines, every amount is in minor unit of the order currency
+type collection struct {
+	ID          primitive.ObjectID   `bson:"_id"`
+	Customer    string               `bson:"customer"`
+	Lines       []lineDocument       `bson:"lines"`
+	Currency    string               `bson:"currency"`
+	Subtotal    int64                `bson:"subtotal"`
+	Discount    int64                `bson:"discount"`
+	Promotion   string               `bson:"promotion_code"`
+	Total       int64                `bson:"total"`
+	Status      string               `bson:"status"`
+	Transitions []transitionDocument `bson:"transitions"`
+	CreatedAt   time.Time            `bson:"created_at"`
+	CreatedBy   string               `bson:"created_by"`
+	ModifiedAt  time.Time            `bson:"modified_at"`
+	ModifiedBy  string               `bson:"modified_by"`
+	Version     int                  `bson:"version"`
+}
+
+type lineDocument struct {
+	ItemID    string `bson:"item_id"`
+	ItemName  string `bson:"item_name"`
+	Quantity  int    `bson:"quantity"`
+	UnitPrice int64  `bson:"unit_price"`
+	Subtotal  int64  `bson:"subtotal"`
+}
+
+type transitionDocument struct {
+	From  string    `bson:"from"`
+	To    string    `bson:"to"`
+	Actor string    `bson:"actor"`
+	At    time.Time `bson:"at"`
+}
+
+func newTransitionDocument(transition order.Transition) transitionDocument {
+	return transitionDocument{
+		string(transition.From),
+		string(transition.To),
+		transition.Actor,
+		transition.At,
+	}
+}
+
+func newCollection(order order.Order) (*collection, error) {
+	objectID, err := primitive.ObjectIDFromHex(order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []lineDocument
+	for _, line := range order.Lines {
+		lines = append(lines, lineDocument{
+			line.ItemID,
+			line.ItemName,
+			line.Quantity,
+			line.UnitPrice.Amount,
+			line.Subtotal.Amount,
+		})
+	}
+
+	transitions := []transitionDocument{}
+	for _, transition := range order.Transitions {
+		transitions = append(transitions, newTransitionDocument(transition))
+	}
+
+	return &collection{
+		objectID,
+		order.Customer,
+		lines,
+		order.Total.Currency,
+		order.Subtotal.Amount,
+		order.Discount.Amount,
+		order.PromotionCode,
+		order.Total.Amount,
+		string(order.Status),
+		transitions,
+		order.CreatedAt,
+		order.CreatedBy,
+		order.ModifiedAt,
+		order.ModifiedBy,
+		order.Version,
+	}, nil
+}
+
+func (col *collection) ToOrder() order.Order {
+	var lines []order.Line
+	for _, line := range col.Lines {
+		lines = append(lines, order.Line{
+			ItemID:    line.ItemID,
+			ItemName:  line.ItemName,
+			Quantity:  line.Quantity,
+			UnitPrice: money.Money{Amount: line.UnitPrice, Currency: col.Currency},
+			Subtotal:  money.Money{Amount: line.Subtotal, Currency: col.Currency},
+		})
+	}
+
+	var transitions []order.Transition
+	for _, transition := range col.Transitions {
+		transitions = append(transitions, order.Transition{
+			From:  order.Status(transition.From),
+			To:    order.Status(transition.To),
+			Actor: transition.Actor,
+			At:    transition.At,
+		})
+	}
+
+	return order.Order{
+		ID:            col.ID.Hex(),
+		Customer:      col.Customer,
+		Lines:         lines,
+		Subtotal:      money.Money{Amount: col.Subtotal, Currency: col.Currency},
+		Discount:      money.Money{Amount: col.Discount, Currency: col.Currency},
+		PromotionCode: col.Promotion,
+		Total:         money.Money{Amount: col.Total, Currency: col.Currency},
+		Status:        order.Status(col.Status),
+		Transitions:   transitions,
+		CreatedAt:     col.CreatedAt,
+		CreatedBy:     col.CreatedBy,
+		ModifiedAt:    col.ModifiedAt,
+		ModifiedBy:    col.ModifiedBy,
+		Version:       col.Version,
+	}
+}
+
+//NewMongoDBRepository Generate mongo DB order repository
+func NewMongoDBRepository(db *mongo.Database) *MongoDBRepository {
+	return &MongoDBRepository{
+		db.Collection("orders"),
+	}
+}
+
+//FindOrderByID Find order based on given ID. Its return nil if not found
+func (repo *MongoDBRepository) FindOrderByID(ID string) (*order.Order, error) {
+	var col collection
+
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		//if cannot be convert means that ID will be never found
+		return nil, nil
+	}
+
+	if err := repo.col.FindOne(context.TODO(), bson.M{"_id": objectID}).Decode(&col); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	order := col.ToOrder()
+	return &order, nil
+}
+
+//FindOrdersByCustomer Find orders of given customer from the newest
+func (repo *MongoDBRepository) FindOrdersByCustomer(customer string) ([]order.Order, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := repo.col.Find(context.TODO(), bson.M{"customer": customer}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	var orders []order.Order
+
+	for cursor.Next(context.TODO()) {
+		var col collection
+		if err = cursor.Decode(&col); err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, col.ToOrder())
+	}
+
+	return orders, cursor.Err()
+}
+
+//InsertOrder Insert order together with its lines in single document
+func (repo *MongoDBRepository) InsertOrder(order order.Order) error {
+	col, err := newCollection(order)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.col.InsertOne(context.TODO(), col)
+	return err
+}
+
+//RevertOrder Update status of existing order and keep only its given transitions
+func (repo *MongoDBRepository) RevertOrder(order order.Order, currentVersion int) error {
+	objectID, err := primitive.ObjectIDFromHex(order.ID)
+	if err != nil {
+		return business.ErrZeroAffected
+	}
+
+	filter := bson.M{
+		"_id":     objectID,
+		"version": currentVersion,
+	}
+
+	transitions := make([]transitionDocument, 0, len(order.Transitions))
+	for _, transition := range order.Transitions {
+		transitions = append(transitions, newTransitionDocument(transition))
+	}
+
+	updated := bson.M{
+		"$set": bson.M{
+			"status":      string(order.Status),
+			"modified_at": order.ModifiedAt,
+			"modified_by": order.ModifiedBy,
+			"version":     order.Version,
+			"transitions": transitions,
+		},
+	}
+
+	result, err := repo.col.UpdateOne(context.TODO(), filter, updated)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
+
+//UpdateOrder Update status of existing order and push its latest transition,
+//the lines never change after the order is created
+func (repo *MongoDBRepository) UpdateOrder(order order.Order, currentVersion int) error {
+	objectID, err := primitive.ObjectIDFromHex(order.ID)
+	if err != nil {
+		return business.ErrZeroAffected
+	}
+
+	filter := bson.M{
+		"_id":     objectID,
+		"version": currentVersion,
+	}
+
+	updated := bson.M{
+		"$set": bson.M{
+			"status":      string(order.Status),
+			"modified_at": order.ModifiedAt,
+			"modified_by": order.ModifiedBy,
+			"version":     order.Version,
+		},
+	}
+
+	if len(order.Transitions) > 0 {
+		updated["$push"] = bson.M{"transitions": newTransitionDocument(order.Transitions[len(order.Transitions)-1])}
+	}
+
+	result, err := repo.col.UpdateOne(context.TODO(), filter, updated)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
@@ -0,0 +1,348 @@
+package order
+
+import (
+	"database/sql"
+	"sample-order/business"
+	"sample-order/business/order"
+)
+
+//MySQLRepository The implementation of order.Repository object
+type MySQLRepository struct {
+	db *sql.DB
+}
+
+//selectOrderQuery base query of order without lines, the columns must be read by scanOrder
+const selectOrderQuery = `SELECT id, customer, currency, subtotal_amount, discount_amount, promotion_code, total_amount, status,
+		created_at, created_by, modified_at, modified_by, version
+		FROM sales_order`
+
+//rowScanner is satisfied by both sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+//NewMySQLRepository Generate MySQL order repository
+func NewMySQLRepository(db *sql.DB) *MySQLRepository {
+	return &MySQLRepository{
+		db,
+	}
+}
+
+//FindOrderByID Find order and its lines based on given ID. Its return nil if not found
+func (repo *MySQLRepository) FindOrderByID(ID string) (*order.Order, error) {
+	order, err := scanOrder(repo.db.QueryRow(selectOrderQuery+" WHERE id = ?", ID))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if err = repo.loadDetails(order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+//FindOrdersByCustomer Find orders and its lines of given customer from the newest
+func (repo *MySQLRepository) FindOrdersByCustomer(customer string) ([]order.Order, error) {
+	selectQuery := selectOrderQuery + " WHERE customer = ? ORDER BY created_at DESC, id DESC"
+
+	row, err := repo.db.Query(selectQuery, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var orders []order.Order
+
+	for row.Next() {
+		order, err := scanOrder(row)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, *order)
+	}
+
+	if err = row.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range orders {
+		if err = repo.loadDetails(&orders[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return orders, nil
+}
+
+//InsertOrder Insert order and its lines in single transaction
+func (repo *MySQLRepository) InsertOrder(order order.Order) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	orderQuery := `INSERT INTO sales_order (
+			id,
+			customer,
+			currency,
+			subtotal_amount,
+			discount_amount,
+			promotion_code,
+			total_amount,
+			status,
+			created_at,
+			created_by,
+			modified_at,
+			modified_by,
+			version
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(orderQuery,
+		order.ID,
+		order.Customer,
+		order.Total.Currency,
+		order.Subtotal.Amount,
+		order.Discount.Amount,
+		order.PromotionCode,
+		order.Total.Amount,
+		order.Status,
+		order.CreatedAt,
+		order.CreatedBy,
+		order.ModifiedAt,
+		order.ModifiedBy,
+		order.Version,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	lineQuery := `INSERT INTO sales_order_line (
+			order_id,
+			line_no,
+			item_id,
+			item_name,
+			quantity,
+			unit_price_amount,
+			subtotal_amount
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	for i, line := range order.Lines {
+		_, err = tx.Exec(lineQuery,
+			order.ID,
+			i+1,
+			line.ItemID,
+			line.ItemName,
+			line.Quantity,
+			line.UnitPrice.Amount,
+			line.Subtotal.Amount,
+		)
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+//UpdateOrder Update status of existing order and insert its latest transition in single transaction,
+//the lines never change after the order is created
+func (repo *MySQLRepository) UpdateOrder(order order.Order, currentVersion int) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := updateStatus(tx, order, currentVersion); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(order.Transitions) > 0 {
+		transition := order.Transitions[len(order.Transitions)-1]
+		transitionQuery := `INSERT INTO sales_order_transition (
+				order_id,
+				seq,
+				from_status,
+				to_status,
+				actor,
+				created_at
+			) VALUES (?, ?, ?, ?, ?, ?)`
+
+		_, err = tx.Exec(transitionQuery,
+			order.ID,
+			len(order.Transitions),
+			transition.From,
+			transition.To,
+			transition.Actor,
+			transition.At,
+		)
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+//RevertOrder Update status of existing order and delete the transitions after its last one in single transaction
+func (repo *MySQLRepository) RevertOrder(order order.Order, currentVersion int) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := updateStatus(tx, order, currentVersion); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM sales_order_transition WHERE order_id = ? AND seq > ?", order.ID, len(order.Transitions))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+//updateStatus update status of the order with matching version, will return business.ErrZeroAffected otherwise
+func updateStatus(tx *sql.Tx, order order.Order, currentVersion int) error {
+	updateQuery := `UPDATE sales_order
+		SET
+			status = ?,
+			modified_at = ?,
+			modified_by = ?,
+			version = ?
+		WHERE id = ? AND version = ?`
+
+	res, err := tx.Exec(updateQuery,
+		order.Status,
+		order.ModifiedAt,
+		order.ModifiedBy,
+		order.Version,
+		order.ID,
+		currentVersion,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
+
+func (repo *MySQLRepository) loadDetails(order *order.Order) error {
+	var err error
+
+	if order.Lines, err = repo.findLines(order.ID, order.Total.Currency); err != nil {
+		return err
+	}
+
+	order.Transitions, err = repo.findTransitions(order.ID)
+	return err
+}
+
+func (repo *MySQLRepository) findTransitions(orderID string) ([]order.Transition, error) {
+	selectQuery := `SELECT from_status, to_status, actor, created_at
+		FROM sales_order_transition
+		WHERE order_id = ?
+		ORDER BY seq`
+
+	row, err := repo.db.Query(selectQuery, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var transitions []order.Transition
+
+	for row.Next() {
+		var transition order.Transition
+
+		if err := row.Scan(&transition.From, &transition.To, &transition.Actor, &transition.At); err != nil {
+			return nil, err
+		}
+
+		transitions = append(transitions, transition)
+	}
+
+	return transitions, row.Err()
+}
+
+func (repo *MySQLRepository) findLines(orderID string, currency string) ([]order.Line, error) {
+	selectQuery := `SELECT item_id, item_name, quantity, unit_price_amount, subtotal_amount
+		FROM sales_order_line
+		WHERE order_id = ?
+		ORDER BY line_no`
+
+	row, err := repo.db.Query(selectQuery, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var lines []order.Line
+
+	for row.Next() {
+		var line order.Line
+
+		err := row.Scan(&line.ItemID, &line.ItemName, &line.Quantity, &line.UnitPrice.Amount, &line.Subtotal.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		line.UnitPrice.Currency = currency
+		line.Subtotal.Currency = currency
+		lines = append(lines, line)
+	}
+
+	return lines, row.Err()
+}
+
+func scanOrder(scanner rowScanner) (*order.Order, error) {
+	var order order.Order
+
+	err := scanner.Scan(
+		&order.ID, &order.Customer,
+		&order.Total.Currency, &order.Subtotal.Amount,
+		&order.Discount.Amount, &order.PromotionCode,
+		&order.Total.Amount,
+		&order.Status,
+		&order.CreatedAt, &order.CreatedBy,
+		&order.ModifiedAt, &order.ModifiedBy,
+		&order.Version)
+
+	if err != nil {
+		return nil, err
+	}
+
+	order.Subtotal.Currency = order.Total.Currency
+	order.Discount.Currency = order.Total.Currency
+
+	return &order, nil
+}
@@ -0,0 +1,19 @@
+package outbox
+
+import (
+	"sample-order/business/outbox"
+	"sample-order/util"
+)
+
+//RepositoryFactory Will return business.outbox.Repository based on active database connection
+func RepositoryFactory(dbCon *util.DatabaseConnection) outbox.Repository {
+	var outboxRepo outbox.Repository
+
+	if dbCon.Driver == util.MySQL {
+		outboxRepo = NewMySQLRepository(dbCon.MySQLDB)
+	} else if dbCon.Driver == util.MongoDB {
+		outboxRepo = NewMongoDBRepository(dbCon.MongoDB)
+	}
+
+	return outboxRepo
+}
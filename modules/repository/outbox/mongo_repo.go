@@ -0,0 +1,136 @@
+package outbox
+
+import (
+	"context"
+	"sample-order/business/outbox"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//MongoDBRepository The implementation of outbox.Repository object
+type MongoDBRepository struct {
+	col *mongo.Collection
+}
+
+//collection outbox message, the payload is kept as JSON string
+type collection struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	AggregateType string             `bson:"aggregate_type"`
+	AggregateID   string             `bson:"aggregate_id"`
+	EventType     string             `bson:"event_type"`
+	Version       int                `bson:"version"`
+	Payload       string             `bson:"payload"`
+	OccurredAt    time.Time          `bson:"occurred_at"`
+	Attempts      int                `bson:"attempts"`
+	LastError     string             `bson:"last_error"`
+	PublishedAt   *time.Time         `bson:"published_at"`
+}
+
+func (col *collection) ToMessage() outbox.Message {
+	return outbox.Message{
+		ID:            col.ID.Hex(),
+		AggregateType: col.AggregateType,
+		AggregateID:   col.AggregateID,
+		EventType:     col.EventType,
+		Version:       col.Version,
+		Payload:       []byte(col.Payload),
+		OccurredAt:    col.OccurredAt,
+		Attempts:      col.Attempts,
+		LastError:     col.LastError,
+		PublishedAt:   col.PublishedAt,
+	}
+}
+
+//NewMongoDBRepository Generate mongo DB outbox repository
+func NewMongoDBRepository(db *mongo.Database) *MongoDBRepository {
+	return &MongoDBRepository{
+		db.Collection("outbox"),
+	}
+}
+
+//InsertMongoDBMessages Insert messages using the session context of the transaction which produce them
+func InsertMongoDBMessages(ctx context.Context, db *mongo.Database, messages []outbox.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, 0, len(messages))
+
+	for _, message := range messages {
+		objectID, err := primitive.ObjectIDFromHex(message.ID)
+		if err != nil {
+			return err
+		}
+
+		documents = append(documents, collection{
+			ID:            objectID,
+			AggregateType: message.AggregateType,
+			AggregateID:   message.AggregateID,
+			EventType:     message.EventType,
+			Version:       message.Version,
+			Payload:       string(message.Payload),
+			OccurredAt:    message.OccurredAt,
+		})
+	}
+
+	_, err := db.Collection("outbox").InsertMany(ctx, documents)
+	return err
+}
+
+//FindPendingMessages Find unpublished messages from the oldest
+func (repo *MongoDBRepository) FindPendingMessages(limit int) ([]outbox.Message, error) {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := repo.col.Find(context.TODO(), bson.M{"published_at": nil}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	messages := []outbox.Message{}
+
+	for cursor.Next(context.TODO()) {
+		var col collection
+		if err = cursor.Decode(&col); err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, col.ToMessage())
+	}
+
+	return messages, cursor.Err()
+}
+
+//MarkPublished Set the published time of the message
+func (repo *MongoDBRepository) MarkPublished(ID string, publishedAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.col.UpdateOne(context.TODO(), bson.M{"_id": objectID}, bson.M{"$set": bson.M{"published_at": publishedAt}})
+	return err
+}
+
+//MarkFailed Increase the attempts of the message and keep the failure reason
+func (repo *MongoDBRepository) MarkFailed(ID string, reason string) error {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	updated := bson.M{
+		"$inc": bson.M{"attempts": 1},
+		"$set": bson.M{"last_error": reason},
+	}
+
+	_, err = repo.col.UpdateOne(context.TODO(), bson.M{"_id": objectID}, updated)
+	return err
+}
@@ -0,0 +1,104 @@
+package outbox
+
+import (
+	"database/sql"
+	"sample-order/business/outbox"
+	"time"
+)
+
+//maxErrorLength size of last_error column
+const maxErrorLength = 1000
+
+//MySQLRepository The implementation of outbox.Repository object
+type MySQLRepository struct {
+	db *sql.DB
+}
+
+//NewMySQLRepository Generate MySQL outbox repository
+func NewMySQLRepository(db *sql.DB) *MySQLRepository {
+	return &MySQLRepository{
+		db,
+	}
+}
+
+//InsertMySQLMessages Insert messages using the transaction of the change which produce them
+func InsertMySQLMessages(tx *sql.Tx, messages []outbox.Message) error {
+	insertQuery := `INSERT INTO outbox (
+			id,
+			aggregate_type,
+			aggregate_id,
+			event_type,
+			version,
+			payload,
+			occurred_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	for _, message := range messages {
+		_, err := tx.Exec(insertQuery,
+			message.ID,
+			message.AggregateType,
+			message.AggregateID,
+			message.EventType,
+			message.Version,
+			message.Payload,
+			message.OccurredAt,
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//FindPendingMessages Find unpublished messages from the oldest
+func (repo *MySQLRepository) FindPendingMessages(limit int) ([]outbox.Message, error) {
+	selectQuery := `SELECT id, aggregate_type, aggregate_id, event_type, version, payload, occurred_at, attempts, last_error
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY seq
+		LIMIT ?`
+
+	row, err := repo.db.Query(selectQuery, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	messages := []outbox.Message{}
+
+	for row.Next() {
+		var message outbox.Message
+
+		err := row.Scan(
+			&message.ID, &message.AggregateType, &message.AggregateID,
+			&message.EventType, &message.Version, &message.Payload,
+			&message.OccurredAt, &message.Attempts, &message.LastError)
+
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, row.Err()
+}
+
+//MarkPublished Set the published time of the message
+func (repo *MySQLRepository) MarkPublished(ID string, publishedAt time.Time) error {
+	_, err := repo.db.Exec("UPDATE outbox SET published_at = ? WHERE id = ?", publishedAt, ID)
+	return err
+}
+
+//MarkFailed Increase the attempts of the message and keep the failure reason, cut to fit the column
+func (repo *MySQLRepository) MarkFailed(ID string, reason string) error {
+	if len(reason) > maxErrorLength {
+		reason = reason[:maxErrorLength]
+	}
+
+	_, err := repo.db.Exec("UPDATE outbox SET attempts = attempts + 1, last_error = ? WHERE id = ?", reason, ID)
+	return err
+}
@@ -0,0 +1,19 @@
+package pricing
+
+import (
+	"sample-order/business/pricing"
+	"sample-order/util"
+)
+
+//RepositoryFactory Will return business.pricing.Repository based on active database connection
+func RepositoryFactory(dbCon *util.DatabaseConnection) pricing.Repository {
+	var pricingRepo pricing.Repository
+
+	if dbCon.Driver == util.MySQL {
+		pricingRepo = NewMySQLRepository(dbCon.MySQLDB)
+	} else if dbCon.Driver == util.MongoDB {
+		pricingRepo = NewMongoDBRepository(dbCon.MongoDB)
+	}
+
+	return pricingRepo
+}
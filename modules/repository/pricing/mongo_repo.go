@@ -0,0 +1,130 @@
+package pricing
+
+import (
+	"context"
+	"sample-order/business/money"
+	"sample-order/business/pricing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//MongoDBRepository The implementation of pricing.Repository object
+type MongoDBRepository struct {
+	col *mongo.Collection
+}
+
+//collection pricing rule keyed by item ID, every amount is in minor unit of the currency
+type collection struct {
+	ItemID                  primitive.ObjectID `bson:"_id"`
+	Currency                string             `bson:"currency"`
+	DailyRate               int64              `bson:"daily_rate"`
+	WeeklyRate              *int64             `bson:"weekly_rate,omitempty"`
+	MinDays                 int                `bson:"min_days"`
+	WeekendSurchargePercent int                `bson:"weekend_surcharge_percent"`
+	Deposit                 *int64             `bson:"deposit,omitempty"`
+	LateFeePerDay           *int64             `bson:"late_fee_per_day,omitempty"`
+	ModifiedAt              time.Time          `bson:"modified_at"`
+	ModifiedBy              string             `bson:"modified_by"`
+}
+
+func newCollection(rule pricing.Rule) (*collection, error) {
+	objectID, err := primitive.ObjectIDFromHex(rule.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &collection{
+		objectID,
+		rule.DailyRate.Currency,
+		rule.DailyRate.Amount,
+		amountField(rule.WeeklyRate),
+		rule.MinDays,
+		rule.WeekendSurchargePercent,
+		amountField(rule.Deposit),
+		amountField(rule.LateFeePerDay),
+		rule.ModifiedAt,
+		rule.ModifiedBy,
+	}, nil
+}
+
+func (col *collection) ToRule() pricing.Rule {
+	var rule pricing.Rule
+	rule.ItemID = col.ItemID.Hex()
+	rule.DailyRate = money.Money{Amount: col.DailyRate, Currency: col.Currency}
+	rule.WeeklyRate = constructMoneyField(col.WeeklyRate, col.Currency)
+	rule.MinDays = col.MinDays
+	rule.WeekendSurchargePercent = col.WeekendSurchargePercent
+	rule.Deposit = constructMoneyField(col.Deposit, col.Currency)
+	rule.LateFeePerDay = constructMoneyField(col.LateFeePerDay, col.Currency)
+	rule.ModifiedAt = col.ModifiedAt
+	rule.ModifiedBy = col.ModifiedBy
+
+	return rule
+}
+
+//NewMongoDBRepository Generate mongo DB pricing repository
+func NewMongoDBRepository(db *mongo.Database) *MongoDBRepository {
+	return &MongoDBRepository{
+		db.Collection("rental_rules"),
+	}
+}
+
+//FindRuleByItemID Find pricing rule of given item. Its return nil if not found
+func (repo *MongoDBRepository) FindRuleByItemID(itemID string) (*pricing.Rule, error) {
+	var col collection
+
+	objectID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		//if cannot be convert means that ID will be never found
+		return nil, nil
+	}
+
+	if err := repo.col.FindOne(context.TODO(), bson.M{"_id": objectID}).Decode(&col); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	rule := col.ToRule()
+	return &rule, nil
+}
+
+//UpsertRule Insert or replace pricing rule of the item
+func (repo *MongoDBRepository) UpsertRule(rule pricing.Rule) error {
+	col, err := newCollection(rule)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.col.ReplaceOne(context.TODO(), bson.M{"_id": col.ItemID}, col, options.Replace().SetUpsert(true))
+	return err
+}
+
+func amountField(price *money.Money) *int64 {
+	if price == nil {
+		return nil
+	}
+
+	amount := price.Amount
+	return &amount
+}
+
+func constructMoneyField(amount *int64, currency string) *money.Money {
+	if amount == nil {
+		return nil
+	}
+
+	return &money.Money{Amount: *amount, Currency: currency}
+}
+
+//DeleteMongoDBRule Delete rule of the item using the session context of the transaction which delete the item
+func DeleteMongoDBRule(ctx context.Context, db *mongo.Database, itemID primitive.ObjectID) error {
+	_, err := db.Collection("rental_rules").DeleteOne(ctx, bson.M{"_id": itemID})
+	return err
+}
@@ -0,0 +1,107 @@
+package pricing
+
+import (
+	"database/sql"
+	"sample-order/business/money"
+	"sample-order/business/pricing"
+)
+
+//MySQLRepository The implementation of pricing.Repository object
+type MySQLRepository struct {
+	db *sql.DB
+}
+
+//NewMySQLRepository Generate MySQL pricing repository
+func NewMySQLRepository(db *sql.DB) *MySQLRepository {
+	return &MySQLRepository{
+		db,
+	}
+}
+
+//FindRuleByItemID Find pricing rule of given item. Its return nil if not found
+func (repo *MySQLRepository) FindRuleByItemID(itemID string) (*pricing.Rule, error) {
+	var rule pricing.Rule
+	var weeklyRate, deposit, lateFee sql.NullInt64
+
+	selectQuery := `SELECT item_id, currency, daily_rate_amount, weekly_rate_amount, min_days,
+			weekend_surcharge_percent, deposit_amount, late_fee_amount, modified_at, modified_by
+		FROM rental_rule
+		WHERE item_id = ?`
+
+	err := repo.db.QueryRow(selectQuery, itemID).Scan(
+		&rule.ItemID, &rule.DailyRate.Currency, &rule.DailyRate.Amount,
+		&weeklyRate, &rule.MinDays, &rule.WeekendSurchargePercent,
+		&deposit, &lateFee,
+		&rule.ModifiedAt, &rule.ModifiedBy)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	rule.WeeklyRate = constructMoney(weeklyRate, rule.DailyRate.Currency)
+	rule.Deposit = constructMoney(deposit, rule.DailyRate.Currency)
+	rule.LateFeePerDay = constructMoney(lateFee, rule.DailyRate.Currency)
+
+	return &rule, nil
+}
+
+//UpsertRule Insert or replace pricing rule of the item, every money of the rule share the daily rate currency
+func (repo *MySQLRepository) UpsertRule(rule pricing.Rule) error {
+	upsertQuery := `INSERT INTO rental_rule (
+			item_id,
+			currency,
+			daily_rate_amount,
+			weekly_rate_amount,
+			min_days,
+			weekend_surcharge_percent,
+			deposit_amount,
+			late_fee_amount,
+			modified_at,
+			modified_by
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			currency = VALUES(currency),
+			daily_rate_amount = VALUES(daily_rate_amount),
+			weekly_rate_amount = VALUES(weekly_rate_amount),
+			min_days = VALUES(min_days),
+			weekend_surcharge_percent = VALUES(weekend_surcharge_percent),
+			deposit_amount = VALUES(deposit_amount),
+			late_fee_amount = VALUES(late_fee_amount),
+			modified_at = VALUES(modified_at),
+			modified_by = VALUES(modified_by)`
+
+	_, err := repo.db.Exec(upsertQuery,
+		rule.ItemID,
+		rule.DailyRate.Currency,
+		rule.DailyRate.Amount,
+		amountColumn(rule.WeeklyRate),
+		rule.MinDays,
+		rule.WeekendSurchargePercent,
+		amountColumn(rule.Deposit),
+		amountColumn(rule.LateFeePerDay),
+		rule.ModifiedAt,
+		rule.ModifiedBy,
+	)
+
+	return err
+}
+
+func amountColumn(price *money.Money) sql.NullInt64 {
+	if price == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: price.Amount, Valid: true}
+}
+
+func constructMoney(amount sql.NullInt64, currency string) *money.Money {
+	if !amount.Valid {
+		return nil
+	}
+
+	return &money.Money{Amount: amount.Int64, Currency: currency}
+}
@@ -0,0 +1,19 @@
+package promotion
+
+import (
+	"sample-order/business/promotion"
+	"sample-order/util"
+)
+
+//RepositoryFactory Will return business.promotion.Repository based on active database connection
+func RepositoryFactory(dbCon *util.DatabaseConnection) promotion.Repository {
+	var promotionRepo promotion.Repository
+
+	if dbCon.Driver == util.MySQL {
+		promotionRepo = NewMySQLRepository(dbCon.MySQLDB)
+	} else if dbCon.Driver == util.MongoDB {
+		promotionRepo = NewMongoDBRepository(dbCon.MongoDB)
+	}
+
+	return promotionRepo
+}
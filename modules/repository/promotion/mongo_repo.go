@@ -0,0 +1,367 @@
+package promotion
+
+import (
+	"context"
+	"sample-order/business"
+	"sample-order/business/money"
+	"sample-order/business/promotion"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//MongoDBRepository The implementation of promotion.Repository object
+type MongoDBRepository struct {
+	client        *mongo.Client
+	promotionCol  *mongo.Collection
+	redemptionCol *mongo.Collection
+}
+
+type moneyDocument struct {
+	Amount   int64  `bson:"amount"`
+	Currency string `bson:"currency"`
+}
+
+type collection struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	Code         string             `bson:"code"`
+	Description  string             `bson:"description"`
+	Type         string             `bson:"type"`
+	Percent      int64              `bson:"percent"`
+	Amount       *moneyDocument     `bson:"amount"`
+	ItemIDs      []string           `bson:"item_ids"`
+	Tags         []string           `bson:"tags"`
+	ValidFrom    time.Time          `bson:"valid_from"`
+	ValidUntil   *time.Time         `bson:"valid_until"`
+	UsageLimit   int                `bson:"usage_limit"`
+	PerUserLimit int                `bson:"per_user_limit"`
+	UsedCount    int                `bson:"used_count"`
+	CreatedAt    time.Time          `bson:"created_at"`
+	CreatedBy    string             `bson:"created_by"`
+	ModifiedAt   time.Time          `bson:"modified_at"`
+	ModifiedBy   string             `bson:"modified_by"`
+	Version      int                `bson:"version"`
+}
+
+func newCollection(promotion promotion.Promotion) (*collection, error) {
+	objectID, err := primitive.ObjectIDFromHex(promotion.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var amount *moneyDocument
+	if promotion.Amount != nil {
+		amount = &moneyDocument{promotion.Amount.Amount, promotion.Amount.Currency}
+	}
+
+	return &collection{
+		objectID,
+		promotion.Code,
+		promotion.Description,
+		string(promotion.Type),
+		promotion.Percent,
+		amount,
+		promotion.ItemIDs,
+		promotion.Tags,
+		promotion.ValidFrom,
+		promotion.ValidUntil,
+		promotion.UsageLimit,
+		promotion.PerUserLimit,
+		promotion.UsedCount,
+		promotion.CreatedAt,
+		promotion.CreatedBy,
+		promotion.ModifiedAt,
+		promotion.ModifiedBy,
+		promotion.Version,
+	}, nil
+}
+
+func (col *collection) ToPromotion() promotion.Promotion {
+	var amount *money.Money
+	if col.Amount != nil {
+		amount = &money.Money{Amount: col.Amount.Amount, Currency: col.Amount.Currency}
+	}
+
+	itemIDs := col.ItemIDs
+	if itemIDs == nil {
+		itemIDs = []string{}
+	}
+
+	tags := col.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return promotion.Promotion{
+		ID:           col.ID.Hex(),
+		Code:         col.Code,
+		Description:  col.Description,
+		Type:         promotion.Type(col.Type),
+		Percent:      col.Percent,
+		Amount:       amount,
+		ItemIDs:      itemIDs,
+		Tags:         tags,
+		ValidFrom:    col.ValidFrom,
+		ValidUntil:   col.ValidUntil,
+		UsageLimit:   col.UsageLimit,
+		PerUserLimit: col.PerUserLimit,
+		UsedCount:    col.UsedCount,
+		CreatedAt:    col.CreatedAt,
+		CreatedBy:    col.CreatedBy,
+		ModifiedAt:   col.ModifiedAt,
+		ModifiedBy:   col.ModifiedBy,
+		Version:      col.Version,
+	}
+}
+
+//redemptionCollection keyed by the order, so single order use the promotion at most once
+type redemptionCollection struct {
+	OrderID     string             `bson:"_id"`
+	PromotionID primitive.ObjectID `bson:"promotion_id"`
+	User        string             `bson:"user"`
+	Amount      moneyDocument      `bson:"amount"`
+	RedeemedAt  time.Time          `bson:"redeemed_at"`
+}
+
+//NewMongoDBRepository Generate mongo DB promotion repository
+func NewMongoDBRepository(db *mongo.Database) *MongoDBRepository {
+	return &MongoDBRepository{
+		db.Client(),
+		db.Collection("promotions"),
+		db.Collection("promotion_redemptions"),
+	}
+}
+
+//FindPromotionByID Find promotion based on given ID. Its return nil if not found
+func (repo *MongoDBRepository) FindPromotionByID(ID string) (*promotion.Promotion, error) {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		//if cannot be convert means that ID will be never found
+		return nil, nil
+	}
+
+	return repo.findPromotion(bson.M{"_id": objectID})
+}
+
+//FindPromotionByCode Find promotion based on given code. Its return nil if not found
+func (repo *MongoDBRepository) FindPromotionByCode(code string) (*promotion.Promotion, error) {
+	return repo.findPromotion(bson.M{"code": code})
+}
+
+//FindAllPromotions Find every promotion ordered by code
+func (repo *MongoDBRepository) FindAllPromotions() ([]promotion.Promotion, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "code", Value: 1}})
+
+	cursor, err := repo.promotionCol.Find(context.TODO(), bson.M{}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	var promotions []promotion.Promotion
+
+	for cursor.Next(context.TODO()) {
+		var col collection
+		if err = cursor.Decode(&col); err != nil {
+			return nil, err
+		}
+
+		promotions = append(promotions, col.ToPromotion())
+	}
+
+	return promotions, cursor.Err()
+}
+
+//InsertPromotion Insert new promotion with its items and tags in single document
+func (repo *MongoDBRepository) InsertPromotion(promotion promotion.Promotion) error {
+	col, err := newCollection(promotion)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.promotionCol.InsertOne(context.TODO(), col)
+	return err
+}
+
+//UpdatePromotion Update the rule of promotion, the used count is not changed
+func (repo *MongoDBRepository) UpdatePromotion(promotion promotion.Promotion, currentVersion int) error {
+	col, err := newCollection(promotion)
+	if err != nil {
+		return business.ErrZeroAffected
+	}
+
+	filter := bson.M{
+		"_id":     col.ID,
+		"version": currentVersion,
+	}
+
+	updated := bson.M{
+		"$set": bson.M{
+			"code":           col.Code,
+			"description":    col.Description,
+			"type":           col.Type,
+			"percent":        col.Percent,
+			"amount":         col.Amount,
+			"item_ids":       col.ItemIDs,
+			"tags":           col.Tags,
+			"valid_from":     col.ValidFrom,
+			"valid_until":    col.ValidUntil,
+			"usage_limit":    col.UsageLimit,
+			"per_user_limit": col.PerUserLimit,
+			"modified_at":    col.ModifiedAt,
+			"modified_by":    col.ModifiedBy,
+			"version":        col.Version,
+		},
+	}
+
+	result, err := repo.promotionCol.UpdateOne(context.TODO(), filter, updated)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
+
+//DeletePromotion Delete the promotion and its redemptions inside a transaction
+func (repo *MongoDBRepository) DeletePromotion(ID string, currentVersion int) error {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return business.ErrZeroAffected
+	}
+
+	return repo.withTransaction(func(sessCtx mongo.SessionContext) error {
+		result, err := repo.promotionCol.DeleteOne(sessCtx, bson.M{"_id": objectID, "version": currentVersion})
+		if err != nil {
+			return err
+		}
+
+		if result.DeletedCount == 0 {
+			return business.ErrZeroAffected
+		}
+
+		_, err = repo.redemptionCol.DeleteMany(sessCtx, bson.M{"promotion_id": objectID})
+		return err
+	})
+}
+
+//CountRedemptionsByUser Count redemptions of the promotion by given user
+func (repo *MongoDBRepository) CountRedemptionsByUser(promotionID string, user string) (int, error) {
+	objectID, err := primitive.ObjectIDFromHex(promotionID)
+	if err != nil {
+		return 0, nil
+	}
+
+	count, err := repo.redemptionCol.CountDocuments(context.TODO(), bson.M{"promotion_id": objectID, "user": user})
+	return int(count), err
+}
+
+//InsertRedemption Increase the used count and insert the redemption inside a transaction.
+//Concurrent redemptions write the same promotion document, so they can not be committed together
+func (repo *MongoDBRepository) InsertRedemption(promotion promotion.Promotion, redemption promotion.Redemption) error {
+	objectID, err := primitive.ObjectIDFromHex(promotion.ID)
+	if err != nil {
+		return business.ErrZeroAffected
+	}
+
+	col := redemptionCollection{
+		redemption.OrderID,
+		objectID,
+		redemption.User,
+		moneyDocument{redemption.Amount.Amount, redemption.Amount.Currency},
+		redemption.RedeemedAt,
+	}
+
+	return repo.withTransaction(func(sessCtx mongo.SessionContext) error {
+		filter := bson.M{
+			"_id": objectID,
+			"$expr": bson.M{"$or": bson.A{
+				bson.M{"$eq": bson.A{"$usage_limit", 0}},
+				bson.M{"$lt": bson.A{"$used_count", "$usage_limit"}},
+			}},
+		}
+
+		result, err := repo.promotionCol.UpdateOne(sessCtx, filter, bson.M{"$inc": bson.M{"used_count": 1}})
+		if err != nil {
+			return err
+		}
+
+		if result.MatchedCount == 0 {
+			return business.ErrZeroAffected
+		}
+
+		if promotion.PerUserLimit > 0 {
+			count, err := repo.redemptionCol.CountDocuments(sessCtx, bson.M{"promotion_id": objectID, "user": redemption.User})
+			if err != nil {
+				return err
+			}
+
+			if int(count) >= promotion.PerUserLimit {
+				return business.ErrZeroAffected
+			}
+		}
+
+		_, err = repo.redemptionCol.InsertOne(sessCtx, col)
+		return err
+	})
+}
+
+//DeleteRedemptionByOrderID Delete the redemption and decrease the used count of its promotion inside a transaction
+func (repo *MongoDBRepository) DeleteRedemptionByOrderID(orderID string) error {
+	return repo.withTransaction(func(sessCtx mongo.SessionContext) error {
+		var col redemptionCollection
+
+		if err := repo.redemptionCol.FindOneAndDelete(sessCtx, bson.M{"_id": orderID}).Decode(&col); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return business.ErrZeroAffected
+			}
+
+			return err
+		}
+
+		filter := bson.M{
+			"_id":        col.PromotionID,
+			"used_count": bson.M{"$gt": 0},
+		}
+
+		_, err := repo.promotionCol.UpdateOne(sessCtx, filter, bson.M{"$inc": bson.M{"used_count": -1}})
+		return err
+	})
+}
+
+func (repo *MongoDBRepository) findPromotion(filter bson.M) (*promotion.Promotion, error) {
+	var col collection
+
+	if err := repo.promotionCol.FindOne(context.TODO(), filter).Decode(&col); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	promotion := col.ToPromotion()
+	return &promotion, nil
+}
+
+func (repo *MongoDBRepository) withTransaction(fn func(sessCtx mongo.SessionContext) error) error {
+	session, err := repo.client.StartSession()
+	if err != nil {
+		return err
+	}
+
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(context.TODO(), func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+
+	return err
+}
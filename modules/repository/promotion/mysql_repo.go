@@ -0,0 +1,430 @@
+package promotion
+
+import (
+	"database/sql"
+	"sample-order/business"
+	"sample-order/business/money"
+	"sample-order/business/promotion"
+	"strings"
+)
+
+//selectPromotionQuery base query of promotion with its items and tags, the columns must be read by scanPromotion
+const selectPromotionQuery = `SELECT id, code, description, type, percent, amount, currency,
+		valid_from, valid_until, usage_limit, per_user_limit, used_count,
+		created_at, created_by, modified_at, modified_by, version,
+		COALESCE(item_ids, ""), COALESCE(tags, "")
+		FROM promotion p
+		LEFT JOIN (
+			SELECT promotion_id,
+			GROUP_CONCAT(item_id) as item_ids
+			FROM promotion_item GROUP BY promotion_id
+		) AS pi ON p.id = pi.promotion_id
+		LEFT JOIN (
+			SELECT promotion_id,
+			GROUP_CONCAT(tag_name) as tags
+			FROM promotion_tag GROUP BY promotion_id
+		) AS pt ON p.id = pt.promotion_id`
+
+//rowScanner is satisfied by both sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+//MySQLRepository The implementation of promotion.Repository object
+type MySQLRepository struct {
+	db *sql.DB
+}
+
+//NewMySQLRepository Generate MySQL promotion repository
+func NewMySQLRepository(db *sql.DB) *MySQLRepository {
+	return &MySQLRepository{
+		db,
+	}
+}
+
+//FindPromotionByID Find promotion based on given ID. Its return nil if not found
+func (repo *MySQLRepository) FindPromotionByID(ID string) (*promotion.Promotion, error) {
+	return repo.findPromotion(selectPromotionQuery+" WHERE p.id = ?", ID)
+}
+
+//FindPromotionByCode Find promotion based on given code. Its return nil if not found
+func (repo *MySQLRepository) FindPromotionByCode(code string) (*promotion.Promotion, error) {
+	return repo.findPromotion(selectPromotionQuery+" WHERE p.code = ?", code)
+}
+
+//FindAllPromotions Find every promotion ordered by code
+func (repo *MySQLRepository) FindAllPromotions() ([]promotion.Promotion, error) {
+	row, err := repo.db.Query(selectPromotionQuery + " ORDER BY p.code")
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var promotions []promotion.Promotion
+
+	for row.Next() {
+		promotion, err := scanPromotion(row)
+		if err != nil {
+			return nil, err
+		}
+
+		promotions = append(promotions, *promotion)
+	}
+
+	return promotions, row.Err()
+}
+
+//InsertPromotion Insert promotion together with its items and tags in single transaction
+func (repo *MySQLRepository) InsertPromotion(promotion promotion.Promotion) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	insertQuery := `INSERT INTO promotion (
+			id,
+			code,
+			description,
+			type,
+			percent,
+			amount,
+			currency,
+			valid_from,
+			valid_until,
+			usage_limit,
+			per_user_limit,
+			used_count,
+			created_at,
+			created_by,
+			modified_at,
+			modified_by,
+			version
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	amount, currency := moneyColumns(promotion.Amount)
+
+	_, err = tx.Exec(insertQuery,
+		promotion.ID,
+		promotion.Code,
+		promotion.Description,
+		promotion.Type,
+		promotion.Percent,
+		amount,
+		currency,
+		promotion.ValidFrom,
+		promotion.ValidUntil,
+		promotion.UsageLimit,
+		promotion.PerUserLimit,
+		promotion.UsedCount,
+		promotion.CreatedAt,
+		promotion.CreatedBy,
+		promotion.ModifiedAt,
+		promotion.ModifiedBy,
+		promotion.Version,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := insertTargets(tx, promotion); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+//UpdatePromotion Update the rule of promotion and replace its items and tags in single transaction, the used count is not changed
+func (repo *MySQLRepository) UpdatePromotion(promotion promotion.Promotion, currentVersion int) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	updateQuery := `UPDATE promotion
+		SET
+			code = ?,
+			description = ?,
+			type = ?,
+			percent = ?,
+			amount = ?,
+			currency = ?,
+			valid_from = ?,
+			valid_until = ?,
+			usage_limit = ?,
+			per_user_limit = ?,
+			modified_at = ?,
+			modified_by = ?,
+			version = ?
+		WHERE id = ? AND version = ?`
+
+	amount, currency := moneyColumns(promotion.Amount)
+
+	res, err := tx.Exec(updateQuery,
+		promotion.Code,
+		promotion.Description,
+		promotion.Type,
+		promotion.Percent,
+		amount,
+		currency,
+		promotion.ValidFrom,
+		promotion.ValidUntil,
+		promotion.UsageLimit,
+		promotion.PerUserLimit,
+		promotion.ModifiedAt,
+		promotion.ModifiedBy,
+		promotion.Version,
+		promotion.ID,
+		currentVersion,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkAffected(res); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, deleteQuery := range []string{
+		"DELETE FROM promotion_item WHERE promotion_id = ?",
+		"DELETE FROM promotion_tag WHERE promotion_id = ?",
+	} {
+		if _, err := tx.Exec(deleteQuery, promotion.ID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := insertTargets(tx, promotion); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+//DeletePromotion Delete the promotion, its items, tags and redemptions are deleted by the foreign key
+func (repo *MySQLRepository) DeletePromotion(ID string, currentVersion int) error {
+	res, err := repo.db.Exec("DELETE FROM promotion WHERE id = ? AND version = ?", ID, currentVersion)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(res)
+}
+
+//CountRedemptionsByUser Count redemptions of the promotion by given user
+func (repo *MySQLRepository) CountRedemptionsByUser(promotionID string, user string) (int, error) {
+	var count int
+
+	selectQuery := "SELECT COUNT(*) FROM promotion_redemption WHERE promotion_id = ? AND user = ?"
+
+	if err := repo.db.QueryRow(selectQuery, promotionID, user).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+//InsertRedemption Increase the used count and insert the redemption in single transaction.
+//The conditional update lock the promotion row, so concurrent redemptions are checked one by one
+func (repo *MySQLRepository) InsertRedemption(promotion promotion.Promotion, redemption promotion.Redemption) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	updateQuery := `UPDATE promotion
+		SET used_count = used_count + 1
+		WHERE id = ? AND (usage_limit = 0 OR used_count < usage_limit)`
+
+	res, err := tx.Exec(updateQuery, promotion.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkAffected(res); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if promotion.PerUserLimit > 0 {
+		var count int
+
+		selectQuery := "SELECT COUNT(*) FROM promotion_redemption WHERE promotion_id = ? AND user = ?"
+
+		if err := tx.QueryRow(selectQuery, promotion.ID, redemption.User).Scan(&count); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if count >= promotion.PerUserLimit {
+			tx.Rollback()
+			return business.ErrZeroAffected
+		}
+	}
+
+	insertQuery := `INSERT INTO promotion_redemption (
+			order_id,
+			promotion_id,
+			user,
+			amount,
+			currency,
+			redeemed_at
+		) VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(insertQuery,
+		redemption.OrderID,
+		redemption.PromotionID,
+		redemption.User,
+		redemption.Amount.Amount,
+		redemption.Amount.Currency,
+		redemption.RedeemedAt,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+//DeleteRedemptionByOrderID Delete the redemption and decrease the used count of its promotion in single transaction
+func (repo *MySQLRepository) DeleteRedemptionByOrderID(orderID string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	var promotionID string
+
+	selectQuery := "SELECT promotion_id FROM promotion_redemption WHERE order_id = ? FOR UPDATE"
+
+	if err := tx.QueryRow(selectQuery, orderID).Scan(&promotionID); err != nil {
+		tx.Rollback()
+
+		if err == sql.ErrNoRows {
+			return business.ErrZeroAffected
+		}
+
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM promotion_redemption WHERE order_id = ?", orderID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	updateQuery := "UPDATE promotion SET used_count = used_count - 1 WHERE id = ? AND used_count > 0"
+
+	if _, err := tx.Exec(updateQuery, promotionID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *MySQLRepository) findPromotion(query string, args ...interface{}) (*promotion.Promotion, error) {
+	promotion, err := scanPromotion(repo.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func insertTargets(tx *sql.Tx, promotion promotion.Promotion) error {
+	itemQuery := "INSERT INTO promotion_item (promotion_id, item_id) VALUES (?, ?)"
+
+	for _, itemID := range promotion.ItemIDs {
+		if _, err := tx.Exec(itemQuery, promotion.ID, itemID); err != nil {
+			return err
+		}
+	}
+
+	tagQuery := "INSERT INTO promotion_tag (promotion_id, tag_name) VALUES (?, ?)"
+
+	for _, tag := range promotion.Tags {
+		if _, err := tx.Exec(tagQuery, promotion.ID, tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanPromotion(scanner rowScanner) (*promotion.Promotion, error) {
+	var promotion promotion.Promotion
+	var itemIDs, tags string
+	var amount sql.NullInt64
+	var currency sql.NullString
+	var validUntil sql.NullTime
+
+	err := scanner.Scan(
+		&promotion.ID, &promotion.Code, &promotion.Description,
+		&promotion.Type, &promotion.Percent, &amount, &currency,
+		&promotion.ValidFrom, &validUntil,
+		&promotion.UsageLimit, &promotion.PerUserLimit, &promotion.UsedCount,
+		&promotion.CreatedAt, &promotion.CreatedBy,
+		&promotion.ModifiedAt, &promotion.ModifiedBy,
+		&promotion.Version, &itemIDs, &tags)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if amount.Valid && currency.Valid {
+		promotion.Amount = &money.Money{Amount: amount.Int64, Currency: currency.String}
+	}
+
+	if validUntil.Valid {
+		promotion.ValidUntil = &validUntil.Time
+	}
+
+	promotion.ItemIDs = splitColumn(itemIDs)
+	promotion.Tags = splitColumn(tags)
+
+	return &promotion, nil
+}
+
+func moneyColumns(price *money.Money) (sql.NullInt64, sql.NullString) {
+	if price == nil {
+		return sql.NullInt64{}, sql.NullString{}
+	}
+
+	return sql.NullInt64{Int64: price.Amount, Valid: true}, sql.NullString{String: price.Currency, Valid: true}
+}
+
+func splitColumn(values string) []string {
+	if values == "" {
+		return make([]string, 0)
+	}
+
+	return strings.Split(values, ",")
+}
+
+func checkAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
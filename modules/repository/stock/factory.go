@@ -0,0 +1,19 @@
+package stock
+
+import (
+	"sample-order/business/stock"
+	"sample-order/util"
+)
+
+//RepositoryFactory Will return business.stock.Repository based on active database connection
+func RepositoryFactory(dbCon *util.DatabaseConnection) stock.Repository {
+	var stockRepo stock.Repository
+
+	if dbCon.Driver == util.MySQL {
+		stockRepo = NewMySQLRepository(dbCon.MySQLDB)
+	} else if dbCon.Driver == util.MongoDB {
+		stockRepo = NewMongoDBRepository(dbCon.MongoDB)
+	}
+
+	return stockRepo
+}
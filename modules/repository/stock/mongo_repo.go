@@ -0,0 +1,534 @@
+package stock
+
+import (
+	"context"
+	"sample-order/business"
+	"sample-order/business/stock"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//MongoDBRepository The implementation of stock.Repository object
+type MongoDBRepository struct {
+	client         *mongo.Client
+	stockCol       *mongo.Collection
+	movementCol    *mongo.Collection
+	reservationCol *mongo.Collection
+}
+
+type stockCollection struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	ItemID      primitive.ObjectID `bson:"item_id"`
+	WarehouseID primitive.ObjectID `bson:"warehouse_id"`
+	Quantity    int                `bson:"quantity"`
+	Reserved    int                `bson:"reserved"`
+	ModifiedAt  time.Time          `bson:"modified_at"`
+}
+
+func (col *stockCollection) ToStock() stock.Stock {
+	var stock stock.Stock
+	stock.ItemID = col.ItemID.Hex()
+	stock.WarehouseID = col.WarehouseID.Hex()
+	stock.Quantity = col.Quantity
+	stock.Reserved = col.Reserved
+	stock.ModifiedAt = col.ModifiedAt
+
+	return stock
+}
+
+type movementCollection struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	ItemID      primitive.ObjectID `bson:"item_id"`
+	WarehouseID primitive.ObjectID `bson:"warehouse_id"`
+	Type        string             `bson:"type"`
+	Quantity    int                `bson:"quantity"`
+	Reason      string             `bson:"reason"`
+	Actor       string             `bson:"actor"`
+	CreatedAt   time.Time          `bson:"created_at"`
+}
+
+func newMovementCollection(movement stock.Movement) (*movementCollection, error) {
+	objectID, err := primitive.ObjectIDFromHex(movement.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	itemObjectID, err := primitive.ObjectIDFromHex(movement.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	warehouseObjectID, err := primitive.ObjectIDFromHex(movement.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &movementCollection{
+		objectID,
+		itemObjectID,
+		warehouseObjectID,
+		string(movement.Type),
+		movement.Quantity,
+		movement.Reason,
+		movement.Actor,
+		movement.CreatedAt,
+	}, nil
+}
+
+func (col *movementCollection) ToMovement() stock.Movement {
+	var movement stock.Movement
+	movement.ID = col.ID.Hex()
+	movement.ItemID = col.ItemID.Hex()
+	movement.WarehouseID = col.WarehouseID.Hex()
+	movement.Type = stock.MovementType(col.Type)
+	movement.Quantity = col.Quantity
+	movement.Reason = col.Reason
+	movement.Actor = col.Actor
+	movement.CreatedAt = col.CreatedAt
+
+	return movement
+}
+
+type reservationCollection struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	ItemID      primitive.ObjectID `bson:"item_id"`
+	WarehouseID primitive.ObjectID `bson:"warehouse_id"`
+	Quantity    int                `bson:"quantity"`
+	Reference   string             `bson:"reference"`
+	Status      string             `bson:"status"`
+	ExpiresAt   time.Time          `bson:"expires_at"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	CreatedBy   string             `bson:"created_by"`
+	ModifiedAt  time.Time          `bson:"modified_at"`
+	ModifiedBy  string             `bson:"modified_by"`
+}
+
+func newReservationCollection(reservation stock.Reservation) (*reservationCollection, error) {
+	objectID, err := primitive.ObjectIDFromHex(reservation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	itemObjectID, err := primitive.ObjectIDFromHex(reservation.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	warehouseObjectID, err := primitive.ObjectIDFromHex(reservation.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reservationCollection{
+		objectID,
+		itemObjectID,
+		warehouseObjectID,
+		reservation.Quantity,
+		reservation.Reference,
+		string(reservation.Status),
+		reservation.ExpiresAt,
+		reservation.CreatedAt,
+		reservation.CreatedBy,
+		reservation.ModifiedAt,
+		reservation.ModifiedBy,
+	}, nil
+}
+
+func (col *reservationCollection) ToReservation() stock.Reservation {
+	var reservation stock.Reservation
+	reservation.ID = col.ID.Hex()
+	reservation.ItemID = col.ItemID.Hex()
+	reservation.WarehouseID = col.WarehouseID.Hex()
+	reservation.Quantity = col.Quantity
+	reservation.Reference = col.Reference
+	reservation.Status = stock.ReservationStatus(col.Status)
+	reservation.ExpiresAt = col.ExpiresAt
+	reservation.CreatedAt = col.CreatedAt
+	reservation.CreatedBy = col.CreatedBy
+	reservation.ModifiedAt = col.ModifiedAt
+	reservation.ModifiedBy = col.ModifiedBy
+
+	return reservation
+}
+
+//NewMongoDBRepository Generate mongo DB stock repository
+func NewMongoDBRepository(db *mongo.Database) *MongoDBRepository {
+	return &MongoDBRepository{
+		db.Client(),
+		db.Collection("stocks"),
+		db.Collection("stock_movements"),
+		db.Collection("stock_reservations"),
+	}
+}
+
+//FindStocksByItemID Find stock of given item in every warehouse
+func (repo *MongoDBRepository) FindStocksByItemID(itemID string) ([]stock.Stock, error) {
+	objectID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		//if cannot be convert means that ID will be never found
+		return nil, nil
+	}
+
+	cursor, err := repo.stockCol.Find(context.TODO(), bson.M{"item_id": objectID})
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	var stocks []stock.Stock
+
+	for cursor.Next(context.TODO()) {
+		var col stockCollection
+		if err = cursor.Decode(&col); err != nil {
+			return nil, err
+		}
+
+		stocks = append(stocks, col.ToStock())
+	}
+
+	return stocks, cursor.Err()
+}
+
+//ApplyMovements Apply movements using conditional update so the quantity never become negative.
+//Multiple movements run inside a transaction, which require MongoDB replica set
+func (repo *MongoDBRepository) ApplyMovements(movements []stock.Movement) ([]stock.Stock, error) {
+	if len(movements) == 1 {
+		current, err := repo.applyMovement(context.TODO(), movements[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return []stock.Stock{*current}, nil
+	}
+
+	session, err := repo.client.StartSession()
+	if err != nil {
+		return nil, err
+	}
+
+	defer session.EndSession(context.TODO())
+
+	result, err := session.WithTransaction(context.TODO(), func(sessCtx mongo.SessionContext) (interface{}, error) {
+		var stocks []stock.Stock
+
+		for _, movement := range movements {
+			current, err := repo.applyMovement(sessCtx, movement)
+			if err != nil {
+				return nil, err
+			}
+
+			stocks = append(stocks, *current)
+		}
+
+		return stocks, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]stock.Stock), nil
+}
+
+func (repo *MongoDBRepository) applyMovement(ctx context.Context, movement stock.Movement) (*stock.Stock, error) {
+	movementCol, err := newMovementCollection(movement)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"item_id":      movementCol.ItemID,
+		"warehouse_id": movementCol.WarehouseID,
+	}
+
+	//decrement only match the document which keep enough quantity for the reserved units,
+	//incoming movement may create the document
+	upsert := true
+	if movement.Quantity < 0 {
+		filter["$expr"] = bson.M{"$gte": bson.A{
+			bson.M{"$add": bson.A{"$quantity", movement.Quantity}},
+			bson.M{"$ifNull": bson.A{"$reserved", 0}},
+		}}
+		upsert = false
+	}
+
+	updated := bson.M{
+		"$inc": bson.M{"quantity": movement.Quantity},
+		"$set": bson.M{"modified_at": movement.CreatedAt},
+	}
+
+	updateOptions := options.FindOneAndUpdate().
+		SetUpsert(upsert).
+		SetReturnDocument(options.After)
+
+	var col stockCollection
+	if err := repo.stockCol.FindOneAndUpdate(ctx, filter, updated, updateOptions).Decode(&col); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, business.ErrInsufficientStock
+		}
+
+		return nil, err
+	}
+
+	if _, err = repo.movementCol.InsertOne(ctx, movementCol); err != nil {
+		return nil, err
+	}
+
+	stock := col.ToStock()
+	return &stock, nil
+}
+
+//FindMovementsByItemID Find movements of given item from the newest
+func (repo *MongoDBRepository) FindMovementsByItemID(itemID string) ([]stock.Movement, error) {
+	objectID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		return nil, nil
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := repo.movementCol.Find(context.TODO(), bson.M{"item_id": objectID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	var movements []stock.Movement
+
+	for cursor.Next(context.TODO()) {
+		var col movementCollection
+		if err = cursor.Decode(&col); err != nil {
+			return nil, err
+		}
+
+		movements = append(movements, col.ToMovement())
+	}
+
+	return movements, cursor.Err()
+}
+
+//FindReservationByID Find reservation based on given ID. Its return nil if not found
+func (repo *MongoDBRepository) FindReservationByID(ID string) (*stock.Reservation, error) {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return nil, nil
+	}
+
+	var col reservationCollection
+	if err := repo.reservationCol.FindOne(context.TODO(), bson.M{"_id": objectID}).Decode(&col); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	reservation := col.ToReservation()
+	return &reservation, nil
+}
+
+//FindReservationsByReference Find reservations with given reference from the oldest
+func (repo *MongoDBRepository) FindReservationsByReference(reference string) ([]stock.Reservation, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	return repo.findReservations(bson.M{"reference": reference}, findOptions)
+}
+
+//FindExpiredReservations Find active reservations which expiry time is not after given time
+func (repo *MongoDBRepository) FindExpiredReservations(now time.Time) ([]stock.Reservation, error) {
+	filter := bson.M{
+		"status":     string(stock.Active),
+		"expires_at": bson.M{"$lte": now},
+	}
+
+	return repo.findReservations(filter, options.Find().SetSort(bson.M{"expires_at": 1}))
+}
+
+//InsertReservation Increase the reserved units and insert the reservation inside a transaction, which require MongoDB replica set
+func (repo *MongoDBRepository) InsertReservation(reservation stock.Reservation) error {
+	col, err := newReservationCollection(reservation)
+	if err != nil {
+		return err
+	}
+
+	return repo.withTransaction(func(sessCtx mongo.SessionContext) error {
+		if err := repo.changeReserved(sessCtx, col, col.Quantity); err != nil {
+			return err
+		}
+
+		_, err := repo.reservationCol.InsertOne(sessCtx, col)
+		return err
+	})
+}
+
+//ReleaseReservation Update the reservation status and decrease the reserved units inside a transaction
+func (repo *MongoDBRepository) ReleaseReservation(reservation stock.Reservation) error {
+	col, err := newReservationCollection(reservation)
+	if err != nil {
+		return err
+	}
+
+	return repo.withTransaction(func(sessCtx mongo.SessionContext) error {
+		return repo.finishReservation(sessCtx, col)
+	})
+}
+
+//ConvertReservations Update the status of every reservation, decrease the reserved units and apply the sale movements inside a transaction
+func (repo *MongoDBRepository) ConvertReservations(reservations []stock.Reservation, movements []stock.Movement) ([]stock.Stock, error) {
+	cols := make([]*reservationCollection, 0, len(reservations))
+
+	for _, reservation := range reservations {
+		col, err := newReservationCollection(reservation)
+		if err != nil {
+			return nil, err
+		}
+
+		cols = append(cols, col)
+	}
+
+	var stocks []stock.Stock
+
+	err := repo.withTransaction(func(sessCtx mongo.SessionContext) error {
+		//the transaction may be retried, so the result is collected from scratch
+		stocks = make([]stock.Stock, 0, len(movements))
+
+		for i, col := range cols {
+			if err := repo.finishReservation(sessCtx, col); err != nil {
+				return err
+			}
+
+			current, err := repo.applyMovement(sessCtx, movements[i])
+			if err != nil {
+				return err
+			}
+
+			stocks = append(stocks, *current)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stocks, nil
+}
+
+func (repo *MongoDBRepository) findReservations(filter interface{}, findOptions *options.FindOptions) ([]stock.Reservation, error) {
+	cursor, err := repo.reservationCol.Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	var reservations []stock.Reservation
+
+	for cursor.Next(context.TODO()) {
+		var col reservationCollection
+		if err = cursor.Decode(&col); err != nil {
+			return nil, err
+		}
+
+		reservations = append(reservations, col.ToReservation())
+	}
+
+	return reservations, cursor.Err()
+}
+
+func (repo *MongoDBRepository) withTransaction(fn func(sessCtx mongo.SessionContext) error) error {
+	session, err := repo.client.StartSession()
+	if err != nil {
+		return err
+	}
+
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(context.TODO(), func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+
+	return err
+}
+
+//finishReservation update the status of active reservation and give its units back to the stock
+func (repo *MongoDBRepository) finishReservation(ctx context.Context, col *reservationCollection) error {
+	filter := bson.M{
+		"_id":    col.ID,
+		"status": string(stock.Active),
+	}
+
+	updated := bson.M{
+		"$set": bson.M{
+			"status":      col.Status,
+			"modified_at": col.ModifiedAt,
+			"modified_by": col.ModifiedBy,
+		},
+	}
+
+	res, err := repo.reservationCol.UpdateOne(ctx, filter, updated)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return repo.changeReserved(ctx, col, -col.Quantity)
+}
+
+//changeReserved add delta into reserved units of the stock, only when the result stay between zero and the quantity
+func (repo *MongoDBRepository) changeReserved(ctx context.Context, col *reservationCollection, delta int) error {
+	reserved := bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$reserved", 0}}, delta}}
+
+	filter := bson.M{
+		"item_id":      col.ItemID,
+		"warehouse_id": col.WarehouseID,
+		"$expr": bson.M{"$and": bson.A{
+			bson.M{"$gte": bson.A{reserved, 0}},
+			bson.M{"$lte": bson.A{reserved, "$quantity"}},
+		}},
+	}
+
+	updated := bson.M{
+		"$inc": bson.M{"reserved": delta},
+		"$set": bson.M{"modified_at": col.ModifiedAt},
+	}
+
+	res, err := repo.stockCol.UpdateOne(ctx, filter, updated)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return business.ErrInsufficientStock
+	}
+
+	return nil
+}
+
+//IsMongoDBItemStocked Tell whether item has stock on hand or active reservation in any warehouse using the session
+//context of the transaction which delete the item
+func IsMongoDBItemStocked(ctx context.Context, db *mongo.Database, itemID primitive.ObjectID) (bool, error) {
+	stockFilter := bson.M{
+		"item_id": itemID,
+		"$or":     bson.A{bson.M{"quantity": bson.M{"$gt": 0}}, bson.M{"reserved": bson.M{"$gt": 0}}},
+	}
+
+	stocks, err := db.Collection("stocks").CountDocuments(ctx, stockFilter, options.Count().SetLimit(1))
+	if err != nil || stocks > 0 {
+		return stocks > 0, err
+	}
+
+	reservationFilter := bson.M{"item_id": itemID, "status": string(stock.Active)}
+
+	reservations, err := db.Collection("stock_reservations").CountDocuments(ctx, reservationFilter, options.Count().SetLimit(1))
+	return reservations > 0, err
+}
@@ -0,0 +1,482 @@
+package stock
+
+import (
+	"context"
+	"database/sql"
+	"sample-order/business"
+	"sample-order/business/stock"
+	"time"
+)
+
+const selectReservationQuery = `SELECT
+		id, item_id, warehouse_id, quantity, reference, status, expires_at,
+		created_at, created_by, modified_at, modified_by
+	FROM stock_reservation`
+
+//rowScanner common part of sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+//MySQLRepository The implementation of stock.Repository object
+type MySQLRepository struct {
+	db *sql.DB
+}
+
+//NewMySQLRepository Generate MySQL stock repository
+func NewMySQLRepository(db *sql.DB) *MySQLRepository {
+	return &MySQLRepository{
+		db,
+	}
+}
+
+//FindStocksByItemID Find stock of given item in every warehouse
+func (repo *MySQLRepository) FindStocksByItemID(itemID string) ([]stock.Stock, error) {
+	selectQuery := `SELECT item_id, warehouse_id, quantity, reserved, modified_at
+		FROM item_stock
+		WHERE item_id = ?
+		ORDER BY warehouse_id`
+
+	row, err := repo.db.Query(selectQuery, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var stocks []stock.Stock
+
+	for row.Next() {
+		var stock stock.Stock
+
+		if err := row.Scan(&stock.ItemID, &stock.WarehouseID, &stock.Quantity, &stock.Reserved, &stock.ModifiedAt); err != nil {
+			return nil, err
+		}
+
+		stocks = append(stocks, stock)
+	}
+
+	return stocks, row.Err()
+}
+
+//ApplyMovements Update the stocks and insert the movements in single transaction.
+//The conditional update make sure the quantity never become negative, the locked item can not be deleted meanwhile
+func (repo *MySQLRepository) ApplyMovements(movements []stock.Movement) ([]stock.Stock, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	var stocks []stock.Stock
+
+	for _, movement := range movements {
+		if err := lockItem(tx, movement.ItemID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		current, err := applyMovement(tx, movement)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		stocks = append(stocks, *current)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return stocks, nil
+}
+
+//FindMovementsByItemID Find movements of given item from the newest
+func (repo *MySQLRepository) FindMovementsByItemID(itemID string) ([]stock.Movement, error) {
+	selectQuery := `SELECT id, item_id, warehouse_id, type, quantity, reason, actor, created_at
+		FROM stock_movement
+		WHERE item_id = ?
+		ORDER BY created_at DESC, id DESC`
+
+	row, err := repo.db.Query(selectQuery, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var movements []stock.Movement
+
+	for row.Next() {
+		var movement stock.Movement
+
+		err := row.Scan(
+			&movement.ID, &movement.ItemID, &movement.WarehouseID,
+			&movement.Type, &movement.Quantity,
+			&movement.Reason, &movement.Actor,
+			&movement.CreatedAt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		movements = append(movements, movement)
+	}
+
+	return movements, row.Err()
+}
+
+func applyMovement(tx *sql.Tx, movement stock.Movement) (*stock.Stock, error) {
+	initQuery := `INSERT INTO item_stock (item_id, warehouse_id, quantity, modified_at)
+		VALUES (?, ?, 0, ?)
+		ON DUPLICATE KEY UPDATE item_id = item_id`
+
+	if _, err := tx.Exec(initQuery, movement.ItemID, movement.WarehouseID, movement.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	updateQuery := `UPDATE item_stock
+		SET
+			quantity = quantity + ?,
+			modified_at = ?
+		WHERE item_id = ? AND warehouse_id = ? AND quantity + ? >= reserved`
+
+	res, err := tx.Exec(updateQuery,
+		movement.Quantity,
+		movement.CreatedAt,
+		movement.ItemID,
+		movement.WarehouseID,
+		movement.Quantity,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if affected == 0 {
+		return nil, business.ErrInsufficientStock
+	}
+
+	movementQuery := `INSERT INTO stock_movement (
+			id,
+			item_id,
+			warehouse_id,
+			type,
+			quantity,
+			reason,
+			actor,
+			created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(movementQuery,
+		movement.ID,
+		movement.ItemID,
+		movement.WarehouseID,
+		movement.Type,
+		movement.Quantity,
+		movement.Reason,
+		movement.Actor,
+		movement.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return findStock(tx, movement.ItemID, movement.WarehouseID)
+}
+
+//lockItem lock the item row until the transaction end, so the item is not deleted while its stock change.
+//The stock has no foreign key into the item to keep its history, will return business.ErrNotFound when the item is deleted
+func lockItem(tx *sql.Tx, itemID string) error {
+	var lockedID string
+	if err := tx.QueryRow("SELECT id FROM item WHERE id = ? FOR UPDATE", itemID).Scan(&lockedID); err != nil {
+		if err == sql.ErrNoRows {
+			return business.ErrNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func findStock(tx *sql.Tx, itemID string, warehouseID string) (*stock.Stock, error) {
+	var stock stock.Stock
+	selectQuery := "SELECT item_id, warehouse_id, quantity, reserved, modified_at FROM item_stock WHERE item_id = ? AND warehouse_id = ?"
+
+	err := tx.QueryRow(selectQuery, itemID, warehouseID).
+		Scan(&stock.ItemID, &stock.WarehouseID, &stock.Quantity, &stock.Reserved, &stock.ModifiedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &stock, nil
+}
+
+//FindReservationByID Find reservation based on given ID. Its return nil if not found
+func (repo *MySQLRepository) FindReservationByID(ID string) (*stock.Reservation, error) {
+	reservation, err := scanReservation(repo.db.QueryRow(selectReservationQuery+" WHERE id = ?", ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+//FindReservationsByReference Find reservations with given reference from the oldest
+func (repo *MySQLRepository) FindReservationsByReference(reference string) ([]stock.Reservation, error) {
+	return repo.findReservations(selectReservationQuery+" WHERE reference = ? ORDER BY created_at, id", reference)
+}
+
+//FindExpiredReservations Find active reservations which expiry time is not after given time
+func (repo *MySQLRepository) FindExpiredReservations(now time.Time) ([]stock.Reservation, error) {
+	return repo.findReservations(selectReservationQuery+" WHERE status = ? AND expires_at <= ? ORDER BY expires_at", stock.Active, now)
+}
+
+//InsertReservation Increase the reserved units and insert the reservation in single transaction.
+//The conditional update make sure the reserved units never exceed the quantity, the locked item can not be deleted meanwhile
+func (repo *MySQLRepository) InsertReservation(reservation stock.Reservation) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := lockItem(tx, reservation.ItemID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := changeReserved(tx, reservation, reservation.Quantity); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	insertQuery := `INSERT INTO stock_reservation (
+			id,
+			item_id,
+			warehouse_id,
+			quantity,
+			reference,
+			status,
+			expires_at,
+			created_at,
+			created_by,
+			modified_at,
+			modified_by
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(insertQuery,
+		reservation.ID,
+		reservation.ItemID,
+		reservation.WarehouseID,
+		reservation.Quantity,
+		reservation.Reference,
+		reservation.Status,
+		reservation.ExpiresAt,
+		reservation.CreatedAt,
+		reservation.CreatedBy,
+		reservation.ModifiedAt,
+		reservation.ModifiedBy,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+//ReleaseReservation Update the reservation status and decrease the reserved units in single transaction
+func (repo *MySQLRepository) ReleaseReservation(reservation stock.Reservation) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := finishReservation(tx, reservation); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+//ConvertReservations Update the status of every reservation, decrease the reserved units and apply the sale movements in single transaction
+func (repo *MySQLRepository) ConvertReservations(reservations []stock.Reservation, movements []stock.Movement) ([]stock.Stock, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	stocks := make([]stock.Stock, 0, len(movements))
+
+	for i, reservation := range reservations {
+		if err := finishReservation(tx, reservation); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		current, err := applyMovement(tx, movements[i])
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		stocks = append(stocks, *current)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return stocks, nil
+}
+
+func (repo *MySQLRepository) findReservations(query string, args ...interface{}) ([]stock.Reservation, error) {
+	row, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var reservations []stock.Reservation
+
+	for row.Next() {
+		reservation, err := scanReservation(row)
+		if err != nil {
+			return nil, err
+		}
+
+		reservations = append(reservations, *reservation)
+	}
+
+	return reservations, row.Err()
+}
+
+//finishReservation update the status of active reservation and give its units back to the stock
+func finishReservation(tx *sql.Tx, reservation stock.Reservation) error {
+	updateQuery := `UPDATE stock_reservation
+		SET
+			status = ?,
+			modified_at = ?,
+			modified_by = ?
+		WHERE id = ? AND status = ?`
+
+	res, err := tx.Exec(updateQuery,
+		reservation.Status,
+		reservation.ModifiedAt,
+		reservation.ModifiedBy,
+		reservation.ID,
+		stock.Active,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return changeReserved(tx, reservation, -reservation.Quantity)
+}
+
+//changeReserved add delta into reserved units of the stock, only when the result stay between zero and the quantity
+func changeReserved(tx *sql.Tx, reservation stock.Reservation, delta int) error {
+	updateQuery := `UPDATE item_stock
+		SET
+			reserved = reserved + ?,
+			modified_at = ?
+		WHERE item_id = ? AND warehouse_id = ? AND reserved + ? BETWEEN 0 AND quantity`
+
+	res, err := tx.Exec(updateQuery,
+		delta,
+		reservation.ModifiedAt,
+		reservation.ItemID,
+		reservation.WarehouseID,
+		delta,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return business.ErrInsufficientStock
+	}
+
+	return nil
+}
+
+func scanReservation(row rowScanner) (*stock.Reservation, error) {
+	var reservation stock.Reservation
+
+	err := row.Scan(
+		&reservation.ID, &reservation.ItemID, &reservation.WarehouseID,
+		&reservation.Quantity, &reservation.Reference,
+		&reservation.Status, &reservation.ExpiresAt,
+		&reservation.CreatedAt, &reservation.CreatedBy,
+		&reservation.ModifiedAt, &reservation.ModifiedBy)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+//IsMySQLItemStocked Tell whether item has stock on hand or active reservation in any warehouse using the transaction
+//which delete the item. The stock rows stay locked until the transaction end, so no stock can be added meanwhile
+func IsMySQLItemStocked(ctx context.Context, tx *sql.Tx, itemID string) (bool, error) {
+	row, err := tx.QueryContext(ctx, "SELECT quantity, reserved FROM item_stock WHERE item_id = ? FOR UPDATE", itemID)
+	if err != nil {
+		return false, err
+	}
+
+	stocked := false
+	for row.Next() {
+		var quantity, reserved int
+		if err := row.Scan(&quantity, &reserved); err != nil {
+			row.Close()
+			return false, err
+		}
+
+		stocked = stocked || quantity > 0 || reserved > 0
+	}
+
+	row.Close()
+	if err := row.Err(); err != nil || stocked {
+		return stocked, err
+	}
+
+	var active int
+	countQuery := "SELECT COUNT(*) FROM stock_reservation WHERE item_id = ? AND status = ?"
+	if err := tx.QueryRowContext(ctx, countQuery, itemID, stock.Active).Scan(&active); err != nil {
+		return false, err
+	}
+
+	return active > 0, nil
+}
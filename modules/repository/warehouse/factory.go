@@ -0,0 +1,19 @@
+package warehouse
+
+import (
+	"sample-order/business/warehouse"
+	"sample-order/util"
+)
+
+//RepositoryFactory Will return business.warehouse.Repository based on active database connection
+func RepositoryFactory(dbCon *util.DatabaseConnection) warehouse.Repository {
+	var warehouseRepo warehouse.Repository
+
+	if dbCon.Driver == util.MySQL {
+		warehouseRepo = NewMySQLRepository(dbCon.MySQLDB)
+	} else if dbCon.Driver == util.MongoDB {
+		warehouseRepo = NewMongoDBRepository(dbCon.MongoDB)
+	}
+
+	return warehouseRepo
+}
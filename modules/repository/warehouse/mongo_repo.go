@@ -0,0 +1,115 @@
+package warehouse
+
+import (
+	"context"
+	"sample-order/business/warehouse"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//MongoDBRepository The implementation of warehouse.Repository object
+type MongoDBRepository struct {
+	col *mongo.Collection
+}
+
+type collection struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Name      string             `bson:"name"`
+	Address   string             `bson:"address"`
+	CreatedAt time.Time          `bson:"created_at"`
+	CreatedBy string             `bson:"created_by"`
+}
+
+func newCollection(warehouse warehouse.Warehouse) (*collection, error) {
+	objectID, err := primitive.ObjectIDFromHex(warehouse.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &collection{
+		objectID,
+		warehouse.Name,
+		warehouse.Address,
+		warehouse.CreatedAt,
+		warehouse.CreatedBy,
+	}, nil
+}
+
+func (col *collection) ToWarehouse() warehouse.Warehouse {
+	var warehouse warehouse.Warehouse
+	warehouse.ID = col.ID.Hex()
+	warehouse.Name = col.Name
+	warehouse.Address = col.Address
+	warehouse.CreatedAt = col.CreatedAt
+	warehouse.CreatedBy = col.CreatedBy
+
+	return warehouse
+}
+
+//NewMongoDBRepository Generate mongo DB warehouse repository
+func NewMongoDBRepository(db *mongo.Database) *MongoDBRepository {
+	return &MongoDBRepository{
+		db.Collection("warehouses"),
+	}
+}
+
+//FindWarehouseByID Find warehouse based on given ID. Its return nil if not found
+func (repo *MongoDBRepository) FindWarehouseByID(ID string) (*warehouse.Warehouse, error) {
+	var col collection
+
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		//if cannot be convert means that ID will be never found
+		return nil, nil
+	}
+
+	if err := repo.col.FindOne(context.TODO(), bson.M{"_id": objectID}).Decode(&col); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	warehouse := col.ToWarehouse()
+	return &warehouse, nil
+}
+
+//FindAllWarehouses Find all warehouses ordered by name
+func (repo *MongoDBRepository) FindAllWarehouses() ([]warehouse.Warehouse, error) {
+	cursor, err := repo.col.Find(context.TODO(), bson.M{}, options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	var warehouses []warehouse.Warehouse
+
+	for cursor.Next(context.TODO()) {
+		var col collection
+		if err = cursor.Decode(&col); err != nil {
+			return nil, err
+		}
+
+		warehouses = append(warehouses, col.ToWarehouse())
+	}
+
+	return warehouses, cursor.Err()
+}
+
+//InsertWarehouse Insert new warehouse into database
+func (repo *MongoDBRepository) InsertWarehouse(warehouse warehouse.Warehouse) error {
+	col, err := newCollection(warehouse)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.col.InsertOne(context.TODO(), col)
+
+	return err
+}
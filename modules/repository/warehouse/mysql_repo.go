@@ -0,0 +1,87 @@
+package warehouse
+
+import (
+	"database/sql"
+	"sample-order/business/warehouse"
+)
+
+//MySQLRepository The implementation of warehouse.Repository object
+type MySQLRepository struct {
+	db *sql.DB
+}
+
+//NewMySQLRepository Generate MySQL warehouse repository
+func NewMySQLRepository(db *sql.DB) *MySQLRepository {
+	return &MySQLRepository{
+		db,
+	}
+}
+
+//FindWarehouseByID Find warehouse based on given ID. Its return nil if not found
+func (repo *MySQLRepository) FindWarehouseByID(ID string) (*warehouse.Warehouse, error) {
+	var warehouse warehouse.Warehouse
+
+	selectQuery := `SELECT id, name, address, created_at, created_by
+		FROM warehouse
+		WHERE id = ?`
+
+	err := repo.db.
+		QueryRow(selectQuery, ID).
+		Scan(&warehouse.ID, &warehouse.Name, &warehouse.Address, &warehouse.CreatedAt, &warehouse.CreatedBy)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &warehouse, nil
+}
+
+//FindAllWarehouses Find all warehouses ordered by name
+func (repo *MySQLRepository) FindAllWarehouses() ([]warehouse.Warehouse, error) {
+	row, err := repo.db.Query("SELECT id, name, address, created_at, created_by FROM warehouse ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var warehouses []warehouse.Warehouse
+
+	for row.Next() {
+		var warehouse warehouse.Warehouse
+
+		err := row.Scan(&warehouse.ID, &warehouse.Name, &warehouse.Address, &warehouse.CreatedAt, &warehouse.CreatedBy)
+		if err != nil {
+			return nil, err
+		}
+
+		warehouses = append(warehouses, warehouse)
+	}
+
+	return warehouses, row.Err()
+}
+
+//InsertWarehouse Insert new warehouse into database
+func (repo *MySQLRepository) InsertWarehouse(warehouse warehouse.Warehouse) error {
+	insertQuery := `INSERT INTO warehouse (
+			id,
+			name,
+			address,
+			created_at,
+			created_by
+		) VALUES (?, ?, ?, ?, ?)`
+
+	_, err := repo.db.Exec(insertQuery,
+		warehouse.ID,
+		warehouse.Name,
+		warehouse.Address,
+		warehouse.CreatedAt,
+		warehouse.CreatedBy,
+	)
+
+	return err
+}
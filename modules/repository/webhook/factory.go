@@ -0,0 +1,19 @@
+package webhook
+
+import (
+	"sample-order/business/webhook"
+	"sample-order/util"
+)
+
+//RepositoryFactory Will return business.webhook.Repository based on active database connection
+func RepositoryFactory(dbCon *util.DatabaseConnection) webhook.Repository {
+	var webhookRepo webhook.Repository
+
+	if dbCon.Driver == util.MySQL {
+		webhookRepo = NewMySQLRepository(dbCon.MySQLDB)
+	} else if dbCon.Driver == util.MongoDB {
+		webhookRepo = NewMongoDBRepository(dbCon.MongoDB)
+	}
+
+	return webhookRepo
+}
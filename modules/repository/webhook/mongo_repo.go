@@ -0,0 +1,408 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"sample-order/business"
+	"sample-order/business/webhook"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//duplicateKeyCode MongoDB error code of unique index violation
+const duplicateKeyCode = 11000
+
+//MongoDBRepository The implementation of webhook.Repository object
+type MongoDBRepository struct {
+	client          *mongo.Client
+	subscriptionCol *mongo.Collection
+	deliveryCol     *mongo.Collection
+}
+
+type subscriptionCollection struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	URL        string             `bson:"url"`
+	EventTypes []string           `bson:"event_types"`
+	Tag        string             `bson:"tag"`
+	Secret     string             `bson:"secret"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	CreatedBy  string             `bson:"created_by"`
+	ModifiedAt time.Time          `bson:"modified_at"`
+	ModifiedBy string             `bson:"modified_by"`
+	Version    int                `bson:"version"`
+}
+
+func newSubscriptionCollection(subscription webhook.Subscription) (*subscriptionCollection, error) {
+	objectID, err := primitive.ObjectIDFromHex(subscription.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscriptionCollection{
+		objectID,
+		subscription.URL,
+		subscription.EventTypes,
+		subscription.Tag,
+		subscription.Secret,
+		subscription.CreatedAt,
+		subscription.CreatedBy,
+		subscription.ModifiedAt,
+		subscription.ModifiedBy,
+		subscription.Version,
+	}, nil
+}
+
+func (col *subscriptionCollection) ToSubscription() webhook.Subscription {
+	eventTypes := col.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	return webhook.Subscription{
+		ID:         col.ID.Hex(),
+		URL:        col.URL,
+		EventTypes: eventTypes,
+		Tag:        col.Tag,
+		Secret:     col.Secret,
+		CreatedAt:  col.CreatedAt,
+		CreatedBy:  col.CreatedBy,
+		ModifiedAt: col.ModifiedAt,
+		ModifiedBy: col.ModifiedBy,
+		Version:    col.Version,
+	}
+}
+
+//deliveryCollection webhook delivery, the payload is kept as JSON string
+type deliveryCollection struct {
+	ID             primitive.ObjectID `bson:"_id"`
+	SubscriptionID primitive.ObjectID `bson:"subscription_id"`
+	MessageID      string             `bson:"message_id"`
+	EventType      string             `bson:"event_type"`
+	Payload        string             `bson:"payload"`
+	Status         string             `bson:"status"`
+	Attempts       int                `bson:"attempts"`
+	NextAttemptAt  time.Time          `bson:"next_attempt_at"`
+	LastStatusCode int                `bson:"last_status_code"`
+	LastError      string             `bson:"last_error"`
+	CreatedAt      time.Time          `bson:"created_at"`
+	DeliveredAt    *time.Time         `bson:"delivered_at"`
+}
+
+func newDeliveryCollection(delivery webhook.Delivery) (*deliveryCollection, error) {
+	objectID, err := primitive.ObjectIDFromHex(delivery.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptionID, err := primitive.ObjectIDFromHex(delivery.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deliveryCollection{
+		objectID,
+		subscriptionID,
+		delivery.MessageID,
+		delivery.EventType,
+		string(delivery.Payload),
+		string(delivery.Status),
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.CreatedAt,
+		delivery.DeliveredAt,
+	}, nil
+}
+
+func (col *deliveryCollection) ToDelivery() webhook.Delivery {
+	return webhook.Delivery{
+		ID:             col.ID.Hex(),
+		SubscriptionID: col.SubscriptionID.Hex(),
+		MessageID:      col.MessageID,
+		EventType:      col.EventType,
+		Payload:        []byte(col.Payload),
+		Status:         webhook.DeliveryStatus(col.Status),
+		Attempts:       col.Attempts,
+		NextAttemptAt:  col.NextAttemptAt,
+		LastStatusCode: col.LastStatusCode,
+		LastError:      col.LastError,
+		CreatedAt:      col.CreatedAt,
+		DeliveredAt:    col.DeliveredAt,
+	}
+}
+
+//NewMongoDBRepository Generate mongo DB webhook repository
+func NewMongoDBRepository(db *mongo.Database) *MongoDBRepository {
+	return &MongoDBRepository{
+		db.Client(),
+		db.Collection("webhook_subscriptions"),
+		db.Collection("webhook_deliveries"),
+	}
+}
+
+//FindSubscriptionByID Find subscription based on given ID. Its return nil if not found
+func (repo *MongoDBRepository) FindSubscriptionByID(ID string) (*webhook.Subscription, error) {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		//if cannot be convert means that ID will be never found
+		return nil, nil
+	}
+
+	var col subscriptionCollection
+
+	if err := repo.subscriptionCol.FindOne(context.TODO(), bson.M{"_id": objectID}).Decode(&col); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	subscription := col.ToSubscription()
+	return &subscription, nil
+}
+
+//FindAllSubscriptions Find every subscription from the oldest
+func (repo *MongoDBRepository) FindAllSubscriptions() ([]webhook.Subscription, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := repo.subscriptionCol.Find(context.TODO(), bson.M{}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	var subscriptions []webhook.Subscription
+
+	for cursor.Next(context.TODO()) {
+		var col subscriptionCollection
+		if err = cursor.Decode(&col); err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, col.ToSubscription())
+	}
+
+	return subscriptions, cursor.Err()
+}
+
+//InsertSubscription Insert new subscription into database
+func (repo *MongoDBRepository) InsertSubscription(subscription webhook.Subscription) error {
+	col, err := newSubscriptionCollection(subscription)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.subscriptionCol.InsertOne(context.TODO(), col)
+	return err
+}
+
+//UpdateSubscription Update existing subscription in database
+func (repo *MongoDBRepository) UpdateSubscription(subscription webhook.Subscription, currentVersion int) error {
+	col, err := newSubscriptionCollection(subscription)
+	if err != nil {
+		return business.ErrZeroAffected
+	}
+
+	filter := bson.M{
+		"_id":     col.ID,
+		"version": currentVersion,
+	}
+
+	updated := bson.M{
+		"$set": col,
+	}
+
+	result, err := repo.subscriptionCol.UpdateOne(context.TODO(), filter, updated)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
+
+//DeleteSubscription Delete the subscription and its deliveries inside a transaction
+func (repo *MongoDBRepository) DeleteSubscription(ID string, currentVersion int) error {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return business.ErrZeroAffected
+	}
+
+	return repo.withTransaction(func(sessCtx mongo.SessionContext) error {
+		result, err := repo.subscriptionCol.DeleteOne(sessCtx, bson.M{"_id": objectID, "version": currentVersion})
+		if err != nil {
+			return err
+		}
+
+		if result.DeletedCount == 0 {
+			return business.ErrZeroAffected
+		}
+
+		_, err = repo.deliveryCol.DeleteMany(sessCtx, bson.M{"subscription_id": objectID})
+		return err
+	})
+}
+
+//InsertDeliveries Insert deliveries at once, the unique index of subscription and message reject the existing one
+func (repo *MongoDBRepository) InsertDeliveries(deliveries []webhook.Delivery) error {
+	documents := make([]interface{}, 0, len(deliveries))
+
+	for _, delivery := range deliveries {
+		col, err := newDeliveryCollection(delivery)
+		if err != nil {
+			return err
+		}
+
+		documents = append(documents, col)
+	}
+
+	_, err := repo.deliveryCol.InsertMany(context.TODO(), documents, options.InsertMany().SetOrdered(false))
+	if err != nil && !isDuplicateOnly(err) {
+		return err
+	}
+
+	return nil
+}
+
+//FindDeliveryByID Find delivery based on given ID. Its return nil if not found
+func (repo *MongoDBRepository) FindDeliveryByID(ID string) (*webhook.Delivery, error) {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		//if cannot be convert means that ID will be never found
+		return nil, nil
+	}
+
+	var col deliveryCollection
+
+	if err := repo.deliveryCol.FindOne(context.TODO(), bson.M{"_id": objectID}).Decode(&col); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	delivery := col.ToDelivery()
+	return &delivery, nil
+}
+
+//FindDueDeliveries Find pending deliveries which next attempt is not after now
+func (repo *MongoDBRepository) FindDueDeliveries(now time.Time, limit int) ([]webhook.Delivery, error) {
+	filter := bson.M{
+		"status":          string(webhook.Pending),
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	return repo.findDeliveries(filter, findOptions)
+}
+
+//FindDeliveriesByStatus Find deliveries with given status from the newest
+func (repo *MongoDBRepository) FindDeliveriesByStatus(status webhook.DeliveryStatus, limit int) ([]webhook.Delivery, error) {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+
+	return repo.findDeliveries(bson.M{"status": string(status)}, findOptions)
+}
+
+//UpdateDelivery Update the attempt state of delivery only when its status is still the current status
+func (repo *MongoDBRepository) UpdateDelivery(delivery webhook.Delivery, currentStatus webhook.DeliveryStatus) error {
+	objectID, err := primitive.ObjectIDFromHex(delivery.ID)
+	if err != nil {
+		return business.ErrZeroAffected
+	}
+
+	filter := bson.M{
+		"_id":    objectID,
+		"status": string(currentStatus),
+	}
+
+	updated := bson.M{
+		"$set": bson.M{
+			"status":           string(delivery.Status),
+			"attempts":         delivery.Attempts,
+			"next_attempt_at":  delivery.NextAttemptAt,
+			"last_status_code": delivery.LastStatusCode,
+			"last_error":       delivery.LastError,
+			"delivered_at":     delivery.DeliveredAt,
+		},
+	}
+
+	result, err := repo.deliveryCol.UpdateOne(context.TODO(), filter, updated)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
+
+func (repo *MongoDBRepository) findDeliveries(filter bson.M, findOptions *options.FindOptions) ([]webhook.Delivery, error) {
+	cursor, err := repo.deliveryCol.Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	var deliveries []webhook.Delivery
+
+	for cursor.Next(context.TODO()) {
+		var col deliveryCollection
+		if err = cursor.Decode(&col); err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, col.ToDelivery())
+	}
+
+	return deliveries, cursor.Err()
+}
+
+func (repo *MongoDBRepository) withTransaction(fn func(sessCtx mongo.SessionContext) error) error {
+	session, err := repo.client.StartSession()
+	if err != nil {
+		return err
+	}
+
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(context.TODO(), func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+
+	return err
+}
+
+//isDuplicateOnly Return true if every failed insert is rejected by unique index
+func isDuplicateOnly(err error) bool {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return false
+	}
+
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != duplicateKeyCode {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,345 @@
+package webhook
+
+import (
+	"database/sql"
+	"sample-order/business"
+	"sample-order/business/webhook"
+	"strings"
+	"time"
+)
+
+//maxErrorLength size of last_error column
+const maxErrorLength = 1000
+
+const selectSubscriptionQuery = `SELECT id, url, event_types, tag, secret,
+		created_at, created_by, modified_at, modified_by, version
+		FROM webhook_subscription`
+
+const selectDeliveryQuery = `SELECT id, subscription_id, message_id, event_type, payload,
+		status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at
+		FROM webhook_delivery`
+
+//rowScanner is satisfied by both sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+//MySQLRepository The implementation of webhook.Repository object
+type MySQLRepository struct {
+	db *sql.DB
+}
+
+//NewMySQLRepository Generate MySQL webhook repository
+func NewMySQLRepository(db *sql.DB) *MySQLRepository {
+	return &MySQLRepository{
+		db,
+	}
+}
+
+//FindSubscriptionByID Find subscription based on given ID. Its return nil if not found
+func (repo *MySQLRepository) FindSubscriptionByID(ID string) (*webhook.Subscription, error) {
+	subscription, err := scanSubscription(repo.db.QueryRow(selectSubscriptionQuery+" WHERE id = ?", ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+//FindAllSubscriptions Find every subscription from the oldest
+func (repo *MySQLRepository) FindAllSubscriptions() ([]webhook.Subscription, error) {
+	row, err := repo.db.Query(selectSubscriptionQuery + " ORDER BY created_at, id")
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var subscriptions []webhook.Subscription
+
+	for row.Next() {
+		subscription, err := scanSubscription(row)
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	return subscriptions, row.Err()
+}
+
+//InsertSubscription Insert new subscription into database
+func (repo *MySQLRepository) InsertSubscription(subscription webhook.Subscription) error {
+	insertQuery := `INSERT INTO webhook_subscription (
+			id,
+			url,
+			event_types,
+			tag,
+			secret,
+			created_at,
+			created_by,
+			modified_at,
+			modified_by,
+			version
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := repo.db.Exec(insertQuery,
+		subscription.ID,
+		subscription.URL,
+		strings.Join(subscription.EventTypes, ","),
+		subscription.Tag,
+		subscription.Secret,
+		subscription.CreatedAt,
+		subscription.CreatedBy,
+		subscription.ModifiedAt,
+		subscription.ModifiedBy,
+		subscription.Version,
+	)
+
+	return err
+}
+
+//UpdateSubscription Update existing subscription in database
+func (repo *MySQLRepository) UpdateSubscription(subscription webhook.Subscription, currentVersion int) error {
+	updateQuery := `UPDATE webhook_subscription
+		SET
+			url = ?,
+			event_types = ?,
+			tag = ?,
+			secret = ?,
+			modified_at = ?,
+			modified_by = ?,
+			version = ?
+		WHERE id = ? AND version = ?`
+
+	res, err := repo.db.Exec(updateQuery,
+		subscription.URL,
+		strings.Join(subscription.EventTypes, ","),
+		subscription.Tag,
+		subscription.Secret,
+		subscription.ModifiedAt,
+		subscription.ModifiedBy,
+		subscription.Version,
+		subscription.ID,
+		currentVersion,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(res)
+}
+
+//DeleteSubscription Delete the subscription, its deliveries are deleted by the foreign key
+func (repo *MySQLRepository) DeleteSubscription(ID string, currentVersion int) error {
+	res, err := repo.db.Exec("DELETE FROM webhook_subscription WHERE id = ? AND version = ?", ID, currentVersion)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(res)
+}
+
+//InsertDeliveries Insert deliveries in single transaction, the unique key of subscription and message skip the existing one
+func (repo *MySQLRepository) InsertDeliveries(deliveries []webhook.Delivery) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	insertQuery := `INSERT IGNORE INTO webhook_delivery (
+			id,
+			subscription_id,
+			message_id,
+			event_type,
+			payload,
+			status,
+			attempts,
+			next_attempt_at,
+			last_status_code,
+			last_error,
+			created_at,
+			delivered_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for _, delivery := range deliveries {
+		_, err := tx.Exec(insertQuery,
+			delivery.ID,
+			delivery.SubscriptionID,
+			delivery.MessageID,
+			delivery.EventType,
+			string(delivery.Payload),
+			delivery.Status,
+			delivery.Attempts,
+			delivery.NextAttemptAt,
+			delivery.LastStatusCode,
+			truncate(delivery.LastError),
+			delivery.CreatedAt,
+			delivery.DeliveredAt,
+		)
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+//FindDeliveryByID Find delivery based on given ID. Its return nil if not found
+func (repo *MySQLRepository) FindDeliveryByID(ID string) (*webhook.Delivery, error) {
+	delivery, err := scanDelivery(repo.db.QueryRow(selectDeliveryQuery+" WHERE id = ?", ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+//FindDueDeliveries Find pending deliveries which next attempt is not after now
+func (repo *MySQLRepository) FindDueDeliveries(now time.Time, limit int) ([]webhook.Delivery, error) {
+	return repo.findDeliveries(selectDeliveryQuery+" WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?",
+		webhook.Pending, now, limit)
+}
+
+//FindDeliveriesByStatus Find deliveries with given status from the newest
+func (repo *MySQLRepository) FindDeliveriesByStatus(status webhook.DeliveryStatus, limit int) ([]webhook.Delivery, error) {
+	return repo.findDeliveries(selectDeliveryQuery+" WHERE status = ? ORDER BY created_at DESC, id DESC LIMIT ?", status, limit)
+}
+
+//UpdateDelivery Update the attempt state of delivery only when its status is still the current status
+func (repo *MySQLRepository) UpdateDelivery(delivery webhook.Delivery, currentStatus webhook.DeliveryStatus) error {
+	updateQuery := `UPDATE webhook_delivery
+		SET
+			status = ?,
+			attempts = ?,
+			next_attempt_at = ?,
+			last_status_code = ?,
+			last_error = ?,
+			delivered_at = ?
+		WHERE id = ? AND status = ?`
+
+	res, err := repo.db.Exec(updateQuery,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastStatusCode,
+		truncate(delivery.LastError),
+		delivery.DeliveredAt,
+		delivery.ID,
+		currentStatus,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(res)
+}
+
+func (repo *MySQLRepository) findDeliveries(query string, args ...interface{}) ([]webhook.Delivery, error) {
+	row, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var deliveries []webhook.Delivery
+
+	for row.Next() {
+		delivery, err := scanDelivery(row)
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, row.Err()
+}
+
+func scanSubscription(scanner rowScanner) (*webhook.Subscription, error) {
+	var subscription webhook.Subscription
+	var eventTypes string
+
+	err := scanner.Scan(
+		&subscription.ID, &subscription.URL, &eventTypes,
+		&subscription.Tag, &subscription.Secret,
+		&subscription.CreatedAt, &subscription.CreatedBy,
+		&subscription.ModifiedAt, &subscription.ModifiedBy,
+		&subscription.Version)
+
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.EventTypes = splitColumn(eventTypes)
+
+	return &subscription, nil
+}
+
+func scanDelivery(scanner rowScanner) (*webhook.Delivery, error) {
+	var delivery webhook.Delivery
+	var payload string
+	var deliveredAt sql.NullTime
+
+	err := scanner.Scan(
+		&delivery.ID, &delivery.SubscriptionID, &delivery.MessageID,
+		&delivery.EventType, &payload,
+		&delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt,
+		&delivery.LastStatusCode, &delivery.LastError,
+		&delivery.CreatedAt, &deliveredAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = []byte(payload)
+
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &delivery, nil
+}
+
+func splitColumn(values string) []string {
+	if values == "" {
+		return make([]string, 0)
+	}
+
+	return strings.Split(values, ",")
+}
+
+func truncate(reason string) string {
+	if len(reason) > maxErrorLength {
+		return reason[:maxErrorLength]
+	}
+
+	return reason
+}
+
+func checkAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return business.ErrZeroAffected
+	}
+
+	return nil
+}
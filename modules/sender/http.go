@@ -0,0 +1,54 @@
+package sender
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sample-order/business/webhook"
+	"time"
+)
+
+//maxDrainedBody response body read before the connection is reused, the rest is dropped
+const maxDrainedBody = 64 * 1024
+
+//HTTPSender The implementation of webhook.Sender object which POST the request over HTTP
+type HTTPSender struct {
+	client *http.Client
+}
+
+//NewHTTPSender Generate sender which give up the request after given timeout. Redirect is not followed,
+//so the partner must answer on the subscribed URL
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	return &HTTPSender{
+		&http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+//Send POST the request body with its headers and return the response status code
+func (sender *HTTPSender) Send(request webhook.Request) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, request.URL, bytes.NewReader(request.Body))
+	if err != nil {
+		return 0, err
+	}
+
+	for key, value := range request.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := sender.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxDrainedBody))
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,71 @@
+package sender_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sample-order/business/webhook"
+	"sample-order/modules/sender"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHTTPSender(t *testing.T) {
+	t.Run("Expect receiver verify the signed request", func(t *testing.T) {
+		secret := "0123456789abcdef"
+		body := []byte(`{"type":"ItemCreated"}`)
+		timestamp := time.Now().Unix()
+
+		var isVerified bool
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received, _ := ioutil.ReadAll(r.Body)
+			receivedAt, _ := strconv.ParseInt(r.Header.Get(webhook.TimestampHeader), 10, 64)
+
+			isVerified = r.Method == http.MethodPost &&
+				webhook.Verify(secret, receivedAt, received, r.Header.Get(webhook.SignatureHeader))
+
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer receiver.Close()
+
+		statusCode, err := sender.NewHTTPSender(time.Second).Send(webhook.Request{
+			URL: receiver.URL,
+			Headers: map[string]string{
+				webhook.SignatureHeader: webhook.Sign(secret, timestamp, body),
+				webhook.TimestampHeader: strconv.FormatInt(timestamp, 10),
+			},
+			Body: body,
+		})
+
+		if err != nil || statusCode != http.StatusNoContent {
+			t.Error("Expect request is accepted", statusCode, err)
+		}
+
+		if !isVerified {
+			t.Error("Expect receiver verify the signature")
+		}
+	})
+
+	t.Run("Expect redirect is returned as status code", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/other", http.StatusFound)
+		}))
+		defer receiver.Close()
+
+		statusCode, err := sender.NewHTTPSender(time.Second).Send(webhook.Request{URL: receiver.URL})
+
+		if err != nil || statusCode != http.StatusFound {
+			t.Error("Expect redirect is not followed", statusCode, err)
+		}
+	})
+
+	t.Run("Expect error when receiver is not reachable", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		receiver.Close()
+
+		if _, err := sender.NewHTTPSender(time.Second).Send(webhook.Request{URL: receiver.URL}); err == nil {
+			t.Error("Expect error is not nil")
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package sweeper
+
+import (
+	"sample-order/business/stock"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+//ReservationSweeper Background worker which periodically release the stock of expired reservations
+type ReservationSweeper struct {
+	service  stock.Service
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+//NewReservationSweeper Generate sweeper which run every given interval
+func NewReservationSweeper(service stock.Service, interval time.Duration) *ReservationSweeper {
+	return &ReservationSweeper{
+		service,
+		interval,
+		make(chan struct{}),
+		make(chan struct{}),
+	}
+}
+
+//Start Run the sweeper in its own goroutine
+func (sweeper *ReservationSweeper) Start() {
+	go sweeper.run()
+}
+
+//Stop Signal the sweeper to stop and wait until the running sweep is finished
+func (sweeper *ReservationSweeper) Stop() {
+	close(sweeper.stop)
+	<-sweeper.done
+}
+
+func (sweeper *ReservationSweeper) run() {
+	defer close(sweeper.done)
+
+	ticker := time.NewTicker(sweeper.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sweeper.stop:
+			return
+		case now := <-ticker.C:
+			sweeper.sweep(now)
+		}
+	}
+}
+
+func (sweeper *ReservationSweeper) sweep(now time.Time) {
+	expired, err := sweeper.service.ExpireReservations(now)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to expire reservations")
+	}
+
+	if expired > 0 {
+		log.Info().Int("expired", expired).Msg("expired reservations")
+	}
+}
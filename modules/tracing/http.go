@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"net/http"
+	"sample-order/util"
+
+	"github.com/labstack/echo"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//Middleware Return echo middleware which start server span of every request. The span continue the trace of the caller
+//given in traceparent header, and the traceparent of the span is sent back so the client can find its trace
+func (tracing *Tracing) Middleware() echo.MiddlewareFunc {
+	matcher := util.NewRouteMatcher()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			route := matcher.Route(c)
+			name := req.Method + " " + route
+			if route == "" {
+				name = "HTTP " + req.Method
+			}
+
+			ctx := tracing.propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			ctx, span := tracing.tracer.Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest("", route, req)...))
+			defer span.End()
+
+			c.SetRequest(req.WithContext(ctx))
+			tracing.propagator.Inject(ctx, propagation.HeaderCarrier(c.Response().Header()))
+
+			err := next(c)
+
+			status := util.ResponseStatus(c, err)
+			span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+
+			//client error is the caller fault, only server error fail the span
+			if status >= http.StatusInternalServerError {
+				if err != nil {
+					span.RecordError(err)
+				}
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+
+			return err
+		}
+	}
+}
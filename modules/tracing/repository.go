@@ -0,0 +1,115 @@
+package tracing
+
+import (
+	"context"
+	"sample-order/business/item"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//itemRepository item.Repository decorator which add span of every operation of the wrapped repository
+type itemRepository struct {
+	next    item.Repository
+	driver  string
+	tracing *Tracing
+}
+
+//TraceItemRepository Wrap the item repository so its database calls appear in the trace of the request
+func (tracing *Tracing) TraceItemRepository(repository item.Repository, driver string) item.Repository {
+	return &itemRepository{
+		repository,
+		driver,
+		tracing,
+	}
+}
+
+func (repo *itemRepository) start(ctx context.Context, method string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	attributes = append(attributes, semconv.DBSystemKey.String(repo.driver), semconv.DBOperationKey.String(method))
+
+	return repo.tracing.startChild(ctx, "item.Repository/"+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...))
+}
+
+//FindItemByID implement item.Repository
+func (repo *itemRepository) FindItemByID(ctx context.Context, ID string) (*item.Item, error) {
+	ctx, span := repo.start(ctx, "FindItemByID", attribute.String("item.id", ID))
+	found, err := repo.next.FindItemByID(ctx, ID)
+	end(span, err)
+
+	return found, err
+}
+
+//FindAllByTag implement item.Repository
+func (repo *itemRepository) FindAllByTag(ctx context.Context, tag string, priceRange item.PriceRange) ([]item.Item, error) {
+	ctx, span := repo.start(ctx, "FindAllByTag", attribute.String("item.tag", tag))
+	items, err := repo.next.FindAllByTag(ctx, tag, priceRange)
+	end(span, err)
+
+	return items, err
+}
+
+//InsertItem implement item.Repository
+func (repo *itemRepository) InsertItem(ctx context.Context, item item.Item, event item.Event) error {
+	ctx, span := repo.start(ctx, "InsertItem", attribute.String("item.id", item.ID))
+	err := repo.next.InsertItem(ctx, item, event)
+	end(span, err)
+
+	return err
+}
+
+//UpdateItem implement item.Repository
+func (repo *itemRepository) UpdateItem(ctx context.Context, item item.Item, currentVersion int, event item.Event) error {
+	ctx, span := repo.start(ctx, "UpdateItem", attribute.String("item.id", item.ID), attribute.Int("item.version", currentVersion))
+	err := repo.next.UpdateItem(ctx, item, currentVersion, event)
+	end(span, err)
+
+	return err
+}
+
+//DeleteItem implement item.Repository
+func (repo *itemRepository) DeleteItem(ctx context.Context, ID string, currentVersion int, event item.Event) error {
+	ctx, span := repo.start(ctx, "DeleteItem", attribute.String("item.id", ID), attribute.Int("item.version", currentVersion))
+	err := repo.next.DeleteItem(ctx, ID, currentVersion, event)
+	end(span, err)
+
+	return err
+}
+
+//InsertItems implement item.Repository
+func (repo *itemRepository) InsertItems(ctx context.Context, items []item.Item, events []item.Event) error {
+	ctx, span := repo.start(ctx, "InsertItems", attribute.Int("item.count", len(items)))
+	err := repo.next.InsertItems(ctx, items, events)
+	end(span, err)
+
+	return err
+}
+
+//StreamItems implement item.Repository, the span include the time spent by fn
+func (repo *itemRepository) StreamItems(ctx context.Context, tag string, fn func(item item.Item) error) error {
+	ctx, span := repo.start(ctx, "StreamItems", attribute.String("item.tag", tag))
+	err := repo.next.StreamItems(ctx, tag, fn)
+	end(span, err)
+
+	return err
+}
+
+//FindItemsAfterID implement item.Repository
+func (repo *itemRepository) FindItemsAfterID(ctx context.Context, afterID string, limit int) ([]item.Item, error) {
+	ctx, span := repo.start(ctx, "FindItemsAfterID", attribute.Int("item.limit", limit))
+	items, err := repo.next.FindItemsAfterID(ctx, afterID, limit)
+	end(span, err)
+
+	return items, err
+}
+
+//FindChangesAfter implement item.Repository
+func (repo *itemRepository) FindChangesAfter(ctx context.Context, token item.ChangeToken, limit int) ([]item.Change, error) {
+	ctx, span := repo.start(ctx, "FindChangesAfter", attribute.Int("item.limit", limit))
+	changes, err := repo.next.FindChangesAfter(ctx, token, limit)
+	end(span, err)
+
+	return changes, err
+}
@@ -0,0 +1,125 @@
+package tracing
+
+import (
+	"context"
+	"sample-order/business"
+	"sample-order/business/item"
+	"sample-order/business/item/spec"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//itemService item.Service decorator which add span of every operation of the wrapped service
+type itemService struct {
+	next    item.Service
+	tracing *Tracing
+}
+
+//TraceItemService Wrap the item service so its operations appear in the trace of the request
+func (tracing *Tracing) TraceItemService(service item.Service) item.Service {
+	return &itemService{
+		service,
+		tracing,
+	}
+}
+
+func (s *itemService) start(ctx context.Context, method string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracing.startChild(ctx, "item.Service/"+method, trace.WithAttributes(attributes...))
+}
+
+//GetItemByID implement item.Service
+func (s *itemService) GetItemByID(ctx context.Context, ID string) (*item.Item, error) {
+	ctx, span := s.start(ctx, "GetItemByID", attribute.String("item.id", ID))
+	found, err := s.next.GetItemByID(ctx, ID)
+	end(span, err)
+
+	return found, err
+}
+
+//GetItemsByTag implement item.Service
+func (s *itemService) GetItemsByTag(ctx context.Context, tag string) ([]item.Item, error) {
+	ctx, span := s.start(ctx, "GetItemsByTag", attribute.String("item.tag", tag))
+	items, err := s.next.GetItemsByTag(ctx, tag)
+	end(span, err)
+
+	return items, err
+}
+
+//GetItemsByTagAndPrice implement item.Service
+func (s *itemService) GetItemsByTagAndPrice(ctx context.Context, tag string, priceRange item.PriceRange) ([]item.Item, error) {
+	ctx, span := s.start(ctx, "GetItemsByTagAndPrice", attribute.String("item.tag", tag))
+	items, err := s.next.GetItemsByTagAndPrice(ctx, tag, priceRange)
+	end(span, err)
+
+	return items, err
+}
+
+//CreateItem implement item.Service
+func (s *itemService) CreateItem(ctx context.Context, upsertitemSpec spec.UpsertItemSpec, createdBy string) (string, error) {
+	ctx, span := s.start(ctx, "CreateItem")
+	ID, err := s.next.CreateItem(ctx, upsertitemSpec, createdBy)
+	span.SetAttributes(attribute.String("item.id", ID))
+	end(span, err)
+
+	return ID, err
+}
+
+//UpdateItem implement item.Service
+func (s *itemService) UpdateItem(ctx context.Context, ID string, upsertitemSpec spec.UpsertItemSpec, currentVersion int, modifiedBy string) error {
+	ctx, span := s.start(ctx, "UpdateItem", attribute.String("item.id", ID), attribute.Int("item.version", currentVersion))
+	err := s.next.UpdateItem(ctx, ID, upsertitemSpec, currentVersion, modifiedBy)
+	end(span, err)
+
+	return err
+}
+
+//DeleteItem implement item.Service
+func (s *itemService) DeleteItem(ctx context.Context, ID string, currentVersion int, deletedBy string) error {
+	ctx, span := s.start(ctx, "DeleteItem", attribute.String("item.id", ID), attribute.Int("item.version", currentVersion))
+	err := s.next.DeleteItem(ctx, ID, currentVersion, deletedBy)
+	end(span, err)
+
+	return err
+}
+
+//ImportItems implement item.Service
+func (s *itemService) ImportItems(ctx context.Context, rows []item.ImportRow, createdBy string, dryRun bool) (*item.ImportReport, error) {
+	ctx, span := s.start(ctx, "ImportItems", attribute.Int("item.import.rows", len(rows)), attribute.Bool("item.import.dry_run", dryRun))
+	report, err := s.next.ImportItems(ctx, rows, createdBy, dryRun)
+	end(span, err)
+
+	return report, err
+}
+
+//ExportItems implement item.Service, the span include the time spent by fn
+func (s *itemService) ExportItems(ctx context.Context, tag string, fn func(item item.Item) error) error {
+	ctx, span := s.start(ctx, "ExportItems", attribute.String("item.tag", tag))
+	err := s.next.ExportItems(ctx, tag, fn)
+	end(span, err)
+
+	return err
+}
+
+//GetChanges implement item.Service
+func (s *itemService) GetChanges(ctx context.Context, since string, limit int) (*item.ChangeFeed, error) {
+	ctx, span := s.start(ctx, "GetChanges", attribute.Int("item.changes.limit", limit))
+	feed, err := s.next.GetChanges(ctx, since, limit)
+	end(span, err)
+
+	return feed, err
+}
+
+//end finish the span and mark it failed when the error is not expected business outcome
+//such as not found, invalid spec or version conflict
+func end(span trace.Span, err error) {
+	switch err {
+	case nil, business.ErrNotFound, business.ErrInvalidSpec, business.ErrHasBeenModified, business.ErrZeroAffected:
+	default:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/exporters/stdout"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//tracerName instrumentation name of the spans created by this service
+const tracerName = "sample-order"
+
+//Options where and how much the spans are exported
+type Options struct {
+	//Exporter none, stdout or otlp
+	Exporter    string
+	Endpoint    string
+	Insecure    bool
+	ServiceName string
+	SampleRatio float64
+}
+
+//Tracing OpenTelemetry tracer of the service with the echo middleware and decorators which add spans into each layer
+type Tracing struct {
+	provider   *sdktrace.TracerProvider
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+//New Construct tracing which export the spans according to the options. Exporter none create no span at all
+func New(options Options) (*Tracing, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch options.Exporter {
+	case "none":
+		return NewWithProvider(sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.NeverSample()))), nil
+	case "stdout":
+		exporter, err = stdout.NewExporter(stdout.WithPrettyPrint(), stdout.WithoutMetricExport())
+	case "otlp":
+		driverOptions := []otlpgrpc.Option{otlpgrpc.WithEndpoint(options.Endpoint)}
+		if options.Insecure {
+			driverOptions = append(driverOptions, otlpgrpc.WithInsecure())
+		}
+
+		//the driver connect in background, so unreachable collector does not stop the server start
+		exporter, err = otlp.NewExporter(context.Background(), otlpgrpc.NewDriver(driverOptions...))
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter %q", options.Exporter)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.ServiceNameKey.String(options.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SampleRatio))),
+	)
+
+	return NewWithProvider(provider), nil
+}
+
+//NewWithProvider Construct tracing with the given provider, the trace context is propagated using W3C traceparent header
+func NewWithProvider(provider *sdktrace.TracerProvider) *Tracing {
+	return &Tracing{
+		provider,
+		provider.Tracer(tracerName),
+		propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+}
+
+//Shutdown Export the remaining spans and stop the exporter
+func (tracing *Tracing) Shutdown(ctx context.Context) error {
+	return tracing.provider.Shutdown(ctx)
+}
+
+//startChild start span only inside the trace of the caller. Call without trace, such as from background worker
+//or other service which has no context yet, would create single span trace which tell nothing
+func (tracing *Tracing) startChild(ctx context.Context, name string, options ...trace.SpanOption) (context.Context, trace.Span) {
+	parent := trace.SpanFromContext(ctx)
+	if !parent.SpanContext().IsValid() {
+		return ctx, parent
+	}
+
+	return tracing.tracer.Start(ctx, name, options...)
+}
@@ -0,0 +1,116 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sample-order/business/item"
+	"sample-order/modules/tracing"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	appTracing := tracing.NewWithProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	service := appTracing.TraceItemService(item.NewService(appTracing.TraceItemRepository(&singleItemRepository{}, "mysql")))
+
+	e := echo.New()
+	e.Use(appTracing.Middleware())
+	e.GET("/items/:id", func(c echo.Context) error {
+		found, err := service.GetItemByID(c.Request().Context(), c.Param("id"))
+		if err != nil {
+			return c.NoContent(http.StatusInternalServerError)
+		} else if found == nil {
+			return c.NoContent(http.StatusNotFound)
+		}
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	t.Run("Expect request, service and repository spans continue the trace of the caller", func(t *testing.T) {
+		exporter.Reset()
+
+		req := httptest.NewRequest(http.MethodGet, "/items/5f350b7d21148431abc65290", nil)
+		req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, req)
+
+		spans := exporter.GetSpans()
+		if len(spans) != 3 {
+			t.Error("Expect three spans", len(spans))
+			t.FailNow()
+		}
+
+		//spans are exported when they end, from the innermost
+		repositorySpan, serviceSpan, serverSpan := spans[0], spans[1], spans[2]
+
+		if serverSpan.Name != "GET /items/:id" || repositorySpan.Name != "item.Repository/FindItemByID" || serviceSpan.Name != "item.Service/GetItemByID" {
+			t.Error("Expect spans named by route and method", serverSpan.Name, serviceSpan.Name, repositorySpan.Name)
+		}
+
+		for _, span := range spans {
+			if span.SpanContext.TraceID().String() != traceID {
+				t.Error("Expect span belong to the trace of the caller", span.Name)
+			}
+		}
+
+		if serviceSpan.Parent.SpanID() != serverSpan.SpanContext.SpanID() || repositorySpan.Parent.SpanID() != serviceSpan.SpanContext.SpanID() {
+			t.Error("Expect repository span inside service span inside server span")
+		}
+
+		if !strings.Contains(recorder.Header().Get("traceparent"), traceID) {
+			t.Error("Expect traceparent of the server span is sent back", recorder.Header().Get("traceparent"))
+		}
+	})
+
+	t.Run("Expect failure mark the spans as error", func(t *testing.T) {
+		exporter.Reset()
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/failing", nil))
+
+		spans := exporter.GetSpans()
+		if len(spans) != 3 {
+			t.Error("Expect three spans", len(spans))
+			t.FailNow()
+		}
+
+		for _, span := range spans {
+			if span.StatusCode != codes.Error {
+				t.Error("Expect span is failed", span.Name)
+			}
+		}
+	})
+
+	t.Run("Expect no span outside of request", func(t *testing.T) {
+		exporter.Reset()
+
+		service.GetItemByID(context.Background(), "5f350b7d21148431abc65290")
+
+		if spans := exporter.GetSpans(); len(spans) != 0 {
+			t.Error("Expect call without trace create no span", len(spans))
+		}
+	})
+}
+
+//singleItemRepository item repository which find every item except the failing one
+type singleItemRepository struct {
+	item.Repository
+}
+
+func (repo *singleItemRepository) FindItemByID(ctx context.Context, ID string) (*item.Item, error) {
+	if ID == "failing" {
+		return nil, errors.New("connection refused")
+	}
+
+	return &item.Item{ID: ID}, nil
+}
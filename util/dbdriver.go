@@ -3,11 +3,13 @@ package util
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sample-order/config"
 	"time"
 
-	"github.com/labstack/gommon/log"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -66,6 +68,39 @@ func (db *DatabaseConnection) CloseConnection() {
 	}
 }
 
+//Ping Check the database is reachable, the check is canceled when ctx is done
+func (db *DatabaseConnection) Ping(ctx context.Context) error {
+	if db.MySQLDB != nil {
+		return db.MySQLDB.PingContext(ctx)
+	}
+
+	return db.mongoClient.Ping(ctx, readpref.Primary())
+}
+
+//CheckTransaction Return error when the database can not run multi document transaction, MongoDB support it only
+//on replica set or sharded cluster while every item, stock, booking and order write use transaction
+func (db *DatabaseConnection) CheckTransaction(ctx context.Context) error {
+	if db.mongoClient == nil {
+		return nil
+	}
+
+	var topology struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+
+	if err := db.MongoDB.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&topology); err != nil {
+		return err
+	}
+
+	//replica set member report its set name and mongos report isdbgrid
+	if topology.SetName == "" && topology.Msg != "isdbgrid" {
+		return errors.New("mongodb is running as standalone server, transaction require replica set or sharded cluster deployment")
+	}
+
+	return nil
+}
+
 func newMysqlDB(config *config.AppConfig) *sql.DB {
 	var uri string
 
@@ -78,7 +113,7 @@ func newMysqlDB(config *config.AppConfig) *sql.DB {
 
 	db, err := sql.Open("mysql", uri)
 	if err != nil {
-		log.Info("failed to connect database: ", err)
+		log.Error().Err(err).Msg("failed to connect database")
 		panic(err)
 	}
 
@@ -87,7 +122,7 @@ func newMysqlDB(config *config.AppConfig) *sql.DB {
 
 	err = db.PingContext(ctx)
 	if err != nil {
-		log.Info("failed to connect database: ", err)
+		log.Error().Err(err).Msg("failed to connect database")
 		panic(err)
 	}
 
@@ -0,0 +1,50 @@
+package util
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/labstack/echo"
+)
+
+//RouteMatcher Find the registered route template of the request, so unknown URL is not used as label or span name
+type RouteMatcher struct {
+	once   sync.Once
+	routes map[string]bool
+}
+
+//NewRouteMatcher Construct route matcher, the routes are read from echo on the first request
+func NewRouteMatcher() *RouteMatcher {
+	return &RouteMatcher{routes: make(map[string]bool)}
+}
+
+//Route Return the route template of the request or empty string when no registered route match.
+//echo keep the requested URL as path when no route match
+func (matcher *RouteMatcher) Route(c echo.Context) string {
+	//routes are registered before the server start, so they are read once
+	matcher.once.Do(func() {
+		for _, route := range c.Echo().Routes() {
+			matcher.routes[route.Path] = true
+		}
+	})
+
+	if route := c.Path(); matcher.routes[route] {
+		return route
+	}
+
+	return ""
+}
+
+//ResponseStatus Return the status code sent to the client. The error returned by the handler is only written
+//by the outermost middleware, until then the status is taken from the error the same way echo error handler does
+func ResponseStatus(c echo.Context, err error) int {
+	if err == nil || c.Response().Committed {
+		return c.Response().Status
+	}
+
+	if httpError, ok := err.(*echo.HTTPError); ok {
+		return httpError.Code
+	}
+
+	return http.StatusInternalServerError
+}